@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/service"
+)
+
+// 관리 명령. 서버와 같은 바이너리/설정(환경 변수, ../conf)을 사용하므로 컨테이너 안에서 바로 실행할 수 있다.
+//
+//	api asset-sync plan <api|menu>
+//	api asset-sync apply [-yes] <api|menu>
+//	api asset-sync backups <api|menu>
+//	api asset-sync rollback [-backup ID] <api|menu>
+//	api menu sync [-dry-run] [-prune] [-offline] [-yes]
+//	api menu check [-strict] [-pages DIR] [-export FILE]
+
+// command 관리 명령 하나
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, cfg *config.Config, args []string) error
+	// apiSpecOptional ../conf/api.yaml 없이도 실행 가능 (asset-sync가 처음 받아옴)
+	apiSpecOptional bool
+}
+
+var commands = []command{
+	{
+		name:            "asset-sync",
+		usage:           "asset-sync plan|apply|backups|rollback [flags] <api|menu>   sync conf/api.yaml or the menu yaml from its remote URL",
+		run:             runAssetSync,
+		apiSpecOptional: true,
+	},
+	{
+		name:  "menu",
+		usage: "menu sync|check [flags]   provision the menu yaml and permission csv to mc-iam-manager, or cross-check them with the page templates and menu export",
+		run:   runMenu,
+	},
+}
+
+// runCommand args[0] 명령 실행 후 종료 코드 반환
+func runCommand(args []string) int {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printCommandUsage(os.Stdout)
+		return 0
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printCommandUsage(os.Stderr)
+		return 2
+	}
+
+	load := config.Load
+	if cmd.apiSpecOptional {
+		load = config.LoadWithoutApiSpec
+	}
+	cfg, err := load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := cmd.run(ctx, cfg, args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printCommandUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: api [command]   (no command starts the server)")
+	fmt.Fprintln(w)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\n", cmd.usage)
+	}
+}
+
+// runAssetSync api asset-sync <plan|apply|backups|rollback> [flags] <api|menu>
+func runAssetSync(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: asset-sync plan|apply|backups|rollback [flags] <api|menu>")
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("asset-sync "+action, flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	yes := fs.Bool("yes", false, "apply without confirmation")
+	backupID := fs.String("backup", "", "backup ID to restore (default: latest)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: asset-sync %s [flags] <api|menu>", action)
+	}
+	name := fs.Arg(0)
+
+	syncer := service.InitAssetSyncer(cfg.RemoteAssets, service.InitRemoteAssetChecker(cfg.RemoteAssets))
+	switch action {
+	case "plan":
+		plan, err := syncer.Plan(ctx, name)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(plan)
+		}
+		printAssetSyncPlan(os.Stdout, plan)
+		return nil
+
+	case "apply":
+		plan, err := syncer.Plan(ctx, name)
+		if err != nil {
+			return err
+		}
+		printAssetSyncPlan(os.Stdout, plan)
+		if !plan.Valid {
+			return service.ErrAssetSyncInvalid
+		}
+		if plan.UpToDate {
+			return nil
+		}
+		if !*yes && !confirm(fmt.Sprintf("Apply to %s?", plan.LocalPath)) {
+			fmt.Println("The operation has been canceled.")
+			return nil
+		}
+		_, result, err := syncer.Apply(ctx, name, plan.ResultSHA)
+		if err != nil {
+			return err
+		}
+		printAssetSyncResult(result, plan.LocalPath)
+		return nil
+
+	case "backups":
+		backups, err := syncer.Backups(name)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(backups)
+		}
+		if len(backups) == 0 {
+			fmt.Println("no backups")
+		}
+		for _, backup := range backups {
+			fmt.Printf("%s  %8d bytes  sha=%s\n", backup.ID, backup.Size, backup.SHA)
+		}
+		return nil
+
+	case "rollback":
+		result, err := syncer.Rollback(name, *backupID)
+		if err != nil {
+			return err
+		}
+		printAssetSyncResult(result, name)
+		return nil
+	}
+	return fmt.Errorf("unknown asset-sync action %q (plan, apply, backups, rollback)", action)
+}
+
+func printAssetSyncPlan(w io.Writer, plan *service.AssetSyncPlan) {
+	fmt.Fprintf(w, "%s: %s -> %s\n", plan.Name, plan.URL, plan.LocalPath)
+	if !plan.Valid {
+		fmt.Fprintln(w, "status: INVALID, nothing will be applied")
+		for _, issue := range plan.Issues {
+			fmt.Fprintf(w, "  ! %s\n", issue)
+		}
+		return
+	}
+	if plan.UpToDate {
+		fmt.Fprintln(w, "status: up to date")
+		return
+	}
+	fmt.Fprintf(w, "status: changes pending (result sha %s)\n", plan.ResultSHA)
+	for _, kept := range plan.Preserved {
+		fmt.Fprintf(w, "  keep local baseurl %s: %s (upstream %s)\n", kept.Service, kept.Local, kept.Upstream)
+	}
+	printAssetSyncChanges(w, "services", plan.Services)
+	printAssetSyncChanges(w, "actions", plan.Actions)
+	printAssetSyncChanges(w, "menus", plan.Menus)
+}
+
+// printAssetSyncChanges 구분별 최대 50줄 출력 (전체는 -json)
+func printAssetSyncChanges(w io.Writer, title string, changes *service.AssetSyncChanges) {
+	if changes == nil {
+		return
+	}
+	fmt.Fprintf(w, "%s: +%d -%d ~%d\n", title, len(changes.Added), len(changes.Removed), len(changes.Changed))
+	var lines []string
+	for _, key := range changes.Added {
+		lines = append(lines, "  + "+key)
+	}
+	for _, key := range changes.Removed {
+		lines = append(lines, "  - "+key)
+	}
+	for _, change := range changes.Changed {
+		fields := make([]string, 0, len(change.Fields))
+		for _, field := range change.Fields {
+			fields = append(fields, fmt.Sprintf("%s %q -> %q", field.Field, field.Local, field.Upstream))
+		}
+		lines = append(lines, fmt.Sprintf("  ~ %s: %s", change.Key, strings.Join(fields, ", ")))
+	}
+	const maxLines = 50
+	for i, line := range lines {
+		if i == maxLines {
+			fmt.Fprintf(w, "  ... %d more (use -json)\n", len(lines)-maxLines)
+			break
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func printAssetSyncResult(result *service.AssetSyncResult, target string) {
+	if !result.Applied {
+		fmt.Printf("%s is already up to date\n", target)
+		return
+	}
+	fmt.Printf("%s written (sha %s)\n", target, result.SHA)
+	if result.Backup != "" {
+		fmt.Printf("previous version backed up as %s\n", result.Backup)
+	}
+	fmt.Printf("running servers load it on restart or via POST /api/admin/asset-sync/%s/reload\n", result.Name)
+}
+
+// runMenu api menu sync [flags]
+func runMenu(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "sync":
+			return runMenuSync(ctx, cfg, args[1:])
+		case "check":
+			return runMenuCheck(cfg, args[1:])
+		}
+	}
+	return fmt.Errorf("usage: menu sync [-dry-run] [-prune] [-offline] [-yes] [-json] | menu check [-strict] [-json]")
+}
+
+func runMenuSync(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("menu sync", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "show the plan without calling mc-iam-manager write APIs")
+	prune := fs.Bool("prune", false, "delete menus that are not in the menu yaml")
+	offline := fs.Bool("offline", false, "plan against the menu export file instead of mc-iam-manager (implies -dry-run)")
+	yes := fs.Bool("yes", false, "apply without confirmation")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts := service.MenuSyncOptions{Prune: *prune, DryRun: *dryRun || *offline, Offline: *offline}
+
+	syncer := service.InitMenuSyncer(cfg)
+	plan, err := syncer.Plan(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		if err := printJSON(plan); err != nil {
+			return err
+		}
+	} else {
+		printMenuSyncPlan(os.Stdout, plan)
+	}
+	if !plan.Valid {
+		return service.ErrMenuSyncInvalid
+	}
+	if opts.DryRun || plan.UpToDate {
+		return nil
+	}
+	if !*yes && !confirm("Apply to mc-iam-manager?") {
+		fmt.Println("The operation has been canceled.")
+		return nil
+	}
+	_, result, err := syncer.Apply(ctx, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created %d, updated %d, deleted %d menus, permissions imported: %t\n",
+		len(result.Created), len(result.Updated), len(result.Deleted), result.PermissionsImported)
+	for _, failure := range result.Failed {
+		fmt.Printf("  ! %s: %s\n", failure.Target, failure.Error)
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d operations failed, run again after fixing them", len(result.Failed))
+	}
+	return nil
+}
+
+// runMenuCheck 메뉴 yaml, 메뉴 권한 CSV, front 페이지 템플릿, 메뉴 내보내기 파일 정합성 검사. 오류가 있으면(-strict면 경고도) 실패한다.
+func runMenuCheck(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("menu check", flag.ContinueOnError)
+	strict := fs.Bool("strict", false, "fail on warnings too")
+	pagesDir := fs.String("pages", cfg.MenuSync.PagesDir, "front page template directory (empty skips the template check)")
+	exportFile := fs.String("export", cfg.MenuSync.ExportFile, "mc-iam-manager menu export file (empty skips the export check)")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	menus, err := config.LoadMenuResources(cfg.MenuSync.MenusFile)
+	if err != nil {
+		return err
+	}
+	perms, err := config.LoadMenuPermissions(cfg.MenuSync.PermissionsFile)
+	if err != nil {
+		return err
+	}
+	src := config.MenuCheckSources{Framework: cfg.MenuSync.Framework, Menus: menus, Permissions: perms}
+	var skipped []string
+	if *pagesDir != "" {
+		if src.Pages, err = config.ListMenuPages(*pagesDir); err != nil {
+			return err
+		}
+	} else {
+		skipped = append(skipped, config.MenuCheckTemplates)
+	}
+	if *exportFile != "" {
+		if src.Export, err = config.LoadMenuExport(*exportFile); err != nil {
+			return err
+		}
+	} else {
+		skipped = append(skipped, config.MenuCheckExport)
+	}
+
+	report := config.CheckMenuConsistency(src)
+	if *asJSON {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("%s + %s", cfg.MenuSync.MenusFile, cfg.MenuSync.PermissionsFile)
+		if *pagesDir != "" {
+			fmt.Printf(" + %s", *pagesDir)
+		}
+		if *exportFile != "" {
+			fmt.Printf(" + %s", *exportFile)
+		}
+		fmt.Printf(" (framework %s)\n", cfg.MenuSync.Framework)
+		if len(skipped) > 0 {
+			fmt.Printf("skipped: %s\n", strings.Join(skipped, ", "))
+		}
+		for _, finding := range report.Errors {
+			fmt.Printf("  ! [%s] %s\n", finding.Check, finding.Message)
+		}
+		for _, finding := range report.Warnings {
+			fmt.Printf("  warning: [%s] %s\n", finding.Check, finding.Message)
+		}
+		fmt.Printf("%d errors, %d warnings\n", len(report.Errors), len(report.Warnings))
+	}
+	if len(report.Errors) > 0 || (*strict && len(report.Warnings) > 0) {
+		return fmt.Errorf("menu check failed: %d errors, %d warnings", len(report.Errors), len(report.Warnings))
+	}
+	return nil
+}
+
+func printMenuSyncPlan(w io.Writer, plan *service.MenuSyncPlan) {
+	fmt.Fprintf(w, "%s + %s -> %s (framework %s)\n", plan.MenusFile, plan.Permissions, plan.Baseline, plan.Framework)
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(w, "  warning: %s\n", warning)
+	}
+	if !plan.Valid {
+		fmt.Fprintln(w, "status: INVALID, nothing will be applied")
+		for _, issue := range plan.Issues {
+			fmt.Fprintf(w, "  ! %s\n", issue)
+		}
+		return
+	}
+	if plan.UpToDate {
+		fmt.Fprintln(w, "status: up to date")
+		return
+	}
+	fmt.Fprintln(w, "status: changes pending")
+	menus := plan.Menus
+	fmt.Fprintf(w, "menus: +%d ~%d -%d (unmanaged %d)\n", len(menus.Create), len(menus.Update), len(menus.Delete), len(menus.Unmanaged))
+	for _, menu := range menus.Create {
+		fmt.Fprintf(w, "  + %s (parent %s)\n", menu.Id, menu.ParentId)
+	}
+	for _, update := range menus.Update {
+		fields := make([]string, 0, len(update.Fields))
+		for _, field := range update.Fields {
+			fields = append(fields, fmt.Sprintf("%s %q -> %q", field.Field, field.Current, field.Desired))
+		}
+		fmt.Fprintf(w, "  ~ %s: %s\n", update.Id, strings.Join(fields, ", "))
+	}
+	for _, id := range menus.Delete {
+		fmt.Fprintf(w, "  - %s\n", id)
+	}
+	if len(menus.Unmanaged) > 0 {
+		fmt.Fprintf(w, "  unmanaged (kept, use -prune to delete): %s\n", strings.Join(menus.Unmanaged, ", "))
+	}
+	if plan.Grants == nil {
+		fmt.Fprintln(w, "grants: not compared (offline)")
+		return
+	}
+	fmt.Fprintf(w, "grants: +%d -%d\n", len(plan.Grants.Grant), len(plan.Grants.Revoke))
+	for _, grant := range plan.Grants.Grant {
+		fmt.Fprintf(w, "  + %s %s\n", grant.Menu, grant.Policy)
+	}
+	for _, grant := range plan.Grants.Revoke {
+		fmt.Fprintf(w, "  - %s %s\n", grant.Menu, grant.Policy)
+	}
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// confirm 표준 입력으로 y/n 확인
+func confirm(question string) bool {
+	fmt.Printf("%s (y/n): ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -10,10 +10,11 @@
 // @securityDefinitions.apikey BearerAuth
 // @in              header
 // @name            Authorization
-// @description     JWT Bearer token (Authorization header or AccessToken cookie)
+// @description     JWT Bearer token (Authorization header, or HttpOnly Authorization cookie set on login)
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"mc_web_console_api/internal/config"
@@ -24,21 +25,34 @@ import (
 	"mc_web_console_api/internal/service"
 	"mc_web_console_api/pkg/errors"
 	"mc_web_console_api/pkg/jwt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 func main() {
+	// 관리 명령 (예: api asset-sync plan api). 서버를 띄우지 않고 실행 후 종료
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	// 종료 시그널(SIGINT/SIGTERM) 수신 시 취소되는 루트 컨텍스트 (graceful shutdown)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// 설정 로드
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// RegistryCache 초기화 (TTL 60초 Passive 캐시)
-	cfg.RegistryCache = service.NewRegistryCache(60 * time.Second)
+	// RegistryCache 초기화 (MC_WEB_CONSOLE_REGISTRY_TTL, 기본 60초). 보관소/백그라운드 갱신은 DB 초기화 후 연결
+	registryCache := service.NewRegistryCache(cfg.Registry.TTL)
+	cfg.RegistryCache = registryCache
 
 	// JWT 시크릿 키 설정 (환경 변수에서 로드, 없으면 기본값)
 	jwtSecret := os.Getenv("MC_WEB_CONSOLE_JWT_SECRET")
@@ -48,6 +62,11 @@ func main() {
 	}
 	jwt.SetSecretKey(jwtSecret)
 
+	// 세션 검증 캐시 TTL 설정 (AuthMiddleware의 DB 조회 앞단 캐시)
+	service.SetSessionCacheTTL(cfg.Session.CacheTTL)
+	service.SetScopeCacheTTL(cfg.Scope.CacheTTL)
+	service.SetTicketCacheTTL(cfg.MCIAM.TicketCacheTTL)
+
 	// 데이터베이스 초기화 (MC_WEB_CONSOLE_POSTGRES_HOST 환경변수가 설정된 경우에만 활성화)
 	if os.Getenv("MC_WEB_CONSOLE_POSTGRES_HOST") != "" {
 		if err := repository.InitDatabase(cfg); err != nil {
@@ -59,13 +78,103 @@ func main() {
 		if err := repository.AutoMigrate(); err != nil {
 			log.Fatalf("Failed to migrate database: %v", err)
 		}
+
+		// 로컬 인증 모드: selfiamauthsetting.yaml의 bootstrap 관리자 계정 생성
+		if !cfg.MCIAM.Use {
+			userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+			if err := userService.EnsureBootstrapUser(cfg.SelfIAM); err != nil {
+				log.Fatalf("Failed to seed bootstrap user: %v", err)
+			}
+		}
+
+		// 만료/폐기 세션 정리 작업 (advisory lock으로 replica 중 하나만 실행)
+		if cfg.Session.JanitorInterval > 0 {
+			janitor := service.NewSessionJanitor(repository.GetDB(), cfg.Session.JanitorInterval)
+			janitor.Start(ctx)
+			defer janitor.Stop()
+		}
 	} else {
 		log.Println("⚠️  MC_WEB_CONSOLE_POSTGRES_HOST not configured, running without database (session management disabled)")
 	}
+	// replica 간 캐시 무효화 버스 (DB가 있으면 Postgres LISTEN/NOTIFY)
+	invalidationBus := service.InitInvalidationBus(cfg.Invalidation, cfg.GetDatabaseDSN(), repository.GetDB())
+	registryCache.SetInvalidationBus(invalidationBus)
+	invalidationBus.Start(ctx)
+	defer invalidationBus.Stop()
+
+	// 레지스트리 캐시: 마지막 정상 레지스트리 복원 + 서비스 계정 백그라운드 갱신 (stale-while-revalidate)
+	if cfg.MCIAM.Use {
+		registryCache.SetSnapshotStore(service.NewRegistrySnapshotStore(cfg.Registry.Persist, repository.GetDB()))
+		if err := registryCache.Restore(); err != nil {
+			log.Printf("⚠️  failed to restore registry snapshot: %v", err)
+		}
+		if refresher := service.InitRegistryRefresher(cfg, registryCache); refresher != nil {
+			registryCache.SetRevalidator(refresher.Trigger, cfg.Registry.MaxStale)
+			refresher.Start(ctx)
+			defer refresher.Stop()
+		}
+		service.RegisterStatusProvider("registry", func() interface{} { return registryCache.Status() })
+	}
+	// 인증 이벤트 webhook (DB가 있으면 재시도 대기열/dead-letter를 replica 간 공유)
+	if dispatcher := service.InitWebhookDispatcher(cfg.Webhook, repository.GetDB()); dispatcher != nil {
+		dispatcher.Start(ctx)
+		defer dispatcher.Stop()
+	}
+	// 백엔드 서비스 상태 점검 (/readyz, 관리자 상태 화면)
+	if monitor := service.InitHealthMonitor(cfg); monitor != nil {
+		monitor.Start(ctx)
+		defer monitor.Stop()
+	}
+	// 원격 기준 파일(메뉴/API yaml, 권한 CSV) 점검과 api.yaml/메뉴 yaml 동기화 (반영 시 ApiSpec hot reload)
+	remoteAssets := service.InitRemoteAssetChecker(cfg.RemoteAssets)
+	assetSyncer := service.InitAssetSyncer(cfg.RemoteAssets, remoteAssets)
+	assetSyncer.OnApply(service.AssetSyncApi, func(data []byte) error {
+		spec, err := config.ParseApiSpec(data)
+		if err != nil {
+			return err
+		}
+		cfg.ApiSpec.Replace(spec)
+		return nil
+	})
+	assetSyncer.SetInvalidationBus(invalidationBus)
+	// 메뉴/메뉴 권한 선언 파일 → mc-iam-manager 반영 (api menu sync, /api/admin/menu-sync)
+	service.InitMenuSyncer(cfg)
+	// 로그인 실패 제한 (DB가 있으면 login_attempts로 replica 간 공유)
+	service.InitLoginThrottle(cfg.LoginThrottle, repository.GetDB())
+
+	// 비밀번호 정책 (길이/문자 종류/유출 목록)과 회원가입 이메일 도메인 제한
+	service.InitPasswordPolicy(cfg.Signup)
+	if cfg.Signup.ApprovalRequired && cfg.MCIAM.Use && cfg.Signup.EncryptionKey == "" {
+		log.Println("⚠️  MC_WEB_CONSOLE_SIGNUP_APPROVAL_REQUIRED=true but MC_WEB_CONSOLE_SIGNUP_ENCRYPTION_KEY is not set, sign-up requests will be rejected")
+	}
+
+	// BFF 인가: 역할 → 정책 → webconsole_api_permissions.csv. MCIAM 토큰은 /api/auth/certs 공개키로 검증
+	service.InitAuthorizer(cfg.ApiPermissions, cfg.Authz)
+	if cfg.MCIAM.Use {
+		service.InitMCIAMKeySet(cfg)
+	}
+	if !cfg.Authz.Enabled {
+		log.Printf("⚠️  MC_WEB_CONSOLE_AUTHZ=false: role policies are not enforced (authentication and token scopes still apply), admin routes require roles %v", cfg.Authz.AdminRoles)
+	}
+	if len(cfg.MFA.RequiredRoles) > 0 && (repository.GetDB() == nil || cfg.MFA.EncryptionKey == "") {
+		log.Printf("⚠️  MFA required for roles %v but database or encryption key is missing, MFA disabled", cfg.MFA.RequiredRoles)
+	}
 
 	// Echo 인스턴스 생성
 	e := echo.New()
 
+	// 클라이언트 IP (로그인 실패 제한 IP 키, 감사 로그): MC_WEB_CONSOLE_TRUSTED_PROXIES에 지정된 프록시(front 서버 등)가
+	// 추가한 X-Forwarded-For만 신뢰한다. 지정하지 않으면 X-Forwarded-For를 무시하고 접속 IP를 사용한다.
+	if len(cfg.TrustedProxies) > 0 {
+		trust := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+		for _, network := range cfg.TrustedProxies {
+			trust = append(trust, echo.TrustIPRange(network))
+		}
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(trust...)
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
 	// 커스텀 에러 핸들러 설정
 	e.HTTPErrorHandler = errors.CustomErrorHandler
 
@@ -84,6 +193,9 @@ func main() {
 		}
 	})
 
+	// 쿠키 인증 요청의 상태 변경 메서드는 CSRF 토큰(double-submit) 또는 동일 출처 확인 필요
+	e.Use(middleware.CSRFMiddleware(cfg.Cookie))
+
 	// Health check 엔드포인트
 	// @Summary     Health check
 	// @Description Server readiness probe
@@ -91,13 +203,28 @@ func main() {
 	// @Produce     json
 	// @Success     200 {object} map[string]interface{}
 	// @Router      /readyz [get]
+	// 백엔드 점검(HealthMonitor)이 켜져 있으면 전체 상태(DEGRADED/UNAVAILABLE)만 보고하고,
+	// MC_WEB_CONSOLE_HEALTH_REQUIRED 서비스가 down이면 503을 반환한다.
+	// 인증 없는 엔드포인트이므로 서비스별 상세(주소, 인증서 등)는 /api/admin/health에서만 제공한다.
 	e.GET("/readyz", func(c echo.Context) error {
-		return c.JSON(200, map[string]interface{}{
+		result := map[string]interface{}{
 			"status":      "OK",
 			"framework":   "Echo v4",
 			"mciam_use":   cfg.MCIAM.Use,
 			"environment": cfg.Server.Env,
-		})
+		}
+		code := http.StatusOK
+		if monitor := service.GetHealthMonitor(); monitor != nil {
+			summary := monitor.Summary()
+			if summary.Status == service.HealthStatusDegraded || summary.Status == service.HealthStatusDown {
+				result["status"] = "DEGRADED"
+			}
+			if len(summary.RequiredDown) > 0 {
+				result["status"] = "UNAVAILABLE"
+				code = http.StatusServiceUnavailable
+			}
+		}
+		return c.JSON(code, result)
 	})
 
 	// API 그룹
@@ -108,28 +235,83 @@ func main() {
 	auth.POST("/login", handler.Login)
 	auth.POST("/refresh", handler.Refresh)
 	auth.POST("/signup", handler.Signup)
+	auth.POST("/verify-email", handler.VerifyEmail)
+	auth.POST("/password/reset-request", handler.RequestPasswordReset)
+	auth.POST("/password/reset", handler.ResetPassword)
+	auth.POST("/login/mfa", handler.LoginMFA)
+	auth.POST("/login/mfa/enroll", handler.LoginMFAEnroll)
 
 	// 보호된 인증 라우트 (인증 필요)
 	authProtected := api.Group("/auth")
 	authProtected.Use(middleware.AuthMiddleware)
 	authProtected.POST("/validate", handler.Validate)
-	authProtected.POST("/logout", handler.Logout)
+	authProtected.POST("/logout", handler.Logout, middleware.RequireDirectLogin)
 	authProtected.GET("/userinfo", handler.UserInfo)
+	authProtected.GET("/menus", handler.GetUserMenus)
+	authProtected.POST("/impersonation/stop", handler.StopImpersonation)
+	// 계정 자격 증명 관리는 본인 세션 로그인으로만 허용 (액세스 토큰·대리 토큰 거부)
+	authProtected.POST("/password/change", handler.ChangePassword, middleware.RequireDirectLogin)
+	authProtected.GET("/mfa", handler.GetMFAStatus, middleware.RequireDirectLogin)
+	authProtected.POST("/mfa/enroll", handler.EnrollMFA, middleware.RequireDirectLogin)
+	authProtected.POST("/mfa/confirm", handler.ConfirmMFA, middleware.RequireDirectLogin)
+	authProtected.POST("/mfa/disable", handler.DisableMFA, middleware.RequireDirectLogin)
+	authProtected.GET("/tokens", handler.ListAccessTokens, middleware.RequireDirectLogin)
+	authProtected.POST("/tokens", handler.CreateAccessToken, middleware.RequireDirectLogin)
+	authProtected.DELETE("/tokens/:id", handler.RevokeAccessToken, middleware.RequireDirectLogin)
 
 	// 단일 세그먼트 내부 핸들러
 	api.POST("/disklookup", handler.DiskLookup)
 	api.POST("/getapihosts", handler.GetApiHosts)
 
 	// 관리자 전용 BFF 라우트 (와일드카드보다 먼저 등록되어야 정적 매칭됨)
-	// FR-CLOUD-ADMIN-006-08: 외부 raw YAML 도달성 확인 (CORS 우회 + 토큰 노출 방지)
-	// 인증 모델: SubsystemAnyController와 동일 — front Buffalo의 IsTokenExistMiddleware(cookie 검증)
-	// 가 1차 게이트 역할을 하므로 BFF는 별도 JWT 검증을 수행하지 않는다.
+	// 인증 후 webconsole_api_permissions.csv의 mc-web-console/admin:<path> 규칙으로 인가한다.
+	adminRoute := func(path string) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{middleware.AuthMiddleware, middleware.RequirePermission("mc-web-console", "admin:"+path)}
+	}
 	adminBFF := api.Group("/admin")
-	adminBFF.GET("/setup-yaml-check", handler.GetSetupYamlCheck)
+	// FR-CLOUD-ADMIN-006-08: 외부 raw YAML 도달성 확인 (CORS 우회 + 토큰 노출 방지)
+	adminBFF.GET("/setup-yaml-check", handler.GetSetupYamlCheck, adminRoute("setup-yaml-check")...)
+	adminBFF.GET("/remote-assets", handler.ListRemoteAssets, adminRoute("remote-assets")...)
+	adminBFF.GET("/remote-assets/:name", handler.GetRemoteAsset, adminRoute("remote-assets")...)
+	adminBFF.GET("/asset-sync/:name/plan", handler.PlanAssetSync, adminRoute("asset-sync")...)
+	adminBFF.POST("/asset-sync/:name/apply", handler.ApplyAssetSync, adminRoute("asset-sync")...)
+	adminBFF.GET("/asset-sync/:name/backups", handler.ListAssetBackups, adminRoute("asset-sync")...)
+	adminBFF.POST("/asset-sync/:name/rollback", handler.RollbackAssetSync, adminRoute("asset-sync")...)
+	adminBFF.POST("/asset-sync/:name/reload", handler.ReloadAsset, adminRoute("asset-sync")...)
+	adminBFF.GET("/menu-sync/plan", handler.PlanMenuSync, adminRoute("menu-sync")...)
+	adminBFF.POST("/menu-sync/apply", handler.ApplyMenuSync, adminRoute("menu-sync")...)
+	adminBFF.GET("/status", handler.GetAdminStatus, adminRoute("status")...)
+	adminBFF.GET("/login-locks", handler.GetLoginLocks, adminRoute("login-locks")...)
+	adminBFF.POST("/login-locks/unlock", handler.UnlockLogin, adminRoute("login-locks")...)
+	adminBFF.GET("/authz/explain", handler.ExplainAuthz, adminRoute("authz-explain")...)
+	adminBFF.POST("/impersonate", handler.Impersonate, append(adminRoute("impersonate"), middleware.RequireDirectLogin)...)
+	adminBFF.GET("/service-accounts", handler.ListServiceAccounts, adminRoute("service-accounts")...)
+	adminBFF.POST("/service-accounts", handler.CreateServiceAccount, adminRoute("service-accounts")...)
+	adminBFF.POST("/service-accounts/:id/state", handler.SetServiceAccountState, adminRoute("service-accounts")...)
+	adminBFF.GET("/service-accounts/:id/tokens", handler.ListServiceAccountTokens, adminRoute("service-accounts")...)
+	adminBFF.POST("/service-accounts/:id/tokens", handler.CreateServiceAccountToken, adminRoute("service-accounts")...)
+	adminBFF.DELETE("/service-accounts/:id/tokens/:tokenId", handler.RevokeServiceAccountToken, adminRoute("service-accounts")...)
+	// 회원가입 승인 대기열 (approvals 메뉴)과 초대 코드
+	adminBFF.GET("/signups", handler.ListSignups, adminRoute("approvals")...)
+	adminBFF.POST("/signups/:id/approve", handler.ApproveSignup, adminRoute("approvals")...)
+	adminBFF.POST("/signups/:id/reject", handler.RejectSignup, adminRoute("approvals")...)
+	adminBFF.GET("/invitations", handler.ListInvitations, adminRoute("invitations")...)
+	adminBFF.POST("/invitations", handler.CreateInvitation, adminRoute("invitations")...)
+	adminBFF.DELETE("/invitations/:id", handler.RevokeInvitation, adminRoute("invitations")...)
+	adminBFF.GET("/webhooks/dead-letters", handler.ListWebhookDeadLetters, adminRoute("webhooks")...)
+	adminBFF.POST("/webhooks/dead-letters/:id/retry", handler.RetryWebhookDeadLetter, adminRoute("webhooks")...)
+	adminBFF.GET("/health", handler.GetBackendHealth, adminRoute("health")...)
+	adminBFF.POST("/health/check", handler.CheckBackendHealth, adminRoute("health")...)
+	// mc-iam-manager 레지스트리 캐시 조회/비교/갱신
+	adminBFF.GET("/registry", handler.GetRegistry, adminRoute("registry")...)
+	adminBFF.GET("/registry/diff", handler.GetRegistryDiff, adminRoute("registry")...)
+	adminBFF.POST("/registry/refresh", handler.RefreshRegistry, adminRoute("registry")...)
+	adminBFF.POST("/registry/invalidate", handler.InvalidateRegistry, adminRoute("registry")...)
 
 	// 서브시스템 프록시 라우트 (Buffalo SubsystemAnyController 호환)
 	// POST /api/:subsystemName/:operationId → conf/api.yaml 기반으로 백엔드 서비스에 프록시
-	api.Any("/:subsystemName/:operationId", handler.SubsystemAnyController)
+	// 토큰 검증 후 핸들러에서 액션 메서드 기준으로 정책을 평가한다 (인가 비활성 시에는 토큰 스코프/대리 읽기 전용 제한만 적용).
+	api.Any("/:subsystemName/:operationId", handler.SubsystemAnyController, middleware.AuthMiddleware)
 
 	// 테스트 엔드포인트들
 	// @Summary     Hello
@@ -173,16 +355,27 @@ func main() {
 
 	// 서버 시작
 	address := cfg.GetServerAddress()
+	apiServices, _ := cfg.ApiSpec.Snapshot()
 	fmt.Printf("\n")
 	fmt.Printf("🚀 Echo server starting on %s\n", address)
 	fmt.Printf("📝 Environment: %s\n", cfg.Server.Env)
 	fmt.Printf("🔐 MCIAM Use: %v\n", cfg.MCIAM.Use)
-	fmt.Printf("✅ API Spec loaded: %d services\n", len(cfg.ApiSpec.Services))
+	fmt.Printf("✅ API Spec loaded: %d services\n", len(apiServices))
 	fmt.Printf("🎯 Authentication System: DB=%v, MCIAM=%v\n", repository.GetDB() != nil, cfg.MCIAM.Use)
 	fmt.Printf("🔐 JWT Secret: configured\n")
 	fmt.Printf("\n")
 
-	if err := e.Start(address); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		if err := e.Start(address); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
 	}
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ApiPermissions conf/webconsole_api_permissions.csv 정책 테이블.
+//
+// 형식은 webconsole_menu_permissions.csv와 같다: 앞의 세 컬럼(framework, operation, methods) 뒤에
+// 정책 컬럼(adminPolicy, operatorPolicy, viewerPolicy, ...)이 오고, 허용이면 TRUE를 적는다.
+// framework/operation은 대소문자 무시 glob 패턴(*, ?)이며, methods는 "|" 구분 HTTP 메서드 목록 또는 *이다.
+// 규칙은 위에서부터 평가되어 처음 일치한 행이 결정을 내린다 (일치하는 행이 없으면 거부).
+type ApiPermissions struct {
+	Source   string
+	Policies []string
+	Rules    []PermissionRule
+}
+
+// PermissionRule 정책 테이블의 한 행
+type PermissionRule struct {
+	Line      int             `json:"line"` // CSV 행 번호 (헤더 = 1)
+	Framework string          `json:"framework"`
+	Operation string          `json:"operation"`
+	Methods   []string        `json:"methods"`
+	Allowed   map[string]bool `json:"-"` // 정책명 → 허용 여부
+}
+
+// LoadApiPermissions 정책 CSV 파일 로드
+func LoadApiPermissions(filePath string) (*ApiPermissions, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open api permissions: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("api permissions file is empty: %s", filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse api permissions: %w", err)
+	}
+	if len(header) < 4 || !strings.EqualFold(header[0], "framework") ||
+		!strings.EqualFold(header[1], "operation") || !strings.EqualFold(header[2], "methods") {
+		return nil, fmt.Errorf("api permissions header must start with framework,operation,methods and at least one policy column")
+	}
+
+	perms := &ApiPermissions{Source: filePath, Policies: header[3:]}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse api permissions: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			return nil, fmt.Errorf("api permissions line %d: expected %d columns, got %d", line, len(header), len(record))
+		}
+
+		rule := PermissionRule{
+			Line:      line,
+			Framework: strings.ToLower(strings.TrimSpace(record[0])),
+			Operation: strings.ToLower(strings.TrimSpace(record[1])),
+			Allowed:   make(map[string]bool, len(perms.Policies)),
+		}
+		for _, pattern := range []string{rule.Framework, rule.Operation} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("api permissions line %d: invalid pattern %q", line, pattern)
+			}
+		}
+		for _, m := range strings.Split(record[2], "|") {
+			if m = strings.ToUpper(strings.TrimSpace(m)); m != "" {
+				rule.Methods = append(rule.Methods, m)
+			}
+		}
+		for j, policy := range perms.Policies {
+			rule.Allowed[policy] = strings.EqualFold(strings.TrimSpace(record[3+j]), "TRUE")
+		}
+		perms.Rules = append(perms.Rules, rule)
+	}
+	return perms, nil
+}
+
+// Match 요청(framework, operation, method)에 처음 일치하는 규칙 반환 (없으면 nil)
+func (p *ApiPermissions) Match(framework, operation, method string) *PermissionRule {
+	framework = strings.ToLower(framework)
+	operation = strings.ToLower(operation)
+	method = strings.ToUpper(method)
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if ok, _ := path.Match(rule.Framework, framework); !ok {
+			continue
+		}
+		if ok, _ := path.Match(rule.Operation, operation); !ok {
+			continue
+		}
+		if !rule.matchMethod(method) {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+func (r *PermissionRule) matchMethod(method string) bool {
+	for _, m := range r.Methods {
+		if m == "*" || m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package config
+
+import "testing"
+
+func TestWebconsoleApiPermissionsIAMReads(t *testing.T) {
+	perms, err := LoadApiPermissions("../../../conf/webconsole_api_permissions.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		operation, method, policy string
+		want                      bool
+	}{
+		// 공통 조회
+		{"GetAllAvailableMenus", "POST", "viewerPolicy", true},
+		{"listUserWorkspaces", "POST", "billviewerPolicy", true},
+		{"listWorkspaceProjects", "POST", "viewerPolicy", true},
+		{"Getprojectbyid", "GET", "billadminPolicy", true},
+		// 자격 증명·권한 원본은 관리자만
+		{"getTempCredentialProviders", "GET", "operatorPolicy", false},
+		{"getTempCredentialProviders", "GET", "adminPolicy", true},
+		{"listCspAccounts", "POST", "operatorPolicy", false},
+		{"Getcurrentpermissioncsv", "GET", "viewerPolicy", false},
+		// 사용자/역할 조회는 관리자·운영자
+		{"Listusers", "POST", "viewerPolicy", false},
+		{"Listusers", "POST", "operatorPolicy", true},
+		{"Getrolelist", "GET", "billviewerPolicy", false},
+		{"Getorganizations", "GET", "operatorPolicy", true},
+		// 변경은 관리자만
+		{"Updateuser", "PUT", "operatorPolicy", false},
+		{"Updateuser", "PUT", "adminPolicy", true},
+	}
+	for _, tt := range tests {
+		rule := perms.Match("mc-iam-manager", tt.operation, tt.method)
+		if rule == nil {
+			t.Errorf("%s %s: no rule", tt.method, tt.operation)
+			continue
+		}
+		if got := rule.Allowed[tt.policy]; got != tt.want {
+			t.Errorf("%s %s for %s = %v (line %d), want %v", tt.method, tt.operation, tt.policy, got, rule.Line, tt.want)
+		}
+	}
+}
@@ -1,16 +1,22 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 )
 
-// ApiSpec API 명세 전체 구조
+// ApiSpec API 명세 전체 구조.
+// 실행 중 교체(Replace)될 수 있으므로 필드를 직접 순회하지 말고 Snapshot/Get* 메서드를 사용한다.
 type ApiSpec struct {
-	Services       map[string]Service                `mapstructure:"services"`
+	Services       map[string]Service               `mapstructure:"services"`
 	ServiceActions map[string]map[string]ActionSpec `mapstructure:"serviceActions"`
+
+	mu sync.RWMutex
 }
 
 // Service 백엔드 서비스 정보
@@ -37,11 +43,19 @@ type ActionSpec struct {
 
 // LoadApiSpec conf/api.yaml 파일 로드
 func LoadApiSpec(path string) (*ApiSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API spec file: %w", err)
+	}
+	return ParseApiSpec(data)
+}
+
+// ParseApiSpec api.yaml 내용 파싱 (키는 viper 규칙대로 소문자로 정규화됨)
+func ParseApiSpec(data []byte) (*ApiSpec, error) {
 	v := viper.New()
-	v.SetConfigFile(path)
 	v.SetConfigType("yaml")
 
-	if err := v.ReadInConfig(); err != nil {
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
 		return nil, fmt.Errorf("failed to read API spec file: %w", err)
 	}
 
@@ -53,10 +67,27 @@ func LoadApiSpec(path string) (*ApiSpec, error) {
 	return &apiSpec, nil
 }
 
+// Replace 다른 ApiSpec 내용으로 교체 (api.yaml hot reload). 진행 중인 요청은 이전 맵을 계속 사용한다.
+func (a *ApiSpec) Replace(next *ApiSpec) {
+	services, actions := next.Snapshot()
+	a.mu.Lock()
+	a.Services = services
+	a.ServiceActions = actions
+	a.mu.Unlock()
+}
+
+// Snapshot 현재 서비스/액션 맵 반환. 맵은 교체만 되고 수정되지 않으므로 읽기 전용으로 사용한다.
+func (a *ApiSpec) Snapshot() (map[string]Service, map[string]map[string]ActionSpec) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Services, a.ServiceActions
+}
+
 // GetService subsystem 서비스 정보만 조회 (action 불필요 시 사용)
 func (a *ApiSpec) GetService(subsystem string) (*Service, error) {
+	services, _ := a.Snapshot()
 	subsystemLower := strings.ToLower(subsystem)
-	for key, svc := range a.Services {
+	for key, svc := range services {
 		if strings.ToLower(key) == subsystemLower {
 			s := svc
 			return &s, nil
@@ -67,6 +98,7 @@ func (a *ApiSpec) GetService(subsystem string) (*Service, error) {
 
 // GetAction subsystem과 operationId로 액션 조회
 func (a *ApiSpec) GetAction(subsystem, operationId string) (*Service, *ActionSpec, error) {
+	services, serviceActions := a.Snapshot()
 	// 소문자로 변환하여 매칭 (Buffalo 호환)
 	subsystemLower := strings.ToLower(subsystem)
 	operationIdLower := strings.ToLower(operationId)
@@ -74,7 +106,7 @@ func (a *ApiSpec) GetAction(subsystem, operationId string) (*Service, *ActionSpe
 	// 서비스 정보 조회
 	var service *Service
 	var serviceKey string
-	for key, svc := range a.Services {
+	for key, svc := range services {
 		if strings.ToLower(key) == subsystemLower {
 			s := svc
 			service = &s
@@ -88,7 +120,7 @@ func (a *ApiSpec) GetAction(subsystem, operationId string) (*Service, *ActionSpe
 	}
 
 	// 액션 조회
-	actions, exists := a.ServiceActions[serviceKey]
+	actions, exists := serviceActions[serviceKey]
 	if !exists {
 		return nil, nil, fmt.Errorf("no actions found for service: %s", serviceKey)
 	}
@@ -111,9 +143,10 @@ func (a *ApiSpec) GetAction(subsystem, operationId string) (*Service, *ActionSpe
 
 // GetServiceBaseURL 서비스 BaseURL 조회
 func (a *ApiSpec) GetServiceBaseURL(subsystem string) (string, error) {
+	services, _ := a.Snapshot()
 	subsystemLower := strings.ToLower(subsystem)
 
-	for key, svc := range a.Services {
+	for key, svc := range services {
 		if strings.ToLower(key) == subsystemLower {
 			return svc.BaseURL, nil
 		}
@@ -121,3 +154,46 @@ func (a *ApiSpec) GetServiceBaseURL(subsystem string) (string, error) {
 
 	return "", fmt.Errorf("service not found: %s", subsystem)
 }
+
+// RequestCoerceTypes requestCoerce에 허용되는 변환 타입
+var RequestCoerceTypes = map[string]bool{"int": true, "float": true, "bool": true}
+
+// MergeActionSpec 레지스트리 ActionSpec과 api.yaml ActionSpec을 필드 단위로 병합한다.
+//
+// 우선순위:
+//   - Method, ResourcePath, Description: 레지스트리 값이 있으면 레지스트리, 없으면 api.yaml
+//   - RequestCoerce: 두 목록의 합집합, 같은 필드는 api.yaml 우선 (BFF 요청 변환 규칙은 로컬 설정이 기준)
+//
+// 둘 중 하나가 nil이면 다른 쪽 사본을 반환한다.
+func MergeActionSpec(static, registry *ActionSpec) *ActionSpec {
+	if registry == nil {
+		if static == nil {
+			return nil
+		}
+		s := *static
+		return &s
+	}
+	merged := *registry
+	if static == nil {
+		return &merged
+	}
+	if merged.Method == "" {
+		merged.Method = static.Method
+	}
+	if merged.ResourcePath == "" {
+		merged.ResourcePath = static.ResourcePath
+	}
+	if merged.Description == "" {
+		merged.Description = static.Description
+	}
+	if len(static.RequestCoerce) > 0 || len(registry.RequestCoerce) > 0 {
+		merged.RequestCoerce = make(map[string]string, len(static.RequestCoerce)+len(registry.RequestCoerce))
+		for field, typ := range registry.RequestCoerce {
+			merged.RequestCoerce[field] = typ
+		}
+		for field, typ := range static.RequestCoerce {
+			merged.RequestCoerce[field] = typ
+		}
+	}
+	return &merged
+}
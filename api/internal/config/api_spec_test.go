@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeActionSpec(t *testing.T) {
+	static := &ActionSpec{
+		Method:        "post",
+		ResourcePath:  "/ns/{nsId}/mci",
+		Description:   "api.yaml",
+		RequestCoerce: map[string]string{"count": "int", "enabled": "bool"},
+	}
+	tests := []struct {
+		name     string
+		static   *ActionSpec
+		registry *ActionSpec
+		want     *ActionSpec
+	}{
+		{
+			name:   "registry wins method, path and description; api.yaml wins requestCoerce",
+			static: static,
+			registry: &ActionSpec{
+				Method:        "put",
+				ResourcePath:  "/v2/ns/{nsId}/mci",
+				Description:   "registry",
+				RequestCoerce: map[string]string{"count": "float", "ratio": "float"},
+			},
+			want: &ActionSpec{
+				Method:        "put",
+				ResourcePath:  "/v2/ns/{nsId}/mci",
+				Description:   "registry",
+				RequestCoerce: map[string]string{"count": "int", "enabled": "bool", "ratio": "float"},
+			},
+		},
+		{
+			name:     "empty registry fields fall back to api.yaml",
+			static:   static,
+			registry: &ActionSpec{ResourcePath: "/v2/ns/{nsId}/mci"},
+			want: &ActionSpec{
+				Method:        "post",
+				ResourcePath:  "/v2/ns/{nsId}/mci",
+				Description:   "api.yaml",
+				RequestCoerce: map[string]string{"count": "int", "enabled": "bool"},
+			},
+		},
+		{
+			name:     "registry only",
+			registry: &ActionSpec{Method: "get", ResourcePath: "/ns"},
+			want:     &ActionSpec{Method: "get", ResourcePath: "/ns"},
+		},
+		{
+			name:   "api.yaml only",
+			static: static,
+			want:   static,
+		},
+		{name: "neither"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeActionSpec(tt.static, tt.registry)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("MergeActionSpec = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && (got == tt.static || got == tt.registry) {
+				t.Fatal("MergeActionSpec returned an input instead of a copy")
+			}
+		})
+	}
+	if static.Method != "post" || len(static.RequestCoerce) != 2 {
+		t.Fatalf("api.yaml spec modified: %+v", static)
+	}
+}
@@ -1,8 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,11 +20,60 @@ import (
 type Config struct {
 	Server             ServerConfig
 	Database           DatabaseConfig
+	Session            SessionConfig
 	MCIAM              MCIAMConfig
 	ApiSpec            *ApiSpec
 	RegistryCache      RegistryCacheInterface
 	SetupYaml          SetupYamlConfig
-	IframeTargetIsHost bool // IFRAME_TARGET_IS_HOST 환경변수
+	RemoteAssets       RemoteAssetsConfig
+	MenuSync           MenuSyncConfig
+	LocalAuth          LocalAuthConfig
+	MFA                MFAConfig
+	LoginThrottle      LoginThrottleConfig
+	Authz              AuthzConfig
+	Scope              ScopeConfig
+	AccessToken        AccessTokenConfig
+	Impersonation      ImpersonationConfig
+	Cookie             CookieConfig
+	Signup             SignupConfig
+	Webhook            WebhookConfig
+	Registry           RegistryConfig
+	Invalidation       InvalidationConfig
+	Health             HealthConfig
+	ApiPermissions     *ApiPermissions // conf/webconsole_api_permissions.csv (Authz 비활성 시 nil)
+	SelfIAM            *SelfIAMSetting // conf/selfiamauthsetting.yaml (없으면 nil)
+	IframeTargetIsHost bool            // IFRAME_TARGET_IS_HOST 환경변수
+	// TrustedProxies X-Forwarded-For를 신뢰할 프록시 대역 (MC_WEB_CONSOLE_TRUSTED_PROXIES, CIDR 또는 IP).
+	// 비어 있으면 X-Forwarded-For를 무시하고 접속 IP를 클라이언트 IP로 쓴다 (로그인 실패 제한, 감사 로그).
+	TrustedProxies []*net.IPNet
+}
+
+// MenuSyncConfig 메뉴/메뉴 권한 선언 파일을 mc-iam-manager에 반영하는 설정 (api menu sync, /api/admin/menu-sync)
+type MenuSyncConfig struct {
+	// Framework 메뉴 권한 framework 이름 (MC_WEB_CONSOLE_MENU_FRAMEWORK)
+	Framework string
+	// MenusFile 메뉴 트리 정의 (MC_WEB_CONSOLE_MENU_RESOURCES)
+	MenusFile string
+	// PermissionsFile 역할별 메뉴 권한 CSV (MC_WEB_CONSOLE_MENU_PERMISSIONS)
+	PermissionsFile string
+	// ExportFile mc-iam-manager 메뉴 내보내기 파일. mc-iam-manager 없이 계획을 볼 때 현재 상태로 사용 (MC_WEB_CONSOLE_MENU_EXPORT)
+	ExportFile string
+	// PagesDir front 페이지 템플릿 디렉터리. menu check에서 메뉴별 페이지 존재 여부 검사에 사용 (MC_WEB_CONSOLE_MENU_PAGES)
+	PagesDir string
+	// Timeout mc-iam-manager 요청별 제한 시간 (MC_WEB_CONSOLE_MENU_SYNC_TIMEOUT)
+	Timeout time.Duration
+}
+
+// LocalAuthConfig MCIAM_USE=false 로컬 사용자 저장소 설정
+type LocalAuthConfig struct {
+	// RequireEmailVerification 이메일 인증 전 로그인 차단 (MC_WEB_CONSOLE_LOCAL_REQUIRE_EMAIL_VERIFY)
+	RequireEmailVerification bool
+	// VerifyTokenTTL 이메일 인증 토큰 유효 시간 (MC_WEB_CONSOLE_LOCAL_VERIFY_TOKEN_TTL)
+	VerifyTokenTTL time.Duration
+	// ResetTokenTTL 비밀번호 재설정 토큰 유효 시간 (MC_WEB_CONSOLE_LOCAL_RESET_TOKEN_TTL)
+	ResetTokenTTL time.Duration
+	// DefaultRole 회원가입 사용자에게 부여할 역할 (MC_WEB_CONSOLE_LOCAL_DEFAULT_ROLE)
+	DefaultRole string
 }
 
 // SetupYamlConfig FR-CLOUD-ADMIN-006-08용 raw yaml 도달성 확인 설정
@@ -26,6 +84,31 @@ type SetupYamlConfig struct {
 	McAdmincliApiYaml string
 }
 
+// RemoteAssetsConfig 원격 기준 파일(메뉴/API yaml, metainfo, 권한 CSV)과 로컬 conf/ 사본 비교 설정
+type RemoteAssetsConfig struct {
+	// Assets 점검 대상 목록. menu/api URL은 SetupYaml 설정을 사용하고
+	// MC_WEB_CONSOLE_REMOTE_ASSETS="name|url|localPath,..."로 추가/덮어쓰기 할 수 있다.
+	Assets []RemoteAsset
+	// Timeout 원격 요청 제한 시간 (MC_WEB_CONSOLE_REMOTE_ASSET_TIMEOUT)
+	Timeout time.Duration
+	// GithubToken raw.githubusercontent.com 자산의 이력 조회(GraphQL)용 GitHub 토큰, 미설정 시 최신 커밋만 조회 (MC_WEB_CONSOLE_REMOTE_ASSET_GITHUB_TOKEN)
+	GithubToken string
+	// HistoryDepth 로컬 사본을 찾기 위해 거슬러 올라가는 최대 커밋 수, 최대 100 (MC_WEB_CONSOLE_REMOTE_ASSET_HISTORY_DEPTH)
+	HistoryDepth int
+	// BackupDir 동기화/롤백 전 로컬 사본 백업 위치 (MC_WEB_CONSOLE_ASSET_BACKUP_DIR)
+	BackupDir string
+	// BackupKeep 자산별 보관하는 백업 수 (MC_WEB_CONSOLE_ASSET_BACKUP_KEEP)
+	BackupKeep int
+}
+
+// RemoteAsset 원격 기준 파일 하나
+type RemoteAsset struct {
+	Name      string // 조회 키 (menu, api, metainfo, menu-permissions, api-permissions, ...)
+	URL       string // raw URL, 비어 있으면 미설정
+	LocalPath string // 비교 대상 로컬 사본
+	Format    string // yaml | csv (LocalPath 확장자로 판단)
+}
+
 // RegistryCacheInterface proxy.go가 의존하는 캐시 인터페이스 (순환 import 방지).
 // BaseURL + ActionSpec(path/method) 모두 캐시 우선, 없으면 api.yaml fallback.
 type RegistryCacheInterface interface {
@@ -56,15 +139,255 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// SessionConfig 세션 검증 설정
+type SessionConfig struct {
+	// CacheTTL AuthMiddleware 세션 검증 결과 in-memory 캐시 유효 시간 (MC_WEB_CONSOLE_SESSION_CACHE_TTL)
+	CacheTTL time.Duration
+	// JanitorInterval 만료/폐기 세션 정리 주기 (MC_WEB_CONSOLE_SESSION_JANITOR_INTERVAL, 0이면 비활성)
+	JanitorInterval time.Duration
+}
+
+// MFAConfig TOTP 2단계 인증 설정
+type MFAConfig struct {
+	// Issuer 인증 앱에 표시될 발급자 이름 (MC_WEB_CONSOLE_MFA_ISSUER)
+	Issuer string
+	// RequiredRoles MFA가 필수인 역할 목록 (MC_WEB_CONSOLE_MFA_REQUIRED_ROLES, 콤마 구분. 예: platformAdmin)
+	RequiredRoles []string
+	// EncryptionKey MFA 시크릿 DB 암호화 키 (MC_WEB_CONSOLE_MFA_ENCRYPTION_KEY). 미설정이면 MFA를 사용하지 않으며 RequiredRoles가 있으면 필수
+	EncryptionKey string
+	// ChallengeTTL 1차 인증 후 2차 코드 입력 대기 시간 (MC_WEB_CONSOLE_MFA_CHALLENGE_TTL)
+	ChallengeTTL time.Duration
+}
+
+// IsRequiredForRole 해당 역할에 MFA가 필수인지 확인 (대소문자 무시)
+func (m MFAConfig) IsRequiredForRole(roles ...string) bool {
+	for _, required := range m.RequiredRoles {
+		for _, role := range roles {
+			if strings.EqualFold(required, role) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoginThrottleConfig 로그인 실패 누적에 따른 지연/잠금 설정
+type LoginThrottleConfig struct {
+	// Enabled 로그인 실패 제한 사용 여부 (MC_WEB_CONSOLE_LOGIN_THROTTLE)
+	Enabled bool
+	// MaxAccountFailures 계정 잠금까지 허용되는 연속 실패 횟수 (MC_WEB_CONSOLE_LOGIN_MAX_ACCOUNT_FAILURES)
+	MaxAccountFailures int
+	// MaxIPFailures IP 잠금까지 허용되는 실패 횟수 (MC_WEB_CONSOLE_LOGIN_MAX_IP_FAILURES)
+	MaxIPFailures int
+	// FreeAttempts 지연 없이 허용되는 실패 횟수 (MC_WEB_CONSOLE_LOGIN_FREE_ATTEMPTS)
+	FreeAttempts int
+	// BaseDelay 지연 시작 값. 이후 실패마다 2배씩 증가 (MC_WEB_CONSOLE_LOGIN_BASE_DELAY)
+	BaseDelay time.Duration
+	// MaxDelay 지연 상한 (MC_WEB_CONSOLE_LOGIN_MAX_DELAY)
+	MaxDelay time.Duration
+	// LockoutDuration 잠금 유지 시간 (MC_WEB_CONSOLE_LOGIN_LOCKOUT_DURATION)
+	LockoutDuration time.Duration
+	// FailureWindow 마지막 실패 후 이 시간이 지나면 카운터 초기화 (MC_WEB_CONSOLE_LOGIN_FAILURE_WINDOW)
+	FailureWindow time.Duration
+}
+
+// AuthzConfig BFF 라우트 역할/정책 기반 인가 설정
+type AuthzConfig struct {
+	// Enabled 인가 적용 여부 (MC_WEB_CONSOLE_AUTHZ). 비활성 시에도 인증은 필요하며 역할 정책 검사만 생략한다.
+	Enabled bool
+	// PermissionsFile 정책 CSV 경로 (MC_WEB_CONSOLE_API_PERMISSIONS)
+	PermissionsFile string
+	// RolePolicies 역할 → 정책 컬럼 매핑 (MC_WEB_CONSOLE_ROLE_POLICIES="role=policy,..."로 추가/덮어쓰기)
+	RolePolicies map[string]string
+	// AdminRoles 인가 비활성 시 mc-web-console/admin:* 라우트를 호출할 수 있는 역할 (MC_WEB_CONSOLE_AUTHZ_ADMIN_ROLES, 대소문자 무시)
+	AdminRoles []string
+}
+
+// defaultRolePolicies mc-iam-manager 기본 역할과 정책 컬럼 매핑
+var defaultRolePolicies = map[string]string{
+	"platformadmin": "adminPolicy",
+	"admin":         "adminPolicy",
+	"operator":      "operatorPolicy",
+	"viewer":        "viewerPolicy",
+	"billadmin":     "billadminPolicy",
+	"billviewer":    "billviewerPolicy",
+}
+
+// PoliciesForRoles 역할 목록에 해당하는 정책 컬럼 목록 (역할명 대소문자 무시, 중복 제거)
+func (a AuthzConfig) PoliciesForRoles(roles ...string) []string {
+	seen := make(map[string]bool)
+	var policies []string
+	for _, role := range roles {
+		policy, ok := a.RolePolicies[strings.ToLower(role)]
+		if !ok || seen[policy] {
+			continue
+		}
+		seen[policy] = true
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// ScopeConfig 프록시 호출의 workspace/project(nsId) 범위 검사 설정 (MCIAM 모드 전용)
+type ScopeConfig struct {
+	// Enabled 범위 검사 사용 여부 (MC_WEB_CONSOLE_SCOPE_CHECK)
+	Enabled bool
+	// CacheTTL 사용자별 workspace/project 멤버십 캐시 유효 시간 (MC_WEB_CONSOLE_SCOPE_CACHE_TTL)
+	CacheTTL time.Duration
+	// BypassRoles 범위 검사를 받지 않는 역할 (MC_WEB_CONSOLE_SCOPE_BYPASS_ROLES)
+	BypassRoles []string
+	// NamespaceParams nsId로 취급할 pathParams/queryParams 키 (MC_WEB_CONSOLE_SCOPE_NS_PARAMS, 대소문자 무시)
+	NamespaceParams []string
+	// ExemptSubsystems 범위 검사 대상에서 제외할 서브시스템 (MC_WEB_CONSOLE_SCOPE_EXEMPT_SUBSYSTEMS)
+	ExemptSubsystems []string
+}
+
+// AccessTokenConfig 개인 액세스 토큰/서비스 계정 토큰 설정
+type AccessTokenConfig struct {
+	// DefaultTTL 만료 미지정 시 유효 기간 (MC_WEB_CONSOLE_PAT_DEFAULT_TTL)
+	DefaultTTL time.Duration
+	// MaxTTL 발급 가능한 최대 유효 기간 (MC_WEB_CONSOLE_PAT_MAX_TTL)
+	MaxTTL time.Duration
+	// MCIAMMaxTTL MCIAM 모드 개인 토큰의 최대 유효 기간 (MC_WEB_CONSOLE_PAT_MCIAM_MAX_TTL).
+	// 소유자 역할을 발급 시점 값으로 고정하므로 mc-iam-manager의 역할 변경·계정 삭제는 이 기간 안에 반영된다.
+	MCIAMMaxTTL time.Duration
+}
+
+// ImpersonationConfig 관리자 대리(impersonation) 토큰 설정
+type ImpersonationConfig struct {
+	// Enabled 대리 토큰 발급 허용 여부 (MC_WEB_CONSOLE_IMPERSONATION)
+	Enabled bool
+	// TTL 대리 토큰 유효 기간 (MC_WEB_CONSOLE_IMPERSONATION_TTL)
+	TTL time.Duration
+	// AllowWrite 관리자가 요청 시 쓰기 메서드까지 허용할 수 있는지 여부. false면 항상 읽기 전용 (MC_WEB_CONSOLE_IMPERSONATION_ALLOW_WRITE)
+	AllowWrite bool
+	// AdminRoles 대리 토큰을 발급받을 수 있는 역할 (MC_WEB_CONSOLE_IMPERSONATION_ROLES, 대소문자 무시)
+	AdminRoles []string
+}
+
+// CookieConfig 인증 쿠키 및 CSRF 설정
+type CookieConfig struct {
+	// Secure Secure 속성 모드: auto(요청이 HTTPS일 때만) | true | false (MC_WEB_CONSOLE_COOKIE_SECURE)
+	Secure string
+	// SameSite SameSite 속성: Lax | Strict | None (MC_WEB_CONSOLE_COOKIE_SAMESITE)
+	SameSite http.SameSite
+	// Domain 쿠키 도메인, 비어 있으면 요청 호스트 (MC_WEB_CONSOLE_COOKIE_DOMAIN)
+	Domain string
+	// CSRFTrustedOrigins CSRF 토큰 없이 허용할 Origin 목록 (MC_WEB_CONSOLE_CSRF_TRUSTED_ORIGINS)
+	CSRFTrustedOrigins []string
+}
+
+// SignupConfig 비밀번호 정책 및 회원가입 절차 설정
+type SignupConfig struct {
+	// PasswordMinLength 최소 비밀번호 길이 (MC_WEB_CONSOLE_PASSWORD_MIN_LENGTH)
+	PasswordMinLength int
+	// PasswordMinClasses 소문자/대문자/숫자/특수문자 중 최소 포함 종류 수 (MC_WEB_CONSOLE_PASSWORD_MIN_CLASSES)
+	PasswordMinClasses int
+	// BreachedPasswordPath 유출 비밀번호 SHA-1 해시 목록 (MC_WEB_CONSOLE_BREACHED_PASSWORD_PATH)
+	// 디렉터리면 5자리 접두사별 범위 파일(<PREFIX>.txt, "SUFFIX:COUNT"), 파일이면 "HASH[:COUNT]" 목록. 비어 있으면 검사 안 함
+	BreachedPasswordPath string
+	// EmailDomainAllow 허용 이메일 도메인, 비어 있으면 모두 허용 (MC_WEB_CONSOLE_SIGNUP_EMAIL_DOMAINS_ALLOW)
+	EmailDomainAllow []string
+	// EmailDomainDeny 거부 이메일 도메인 (MC_WEB_CONSOLE_SIGNUP_EMAIL_DOMAINS_DENY)
+	EmailDomainDeny []string
+	// InvitationRequired 초대 코드가 있어야 회원가입 가능 (MC_WEB_CONSOLE_SIGNUP_INVITATION_REQUIRED)
+	InvitationRequired bool
+	// InvitationTTL 초대 코드 기본 유효 기간 (MC_WEB_CONSOLE_SIGNUP_INVITATION_TTL)
+	InvitationTTL time.Duration
+	// ApprovalRequired 관리자 승인 후 계정 생성 (MC_WEB_CONSOLE_SIGNUP_APPROVAL_REQUIRED)
+	ApprovalRequired bool
+	// EncryptionKey 승인 대기 중 MCIAM 전달용 비밀번호 암호화 키 (MC_WEB_CONSOLE_SIGNUP_ENCRYPTION_KEY)
+	EncryptionKey string
+}
+
+// WebhookConfig 인증 이벤트 webhook 전송 설정
+type WebhookConfig struct {
+	// URLs 이벤트를 받을 webhook URL 목록, 비어 있으면 전송 안 함 (MC_WEB_CONSOLE_WEBHOOK_URLS)
+	URLs []string
+	// Secret HMAC-SHA256 서명 키, URLs가 있으면 필수이며 없으면 전송하지 않는다 (MC_WEB_CONSOLE_WEBHOOK_SECRET)
+	Secret string
+	// Events 전송할 이벤트 종류, 비어 있으면 전체 (MC_WEB_CONSOLE_WEBHOOK_EVENTS)
+	Events []string
+	// MaxAttempts 최대 전송 시도 횟수, 초과 시 dead-letter로 이동 (MC_WEB_CONSOLE_WEBHOOK_MAX_ATTEMPTS)
+	MaxAttempts int
+	// Timeout 전송 1회 HTTP 타임아웃 (MC_WEB_CONSOLE_WEBHOOK_TIMEOUT)
+	Timeout time.Duration
+	// RetryBackoff 첫 재시도 대기 시간, 시도마다 2배 (최대 1시간) (MC_WEB_CONSOLE_WEBHOOK_RETRY_BACKOFF)
+	RetryBackoff time.Duration
+	// PollInterval 재시도 대기열 확인 주기 (MC_WEB_CONSOLE_WEBHOOK_POLL_INTERVAL)
+	PollInterval time.Duration
+}
+
+// RegistryConfig mc-iam-manager 서비스 레지스트리 캐시 설정
+type RegistryConfig struct {
+	// TTL 캐시 신선도 유지 시간 (MC_WEB_CONSOLE_REGISTRY_TTL)
+	TTL time.Duration
+	// MaxStale TTL 경과 후에도 백그라운드 재검증 동안 기존 값을 제공하는 최대 시간 (MC_WEB_CONSOLE_REGISTRY_MAX_STALE)
+	MaxStale time.Duration
+	// RefreshInterval 백그라운드 갱신 주기, 0이면 갱신 안 함 (MC_WEB_CONSOLE_REGISTRY_REFRESH_INTERVAL)
+	RefreshInterval time.Duration
+	// ServiceUser/ServicePassword 백그라운드 갱신에 사용하는 mc-iam-manager 서비스 계정
+	// (MC_WEB_CONSOLE_REGISTRY_SERVICE_USER, MC_WEB_CONSOLE_REGISTRY_SERVICE_PASSWORD). 비어 있으면 백그라운드 갱신 안 함
+	ServiceUser     string
+	ServicePassword string
+	// Persist 마지막 정상 레지스트리 보관 위치: "db"(Postgres), 파일 경로, 비어 있으면 보관 안 함 (MC_WEB_CONSOLE_REGISTRY_PERSIST)
+	Persist string
+}
+
+// InvalidationConfig replica 간 캐시 무효화 버스 설정
+type InvalidationConfig struct {
+	// Bus 무효화 버스 종류 (MC_WEB_CONSOLE_INVALIDATION_BUS)
+	// auto: DB가 있으면 postgres, 없으면 memory / postgres: LISTEN/NOTIFY / memory: 프로세스 내부 (단일 인스턴스, 테스트용)
+	Bus string
+	// Channel Postgres NOTIFY 채널 이름 (MC_WEB_CONSOLE_INVALIDATION_CHANNEL)
+	Channel string
+	// ReconnectDelay LISTEN 연결이 끊겼을 때 재연결 대기 시간 (MC_WEB_CONSOLE_INVALIDATION_RECONNECT_DELAY)
+	ReconnectDelay time.Duration
+}
+
+// HealthConfig 백엔드 서비스 상태 점검 설정
+type HealthConfig struct {
+	// Interval 점검 주기, 0이면 점검 안 함 (MC_WEB_CONSOLE_HEALTH_INTERVAL)
+	Interval time.Duration
+	// Timeout 서비스별 요청 제한 시간 (MC_WEB_CONSOLE_HEALTH_TIMEOUT)
+	Timeout time.Duration
+	// DefaultPath api.yaml에 readyz action이 없는 서비스의 점검 경로 (MC_WEB_CONSOLE_HEALTH_PATH)
+	DefaultPath string
+	// Paths 서비스별 점검 경로 (MC_WEB_CONSOLE_HEALTH_PATHS="service=/path,...")
+	Paths map[string]string
+	// Exclude 점검 제외 서비스 (MC_WEB_CONSOLE_HEALTH_EXCLUDE)
+	Exclude []string
+	// History 서비스별 보관하는 최근 점검 결과 수 (MC_WEB_CONSOLE_HEALTH_HISTORY)
+	History int
+	// FailThreshold 연속 실패가 이 횟수 이상이면 down, 그 전에는 degraded (MC_WEB_CONSOLE_HEALTH_FAIL_THRESHOLD)
+	FailThreshold int
+	// CertWarn TLS 인증서 만료가 이 기간 이내면 degraded (MC_WEB_CONSOLE_HEALTH_CERT_WARN)
+	CertWarn time.Duration
+	// Required down이면 /readyz가 503을 반환하는 서비스 (MC_WEB_CONSOLE_HEALTH_REQUIRED)
+	Required []string
+}
+
 // MCIAMConfig MC-IAM 설정
 type MCIAMConfig struct {
-	Use           bool
-	TicketUse     bool
+	Use            bool
+	TicketUse      bool
 	UseRegistryURL bool
+	// TicketCacheTTL 발급받은 RPT 티켓 캐시 최대 유지 시간. 티켓 만료가 더 이르면 만료 시각까지만 캐시 (MC_WEB_CONSOLE_TICKET_CACHE_TTL)
+	TicketCacheTTL time.Duration
 }
 
 // Load 설정 로드
 func Load() (*Config, error) {
+	return load(true)
+}
+
+// LoadWithoutApiSpec ../conf/api.yaml이 없어도 실패하지 않는 설정 로드 (asset-sync로 api.yaml을 처음 받는 경우).
+// 파일이 없으면 ApiSpec은 빈 명세다.
+func LoadWithoutApiSpec() (*Config, error) {
+	return load(false)
+}
+
+func load(requireApiSpec bool) (*Config, error) {
 	// 환경 변수 우선
 	viper.AutomaticEnv()
 
@@ -82,28 +405,283 @@ func Load() (*Config, error) {
 			DBName:   getEnv("MC_WEB_CONSOLE_POSTGRES_DB", "mc_web_console"),
 			SSLMode:  getEnv("MC_WEB_CONSOLE_POSTGRES_SSLMODE", "disable"),
 		},
+		Session: SessionConfig{
+			CacheTTL:        getEnvDuration("MC_WEB_CONSOLE_SESSION_CACHE_TTL", 30*time.Second),
+			JanitorInterval: getEnvDuration("MC_WEB_CONSOLE_SESSION_JANITOR_INTERVAL", 10*time.Minute),
+		},
 		MCIAM: MCIAMConfig{
 			Use:            getEnv("MC_WEB_CONSOLE_USE_IAM", "false") == "true",
 			TicketUse:      getEnv("MC_WEB_CONSOLE_USE_TICKET_VALID", "false") == "true",
 			UseRegistryURL: getEnv("MC_WEB_CONSOLE_USE_REGISTRY_URL", "true") == "true",
+			TicketCacheTTL: getEnvDuration("MC_WEB_CONSOLE_TICKET_CACHE_TTL", 5*time.Minute),
 		},
 		SetupYaml: SetupYamlConfig{
 			McWebconsoleMenuYaml: getEnv("MC_WEB_CONSOLE_MENUYAML", ""),
 			McAdmincliApiYaml:    getEnv("MC_ADMIN_CLI_APIYAML", ""),
 		},
+		RemoteAssets: RemoteAssetsConfig{
+			Timeout:      getEnvDuration("MC_WEB_CONSOLE_REMOTE_ASSET_TIMEOUT", 10*time.Second),
+			GithubToken:  getEnv("MC_WEB_CONSOLE_REMOTE_ASSET_GITHUB_TOKEN", ""),
+			HistoryDepth: getEnvInt("MC_WEB_CONSOLE_REMOTE_ASSET_HISTORY_DEPTH", 20),
+			BackupDir:    getEnv("MC_WEB_CONSOLE_ASSET_BACKUP_DIR", "../conf/backup"),
+			BackupKeep:   getEnvInt("MC_WEB_CONSOLE_ASSET_BACKUP_KEEP", 10),
+		},
+		MenuSync: MenuSyncConfig{
+			Framework:       getEnv("MC_WEB_CONSOLE_MENU_FRAMEWORK", "mc-web-console"),
+			MenusFile:       getEnv("MC_WEB_CONSOLE_MENU_RESOURCES", "../conf/webconsole_menu_resources.yaml"),
+			PermissionsFile: getEnv("MC_WEB_CONSOLE_MENU_PERMISSIONS", "../conf/webconsole_menu_permissions.csv"),
+			ExportFile:      getEnv("MC_WEB_CONSOLE_MENU_EXPORT", "../conf/selfiammenu.yaml"),
+			PagesDir:        getEnv("MC_WEB_CONSOLE_MENU_PAGES", "../front/templates/pages"),
+			Timeout:         getEnvDuration("MC_WEB_CONSOLE_MENU_SYNC_TIMEOUT", 15*time.Second),
+		},
+		LocalAuth: LocalAuthConfig{
+			RequireEmailVerification: getEnv("MC_WEB_CONSOLE_LOCAL_REQUIRE_EMAIL_VERIFY", "true") == "true",
+			VerifyTokenTTL:           getEnvDuration("MC_WEB_CONSOLE_LOCAL_VERIFY_TOKEN_TTL", 24*time.Hour),
+			ResetTokenTTL:            getEnvDuration("MC_WEB_CONSOLE_LOCAL_RESET_TOKEN_TTL", 30*time.Minute),
+			DefaultRole:              getEnv("MC_WEB_CONSOLE_LOCAL_DEFAULT_ROLE", "viewer"),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MC_WEB_CONSOLE_MFA_ISSUER", "mc-web-console"),
+			RequiredRoles: getEnvList("MC_WEB_CONSOLE_MFA_REQUIRED_ROLES", ""),
+			EncryptionKey: getEnv("MC_WEB_CONSOLE_MFA_ENCRYPTION_KEY", ""),
+			ChallengeTTL:  getEnvDuration("MC_WEB_CONSOLE_MFA_CHALLENGE_TTL", 5*time.Minute),
+		},
+		LoginThrottle: LoginThrottleConfig{
+			Enabled:            getEnv("MC_WEB_CONSOLE_LOGIN_THROTTLE", "true") == "true",
+			MaxAccountFailures: getEnvInt("MC_WEB_CONSOLE_LOGIN_MAX_ACCOUNT_FAILURES", 5),
+			MaxIPFailures:      getEnvInt("MC_WEB_CONSOLE_LOGIN_MAX_IP_FAILURES", 50),
+			FreeAttempts:       getEnvInt("MC_WEB_CONSOLE_LOGIN_FREE_ATTEMPTS", 2),
+			BaseDelay:          getEnvDuration("MC_WEB_CONSOLE_LOGIN_BASE_DELAY", time.Second),
+			MaxDelay:           getEnvDuration("MC_WEB_CONSOLE_LOGIN_MAX_DELAY", 30*time.Second),
+			LockoutDuration:    getEnvDuration("MC_WEB_CONSOLE_LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			FailureWindow:      getEnvDuration("MC_WEB_CONSOLE_LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		},
+		Authz: AuthzConfig{
+			Enabled:         getEnv("MC_WEB_CONSOLE_AUTHZ", "true") == "true",
+			PermissionsFile: getEnv("MC_WEB_CONSOLE_API_PERMISSIONS", "../conf/webconsole_api_permissions.csv"),
+			RolePolicies:    loadRolePolicies(getEnvList("MC_WEB_CONSOLE_ROLE_POLICIES", "")),
+			AdminRoles:      getEnvList("MC_WEB_CONSOLE_AUTHZ_ADMIN_ROLES", "platformAdmin,admin"),
+		},
+		Scope: ScopeConfig{
+			Enabled:          getEnv("MC_WEB_CONSOLE_SCOPE_CHECK", "true") == "true",
+			CacheTTL:         getEnvDuration("MC_WEB_CONSOLE_SCOPE_CACHE_TTL", 2*time.Minute),
+			BypassRoles:      getEnvList("MC_WEB_CONSOLE_SCOPE_BYPASS_ROLES", "platformAdmin,admin"),
+			NamespaceParams:  getEnvList("MC_WEB_CONSOLE_SCOPE_NS_PARAMS", "nsId,ns_id"),
+			ExemptSubsystems: getEnvList("MC_WEB_CONSOLE_SCOPE_EXEMPT_SUBSYSTEMS", "mc-iam-manager,mc-web-console"),
+		},
+		AccessToken: AccessTokenConfig{
+			DefaultTTL:  getEnvDuration("MC_WEB_CONSOLE_PAT_DEFAULT_TTL", 90*24*time.Hour),
+			MaxTTL:      getEnvDuration("MC_WEB_CONSOLE_PAT_MAX_TTL", 365*24*time.Hour),
+			MCIAMMaxTTL: getEnvDuration("MC_WEB_CONSOLE_PAT_MCIAM_MAX_TTL", 7*24*time.Hour),
+		},
+		Cookie: CookieConfig{
+			Secure:             strings.ToLower(getEnv("MC_WEB_CONSOLE_COOKIE_SECURE", "auto")),
+			SameSite:           parseSameSite(getEnv("MC_WEB_CONSOLE_COOKIE_SAMESITE", "Lax")),
+			Domain:             getEnv("MC_WEB_CONSOLE_COOKIE_DOMAIN", ""),
+			CSRFTrustedOrigins: getEnvList("MC_WEB_CONSOLE_CSRF_TRUSTED_ORIGINS", ""),
+		},
+		Signup: SignupConfig{
+			PasswordMinLength:    getEnvInt("MC_WEB_CONSOLE_PASSWORD_MIN_LENGTH", 8),
+			PasswordMinClasses:   getEnvInt("MC_WEB_CONSOLE_PASSWORD_MIN_CLASSES", 1),
+			BreachedPasswordPath: getEnv("MC_WEB_CONSOLE_BREACHED_PASSWORD_PATH", ""),
+			EmailDomainAllow:     getEnvList("MC_WEB_CONSOLE_SIGNUP_EMAIL_DOMAINS_ALLOW", ""),
+			EmailDomainDeny:      getEnvList("MC_WEB_CONSOLE_SIGNUP_EMAIL_DOMAINS_DENY", ""),
+			InvitationRequired:   getEnv("MC_WEB_CONSOLE_SIGNUP_INVITATION_REQUIRED", "false") == "true",
+			InvitationTTL:        getEnvDuration("MC_WEB_CONSOLE_SIGNUP_INVITATION_TTL", 7*24*time.Hour),
+			ApprovalRequired:     getEnv("MC_WEB_CONSOLE_SIGNUP_APPROVAL_REQUIRED", "false") == "true",
+			EncryptionKey:        getEnv("MC_WEB_CONSOLE_SIGNUP_ENCRYPTION_KEY", ""),
+		},
+		Registry: RegistryConfig{
+			TTL:             getEnvDuration("MC_WEB_CONSOLE_REGISTRY_TTL", 60*time.Second),
+			MaxStale:        getEnvDuration("MC_WEB_CONSOLE_REGISTRY_MAX_STALE", 24*time.Hour),
+			RefreshInterval: getEnvDuration("MC_WEB_CONSOLE_REGISTRY_REFRESH_INTERVAL", 45*time.Second),
+			ServiceUser:     getEnv("MC_WEB_CONSOLE_REGISTRY_SERVICE_USER", ""),
+			ServicePassword: getEnv("MC_WEB_CONSOLE_REGISTRY_SERVICE_PASSWORD", ""),
+			Persist:         getEnv("MC_WEB_CONSOLE_REGISTRY_PERSIST", ""),
+		},
+		Health: HealthConfig{
+			Interval:      getEnvDuration("MC_WEB_CONSOLE_HEALTH_INTERVAL", 30*time.Second),
+			Timeout:       getEnvDuration("MC_WEB_CONSOLE_HEALTH_TIMEOUT", 5*time.Second),
+			DefaultPath:   getEnv("MC_WEB_CONSOLE_HEALTH_PATH", "/readyz"),
+			Paths:         loadHealthPaths(getEnvList("MC_WEB_CONSOLE_HEALTH_PATHS", "")),
+			Exclude:       getEnvList("MC_WEB_CONSOLE_HEALTH_EXCLUDE", "mc-web-console"),
+			History:       getEnvInt("MC_WEB_CONSOLE_HEALTH_HISTORY", 20),
+			FailThreshold: getEnvInt("MC_WEB_CONSOLE_HEALTH_FAIL_THRESHOLD", 3),
+			CertWarn:      getEnvDuration("MC_WEB_CONSOLE_HEALTH_CERT_WARN", 14*24*time.Hour),
+			Required:      getEnvList("MC_WEB_CONSOLE_HEALTH_REQUIRED", ""),
+		},
+		Invalidation: InvalidationConfig{
+			Bus:            getEnv("MC_WEB_CONSOLE_INVALIDATION_BUS", "auto"),
+			Channel:        getEnv("MC_WEB_CONSOLE_INVALIDATION_CHANNEL", "mcwc_invalidation"),
+			ReconnectDelay: getEnvDuration("MC_WEB_CONSOLE_INVALIDATION_RECONNECT_DELAY", 5*time.Second),
+		},
+		Webhook: WebhookConfig{
+			URLs:         getEnvList("MC_WEB_CONSOLE_WEBHOOK_URLS", ""),
+			Secret:       getEnv("MC_WEB_CONSOLE_WEBHOOK_SECRET", ""),
+			Events:       getEnvList("MC_WEB_CONSOLE_WEBHOOK_EVENTS", ""),
+			MaxAttempts:  getEnvInt("MC_WEB_CONSOLE_WEBHOOK_MAX_ATTEMPTS", 8),
+			Timeout:      getEnvDuration("MC_WEB_CONSOLE_WEBHOOK_TIMEOUT", 5*time.Second),
+			RetryBackoff: getEnvDuration("MC_WEB_CONSOLE_WEBHOOK_RETRY_BACKOFF", 10*time.Second),
+			PollInterval: getEnvDuration("MC_WEB_CONSOLE_WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		},
+		Impersonation: ImpersonationConfig{
+			Enabled:    getEnv("MC_WEB_CONSOLE_IMPERSONATION", "true") == "true",
+			TTL:        getEnvDuration("MC_WEB_CONSOLE_IMPERSONATION_TTL", 15*time.Minute),
+			AllowWrite: getEnv("MC_WEB_CONSOLE_IMPERSONATION_ALLOW_WRITE", "false") == "true",
+			AdminRoles: getEnvList("MC_WEB_CONSOLE_IMPERSONATION_ROLES", "platformAdmin,admin"),
+		},
 		IframeTargetIsHost: getEnv("MC_WEB_CONSOLE_IFRAME_TARGET_IS_HOST", "false") == "true",
 	}
 
+	trustedProxies, err := parseTrustedProxies(getEnvList("MC_WEB_CONSOLE_TRUSTED_PROXIES", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.TrustedProxies = trustedProxies
+
+	cfg.RemoteAssets.Assets = loadRemoteAssets(cfg, getEnvList("MC_WEB_CONSOLE_REMOTE_ASSETS", ""))
+
 	// API 스펙 로드
 	apiSpec, err := LoadApiSpec("../conf/api.yaml")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load API spec: %w", err)
+		if requireApiSpec || !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load API spec: %w", err)
+		}
+		log.Printf("[Config] ../conf/api.yaml not found, continuing with an empty API spec")
+		apiSpec = &ApiSpec{}
 	}
 	cfg.ApiSpec = apiSpec
 
+	// 로컬 인증(selfiam) 설정 로드 — bootstrap 관리자 계정 및 암호화 키
+	selfIAM, err := LoadSelfIAMSetting(
+		getEnv("MC_WEB_CONSOLE_SELFIAM_SETTING", "../conf/selfiamauthsetting.yaml"),
+		"../conf/selfiamauthsetting.sample.yaml",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load selfiam setting: %w", err)
+	}
+	cfg.SelfIAM = selfIAM
+	// MFA 시크릿은 전용 키로만 암호화한다 (selfiam 샘플 키 재사용 방지). 필수 역할이 있으면 키 없이 기동하지 않는다.
+	if len(cfg.MFA.RequiredRoles) > 0 && cfg.MFA.EncryptionKey == "" {
+		return nil, fmt.Errorf("MC_WEB_CONSOLE_MFA_ENCRYPTION_KEY is required when MC_WEB_CONSOLE_MFA_REQUIRED_ROLES is set")
+	}
+
+	// BFF 인가 정책 로드 (활성화 시 파일이 없으면 기동 실패: 정책 없이 전부 허용하지 않도록)
+	if cfg.Authz.Enabled {
+		perms, err := LoadApiPermissions(cfg.Authz.PermissionsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load api permissions: %w", err)
+		}
+		cfg.ApiPermissions = perms
+	}
+
 	return cfg, nil
 }
 
+// parseTrustedProxies CIDR 또는 단일 IP 목록을 대역으로 변환
+func parseTrustedProxies(items []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(items))
+	for _, item := range items {
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid MC_WEB_CONSOLE_TRUSTED_PROXIES entry %q", item)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MC_WEB_CONSOLE_TRUSTED_PROXIES entry %q: %w", item, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// loadRolePolicies 기본 역할 매핑에 "role=policy" 항목을 덮어쓴다.
+func loadRolePolicies(overrides []string) map[string]string {
+	policies := make(map[string]string, len(defaultRolePolicies)+len(overrides))
+	for role, policy := range defaultRolePolicies {
+		policies[role] = policy
+	}
+	for _, item := range overrides {
+		role, policy, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(role) == "" || strings.TrimSpace(policy) == "" {
+			log.Printf("⚠️  invalid MC_WEB_CONSOLE_ROLE_POLICIES entry %q (expected role=policy)", item)
+			continue
+		}
+		policies[strings.ToLower(strings.TrimSpace(role))] = strings.TrimSpace(policy)
+	}
+	return policies
+}
+
+// loadHealthPaths "service=/path" 항목을 서비스(소문자) → 점검 경로로 파싱
+func loadHealthPaths(items []string) map[string]string {
+	paths := make(map[string]string, len(items))
+	for _, item := range items {
+		name, path, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(name) == "" || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+			log.Printf("⚠️  invalid MC_WEB_CONSOLE_HEALTH_PATHS entry %q (expected service=/path)", item)
+			continue
+		}
+		paths[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(path)
+	}
+	return paths
+}
+
+// loadRemoteAssets 기본 원격 자산 목록에 "name|url|localPath" 항목을 덮어쓴다 (localPath 생략 시 기존 값 유지).
+func loadRemoteAssets(cfg *Config, items []string) []RemoteAsset {
+	assets := []RemoteAsset{
+		{Name: "menu", URL: cfg.SetupYaml.McWebconsoleMenuYaml, LocalPath: cfg.MenuSync.MenusFile},
+		{Name: "api", URL: cfg.SetupYaml.McAdmincliApiYaml, LocalPath: "../conf/api.yaml"},
+		{Name: "metainfo", URL: getEnv("MC_WEB_CONSOLE_METAINFOYAML", ""), LocalPath: "../conf/metainfo.yaml"},
+		{Name: "menu-permissions", URL: getEnv("MC_WEB_CONSOLE_MENU_PERMISSIONS_URL", ""), LocalPath: cfg.MenuSync.PermissionsFile},
+		{Name: "api-permissions", URL: getEnv("MC_WEB_CONSOLE_API_PERMISSIONS_URL", ""), LocalPath: cfg.Authz.PermissionsFile},
+	}
+	for _, item := range items {
+		parts := strings.Split(item, "|")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if len(parts) < 2 || len(parts) > 3 || name == "" || !strings.HasPrefix(strings.TrimSpace(parts[1]), "http") {
+			log.Printf("⚠️  invalid MC_WEB_CONSOLE_REMOTE_ASSETS entry %q (expected name|url|localPath)", item)
+			continue
+		}
+		asset := RemoteAsset{Name: name, URL: strings.TrimSpace(parts[1])}
+		if len(parts) == 3 {
+			asset.LocalPath = strings.TrimSpace(parts[2])
+		}
+		replaced := false
+		for i := range assets {
+			if assets[i].Name == name {
+				if asset.LocalPath == "" {
+					asset.LocalPath = assets[i].LocalPath
+				}
+				assets[i] = asset
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			if asset.LocalPath == "" {
+				log.Printf("⚠️  MC_WEB_CONSOLE_REMOTE_ASSETS entry %q has no local path", item)
+				continue
+			}
+			assets = append(assets, asset)
+		}
+	}
+	for i := range assets {
+		assets[i].Format = "yaml"
+		if strings.EqualFold(filepath.Ext(assets[i].LocalPath), ".csv") {
+			assets[i].Format = "csv"
+		}
+	}
+	return assets
+}
+
 // getEnv 환경 변수 또는 기본값 반환
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -112,6 +690,57 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList 콤마로 구분된 환경 변수를 문자열 목록으로 파싱 (공백 항목 제외)
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseSameSite SameSite 속성 문자열 파싱 (알 수 없는 값은 Lax)
+func parseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// getEnvDuration 환경 변수를 time.Duration("30s", "5m")으로 파싱. 미설정/형식 오류 시 기본값 반환
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("⚠️  invalid duration %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvInt 정수 환경 변수 조회 (형식 오류 시 기본값)
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("⚠️  invalid integer %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
 // GetServerAddress 서버 주소 반환
 func (c *Config) GetServerAddress() string {
 	if c.Server.Address != "" {
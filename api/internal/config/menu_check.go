@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 메뉴 정합성 검사 항목 (MenuCheckFinding.Check)
+const (
+	MenuCheckTree        = "tree"        // 빈/중복 id, 존재하지 않는 parentid
+	MenuCheckCycle       = "cycle"       // parentid 순환
+	MenuCheckPermissions = "permissions" // 메뉴 권한 CSV
+	MenuCheckTemplates   = "templates"   // front 페이지 템플릿
+	MenuCheckMenuNumber  = "menunumber"  // 중복 menunumber
+	MenuCheckPriority    = "priority"    // 형제 메뉴 정렬 충돌
+	MenuCheckExport      = "export"      // mc-iam-manager 메뉴 내보내기 파일
+)
+
+// MenuCheckSources 정합성 검사 입력. Pages가 nil이면 템플릿 검사, Export가 nil이면 내보내기 비교를 건너뛴다.
+type MenuCheckSources struct {
+	Framework   string
+	Menus       MenuResources
+	Permissions *MenuPermissions
+	Pages       []string // templates/pages 기준 페이지 경로 ("operations/manage/workspaces", 확장자 제외)
+	Export      *MenuExport
+}
+
+// MenuCheckFinding 검사 결과 한 건
+type MenuCheckFinding struct {
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// MenuCheckReport 정합성 검사 결과. Errors는 배포 전에 고쳐야 하는 항목, Warnings는 확인이 필요한 항목이다.
+type MenuCheckReport struct {
+	Errors   []MenuCheckFinding `json:"errors"`
+	Warnings []MenuCheckFinding `json:"warnings"`
+}
+
+func (r *MenuCheckReport) addError(check, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, MenuCheckFinding{Check: check, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *MenuCheckReport) addWarning(check, format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, MenuCheckFinding{Check: check, Message: fmt.Sprintf(format, args...)})
+}
+
+// CheckMenuConsistency 메뉴 yaml, 메뉴 권한 CSV, front 페이지 템플릿, mc-iam-manager 메뉴 내보내기 파일 교차 검사.
+//   - errors: 트리 구조 오류, 순환, CSV 형식 오류
+//   - warnings: 권한 행/페이지가 없는 메뉴, 메뉴가 없는 권한 행/페이지, 중복 menunumber, 형제 메뉴 정렬 충돌, 내보내기 파일과의 차이
+func CheckMenuConsistency(src MenuCheckSources) *MenuCheckReport {
+	report := &MenuCheckReport{Errors: []MenuCheckFinding{}, Warnings: []MenuCheckFinding{}}
+	byId := src.Menus.ById()
+
+	for _, issue := range src.Menus.structureIssues() {
+		report.addError(MenuCheckTree, "%s", issue)
+	}
+	for _, cycle := range src.Menus.Cycles(byId) {
+		report.addError(MenuCheckCycle, "menu cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	if src.Permissions != nil {
+		issues, warnings := src.Permissions.Validate(src.Framework, src.Menus)
+		for _, issue := range issues {
+			report.addError(MenuCheckPermissions, "%s", issue)
+		}
+		for _, warning := range warnings {
+			report.addWarning(MenuCheckPermissions, "%s", warning)
+		}
+	}
+
+	checkMenuOrder(report, src.Menus)
+	if src.Pages != nil {
+		checkMenuPages(report, src.Menus, byId, src.Pages)
+	}
+	if src.Export != nil {
+		checkMenuExport(report, src.Menus, byId, src.Export)
+	}
+	return report
+}
+
+// checkMenuOrder 중복 menunumber와 priority/menunumber가 같아 정렬 순서가 정해지지 않는 형제 메뉴
+func checkMenuOrder(report *MenuCheckReport, menus MenuResources) {
+	byNumber := make(map[int][]string)
+	var numbers []int
+	for _, menu := range menus {
+		if _, ok := byNumber[menu.MenuNumber]; !ok {
+			numbers = append(numbers, menu.MenuNumber)
+		}
+		byNumber[menu.MenuNumber] = append(byNumber[menu.MenuNumber], menu.Id)
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		if ids := byNumber[number]; len(ids) > 1 {
+			report.addWarning(MenuCheckMenuNumber, "menunumber %d is shared by %s", number, strings.Join(ids, ", "))
+		}
+	}
+
+	type sortKey struct {
+		parent     string
+		priority   int
+		menuNumber int
+	}
+	siblings := make(map[sortKey][]string)
+	var keys []sortKey
+	for _, menu := range menus {
+		key := sortKey{menu.ParentId, menu.Priority, menu.MenuNumber}
+		if _, ok := siblings[key]; !ok {
+			keys = append(keys, key)
+		}
+		siblings[key] = append(siblings[key], menu.Id)
+	}
+	for _, key := range keys {
+		if ids := siblings[key]; len(ids) > 1 {
+			report.addWarning(MenuCheckPriority, "menus %s under %s have the same priority %d and menunumber %d (sidebar order is undefined)",
+				strings.Join(ids, ", "), key.parent, key.priority, key.menuNumber)
+		}
+	}
+}
+
+// checkMenuPages isaction 메뉴의 페이지(/webconsole/<메뉴 경로>)가 있는지, 메뉴 영역의 페이지에 메뉴가 있는지 검사.
+// 최상위 메뉴 id로 시작하지 않는 페이지(auth, _로 시작하는 디렉터리 등)는 메뉴와 무관한 페이지로 본다.
+func checkMenuPages(report *MenuCheckReport, menus MenuResources, byId map[string]MenuResource, pages []string) {
+	pageSet := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		pageSet[page] = true
+	}
+
+	menuPaths := make(map[string]MenuResource, len(menus))
+	for _, menu := range menus {
+		path := MenuPath(byId, menu.Id)
+		if path == nil {
+			continue
+		}
+		page := strings.Join(path, "/")
+		menuPaths[page] = menu
+		switch {
+		case menu.IsAction && !pageSet[page]:
+			report.addWarning(MenuCheckTemplates, "menu %s has no page template (pages/%s.html or .iframe.html)", menu.Id, page)
+		case !menu.IsAction && pageSet[page]:
+			report.addWarning(MenuCheckTemplates, "menu %s has page template pages/%s but isaction is false (not linked from the sidebar)", menu.Id, page)
+		}
+	}
+
+	for _, page := range pages {
+		top := strings.SplitN(page, "/", 2)[0]
+		if menu, ok := byId[top]; !ok || menu.ParentId != MenuRootId {
+			continue
+		}
+		if _, ok := menuPaths[page]; !ok {
+			report.addWarning(MenuCheckTemplates, "page template pages/%s has no menu", page)
+		}
+	}
+}
+
+// checkMenuExport 메뉴 yaml과 mc-iam-manager 메뉴 내보내기 파일 비교
+func checkMenuExport(report *MenuCheckReport, menus MenuResources, byId map[string]MenuResource, export *MenuExport) {
+	for _, issue := range export.Menus.Validate() {
+		report.addWarning(MenuCheckExport, "export: %s", issue)
+	}
+	exported := export.Menus.ById()
+	for _, menu := range menus {
+		current, ok := exported[menu.Id]
+		if !ok {
+			report.addWarning(MenuCheckExport, "menu %s is not in the export (not provisioned to mc-iam-manager?)", menu.Id)
+			continue
+		}
+		var diffs []string
+		if current.ParentId != menu.ParentId {
+			diffs = append(diffs, fmt.Sprintf("parentid export %s, yaml %s", current.ParentId, menu.ParentId))
+		}
+		if current.DisplayName != menu.DisplayName {
+			diffs = append(diffs, fmt.Sprintf("displayname export %q, yaml %q", current.DisplayName, menu.DisplayName))
+		}
+		if current.IsAction != menu.IsAction {
+			diffs = append(diffs, fmt.Sprintf("isaction export %t, yaml %t", current.IsAction, menu.IsAction))
+		}
+		if current.Priority != menu.Priority {
+			diffs = append(diffs, fmt.Sprintf("priority export %d, yaml %d", current.Priority, menu.Priority))
+		}
+		if current.MenuNumber != menu.MenuNumber {
+			diffs = append(diffs, fmt.Sprintf("menunumber export %d, yaml %d", current.MenuNumber, menu.MenuNumber))
+		}
+		if len(diffs) > 0 {
+			report.addWarning(MenuCheckExport, "menu %s differs from the export: %s", menu.Id, strings.Join(diffs, "; "))
+		}
+	}
+	for _, menu := range export.Menus {
+		if _, ok := byId[menu.Id]; !ok {
+			report.addWarning(MenuCheckExport, "exported menu %s is not in the menu resources", menu.Id)
+		}
+	}
+}
+
+// ListMenuPages front 페이지 템플릿 디렉터리의 페이지 경로 목록 (.html, .iframe.html 확장자 제외, 정렬)
+func ListMenuPages(dir string) ([]string, error) {
+	pages := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".html") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		page := strings.TrimSuffix(strings.TrimSuffix(filepath.ToSlash(rel), ".html"), ".iframe")
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("page template directory %s does not exist", dir)
+		}
+		return nil, fmt.Errorf("failed to list page templates: %w", err)
+	}
+	sort.Strings(pages)
+	return pages, nil
+}
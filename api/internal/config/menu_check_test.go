@@ -0,0 +1,248 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMenuYaml = `
+menus:
+  - id: operations
+    parentid: home
+    displayname: Operations
+    restype: menu
+    isaction: false
+    priority: 1
+    menunumber: 100
+  - id: manage
+    parentid: operations
+    displayname: Manage
+    restype: menu
+    isaction: false
+    priority: 1
+    menunumber: 110
+  - id: workloads
+    parentid: manage
+    displayname: Workloads
+    restype: menu
+    isaction: true
+    priority: 1
+    menunumber: 120
+  - id: workflows
+    parentid: manage
+    displayname: Workflows
+    restype: menu
+    isaction: true
+    priority: 2
+    menunumber: 130
+`
+
+const testMenuPermissionsCsv = `framework,resource,adminPolicy,viewerPolicy
+mc-web-console,operationsmenu,TRUE,TRUE
+mc-web-console,managemenu,TRUE,TRUE
+mc-web-console,workloadsmenu,TRUE,TRUE
+mc-web-console,workflowsmenu,TRUE,
+`
+
+func testMenuSources(t *testing.T) MenuCheckSources {
+	t.Helper()
+	menus, err := ParseMenuResources([]byte(testMenuYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	perms, err := ParseMenuPermissions([]byte(testMenuPermissionsCsv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return MenuCheckSources{
+		Framework:   "mc-web-console",
+		Menus:       menus,
+		Permissions: perms,
+		Pages:       []string{"auth/login", "operations/manage/workloads", "operations/manage/workflows"},
+	}
+}
+
+// findings Check별 메시지 (비교용)
+func findings(list []MenuCheckFinding, check string) []string {
+	var messages []string
+	for _, finding := range list {
+		if finding.Check == check {
+			messages = append(messages, finding.Message)
+		}
+	}
+	return messages
+}
+
+func TestCheckMenuConsistencyClean(t *testing.T) {
+	report := CheckMenuConsistency(testMenuSources(t))
+	if len(report.Errors) != 0 || len(report.Warnings) != 0 {
+		t.Fatalf("expected a clean report, got errors %v warnings %v", report.Errors, report.Warnings)
+	}
+}
+
+func TestCheckMenuConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(src *MenuCheckSources)
+		check    string
+		errors   []string
+		warnings []string
+	}{
+		{
+			name: "menu without permission row",
+			modify: func(src *MenuCheckSources) {
+				src.Menus = append(src.Menus, MenuResource{Id: "swcatalogs", ParentId: "manage", IsAction: true, Priority: 3, MenuNumber: 140})
+				src.Pages = append(src.Pages, "operations/manage/swcatalogs")
+			},
+			check:    MenuCheckPermissions,
+			warnings: []string{"menu swcatalogs has no permission row (hidden from every role)"},
+		},
+		{
+			name: "permission row without menu",
+			modify: func(src *MenuCheckSources) {
+				src.Permissions.Rows = append(src.Permissions.Rows, MenuPermission{Line: 6, Framework: "mc-web-console", Resource: "regionsmenu", Cells: []string{"TRUE", ""}})
+			},
+			check:    MenuCheckPermissions,
+			warnings: []string{"line 6: menu regions does not exist, row is skipped"},
+		},
+		{
+			name: "invalid permission value",
+			modify: func(src *MenuCheckSources) {
+				src.Permissions.Rows[3].Cells[1] = "yes"
+			},
+			check:  MenuCheckPermissions,
+			errors: []string{`line 5: viewerPolicy value "yes" must be TRUE, FALSE or empty`},
+		},
+		{
+			name: "action menu without template",
+			modify: func(src *MenuCheckSources) {
+				src.Pages = src.Pages[:2]
+			},
+			check:    MenuCheckTemplates,
+			warnings: []string{"menu workflows has no page template (pages/operations/manage/workflows.html or .iframe.html)"},
+		},
+		{
+			name: "template without menu",
+			modify: func(src *MenuCheckSources) {
+				src.Pages = append(src.Pages, "operations/manage/eventtrace", "_demo/vm/list")
+			},
+			check:    MenuCheckTemplates,
+			warnings: []string{"page template pages/operations/manage/eventtrace has no menu"},
+		},
+		{
+			name: "template of a non-action menu",
+			modify: func(src *MenuCheckSources) {
+				src.Pages = append(src.Pages, "operations/manage")
+			},
+			check:    MenuCheckTemplates,
+			warnings: []string{"menu manage has page template pages/operations/manage but isaction is false (not linked from the sidebar)"},
+		},
+		{
+			name: "missing parent",
+			modify: func(src *MenuCheckSources) {
+				src.Menus[3].ParentId = "settings"
+			},
+			check:  MenuCheckTree,
+			errors: []string{"menu workflows: parent settings does not exist"},
+		},
+		{
+			name: "cycle",
+			modify: func(src *MenuCheckSources) {
+				src.Menus[0].ParentId = "workloads"
+			},
+			check:  MenuCheckCycle,
+			errors: []string{"menu cycle: operations -> workloads -> manage -> operations"},
+		},
+		{
+			name: "duplicate menunumber",
+			modify: func(src *MenuCheckSources) {
+				src.Menus[3].MenuNumber = 100
+			},
+			check:    MenuCheckMenuNumber,
+			warnings: []string{"menunumber 100 is shared by operations, workflows"},
+		},
+		{
+			name: "priority collision",
+			modify: func(src *MenuCheckSources) {
+				src.Menus[3].Priority, src.Menus[3].MenuNumber = 1, 120
+			},
+			check:    MenuCheckPriority,
+			warnings: []string{"menus workloads, workflows under manage have the same priority 1 and menunumber 120 (sidebar order is undefined)"},
+		},
+		{
+			name: "export differences",
+			modify: func(src *MenuCheckSources) {
+				exported := append(MenuResources{}, src.Menus[:3]...)
+				exported[2].DisplayName = "MCI Workloads"
+				exported = append(exported, MenuResource{Id: "st4_root", ParentId: "home"})
+				src.Export = &MenuExport{Menus: exported}
+			},
+			check: MenuCheckExport,
+			warnings: []string{
+				`menu workloads differs from the export: displayname export "MCI Workloads", yaml "Workloads"`,
+				"menu workflows is not in the export (not provisioned to mc-iam-manager?)",
+				"exported menu st4_root is not in the menu resources",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := testMenuSources(t)
+			tt.modify(&src)
+			report := CheckMenuConsistency(src)
+			if got := findings(report.Errors, tt.check); !reflect.DeepEqual(got, tt.errors) {
+				t.Errorf("errors[%s] = %q, want %q", tt.check, got, tt.errors)
+			}
+			if got := findings(report.Warnings, tt.check); !reflect.DeepEqual(got, tt.warnings) {
+				t.Errorf("warnings[%s] = %q, want %q", tt.check, got, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestListMenuPages(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"auth/login.html", "operations/manage/workloads.html", "operations/analytics/costanalysis.iframe.html", "operations/readme.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pages, err := ListMenuPages(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"auth/login", "operations/analytics/costanalysis", "operations/manage/workloads"}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("ListMenuPages = %q, want %q", pages, want)
+	}
+	if _, err := ListMenuPages(filepath.Join(dir, "missing")); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("ListMenuPages(missing) error = %v", err)
+	}
+}
+
+// TestCheckMenuConsistencyConf 저장소의 메뉴 yaml, 메뉴 권한 CSV, 페이지 템플릿에 오류가 없는지 (경고는 허용)
+func TestCheckMenuConsistencyConf(t *testing.T) {
+	menus, err := LoadMenuResources("../../../conf/webconsole_menu_resources.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	perms, err := LoadMenuPermissions("../../../conf/webconsole_menu_permissions.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := ListMenuPages("../../../front/templates/pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := CheckMenuConsistency(MenuCheckSources{Framework: "mc-web-console", Menus: menus, Permissions: perms, Pages: pages})
+	for _, finding := range report.Errors {
+		t.Errorf("[%s] %s", finding.Check, finding.Message)
+	}
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MenuPermissionSuffix 메뉴 권한 리소스 이름 접미사 (메뉴 id "settings" → 리소스 "settingsmenu")
+const MenuPermissionSuffix = "menu"
+
+// MenuPermissions conf/webconsole_menu_permissions.csv 메뉴 권한 테이블.
+//
+// 앞의 두 컬럼(framework, resource) 뒤에 역할별 정책 컬럼(adminPolicy, operatorPolicy, ...)이 오고,
+// 허용이면 TRUE, 아니면 빈 칸(또는 FALSE)을 적는다. mc-iam-manager Getcurrentpermissioncsv 응답도 같은 형식이다.
+type MenuPermissions struct {
+	Policies []string
+	Rows     []MenuPermission
+}
+
+// MenuPermission 메뉴 권한 테이블의 한 행. Cells는 정책 컬럼 원문 (Validate에서 값 검사)
+type MenuPermission struct {
+	Line      int      `json:"line"` // CSV 행 번호 (헤더 = 1)
+	Framework string   `json:"framework"`
+	Resource  string   `json:"resource"`
+	Cells     []string `json:"-"`
+}
+
+// LoadMenuPermissions 메뉴 권한 CSV 파일 로드
+func LoadMenuPermissions(path string) (*MenuPermissions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read menu permissions file: %w", err)
+	}
+	return ParseMenuPermissions(data)
+}
+
+// ParseMenuPermissions 헤더와 컬럼 수만 검사해 파싱한다. 셀 값은 Validate에서 검사한다.
+func ParseMenuPermissions(data []byte) (*MenuPermissions, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("menu permissions is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse menu permissions: %w", err)
+	}
+	if len(header) < 3 || !strings.EqualFold(header[0], "framework") || !strings.EqualFold(header[1], "resource") {
+		return nil, fmt.Errorf("menu permissions header must start with framework,resource and at least one policy column")
+	}
+
+	perms := &MenuPermissions{}
+	for _, policy := range header[2:] {
+		perms.Policies = append(perms.Policies, strings.TrimSpace(policy))
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse menu permissions: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			return nil, fmt.Errorf("menu permissions line %d: expected %d columns, got %d", line, len(header), len(record))
+		}
+		row := MenuPermission{
+			Line:      line,
+			Framework: strings.TrimSpace(record[0]),
+			Resource:  strings.TrimSpace(record[1]),
+		}
+		for _, cell := range record[2:] {
+			row.Cells = append(row.Cells, strings.TrimSpace(cell))
+		}
+		perms.Rows = append(perms.Rows, row)
+	}
+	return perms, nil
+}
+
+// MenuId 리소스 이름에서 메뉴 id 추출 ("settingsmenu" → "settings"). 접미사가 없으면 ""
+func (p MenuPermission) MenuId() string {
+	if !strings.HasSuffix(p.Resource, MenuPermissionSuffix) || p.Resource == MenuPermissionSuffix {
+		return ""
+	}
+	return strings.TrimSuffix(p.Resource, MenuPermissionSuffix)
+}
+
+// Granted 정책 컬럼 index 값이 TRUE인지
+func (p MenuPermission) Granted(index int) bool {
+	return index < len(p.Cells) && strings.EqualFold(p.Cells[index], "TRUE")
+}
+
+// Grants framework 행의 메뉴 id → 허용된 정책 목록 (중복 행은 먼저 나온 행 사용)
+func (p *MenuPermissions) Grants(framework string) map[string][]string {
+	grants := make(map[string][]string)
+	for _, row := range p.Rows {
+		id := row.MenuId()
+		if row.Framework != framework || id == "" {
+			continue
+		}
+		if _, exists := grants[id]; exists {
+			continue
+		}
+		policies := []string{}
+		for i, policy := range p.Policies {
+			if row.Granted(i) {
+				policies = append(policies, policy)
+			}
+		}
+		grants[id] = policies
+	}
+	return grants
+}
+
+// Validate 권한 테이블 검사. issues는 반영을 막는 오류, warnings는 반영은 가능하지만 확인이 필요한 항목이다.
+//   - issues: 정책 컬럼 이름(…Policy) 중복/형식, TRUE/FALSE/빈 칸 이외의 값, 다른 framework 행, 메뉴 리소스가 아닌 행, 중복 행
+//   - warnings: menus에 없는 메뉴의 행, 권한 행이 없는 메뉴, 부모 메뉴가 허용되지 않아 트리에 보이지 않는 허용
+func (p *MenuPermissions) Validate(framework string, menus MenuResources) (issues []string, warnings []string) {
+	seenPolicy := make(map[string]bool, len(p.Policies))
+	for _, policy := range p.Policies {
+		if !strings.HasSuffix(policy, "Policy") || policy == "Policy" {
+			issues = append(issues, fmt.Sprintf("policy column %q must be named <role>Policy", policy))
+		}
+		if seenPolicy[strings.ToLower(policy)] {
+			issues = append(issues, fmt.Sprintf("policy column %q is duplicated", policy))
+		}
+		seenPolicy[strings.ToLower(policy)] = true
+	}
+
+	byId := menus.ById()
+	seen := make(map[string]int, len(p.Rows))
+	for _, row := range p.Rows {
+		if row.Framework != framework {
+			issues = append(issues, fmt.Sprintf("line %d: framework %q, expected %q", row.Line, row.Framework, framework))
+			continue
+		}
+		id := row.MenuId()
+		if id == "" {
+			issues = append(issues, fmt.Sprintf("line %d: resource %q is not a menu resource (<menu id>%s)", row.Line, row.Resource, MenuPermissionSuffix))
+			continue
+		}
+		if first, dup := seen[row.Resource]; dup {
+			issues = append(issues, fmt.Sprintf("line %d: resource %s is already defined on line %d", row.Line, row.Resource, first))
+			continue
+		}
+		seen[row.Resource] = row.Line
+		for i, cell := range row.Cells {
+			if cell != "" && !strings.EqualFold(cell, "TRUE") && !strings.EqualFold(cell, "FALSE") {
+				issues = append(issues, fmt.Sprintf("line %d: %s value %q must be TRUE, FALSE or empty", row.Line, p.Policies[i], cell))
+			}
+		}
+		if _, ok := byId[id]; !ok {
+			warnings = append(warnings, fmt.Sprintf("line %d: menu %s does not exist, row is skipped", row.Line, id))
+		}
+	}
+
+	grants := p.Grants(framework)
+	for _, menu := range menus {
+		policies, ok := grants[menu.Id]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("menu %s has no permission row (hidden from every role)", menu.Id))
+			continue
+		}
+		if menu.ParentId == MenuRootId {
+			continue
+		}
+		parentPolicies := grants[menu.ParentId]
+		for _, policy := range policies {
+			if !containsString(parentPolicies, policy) {
+				warnings = append(warnings, fmt.Sprintf("menu %s is granted to %s but its parent %s is not", menu.Id, policy, menu.ParentId))
+			}
+		}
+	}
+	return issues, warnings
+}
+
+// EncodeMenuPermissions framework의 메뉴 id → 허용 정책으로 권한 CSV 생성 (menus 순서, Importpermissionbycsv 업로드용)
+func EncodeMenuPermissions(framework string, policies []string, menus MenuResources, grants map[string][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(append([]string{"framework", "resource"}, policies...)); err != nil {
+		return nil, err
+	}
+	for _, menu := range menus {
+		granted, ok := grants[menu.Id]
+		if !ok {
+			continue
+		}
+		record := []string{framework, menu.Id + MenuPermissionSuffix}
+		for _, policy := range policies {
+			value := ""
+			if containsString(granted, policy) {
+				value = "TRUE"
+			}
+			record = append(record, value)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
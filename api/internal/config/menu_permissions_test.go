@@ -0,0 +1,122 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMenuPermissionsValidate(t *testing.T) {
+	menus, err := ParseMenuResources([]byte(testMenuYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		csv          string
+		wantIssues   []string
+		wantWarnings []string
+	}{
+		{
+			name:       "clean",
+			csv:        testMenuPermissionsCsv,
+			wantIssues: nil,
+		},
+		{
+			name: "bad policy columns",
+			csv: "framework,resource,admin,adminPolicy,AdminPolicy\n" +
+				"mc-web-console,operationsmenu,TRUE,TRUE,TRUE\n",
+			wantIssues: []string{
+				`policy column "admin" must be named <role>Policy`,
+				`policy column "AdminPolicy" is duplicated`,
+			},
+		},
+		{
+			name: "bad rows",
+			csv: "framework,resource,adminPolicy\n" +
+				"other-console,operationsmenu,TRUE\n" +
+				"mc-web-console,operations,TRUE\n" +
+				"mc-web-console,operationsmenu,yes\n" +
+				"mc-web-console,operationsmenu,TRUE\n",
+			wantIssues: []string{
+				`line 2: framework "other-console", expected "mc-web-console"`,
+				`line 3: resource "operations" is not a menu resource (<menu id>menu)`,
+				`line 4: adminPolicy value "yes" must be TRUE, FALSE or empty`,
+				`line 5: resource operationsmenu is already defined on line 4`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		perms, err := ParseMenuPermissions([]byte(tt.csv))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		issues, _ := perms.Validate("mc-web-console", menus)
+		if !reflect.DeepEqual(issues, tt.wantIssues) {
+			t.Errorf("%s: issues = %q, want %q", tt.name, issues, tt.wantIssues)
+		}
+	}
+}
+
+func TestMenuPermissionsValidateWarnings(t *testing.T) {
+	menus, err := ParseMenuResources([]byte(testMenuYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	perms, err := ParseMenuPermissions([]byte("framework,resource,adminPolicy,viewerPolicy\n" +
+		"mc-web-console,operationsmenu,TRUE,\n" +
+		"mc-web-console,managemenu,TRUE,TRUE\n" +
+		"mc-web-console,workloadsmenu,TRUE,\n" +
+		"mc-web-console,removedmenu,TRUE,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, warnings := perms.Validate("mc-web-console", menus)
+	if len(issues) != 0 {
+		t.Fatalf("issues = %q", issues)
+	}
+	want := []string{
+		"line 5: menu removed does not exist, row is skipped",
+		"menu manage is granted to viewerPolicy but its parent operations is not",
+		"menu workflows has no permission row (hidden from every role)",
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Fatalf("warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestEncodeMenuPermissions(t *testing.T) {
+	menus, err := ParseMenuResources([]byte(testMenuYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	grants := map[string][]string{
+		"workflows":  {"adminPolicy"},
+		"operations": {"adminPolicy", "viewerPolicy"},
+		"unknown":    {"adminPolicy"},
+	}
+	data, err := EncodeMenuPermissions("mc-web-console", []string{"adminPolicy", "viewerPolicy"}, menus, grants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"framework,resource,adminPolicy,viewerPolicy",
+		"mc-web-console,operationsmenu,TRUE,TRUE",
+		"mc-web-console,workflowsmenu,TRUE,",
+		"",
+	}, "\n")
+	if string(data) != want {
+		t.Fatalf("csv =\n%s\nwant\n%s", data, want)
+	}
+
+	// 다시 파싱하면 같은 허용이 나온다 (메뉴 목록에 없는 id는 빠짐)
+	perms, err := ParseMenuPermissions(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(grants, "unknown")
+	if got := perms.Grants("mc-web-console"); !reflect.DeepEqual(got, grants) {
+		t.Fatalf("round trip grants = %v, want %v", got, grants)
+	}
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// MenuRootId 최상위 메뉴의 parentid (메뉴 정의에는 없는 가상 루트)
+const MenuRootId = "home"
+
+// MenuResource 메뉴 정의 한 항목 (conf/webconsole_menu_resources.yaml)
+type MenuResource struct {
+	Id          string `yaml:"id" json:"id"`
+	ParentId    string `yaml:"parentid" json:"parentId"`
+	DisplayName string `yaml:"displayname" json:"displayName"`
+	ResType     string `yaml:"restype" json:"resType"`
+	IsAction    bool   `yaml:"isaction" json:"isAction"`
+	Priority    int    `yaml:"priority" json:"priority"`
+	MenuNumber  int    `yaml:"menunumber" json:"menuNumber"`
+}
+
+// MenuResources 메뉴 정의 목록 (파일 순서 유지)
+type MenuResources []MenuResource
+
+// LoadMenuResources conf/webconsole_menu_resources.yaml 파일 로드
+func LoadMenuResources(path string) (MenuResources, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read menu resources file: %w", err)
+	}
+	return ParseMenuResources(data)
+}
+
+// ParseMenuResources "menus:" 목록 파싱. 알 수 없는 필드는 오류로 본다.
+func ParseMenuResources(data []byte) (MenuResources, error) {
+	var doc struct {
+		Menus MenuResources `yaml:"menus"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse menu resources: %w", err)
+	}
+	if len(doc.Menus) == 0 {
+		return nil, fmt.Errorf("menu resources has no menus")
+	}
+	return doc.Menus, nil
+}
+
+// ById id → 메뉴. 중복 id는 먼저 나온 항목을 사용한다.
+func (m MenuResources) ById() map[string]MenuResource {
+	byId := make(map[string]MenuResource, len(m))
+	for _, menu := range m {
+		if _, exists := byId[menu.Id]; !exists {
+			byId[menu.Id] = menu
+		}
+	}
+	return byId
+}
+
+// Validate 메뉴 트리 구조 검사: 빈/중복 id, 존재하지 않는 parentid, 순환 참조
+func (m MenuResources) Validate() []string {
+	issues := m.structureIssues()
+	for _, cycle := range m.Cycles(m.ById()) {
+		issues = append(issues, fmt.Sprintf("menu cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	return issues
+}
+
+// structureIssues 순환 참조를 제외한 구조 검사 (빈/중복 id, 빈/존재하지 않는 parentid)
+func (m MenuResources) structureIssues() []string {
+	var issues []string
+	seen := make(map[string]bool, len(m))
+	for i, menu := range m {
+		switch {
+		case menu.Id == "":
+			issues = append(issues, fmt.Sprintf("menus[%d]: empty id", i))
+		case seen[menu.Id]:
+			issues = append(issues, fmt.Sprintf("menu %s: duplicate id", menu.Id))
+		}
+		seen[menu.Id] = true
+	}
+
+	for _, menu := range m {
+		if menu.Id == "" {
+			continue
+		}
+		if menu.ParentId == "" {
+			issues = append(issues, fmt.Sprintf("menu %s: empty parentid", menu.Id))
+			continue
+		}
+		if menu.ParentId != MenuRootId && !seen[menu.ParentId] {
+			issues = append(issues, fmt.Sprintf("menu %s: parent %s does not exist", menu.Id, menu.ParentId))
+		}
+	}
+	return issues
+}
+
+// Cycles parentid를 따라 올라가다 자기 자신으로 돌아오는 경로 목록 (같은 순환은 한 번만)
+func (m MenuResources) Cycles(byId map[string]MenuResource) [][]string {
+	var cycles [][]string
+	reported := make(map[string]bool)
+	for _, menu := range m {
+		path := []string{menu.Id}
+		index := map[string]int{menu.Id: 0}
+		for current := byId[menu.Id]; ; {
+			parent, ok := byId[current.ParentId]
+			if !ok || current.ParentId == MenuRootId {
+				break
+			}
+			if start, visited := index[parent.Id]; visited {
+				cycle := append(append([]string{}, path[start:]...), parent.Id)
+				if !reported[parent.Id] {
+					for _, id := range cycle {
+						reported[id] = true
+					}
+					cycles = append(cycles, cycle)
+				}
+				break
+			}
+			index[parent.Id] = len(path)
+			path = append(path, parent.Id)
+			current = parent
+		}
+	}
+	return cycles
+}
+
+// MenuExport mc-iam-manager 메뉴 내보내기 파일 (메뉴 관리 화면 Export, conf/selfiammenu.yaml)
+type MenuExport struct {
+	Version    string
+	ExportedAt string
+	Menus      MenuResources
+}
+
+// LoadMenuExport 메뉴 내보내기 파일 로드
+func LoadMenuExport(path string) (*MenuExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read menu export file: %w", err)
+	}
+	return ParseMenuExport(data)
+}
+
+// ParseMenuExport version/exported_at/menus 형식 파싱. 메뉴 필드는 mc-iam-manager JSON과 같은 camelCase이다.
+func ParseMenuExport(data []byte) (*MenuExport, error) {
+	var doc struct {
+		Version    string `yaml:"version"`
+		ExportedAt string `yaml:"exported_at"`
+		Menus      []struct {
+			Id          string `yaml:"id"`
+			ParentId    string `yaml:"parentId"`
+			DisplayName string `yaml:"displayName"`
+			ResType     string `yaml:"resType"`
+			IsAction    bool   `yaml:"isAction"`
+			Priority    int    `yaml:"priority"`
+			MenuNumber  int    `yaml:"menuNumber"`
+		} `yaml:"menus"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse menu export: %w", err)
+	}
+	if len(doc.Menus) == 0 {
+		return nil, fmt.Errorf("menu export has no menus")
+	}
+	export := &MenuExport{Version: doc.Version, ExportedAt: doc.ExportedAt}
+	for _, m := range doc.Menus {
+		export.Menus = append(export.Menus, MenuResource{
+			Id:          m.Id,
+			ParentId:    m.ParentId,
+			DisplayName: m.DisplayName,
+			ResType:     m.ResType,
+			IsAction:    m.IsAction,
+			Priority:    m.Priority,
+			MenuNumber:  m.MenuNumber,
+		})
+	}
+	return export, nil
+}
+
+// MenuDepth 루트(home)부터의 깊이 (최상위 = 1). 부모가 없거나 순환이면 지금까지 센 깊이를 반환한다.
+func MenuDepth(byId map[string]MenuResource, id string) int {
+	depth := 0
+	for seen := map[string]bool{}; id != MenuRootId && !seen[id]; {
+		menu, ok := byId[id]
+		if !ok {
+			break
+		}
+		seen[id] = true
+		depth++
+		id = menu.ParentId
+	}
+	return depth
+}
+
+// MenuPath 최상위 메뉴부터 id까지의 메뉴 id 목록 (front 페이지 경로 /webconsole/<path>).
+// 부모가 없거나 순환이면 nil을 반환한다.
+func MenuPath(byId map[string]MenuResource, id string) []string {
+	var path []string
+	for seen := map[string]bool{}; id != MenuRootId; {
+		menu, ok := byId[id]
+		if !ok || seen[id] {
+			return nil
+		}
+		seen[id] = true
+		path = append([]string{id}, path...)
+		id = menu.ParentId
+	}
+	return path
+}
@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestMFAConfigIsRequiredForRole(t *testing.T) {
+	cfg := MFAConfig{RequiredRoles: []string{"platformAdmin", "operator"}}
+	tests := []struct {
+		roles []string
+		want  bool
+	}{
+		{[]string{"platformAdmin"}, true},
+		{[]string{"platformadmin"}, true},
+		{[]string{"viewer", "Operator"}, true},
+		{[]string{"viewer"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsRequiredForRole(tt.roles...); got != tt.want {
+			t.Errorf("IsRequiredForRole(%v) = %t, want %t", tt.roles, got, tt.want)
+		}
+	}
+	if (MFAConfig{}).IsRequiredForRole("platformAdmin") {
+		t.Error("MFA required without RequiredRoles")
+	}
+}
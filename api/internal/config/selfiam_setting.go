@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// SelfIAMSetting conf/selfiamauthsetting.yaml 구조 (mc-iam-manager 없이 운영하는 로컬 인증 모드용)
+type SelfIAMSetting struct {
+	Setting SelfIAMSettingSection `mapstructure:"setting"`
+	User    SelfIAMBootstrapUser  `mapstructure:"user"`
+}
+
+// SelfIAMSettingSection 로컬 인증 공통 설정
+type SelfIAMSettingSection struct {
+	EncryptionKey string `mapstructure:"encryptionkey"`
+}
+
+// SelfIAMBootstrapUser 최초 기동 시 생성되는 관리자 계정
+type SelfIAMBootstrapUser struct {
+	ID        string `mapstructure:"id"`
+	Password  string `mapstructure:"password"`
+	Role      string `mapstructure:"role"`
+	FirstName string `mapstructure:"firstName"`
+	LastName  string `mapstructure:"lastName"`
+	Email     string `mapstructure:"email"`
+}
+
+// LoadSelfIAMSetting selfiam 설정 파일 로드.
+// path가 없으면 fallbackPath(.sample.yaml)를 사용하고 경고를 남긴다. 둘 다 없으면 nil 반환.
+func LoadSelfIAMSetting(path, fallbackPath string) (*SelfIAMSetting, error) {
+	target := path
+	if _, err := os.Stat(target); err != nil {
+		if fallbackPath == "" {
+			return nil, nil
+		}
+		if _, fbErr := os.Stat(fallbackPath); fbErr != nil {
+			return nil, nil
+		}
+		log.Printf("⚠️  %s not found, using sample setting %s (CHANGE the bootstrap password)", path, fallbackPath)
+		target = fallbackPath
+	}
+
+	v := viper.New()
+	v.SetConfigFile(target)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read selfiam setting file: %w", err)
+	}
+
+	var setting SelfIAMSetting
+	if err := v.Unmarshal(&setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal selfiam setting: %w", err)
+	}
+	return &setting, nil
+}
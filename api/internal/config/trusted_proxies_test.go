@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestParseTrustedProxies(t *testing.T) {
+	networks, err := parseTrustedProxies([]string{"10.0.0.0/8", "172.18.0.5", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0/8", "172.18.0.5/32", "::1/128"}
+	if len(networks) != len(want) {
+		t.Fatalf("parsed %d networks, want %d", len(networks), len(want))
+	}
+	for i, network := range networks {
+		if network.String() != want[i] {
+			t.Errorf("network[%d] = %s, want %s", i, network, want[i])
+		}
+	}
+
+	for _, invalid := range []string{"10.0.0.0/33", "front", "300.1.1.1"} {
+		if _, err := parseTrustedProxies([]string{invalid}); err == nil {
+			t.Errorf("parseTrustedProxies(%q) accepted", invalid)
+		}
+	}
+}
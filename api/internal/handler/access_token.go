@@ -0,0 +1,370 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// 개인 액세스 토큰(PAT)과 서비스 계정 핸들러.
+// 토큰은 "mcwc_pat_" 접두사로 JWT와 구분되며 AuthMiddleware가 DB에서 해시로 검증한다.
+// 토큰은 BFF 전용 자격 증명이라 백엔드로 전달되지 않으므로, MCIAM 모드에서 bearer 인증 백엔드 호출에는 사용할 수 없다.
+
+// CreateAccessTokenRequest 토큰 발급 요청
+type CreateAccessTokenRequest struct {
+	Request struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`          // "subsystem/operation" glob 패턴, 비어 있으면 "*"
+		ExpiresInDays int      `json:"expires_in_days"` // 0이면 기본 유효기간
+	} `json:"request"`
+}
+
+// CreateServiceAccountRequest 서비스 계정 생성 요청
+type CreateServiceAccountRequest struct {
+	Request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Role        string `json:"role"`
+	} `json:"request"`
+}
+
+// ServiceAccountStateRequest 서비스 계정 활성/비활성 요청
+type ServiceAccountStateRequest struct {
+	Request struct {
+		Disabled bool `json:"disabled"`
+	} `json:"request"`
+}
+
+// CreatedAccessToken 발급 응답. token 원문은 이 응답에서만 확인할 수 있다.
+type CreatedAccessToken struct {
+	Token       string             `json:"token"`
+	AccessToken *model.AccessToken `json:"access_token"`
+}
+
+// ListAccessTokens 로그인 사용자의 액세스 토큰 목록 핸들러
+// @Summary     List access tokens
+// @Description List personal access tokens of the current user (token values are never returned)
+// @Tags        auth
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=[]model.AccessToken}
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/auth/tokens [get]
+func ListAccessTokens(c echo.Context) error {
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	tokens, err := svc.ListUserTokens(middleware.GetUserID(c))
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list access tokens", err)
+	}
+	resp := model.CommonResponseStatusOK(tokens)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// CreateAccessToken 로그인 사용자의 액세스 토큰 발급 핸들러
+// @Summary     Create access token
+// @Description Issue a personal access token with optional scopes and expiry. The token value is shown only once.
+// @Tags        auth
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body CreateAccessTokenRequest true "Token name, scopes and expiry"
+// @Success     200 {object} model.CommonResponse{responseData=CreatedAccessToken}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/auth/tokens [post]
+func CreateAccessToken(c echo.Context) error {
+	var req CreateAccessTokenRequest
+	if err := c.Bind(&req); err != nil || strings.TrimSpace(req.Request.Name) == "" {
+		return errors.NewBadRequest("name is required")
+	}
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	userID := middleware.GetUserID(c)
+	plain, token, err := svc.IssueUserToken(userID, middleware.GetUserName(c), middleware.GetEmail(c), middleware.GetRole(c),
+		issueInput(req, userID))
+	if err != nil {
+		return accessTokenError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionTokenCreated,
+		Actor:  userID,
+		Target: token.ID,
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("name=%s scopes=%s", token.Name, token.Scopes),
+	})
+
+	resp := model.CommonResponseStatusOK(CreatedAccessToken{Token: plain, AccessToken: token})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RevokeAccessToken 로그인 사용자의 액세스 토큰 폐기 핸들러
+// @Summary     Revoke access token
+// @Tags        auth
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Token ID"
+// @Success     200 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/auth/tokens/{id} [delete]
+func RevokeAccessToken(c echo.Context) error {
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	userID := middleware.GetUserID(c)
+	if err := svc.RevokeUserToken(userID, c.Param("id")); err != nil {
+		return accessTokenError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionTokenRevoked,
+		Actor:  userID,
+		Target: c.Param("id"),
+		IP:     c.RealIP(),
+	})
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"revoked": c.Param("id")})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ListServiceAccounts 서비스 계정 목록 핸들러
+// @Summary     List service accounts
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=[]model.ServiceAccount}
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/admin/service-accounts [get]
+func ListServiceAccounts(c echo.Context) error {
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	accounts, err := svc.ListServiceAccounts()
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list service accounts", err)
+	}
+	resp := model.CommonResponseStatusOK(accounts)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// CreateServiceAccount 서비스 계정 생성 핸들러
+// @Summary     Create service account
+// @Description Create a non-human identity with a fixed role. Tokens are issued separately.
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body CreateServiceAccountRequest true "Service account"
+// @Success     200 {object} model.CommonResponse{responseData=model.ServiceAccount}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     409 {object} model.CommonResponse
+// @Router      /api/admin/service-accounts [post]
+func CreateServiceAccount(c echo.Context) error {
+	var req CreateServiceAccountRequest
+	if err := c.Bind(&req); err != nil || strings.TrimSpace(req.Request.Name) == "" || req.Request.Role == "" {
+		return errors.NewBadRequest("name and role are required")
+	}
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	actor := middleware.GetUserID(c)
+	account, err := svc.CreateServiceAccount(req.Request.Name, req.Request.Description, req.Request.Role, actor)
+	if err != nil {
+		return accessTokenError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionServiceAccountCreated,
+		Actor:  actor,
+		Target: account.PrincipalID(),
+		IP:     c.RealIP(),
+		Detail: "role=" + account.Role,
+	})
+	resp := model.CommonResponseStatusOK(account)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ListServiceAccountTokens 서비스 계정 토큰 목록 핸들러
+// @Summary     List service account tokens
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Service account ID"
+// @Success     200 {object} model.CommonResponse{responseData=[]model.AccessToken}
+// @Router      /api/admin/service-accounts/{id}/tokens [get]
+func ListServiceAccountTokens(c echo.Context) error {
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	tokens, err := svc.ListServiceAccountTokens(c.Param("id"))
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list service account tokens", err)
+	}
+	resp := model.CommonResponseStatusOK(tokens)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// CreateServiceAccountToken 서비스 계정 토큰 발급 핸들러
+// @Summary     Create service account token
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                   true "Service account ID"
+// @Param       request body CreateAccessTokenRequest true "Token name, scopes and expiry"
+// @Success     200 {object} model.CommonResponse{responseData=CreatedAccessToken}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/service-accounts/{id}/tokens [post]
+func CreateServiceAccountToken(c echo.Context) error {
+	var req CreateAccessTokenRequest
+	if err := c.Bind(&req); err != nil || strings.TrimSpace(req.Request.Name) == "" {
+		return errors.NewBadRequest("name is required")
+	}
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	actor := middleware.GetUserID(c)
+	plain, token, err := svc.IssueServiceAccountToken(c.Param("id"), issueInput(req, actor))
+	if err != nil {
+		return accessTokenError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionTokenCreated,
+		Actor:  actor,
+		Target: token.ID,
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("service_account=%s name=%s scopes=%s", token.UserName, token.Name, token.Scopes),
+	})
+	resp := model.CommonResponseStatusOK(CreatedAccessToken{Token: plain, AccessToken: token})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RevokeServiceAccountToken 서비스 계정 토큰 폐기 핸들러
+// @Summary     Revoke service account token
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id      path string true "Service account ID"
+// @Param       tokenId path string true "Token ID"
+// @Success     200 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/service-accounts/{id}/tokens/{tokenId} [delete]
+func RevokeServiceAccountToken(c echo.Context) error {
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := svc.RevokeServiceAccountToken(c.Param("id"), c.Param("tokenId")); err != nil {
+		return accessTokenError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionTokenRevoked,
+		Actor:  middleware.GetUserID(c),
+		Target: c.Param("tokenId"),
+		IP:     c.RealIP(),
+	})
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"revoked": c.Param("tokenId")})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// SetServiceAccountState 서비스 계정 비활성화(토큰 전부 폐기)/재활성화 핸들러
+// @Summary     Disable or enable service account
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id      path string                     true "Service account ID"
+// @Param       request body ServiceAccountStateRequest true "Disabled flag"
+// @Success     200 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/service-accounts/{id}/state [post]
+func SetServiceAccountState(c echo.Context) error {
+	var req ServiceAccountStateRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewBadRequest("Invalid request body")
+	}
+	svc, err := accessTokenServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := svc.SetServiceAccountDisabled(c.Param("id"), req.Request.Disabled); err != nil {
+		return accessTokenError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionServiceAccountUpdated,
+		Actor:  middleware.GetUserID(c),
+		Target: c.Param("id"),
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("disabled=%t", req.Request.Disabled),
+	})
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"id": c.Param("id"), "disabled": req.Request.Disabled})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// accessTokenServiceFromContext 액세스 토큰 핸들러용 서비스 생성. DB가 없으면 503 반환.
+func accessTokenServiceFromContext(c echo.Context) (*service.AccessTokenService, error) {
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil {
+		return nil, errors.NewInternalServerError("config not available", nil)
+	}
+	db := repository.GetDB()
+	if db == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Access tokens require database", nil)
+	}
+	var users *repository.UserRepository
+	if !cfg.MCIAM.Use {
+		users = repository.NewUserRepository(db)
+	}
+	return service.NewAccessTokenService(repository.NewAccessTokenRepository(db), users, cfg.AccessToken), nil
+}
+
+func issueInput(req CreateAccessTokenRequest, createdBy string) service.AccessTokenIssueInput {
+	return service.AccessTokenIssueInput{
+		Name:      strings.TrimSpace(req.Request.Name),
+		Scopes:    req.Request.Scopes,
+		ExpiresIn: time.Duration(req.Request.ExpiresInDays) * 24 * time.Hour,
+		CreatedBy: createdBy,
+	}
+}
+
+// accessTokenError 액세스 토큰 서비스 에러를 HTTP 에러로 변환
+func accessTokenError(err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrAccessTokenScope), stderrors.Is(err, service.ErrAccessTokenTTL):
+		return errors.NewBadRequest(err.Error())
+	case stderrors.Is(err, service.ErrAccessTokenInvalid), stderrors.Is(err, gorm.ErrRecordNotFound):
+		return errors.NewNotFound("Not found")
+	case stderrors.Is(err, service.ErrServiceAccountDisabled):
+		return errors.NewBadRequest(err.Error())
+	case stderrors.Is(err, service.ErrServiceAccountNameTaken):
+		return errors.New(http.StatusConflict, err.Error(), nil)
+	default:
+		return errors.NewInternalServerError("Access token operation failed", err)
+	}
+}
+
+// isAccessTokenHeader Authorization 헤더 값이 액세스 토큰인지 확인 (프록시에서 백엔드 전달 차단용)
+func isAccessTokenHeader(authValue string) bool {
+	return service.IsAccessToken(strings.TrimPrefix(authValue, "Bearer "))
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminStatusResult 관리자 상태 화면 응답 데이터
+type AdminStatusResult struct {
+	CheckedAt  string                 `json:"checkedAt"` // RFC3339
+	Database   bool                   `json:"database"`  // DB 연결 사용 여부
+	MCIAMUse   bool                   `json:"mciamUse"`
+	Components map[string]interface{} `json:"components"` // 백그라운드 컴포넌트별 상태 (sessions 등)
+}
+
+// GetAdminStatus 관리자 상태 화면 핸들러.
+// @Summary     Admin status
+// @Description Runtime status of background components (session janitor, caches, ...)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=AdminStatusResult}
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/admin/status [get]
+func GetAdminStatus(c echo.Context) error {
+	result := AdminStatusResult{
+		CheckedAt:  time.Now().UTC().Format(time.RFC3339),
+		Database:   repository.GetDB() != nil,
+		Components: service.StatusSnapshot(),
+	}
+	if cfg, ok := c.Get("config").(*config.Config); ok && cfg != nil {
+		result.MCIAMUse = cfg.MCIAM.Use
+	}
+
+	resp := model.CommonResponseStatusOK(result)
+	return c.JSON(resp.ToJSON())
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +10,7 @@ import (
 	"strings"
 
 	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
 	"mc_web_console_api/internal/model"
 
 	"github.com/labstack/echo/v4"
@@ -38,7 +38,8 @@ func GetApiHosts(c echo.Context) error {
 	apiHosts := make(map[string]ServiceNoAuth)
 
 	// api.yaml을 기본값으로 사용 (레지스트리 미등록 서비스도 포함)
-	for k, v := range cfg.ApiSpec.Services {
+	services, _ := cfg.ApiSpec.Snapshot()
+	for k, v := range services {
 		apiHosts[k] = ServiceNoAuth{BaseURL: v.BaseURL}
 	}
 
@@ -80,7 +81,7 @@ func GetApiHosts(c echo.Context) error {
 
 // refreshRegistryCache mc-iam-manager의 ListMcmpApisServices를 직접 호출하여 RegistryCache 갱신.
 // buildAuthHeader는 proxy.go에서 공유 (같은 handler 패키지).
-// 401 응답 시 RefreshToken 쿠키로 액세스 토큰을 자동 갱신 후 재시도한다.
+// 401 응답 시 사용자 토큰을 갱신(refreshUserTokens) 후 재시도한다.
 func refreshRegistryCache(cfg *config.Config, c echo.Context) error {
 	service, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "ListMcmpApisServices")
 	if err != nil {
@@ -105,7 +106,7 @@ func refreshRegistryCache(cfg *config.Config, c echo.Context) error {
 }
 
 // doListMcmpApisServices ListMcmpApisServices HTTP 호출.
-// 401 수신 시 RefreshToken 쿠키로 액세스 토큰 갱신 후 1회 재시도한다.
+// 401 수신 시 사용자 토큰 갱신 후 1회 재시도한다.
 func doListMcmpApisServices(cfg *config.Config, c echo.Context, targetURL, method, authHeader string) ([]byte, error) {
 	resp, err := callHTTP(strings.ToUpper(method), targetURL, authHeader)
 	if err != nil {
@@ -114,12 +115,12 @@ func doListMcmpApisServices(cfg *config.Config, c echo.Context, targetURL, metho
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		newToken, refreshErr := refreshUserAccessToken(cfg, c)
+		refreshed, refreshErr := refreshUserTokens(cfg, c, middleware.RequestToken(c))
 		if refreshErr != nil {
 			return nil, fmt.Errorf("ListMcmpApisServices 401, token refresh failed: %w", refreshErr)
 		}
 		log.Printf("[RegistryCache] token refreshed, retrying ListMcmpApisServices")
-		resp2, err2 := callHTTP(strings.ToUpper(method), targetURL, "Bearer "+newToken)
+		resp2, err2 := callHTTP(strings.ToUpper(method), targetURL, "Bearer "+refreshed.AccessToken)
 		if err2 != nil {
 			return nil, fmt.Errorf("ListMcmpApisServices retry failed: %w", err2)
 		}
@@ -148,47 +149,3 @@ func callHTTP(method, url, authHeader string) (*http.Response, error) {
 	}
 	return (&http.Client{}).Do(req)
 }
-
-// refreshUserAccessToken RefreshToken 쿠키를 이용해 mc-iam-manager에서 새 access_token 발급.
-func refreshUserAccessToken(cfg *config.Config, c echo.Context) (string, error) {
-	refreshCookie, err := c.Cookie("RefreshToken")
-	if err != nil || refreshCookie.Value == "" {
-		return "", fmt.Errorf("RefreshToken cookie not found")
-	}
-
-	service, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "loginrefresh")
-	if err != nil {
-		return "", fmt.Errorf("loginrefresh not found in api.yaml: %w", err)
-	}
-
-	targetURL := service.BaseURL + actionSpec.ResourcePath
-
-	body, _ := json.Marshal(map[string]string{"refresh_token": refreshCookie.Value})
-	req, err := http.NewRequest(http.MethodPost, targetURL, bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := (&http.Client{}).Do(req)
-	if err != nil {
-		return "", fmt.Errorf("loginrefresh call failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("loginrefresh returned %d", resp.StatusCode)
-	}
-
-	respBody, _ := io.ReadAll(resp.Body)
-	var data map[string]interface{}
-	if err := json.Unmarshal(respBody, &data); err != nil {
-		return "", fmt.Errorf("loginrefresh response parse failed: %w", err)
-	}
-
-	accessToken, ok := data["access_token"].(string)
-	if !ok || accessToken == "" {
-		return "", fmt.Errorf("access_token not found in loginrefresh response")
-	}
-	return accessToken, nil
-}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 원격 api.yaml / 메뉴 yaml 동기화 핸들러 (conf/getapiyaml.sh 대체).
+// plan으로 검증/구조 차이를 확인한 뒤 apply에 resultSha를 넘기면 확인한 내용만 반영된다.
+
+// ApplyAssetSyncRequest 동기화 반영 요청
+type ApplyAssetSyncRequest struct {
+	Request struct {
+		ExpectSHA string `json:"expect_sha"` // plan의 resultSha. 비어 있으면 현재 원격 내용을 그대로 반영
+	} `json:"request"`
+}
+
+// RollbackAssetSyncRequest 롤백 요청
+type RollbackAssetSyncRequest struct {
+	Request struct {
+		Backup string `json:"backup"` // 백업 ID. 비어 있으면 가장 최근 백업
+	} `json:"request"`
+}
+
+// PlanAssetSync 동기화 계획 조회 핸들러
+// @Summary     Asset sync plan
+// @Description Fetch the remote api.yaml or menu yaml, validate it and show the structural diff against the local conf/ copy (dry-run)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       name path string true "Asset" Enums(api, menu)
+// @Success     200 {object} model.CommonResponse{responseData=service.AssetSyncPlan}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     502 {object} model.CommonResponse
+// @Router      /api/admin/asset-sync/{name}/plan [get]
+func PlanAssetSync(c echo.Context) error {
+	syncer, err := assetSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	plan, err := syncer.Plan(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return assetSyncError(c.Param("name"), err)
+	}
+	resp := model.CommonResponseStatusOK(plan)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ApplyAssetSync 동기화 반영 핸들러 (기존 파일 백업 후 원자적 교체, hot reload)
+// @Summary     Apply asset sync
+// @Description Back up the local copy, write the validated remote content (local baseurls kept) and reload it
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       name    path string                true  "Asset" Enums(api, menu)
+// @Param       request body ApplyAssetSyncRequest false "Expected plan result"
+// @Success     200 {object} model.CommonResponse{responseData=service.AssetSyncResult}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     409 {object} model.CommonResponse
+// @Failure     502 {object} model.CommonResponse
+// @Router      /api/admin/asset-sync/{name}/apply [post]
+func ApplyAssetSync(c echo.Context) error {
+	syncer, err := assetSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	var req ApplyAssetSyncRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewBadRequest("invalid request body")
+	}
+	plan, result, err := syncer.Apply(c.Request().Context(), c.Param("name"), req.Request.ExpectSHA)
+	if err != nil {
+		return assetSyncError(c.Param("name"), err)
+	}
+	if result.Applied {
+		service.RecordAudit(model.AuditEvent{
+			Action: model.AuditActionAssetSynced,
+			Actor:  middleware.GetUserID(c),
+			Target: plan.LocalPath,
+			IP:     c.RealIP(),
+			Detail: fmt.Sprintf("url=%s sha=%s backup=%s", plan.URL, result.SHA, result.Backup),
+		})
+	}
+	resp := model.CommonResponseStatusOK(result)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ListAssetBackups 백업 목록 핸들러
+// @Summary     Asset backups
+// @Description List backups taken before each sync or rollback (newest first)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       name path string true "Asset" Enums(api, menu)
+// @Success     200 {object} model.CommonResponse{responseData=[]service.AssetBackup}
+// @Router      /api/admin/asset-sync/{name}/backups [get]
+func ListAssetBackups(c echo.Context) error {
+	syncer, err := assetSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	backups, err := syncer.Backups(c.Param("name"))
+	if err != nil {
+		return assetSyncError(c.Param("name"), err)
+	}
+	resp := model.CommonResponseStatusOK(backups)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RollbackAssetSync 백업으로 되돌리기 핸들러
+// @Summary     Roll back asset
+// @Description Restore a backup (latest if omitted); the current file is backed up first so a rollback can be undone
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       name    path string                   true  "Asset" Enums(api, menu)
+// @Param       request body RollbackAssetSyncRequest false "Backup ID"
+// @Success     200 {object} model.CommonResponse{responseData=service.AssetSyncResult}
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/asset-sync/{name}/rollback [post]
+func RollbackAssetSync(c echo.Context) error {
+	syncer, err := assetSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	var req RollbackAssetSyncRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewBadRequest("invalid request body")
+	}
+	result, err := syncer.Rollback(c.Param("name"), req.Request.Backup)
+	if err != nil {
+		return assetSyncError(c.Param("name"), err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionAssetRolledBack,
+		Actor:  middleware.GetUserID(c),
+		Target: c.Param("name"),
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("sha=%s backup=%s", result.SHA, result.Backup),
+	})
+	resp := model.CommonResponseStatusOK(result)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ReloadAsset 로컬 파일 재적재 핸들러 (CLI로 반영했거나 파일을 직접 수정한 경우)
+// @Summary     Reload asset
+// @Description Validate the local conf/ copy and load it into the running server on every replica
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       name path string true "Asset" Enums(api, menu)
+// @Success     200 {object} model.CommonResponse{responseData=service.AssetSyncResult}
+// @Failure     400 {object} model.CommonResponse
+// @Router      /api/admin/asset-sync/{name}/reload [post]
+func ReloadAsset(c echo.Context) error {
+	syncer, err := assetSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	result, err := syncer.Reload(c.Param("name"))
+	if err != nil {
+		return assetSyncError(c.Param("name"), err)
+	}
+	service.PublishInvalidation(service.InvalidationTopicAsset, result.Name)
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionAssetReloaded,
+		Actor:  middleware.GetUserID(c),
+		Target: c.Param("name"),
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("sha=%s", result.SHA),
+	})
+	resp := model.CommonResponseStatusOK(result)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+func assetSyncerFromContext() (*service.AssetSyncer, error) {
+	syncer := service.GetAssetSyncer()
+	if syncer == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Asset sync is not initialized", nil)
+	}
+	return syncer, nil
+}
+
+// assetSyncError service 오류를 HTTP 상태로 변환
+func assetSyncError(name string, err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrAssetSyncUnsupported), stderrors.Is(err, service.ErrRemoteAssetUnknown):
+		return errors.NewBadRequest(fmt.Sprintf("asset %q does not support sync (api, menu)", name))
+	case stderrors.Is(err, service.ErrRemoteAssetNotConfigured):
+		return errors.NewBadRequest(fmt.Sprintf("remote url for asset %q is not configured", name))
+	case stderrors.Is(err, service.ErrAssetSyncInvalid):
+		return errors.NewBadRequestWithError(err.Error(), err)
+	case stderrors.Is(err, service.ErrAssetSyncPlanChanged):
+		return errors.New(http.StatusConflict, "Upstream changed since the plan was made, review the plan again", err)
+	case stderrors.Is(err, service.ErrAssetBackupNotFound):
+		return errors.NewNotFound(fmt.Sprintf("no backup found for asset %q", name))
+	case stderrors.Is(err, service.ErrAssetFetchFailed):
+		return errors.New(http.StatusBadGateway, "Failed to fetch upstream asset", err)
+	default:
+		return errors.NewInternalServerError("Asset sync failed", err)
+	}
+}
@@ -2,16 +2,20 @@ package handler
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"encoding/json"
+	stderrors "errors"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"mc_web_console_api/internal/config"
 	"mc_web_console_api/internal/middleware"
 	"mc_web_console_api/internal/model"
 	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
 	"mc_web_console_api/pkg/errors"
 	"mc_web_console_api/pkg/jwt"
 
@@ -60,6 +64,7 @@ type RefreshRequestWrapper struct {
 // @Param       request body LoginRequest true "Login credentials"
 // @Success     200 {object} model.CommonResponse
 // @Failure     400 {object} model.CommonResponse
+// @Failure     429 {object} model.CommonResponse
 // @Router      /api/auth/login [post]
 func Login(c echo.Context) error {
 	var req LoginRequest
@@ -71,21 +76,49 @@ func Login(c echo.Context) error {
 		return errors.NewBadRequest("ID and password are required")
 	}
 
+	// 계정/IP 단위 로그인 실패 제한 (잠금 또는 점진적 지연 중이면 429)
+	throttle := service.GetLoginThrottle()
+	if err := checkLoginThrottle(c, throttle, req.Request.ID); err != nil {
+		emitLoginFailed(c, req.Request.ID, err)
+		return err
+	}
+
+	var err error
 	cfg, _ := c.Get("config").(*config.Config)
 	if cfg != nil && cfg.MCIAM.Use {
-		return loginViaMCIAM(c, req.Request.ID, req.Request.Password, cfg)
+		err = loginViaMCIAM(c, req.Request.ID, req.Request.Password, cfg)
+	} else {
+		err = loginLocal(c, req.Request.ID, req.Request.Password, cfg)
+	}
+	recordLoginOutcome(c, throttle, req.Request.ID, err)
+	emitLoginFailed(c, req.Request.ID, err)
+	return err
+}
+
+// emitLoginFailed 자격 증명 오류(401), 비활성/미인증 계정(403), 잠금(429) 로그인 결과를 이벤트로 발행
+func emitLoginFailed(c echo.Context, account string, err error) {
+	status := loginResponseStatus(c, err)
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+	default:
+		return
+	}
+	data := model.LoginFailedEventData{Account: account, Status: status}
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		data.Reason = appErr.Message
 	}
-	return loginLocal(c, req.Request.ID, req.Request.Password)
+	emitAuthEvent(c, model.AuthEventLoginFailed, data)
 }
 
 // loginViaMCIAM MCIAM 서버에 로그인 요청을 프록시
 func loginViaMCIAM(c echo.Context, id, password string, cfg *config.Config) error {
-	service, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "login")
+	svc, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "login")
 	if err != nil {
 		return errors.NewInternalServerError("MCIAM login config not found", err)
 	}
 
-	targetURL := service.BaseURL + actionSpec.ResourcePath
+	targetURL := svc.BaseURL + actionSpec.ResourcePath
 
 	body, _ := json.Marshal(map[string]string{
 		"id":       id,
@@ -116,49 +149,114 @@ func loginViaMCIAM(c echo.Context, id, password string, cfg *config.Config) erro
 		return errors.NewInternalServerError("Invalid MCIAM response", err)
 	}
 
-	// DB가 활성화된 경우 세션 저장 (MCIAM 응답에서 토큰 추출 시도)
+	// 로그인 성공 시 MFA 확인 후 세션 저장 (MCIAM 응답에서 토큰 추출 시도)
 	if resp.StatusCode == http.StatusOK {
 		var loginResp LoginResponse
 		if jsonErr := json.Unmarshal(respBody, &loginResp); jsonErr == nil && loginResp.AccessToken != "" && loginResp.UserID != "" {
-			storeSession(loginResp.UserID, loginResp.AccessToken, loginResp.ExpiresIn, loginResp.RefreshToken, loginResp.RefreshExpiresIn)
+			roles := jwt.PeekRoles(loginResp.AccessToken)
+			if loginResp.Role != "" {
+				roles = append(roles, loginResp.Role)
+			}
+			account := loginResp.Email
+			if account == "" {
+				account = loginResp.UserID
+			}
+			// 세션 사용자 키는 AuthMiddleware의 토큰 검증 결과와 같은 클레임에서 가져온다
+			userID := jwt.PeekUserID(loginResp.AccessToken)
+			if userID == "" {
+				userID = loginResp.UserID
+			}
+			return completeLogin(c, cfg, service.PendingLogin{
+				UserID:           userID,
+				LoginID:          id,
+				Account:          account,
+				Roles:            roles,
+				AccessToken:      loginResp.AccessToken,
+				ExpiresIn:        loginResp.ExpiresIn,
+				RefreshToken:     loginResp.RefreshToken,
+				RefreshExpiresIn: loginResp.RefreshExpiresIn,
+				StatusCode:       resp.StatusCode,
+				Payload:          data,
+			})
 		}
 	}
 
 	return c.JSON(resp.StatusCode, data)
 }
 
-// loginLocal DB 비활성 상태에서의 로컬 JWT 발급 (MCIAM_USE=false 용)
-func loginLocal(c echo.Context, id, password string) error {
+// loginLocal 로컬 사용자 저장소 인증 후 JWT 발급 (MCIAM_USE=false 용)
+// DB가 활성화되어 있으면 local_users 테이블의 bcrypt 해시로 검증하고,
+// DB가 없으면 selfiamauthsetting.yaml의 bootstrap 계정만 허용한다.
+func loginLocal(c echo.Context, id, password string, cfg *config.Config) error {
+	var localCfg config.LocalAuthConfig
+	var selfIAM *config.SelfIAMSetting
+	if cfg != nil {
+		localCfg = cfg.LocalAuth
+		selfIAM = cfg.SelfIAM
+	}
+
+	var userID, userName, email, role string
+	if db := repository.GetDB(); db != nil {
+		userService := service.NewLocalUserService(repository.NewUserRepository(db), localCfg)
+		user, err := userService.Authenticate(id, password)
+		if err != nil {
+			return localAuthError(err)
+		}
+		userID, userName, email, role = user.LoginID, user.DisplayName(), user.Email, user.Role
+	} else {
+		if selfIAM == nil || selfIAM.User.ID == "" ||
+			subtle.ConstantTimeCompare([]byte(id), []byte(selfIAM.User.ID)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(selfIAM.User.Password)) != 1 {
+			return errors.NewUnauthorized("Invalid id or password")
+		}
+		u := selfIAM.User
+		userID, userName, email, role = u.ID, strings.TrimSpace(u.FirstName+" "+u.LastName), u.Email, u.Role
+	}
+
+	return issueLocalTokens(c, cfg, id, userID, userName, email, role)
+}
+
+// issueLocalTokens 인증된 로컬 사용자에게 access/refresh JWT 발급 후 MFA 확인 및 세션 저장 (loginID는 로그인 요청에 입력한 ID)
+func issueLocalTokens(c echo.Context, cfg *config.Config, loginID, userID, userName, email, role string) error {
 	accessExpiresIn := time.Duration(3600) * time.Second
 	refreshExpiresIn := time.Duration(604800) * time.Second
 
-	userName := "Demo User"
-	email := id + "@example.com"
-	role := "user"
-
-	accessToken, err := jwt.GenerateToken(id, userName, email, role, accessExpiresIn)
+	accessToken, err := jwt.GenerateToken(userID, userName, email, role, accessExpiresIn)
 	if err != nil {
 		return errors.NewInternalServerError("Failed to generate access token", err)
 	}
-	refreshToken, err := jwt.GenerateToken(id, userName, email, role, refreshExpiresIn)
+	refreshToken, err := jwt.GenerateRefreshToken(userID, userName, email, role, refreshExpiresIn)
 	if err != nil {
 		return errors.NewInternalServerError("Failed to generate refresh token", err)
 	}
 
-	// DB가 활성화된 경우 세션 저장
-	storeSession(id, accessToken, accessExpiresIn.Seconds(), refreshToken, refreshExpiresIn.Seconds())
-
 	resp := model.CommonResponseStatusOK(&LoginResponse{
 		AccessToken:      accessToken,
 		RefreshToken:     refreshToken,
 		ExpiresIn:        accessExpiresIn.Seconds(),
 		RefreshExpiresIn: refreshExpiresIn.Seconds(),
-		UserID:           id,
+		UserID:           userID,
 		UserName:         userName,
 		Email:            email,
 		Role:             role,
 	})
-	return c.JSON(resp.Status.Code, resp)
+
+	account := email
+	if account == "" {
+		account = userID
+	}
+	return completeLogin(c, cfg, service.PendingLogin{
+		UserID:           userID,
+		LoginID:          loginID,
+		Account:          account,
+		Roles:            []string{role},
+		AccessToken:      accessToken,
+		ExpiresIn:        accessExpiresIn.Seconds(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: refreshExpiresIn.Seconds(),
+		StatusCode:       resp.Status.Code,
+		Payload:          resp,
+	})
 }
 
 // Refresh 토큰 갱신 핸들러
@@ -192,6 +290,12 @@ func Refresh(c echo.Context) error {
 		}
 	}
 
+	// 본문에 없으면 HttpOnly refresh 쿠키 사용 (브라우저 클라이언트)
+	if refreshToken == "" {
+		if cookie, cookieErr := c.Cookie(middleware.RefreshTokenCookie); cookieErr == nil {
+			refreshToken = cookie.Value
+		}
+	}
 	if refreshToken == "" {
 		return errors.NewBadRequest("Refresh token is required")
 	}
@@ -201,17 +305,42 @@ func Refresh(c echo.Context) error {
 		return refreshViaMCIAM(c, refreshToken, cfg)
 	}
 
-	// 로컬 모드: refresh token으로 새 access token 발급
-	userID, err := jwt.ExtractUserID(refreshToken)
+	// 로컬 모드: typ=refresh 토큰만 허용하고 새 access token 발급
+	claims, err := jwt.ParseRefreshToken(refreshToken)
 	if err != nil {
 		return errors.NewUnauthorized("Invalid refresh token")
 	}
+	userName, email, role := claims.UserName, claims.Email, claims.Role
+	if db := repository.GetDB(); db != nil {
+		// 로그아웃/비밀번호 변경으로 폐기된 세션의 refresh token은 거부
+		if _, err := repository.NewSessionRepository(db).FindByRefreshToken(refreshToken); err != nil {
+			return errors.NewUnauthorized("Session expired or revoked")
+		}
+		// 비활성화·삭제된 사용자는 갱신할 수 없으며, 현재 이름/역할로 발급한다
+		var localCfg config.LocalAuthConfig
+		if cfg != nil {
+			localCfg = cfg.LocalAuth
+		}
+		user, err := service.NewLocalUserService(repository.NewUserRepository(db), localCfg).ActiveUser(claims.UserID)
+		if err != nil {
+			if stderrors.Is(err, service.ErrInvalidCredentials) || stderrors.Is(err, service.ErrUserDisabled) {
+				return errors.NewUnauthorized("User is disabled or no longer exists")
+			}
+			return localAuthError(err)
+		}
+		userName, email, role = user.DisplayName(), user.Email, user.Role
+	}
 
-	newToken, err := jwt.GenerateToken(userID, "Demo User", userID+"@example.com", "user", time.Duration(3600)*time.Second)
+	newToken, err := jwt.GenerateToken(claims.UserID, userName, email, role, time.Duration(3600)*time.Second)
 	if err != nil {
 		return errors.NewInternalServerError("Failed to generate token", err)
 	}
 
+	// 세션에 새 access token 바인딩 (이전 access token은 더 이상 세션과 일치하지 않음)
+	rotateSessionAccessToken(refreshToken, newToken, float64(3600), "", 0)
+	setAuthCookies(c, newToken, float64(3600), "", time.Until(jwt.PeekExpiry(refreshToken)).Seconds())
+	emitAuthEvent(c, model.AuthEventTokenRefreshed, model.TokenRefreshedEventData{UserID: claims.UserID, Source: "client"})
+
 	resp := model.CommonResponseStatusOK(map[string]interface{}{
 		"access_token": newToken,
 		"expires_in":   float64(3600),
@@ -221,9 +350,33 @@ func Refresh(c echo.Context) error {
 
 // refreshViaMCIAM MCIAM 서버에 토큰 갱신 요청 프록시
 func refreshViaMCIAM(c echo.Context, refreshToken string, cfg *config.Config) error {
+	status, respBody, err := callMCIAMRefresh(cfg, refreshToken)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	json.Unmarshal(respBody, &data)
+
+	// 갱신 성공 시 세션에 새 토큰 바인딩
+	if status == http.StatusOK {
+		var refreshed LoginResponse
+		if jsonErr := json.Unmarshal(respBody, &refreshed); jsonErr == nil && refreshed.AccessToken != "" {
+			rotateSessionAccessToken(refreshToken, refreshed.AccessToken, refreshed.ExpiresIn, refreshed.RefreshToken, refreshed.RefreshExpiresIn)
+			setAuthCookies(c, refreshed.AccessToken, refreshed.ExpiresIn, refreshed.RefreshToken, refreshed.RefreshExpiresIn)
+			emitAuthEvent(c, model.AuthEventTokenRefreshed, model.TokenRefreshedEventData{
+				UserID: jwt.PeekUserID(refreshed.AccessToken),
+				Source: "client",
+			})
+		}
+	}
+	return c.JSON(status, data)
+}
+
+// callMCIAMRefresh mc-iam-manager loginrefresh 호출 후 상태 코드와 응답 본문 반환
+func callMCIAMRefresh(cfg *config.Config, refreshToken string) (int, []byte, error) {
 	service, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "loginrefresh")
 	if err != nil {
-		return errors.NewInternalServerError("MCIAM refresh config not found", err)
+		return 0, nil, errors.NewInternalServerError("MCIAM refresh config not found", err)
 	}
 
 	targetURL := service.BaseURL + actionSpec.ResourcePath
@@ -234,21 +387,19 @@ func refreshViaMCIAM(c echo.Context, refreshToken string, cfg *config.Config) er
 
 	httpReq, err := http.NewRequest(http.MethodPost, targetURL, bytes.NewBuffer(body))
 	if err != nil {
-		return errors.NewInternalServerError("Failed to build MCIAM request", err)
+		return 0, nil, errors.NewInternalServerError("Failed to build MCIAM request", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{}
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return errors.NewInternalServerError("Failed to reach MCIAM server", err)
+		return 0, nil, errors.NewInternalServerError("Failed to reach MCIAM server", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, _ := io.ReadAll(resp.Body)
-	var data interface{}
-	json.Unmarshal(respBody, &data)
-	return c.JSON(resp.StatusCode, data)
+	return resp.StatusCode, respBody, nil
 }
 
 // SignupRequestBody 회원가입 페이로드
@@ -258,6 +409,8 @@ type SignupRequestBody struct {
 	FirstName    string `json:"firstName"`
 	LastName     string `json:"lastName"`
 	Organization string `json:"organization,omitempty"`
+	// InvitationCode 초대 코드 (MC_WEB_CONSOLE_SIGNUP_INVITATION_REQUIRED=true 이면 필수)
+	InvitationCode string `json:"invitationCode,omitempty"`
 }
 
 // SignupRequest Buffalo CommonRequest 호환 래퍼
@@ -268,12 +421,16 @@ type SignupRequest struct {
 // Signup 회원가입 핸들러
 // @Summary     Sign up
 // @Description Register a new user. MCIAM_USE=true proxies to mc-iam-manager.
+// @Description The password policy, email domain lists and invitation code are enforced first;
+// @Description when admin approval is required the request is queued and 202 is returned.
 // @Tags        auth
 // @Accept      json
 // @Produce     json
 // @Param       request body SignupRequest true "Signup payload"
 // @Success     200 {object} model.CommonResponse
+// @Success     202 {object} model.CommonResponse
 // @Failure     400 {object} model.CommonResponse
+// @Failure     409 {object} model.CommonResponse
 // @Failure     503 {object} map[string]interface{}
 // @Router      /api/auth/signup [post]
 func Signup(c echo.Context) error {
@@ -287,21 +444,59 @@ func Signup(c echo.Context) error {
 	}
 
 	cfg, _ := c.Get("config").(*config.Config)
+	var invitation *model.SignupInvitation
+	if cfg != nil {
+		// 비밀번호 정책, 이메일 도메인, 초대 코드 확인 후 승인이 필요하면 대기열에 저장
+		queued, checked, err := enforceSignupPolicy(c, req.Request, cfg)
+		if err != nil || queued {
+			return err
+		}
+		invitation = checked
+	}
 	if cfg != nil && cfg.MCIAM.Use {
-		return signupViaMCIAM(c, req.Request, cfg)
+		err := signupViaMCIAM(c, req.Request, cfg)
+		if invitation != nil && err == nil && c.Response().Status < http.StatusMultipleChoices {
+			consumeSignupInvitation(c, invitation)
+		}
+		return err
 	}
 
-	// 로컬 모드에서는 회원가입 미지원
-	return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
-		"error": "Sign-up is not supported in this environment.",
-	})
+	// 로컬 모드: DB가 없으면 사용자 저장소가 없으므로 회원가입 미지원
+	if repository.GetDB() == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+			"error": "Sign-up is not supported in this environment.",
+		})
+	}
+	err := signupLocal(c, req.Request, cfg)
+	if invitation != nil && err == nil && c.Response().Status < http.StatusMultipleChoices {
+		consumeSignupInvitation(c, invitation)
+	}
+	return err
 }
 
 // signupViaMCIAM mc-iam-manager에 회원가입 요청을 프록시
 func signupViaMCIAM(c echo.Context, req SignupRequestBody, cfg *config.Config) error {
+	status, respBody, err := callMCIAMSignup(cfg, req)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(respBody, &data); err != nil {
+		return errors.NewInternalServerError("Invalid MCIAM response", err)
+	}
+
+	if status < http.StatusMultipleChoices {
+		emitAuthEvent(c, model.AuthEventSignup, model.SignupEventData{Email: req.Email, Status: "created"})
+	}
+	return c.JSON(status, data)
+}
+
+// callMCIAMSignup mc-iam-manager signup 호출 (회원가입 핸들러, 승인 처리 공용)
+func callMCIAMSignup(cfg *config.Config, req SignupRequestBody) (int, []byte, error) {
 	service, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "signup")
 	if err != nil {
-		return errors.NewInternalServerError("MCIAM signup config not found", err)
+		return 0, nil, errors.NewInternalServerError("MCIAM signup config not found", err)
 	}
 
 	targetURL := service.BaseURL + actionSpec.ResourcePath
@@ -320,28 +515,22 @@ func signupViaMCIAM(c echo.Context, req SignupRequestBody, cfg *config.Config) e
 
 	httpReq, err := http.NewRequest(http.MethodPost, targetURL, bytes.NewBuffer(body))
 	if err != nil {
-		return errors.NewInternalServerError("Failed to build MCIAM request", err)
+		return 0, nil, errors.NewInternalServerError("Failed to build MCIAM request", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{}
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return errors.NewInternalServerError("Failed to reach MCIAM server", err)
+		return 0, nil, errors.NewInternalServerError("Failed to reach MCIAM server", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return errors.NewInternalServerError("Failed to read MCIAM response", err)
-	}
-
-	var data interface{}
-	if err := json.Unmarshal(respBody, &data); err != nil {
-		return errors.NewInternalServerError("Invalid MCIAM response", err)
+		return 0, nil, errors.NewInternalServerError("Failed to read MCIAM response", err)
 	}
-
-	return c.JSON(resp.StatusCode, data)
+	return resp.StatusCode, respBody, nil
 }
 
 // Validate 토큰 검증 핸들러
@@ -360,16 +549,17 @@ func Validate(c echo.Context) error {
 	}
 
 	resp := model.CommonResponseStatusOK(map[string]interface{}{
-		"valid":     true,
-		"user_id":   userID,
-		"user_name": middleware.GetUserName(c),
-		"email":     middleware.GetEmail(c),
-		"role":      middleware.GetRole(c),
+		"valid":         true,
+		"user_id":       userID,
+		"user_name":     middleware.GetUserName(c),
+		"email":         middleware.GetEmail(c),
+		"role":          middleware.GetRole(c),
+		"impersonation": impersonationInfo(c),
 	})
 	return c.JSON(resp.Status.Code, resp)
 }
 
-// storeSession DB가 활성화된 경우 세션을 저장한다. 기존 세션은 교체된다.
+// storeSession DB가 활성화된 경우 세션을 저장한다. 로그인마다 세션이 하나씩 생기며 다른 기기의 세션은 유지된다.
 func storeSession(userID, accessToken string, expiresIn float64, refreshToken string, refreshExpiresIn float64) {
 	db := repository.GetDB()
 	if db == nil {
@@ -383,14 +573,35 @@ func storeSession(userID, accessToken string, expiresIn float64, refreshToken st
 		RefreshToken:     refreshToken,
 		RefreshExpiresIn: refreshExpiresIn,
 	}
-	if err := sessionRepo.Delete(userID); err != nil {
-		log.Printf("storeSession: delete old session error (userID=%s): %v", userID, err)
-	}
 	if err := sessionRepo.Create(session); err != nil {
 		log.Printf("storeSession: create session error (userID=%s): %v", userID, err)
 	}
 }
 
+// rotateSessionAccessToken 토큰 갱신 후 refresh token으로 세션을 찾아 새 access token을 바인딩한다.
+// newRefreshToken이 비어 있으면 기존 refresh token과 세션 만료 시각을 유지하고,
+// 새 refresh token이 발급되면 세션 만료 시각을 연장한다. DB 비활성 시 무시.
+func rotateSessionAccessToken(refreshToken, newAccessToken string, expiresIn float64, newRefreshToken string, refreshExpiresIn float64) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+	sessionRepo := repository.NewSessionRepository(db)
+	session, err := sessionRepo.FindByRefreshToken(refreshToken)
+	if err != nil {
+		log.Printf("rotateSessionAccessToken: session not found for refresh token: %v", err)
+		return
+	}
+	if newRefreshToken == "" {
+		newRefreshToken = session.RefreshToken
+		refreshExpiresIn = 0
+	}
+	service.GetSessionCache().InvalidateUser(session.UserID)
+	if err := sessionRepo.UpdateTokens(session.ID, newAccessToken, newRefreshToken, expiresIn, refreshExpiresIn); err != nil {
+		log.Printf("rotateSessionAccessToken: update error (userID=%s): %v", session.UserID, err)
+	}
+}
+
 // Logout 로그아웃 핸들러
 // @Summary     Logout
 // @Description Invalidate session and remove stored tokens
@@ -406,13 +617,18 @@ func Logout(c echo.Context) error {
 		return errors.NewUnauthorized("Not authenticated")
 	}
 
-	// DB 세션 삭제
+	// 요청 토큰이 바인딩된 DB 세션만 폐기 (폐기된 세션에 바인딩된 토큰은 AuthMiddleware에서 거부됨)
 	if db := repository.GetDB(); db != nil {
-		sessionRepo := repository.NewSessionRepository(db)
-		if err := sessionRepo.Delete(userID); err != nil {
-			log.Printf("Logout: delete session error (userID=%s): %v", userID, err)
+		sessionService := service.NewSessionService(repository.NewSessionRepository(db))
+		if err := sessionService.RevokeAccessTokenSession(userID, middleware.RequestToken(c)); err != nil {
+			log.Printf("Logout: revoke session error (userID=%s): %v", userID, err)
 		}
 	}
+	service.GetScopeCache().InvalidateUser(userID)
+	service.GetTicketCache().InvalidateUser(userID)
+	service.GetRefreshCoordinator().Forget(userID)
+	clearAuthCookies(c)
+	emitAuthEvent(c, model.AuthEventLogout, model.LogoutEventData{UserID: userID})
 
 	resp := model.CommonResponseStatusOK(map[string]interface{}{
 		"message": "Logged out successfully",
@@ -436,10 +652,11 @@ func UserInfo(c echo.Context) error {
 	}
 
 	resp := model.CommonResponseStatusOK(map[string]interface{}{
-		"user_id":   userID,
-		"user_name": middleware.GetUserName(c),
-		"email":     middleware.GetEmail(c),
-		"role":      middleware.GetRole(c),
+		"user_id":       userID,
+		"user_name":     middleware.GetUserName(c),
+		"email":         middleware.GetEmail(c),
+		"role":          middleware.GetRole(c),
+		"impersonation": impersonationInfo(c),
 	})
 	return c.JSON(resp.Status.Code, resp)
 }
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 인증 쿠키 발급/삭제.
+// 로그인·갱신 시 access/refresh 토큰을 HttpOnly 쿠키로 내려주고, CSRF double-submit용 mcwc_csrf 쿠키(JS 읽기 가능)를 함께 발급한다.
+// 응답 본문의 토큰은 기존 클라이언트 호환을 위해 유지하며, API 클라이언트는 Authorization 헤더를 계속 사용할 수 있다.
+
+// finishLogin 세션 저장, 인증 쿠키 발급, 계정 로그인 실패 카운터 초기화 후 로그인 응답 반환 (MFA 대상이 아니거나 MFA 확인 완료 시)
+func finishLogin(c echo.Context, login service.PendingLogin) error {
+	storeSession(login.UserID, login.AccessToken, login.ExpiresIn, login.RefreshToken, login.RefreshExpiresIn)
+	if login.StatusCode == http.StatusOK && login.AccessToken != "" {
+		setAuthCookies(c, login.AccessToken, login.ExpiresIn, login.RefreshToken, login.RefreshExpiresIn)
+		recordLoginSuccess(login.LoginID)
+		emitAuthEvent(c, model.AuthEventLoginSucceeded, model.LoginSucceededEventData{
+			UserID:  login.UserID,
+			Account: login.Account,
+			MFA:     login.MFA,
+		})
+	}
+	return c.JSON(login.StatusCode, login.Payload)
+}
+
+// setAuthCookies access/refresh 토큰 쿠키와 CSRF 쿠키 설정. refreshToken이 비어 있으면 기존 refresh 쿠키를 유지한다.
+// access 쿠키는 refresh 토큰 수명만큼 유지해 만료된 access token도 401 → 갱신 흐름을 탈 수 있게 한다.
+func setAuthCookies(c echo.Context, accessToken string, expiresIn float64, refreshToken string, refreshExpiresIn float64) {
+	cfg := cookieConfig(c)
+	setCookie(c, cfg, middleware.AccessTokenCookie, accessToken, cookieMaxAge(refreshExpiresIn, expiresIn), true)
+	if refreshToken != "" {
+		setCookie(c, cfg, middleware.RefreshTokenCookie, refreshToken, cookieMaxAge(refreshExpiresIn, 0), true)
+	}
+	if existing, err := c.Cookie(middleware.CSRFCookie); err != nil || existing.Value == "" || refreshToken != "" {
+		setCookie(c, cfg, middleware.CSRFCookie, newCSRFToken(), cookieMaxAge(refreshExpiresIn, 0), false)
+	}
+}
+
+// clearAuthCookies 로그아웃 시 인증/CSRF/대리 쿠키 삭제
+func clearAuthCookies(c echo.Context) {
+	cfg := cookieConfig(c)
+	for _, name := range []string{middleware.AccessTokenCookie, middleware.RefreshTokenCookie, middleware.ImpersonatorCookie} {
+		setCookie(c, cfg, name, "", -1, true)
+	}
+	setCookie(c, cfg, middleware.CSRFCookie, "", -1, false)
+}
+
+func setCookie(c echo.Context, cfg config.CookieConfig, name, value string, maxAge int, httpOnly bool) {
+	c.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		HttpOnly: httpOnly,
+		Secure:   cookieSecure(c, cfg),
+		SameSite: cfg.SameSite,
+	})
+}
+
+// cookieSecure Secure 속성 결정. auto면 HTTPS 요청(X-Forwarded-Proto 포함)일 때만 설정한다.
+func cookieSecure(c echo.Context, cfg config.CookieConfig) bool {
+	switch cfg.Secure {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return c.Scheme() == "https"
+	}
+}
+
+func cookieConfig(c echo.Context) config.CookieConfig {
+	if cfg, _ := c.Get("config").(*config.Config); cfg != nil {
+		return cfg.Cookie
+	}
+	return config.CookieConfig{Secure: "auto", SameSite: http.SameSiteLaxMode}
+}
+
+// cookieMaxAge 초 단위 유효 기간을 쿠키 MaxAge로 변환 (0이면 fallback, 둘 다 0이면 세션 쿠키)
+func cookieMaxAge(seconds, fallback float64) int {
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return int((time.Duration(seconds) * time.Second).Seconds())
+}
+
+func newCSRFToken() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
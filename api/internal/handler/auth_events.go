@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 인증 이벤트 webhook 발행과 dead-letter 관리 핸들러.
+// 이벤트 envelope/data 스키마는 model.AuthEvent와 *EventData 구조체를 따른다.
+
+// deadLetterListLimit dead-letter 목록 최대 건수
+const deadLetterListLimit = 200
+
+// emitAuthEvent 요청 정보를 채워 인증 이벤트 발행 (webhook 미설정이면 무시)
+func emitAuthEvent(c echo.Context, eventType string, data interface{}) {
+	service.EmitAuthEvent(model.AuthEvent{
+		Type:      eventType,
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+		RequestID: middleware.GetRequestID(c),
+		Data:      data,
+	})
+}
+
+// ListWebhookDeadLetters webhook dead-letter 목록 핸들러
+// @Summary     List webhook dead letters
+// @Description Auth event deliveries that exceeded MC_WEB_CONSOLE_WEBHOOK_MAX_ATTEMPTS
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=[]model.WebhookDeadLetter}
+// @Failure     503 {object} model.CommonResponse
+// @Router      /api/admin/webhooks/dead-letters [get]
+func ListWebhookDeadLetters(c echo.Context) error {
+	dispatcher, err := webhookDispatcher()
+	if err != nil {
+		return err
+	}
+	list, err := dispatcher.DeadLetters(deadLetterListLimit)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list webhook dead letters", err)
+	}
+	resp := model.CommonResponseStatusOK(list)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RetryWebhookDeadLetter dead-letter 재전송 핸들러
+// @Summary     Retry webhook dead letter
+// @Description Move a dead letter back to the delivery queue with a fresh attempt count
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path int true "Dead letter ID"
+// @Success     200 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/webhooks/dead-letters/{id}/retry [post]
+func RetryWebhookDeadLetter(c echo.Context) error {
+	dispatcher, err := webhookDispatcher()
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewBadRequest("Invalid dead letter id")
+	}
+	ok, err := dispatcher.RequeueDeadLetter(uint(id))
+	if err != nil {
+		return errors.NewInternalServerError("Failed to requeue dead letter", err)
+	}
+	if !ok {
+		return errors.NewNotFound("Dead letter not found")
+	}
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"requeued": id})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+func webhookDispatcher() (*service.WebhookDispatcher, error) {
+	dispatcher := service.GetWebhookDispatcher()
+	if dispatcher == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Webhooks are not configured (MC_WEB_CONSOLE_WEBHOOK_URLS)", nil)
+	}
+	return dispatcher, nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strings"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ExplainAuthz 인가 판정 설명 핸들러.
+// role을 지정하지 않으면 호출자 역할로 판정하며, method를 생략하면 api.yaml의 액션 메서드를 사용한다.
+// @Summary     Explain authorization decision
+// @Description Evaluate webconsole_api_permissions.csv for roles and an operation, and show which rule decided
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       subsystem query string true "Subsystem (framework) name"
+// @Param       operation query string true "Operation ID (admin:<path> for BFF admin routes)"
+// @Param       method query string false "Backend method (default: api.yaml action method)"
+// @Param       role query []string false "Roles to evaluate (default: caller roles)"
+// @Success     200 {object} model.CommonResponse{responseData=service.AuthzDecision}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     403 {object} model.CommonResponse
+// @Router      /api/admin/authz/explain [get]
+func ExplainAuthz(c echo.Context) error {
+	authorizer := service.GetAuthorizer()
+	if authorizer == nil {
+		return errors.NewBadRequest("Authorization is disabled (MC_WEB_CONSOLE_AUTHZ=false)")
+	}
+
+	subsystem := c.QueryParam("subsystem")
+	operation := c.QueryParam("operation")
+	if subsystem == "" || operation == "" {
+		return errors.NewBadRequest("subsystem and operation are required")
+	}
+
+	method := c.QueryParam("method")
+	if method == "" {
+		method = c.Request().Method
+		if cfg, _ := c.Get("config").(*config.Config); cfg != nil {
+			if _, actionSpec, err := cfg.ApiSpec.GetAction(subsystem, operation); err == nil {
+				method = actionSpec.Method
+			}
+		}
+	}
+
+	roles := c.QueryParams()["role"]
+	if len(roles) == 0 {
+		roles = middleware.GetRoles(c)
+	}
+
+	decision := authorizer.Decide(roles, subsystem, operation, strings.ToUpper(method))
+	resp := model.CommonResponseStatusOK(decision)
+	return c.JSON(resp.Status.Code, resp)
+}
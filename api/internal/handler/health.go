@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BackendHealthResult 백엔드 상태 화면 응답 데이터
+type BackendHealthResult struct {
+	Summary  service.HealthSummary   `json:"summary"`
+	Backends []service.BackendHealth `json:"backends"`
+}
+
+// GetBackendHealth 백엔드 서비스 상태 조회 핸들러
+// @Summary     Backend health
+// @Description Periodic probe results of every api.yaml service (status, latency, up/down history, TLS certificate expiry)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=BackendHealthResult}
+// @Failure     503 {object} model.CommonResponse
+// @Router      /api/admin/health [get]
+func GetBackendHealth(c echo.Context) error {
+	monitor, err := healthMonitor()
+	if err != nil {
+		return err
+	}
+	resp := model.CommonResponseStatusOK(BackendHealthResult{Summary: monitor.Summary(), Backends: monitor.Backends()})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// CheckBackendHealth 백엔드 서비스 즉시 점검 핸들러
+// @Summary     Check backend health now
+// @Description Probe every service immediately and return the updated results
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=BackendHealthResult}
+// @Failure     503 {object} model.CommonResponse
+// @Router      /api/admin/health/check [post]
+func CheckBackendHealth(c echo.Context) error {
+	monitor, err := healthMonitor()
+	if err != nil {
+		return err
+	}
+	monitor.RunOnce(c.Request().Context())
+	resp := model.CommonResponseStatusOK(BackendHealthResult{Summary: monitor.Summary(), Backends: monitor.Backends()})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+func healthMonitor() (*service.HealthMonitor, error) {
+	monitor := service.GetHealthMonitor()
+	if monitor == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Backend health monitor is disabled (MC_WEB_CONSOLE_HEALTH_INTERVAL=0)", nil)
+	}
+	return monitor, nil
+}
@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 관리자 대리(impersonation) 핸들러.
+// 대리 토큰은 BFF가 서명한 단기 JWT로 act 클레임에 관리자와 관리자 세션을 담는다.
+// BFF는 대상 사용자의 역할/범위로 인가하고, 백엔드 호출에는 관리자 세션의 토큰을 사용한다 (만료 시 세션에서 갱신).
+// 호출자 토큰으로 대상이 정해지는 mc-iam-manager 액션은 대상 사용자 ID 지정 액션으로 바꾸거나 거부하고,
+// 메뉴는 대상 사용자 역할로 메뉴 권한 선언에서 계산한다.
+
+// ImpersonateRequest 대리 토큰 발급 요청
+type ImpersonateRequest struct {
+	Request struct {
+		UserID     string `json:"user_id"`
+		AllowWrite bool   `json:"allow_write"` // MC_WEB_CONSOLE_IMPERSONATION_ALLOW_WRITE=true일 때만 허용
+	} `json:"request"`
+}
+
+// Impersonate 대상 사용자로 동작하는 대리 토큰 발급 핸들러
+// @Summary     Impersonate user
+// @Description Issue a short-lived token acting as another user (act claim). Read-only unless write impersonation is enabled.
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body ImpersonateRequest true "Target user"
+// @Success     200 {object} model.CommonResponse{responseData=service.ImpersonationGrant}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     403 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/impersonate [post]
+func Impersonate(c echo.Context) error {
+	var req ImpersonateRequest
+	if err := c.Bind(&req); err != nil || strings.TrimSpace(req.Request.UserID) == "" {
+		return errors.NewBadRequest("user_id is required")
+	}
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil {
+		return errors.NewInternalServerError("config not available", nil)
+	}
+	if repository.GetDB() == nil {
+		return errors.New(http.StatusServiceUnavailable, "Impersonation requires database", nil)
+	}
+
+	actorToken := middleware.RequestToken(c)
+	actorSession, err := service.NewSessionService(repository.NewSessionRepository(repository.GetDB())).GetSessionByTokenHash(jwt.TokenHash(actorToken))
+	if err != nil {
+		return errors.NewForbidden("Impersonator session not found")
+	}
+	authHeader := "Bearer " + actorToken
+	identifier := strings.TrimSpace(req.Request.UserID)
+	var target *service.ImpersonationTarget
+	if cfg.MCIAM.Use {
+		target, err = mciamImpersonationTarget(cfg, authHeader, identifier)
+	} else {
+		target, err = service.LocalImpersonationTarget(identifier)
+	}
+	if err != nil {
+		return impersonationError(err)
+	}
+
+	grant, err := service.IssueImpersonationToken(cfg.Impersonation, middleware.GetUserID(c), middleware.GetRoles(c),
+		actorToken, actorSession.ID, *target, req.Request.AllowWrite)
+	if err != nil {
+		return impersonationError(err)
+	}
+	// 브라우저: 대리 토큰을 인증 쿠키로, 관리자 토큰은 종료 시 복원용 HttpOnly 쿠키로 보관
+	cookieCfg := cookieConfig(c)
+	setCookie(c, cookieCfg, middleware.ImpersonatorCookie, actorToken, cookieMaxAge(grant.ExpiresIn, 0), true)
+	setCookie(c, cookieCfg, middleware.AccessTokenCookie, grant.AccessToken, cookieMaxAge(grant.ExpiresIn, 0), true)
+
+	resp := model.CommonResponseStatusOK(grant)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// StopImpersonation 대리 종료 후 관리자 토큰 쿠키 복원 핸들러
+// @Summary     Stop impersonation
+// @Description Restore the administrator's Authorization cookie saved when impersonation started
+// @Tags        auth
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Failure     403 {object} model.CommonResponse
+// @Router      /api/auth/impersonation/stop [post]
+func StopImpersonation(c echo.Context) error {
+	if !middleware.IsImpersonated(c) {
+		return errors.NewBadRequest("Not impersonating")
+	}
+	cookie, err := c.Cookie(middleware.ImpersonatorCookie)
+	if err != nil || cookie.Value == "" {
+		return errors.NewForbidden("Impersonator session not found")
+	}
+	// 복원할 토큰이 대리 토큰을 발급한 관리자 세션의 토큰인지 확인
+	sessionHash, _ := c.Get("impersonatorSessionHash").(string)
+	if jwt.TokenHash(cookie.Value) != sessionHash {
+		return errors.NewForbidden("Impersonator session mismatch")
+	}
+
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionImpersonationStopped,
+		Actor:  middleware.GetImpersonator(c),
+		Target: middleware.GetUserID(c),
+		IP:     c.RealIP(),
+	})
+
+	cookieCfg := cookieConfig(c)
+	// 대리 중 관리자 토큰이 갱신되었으면 쿠키에 보관한 토큰 대신 세션의 현재 토큰을 복원한다
+	if session, err := impersonatorSession(c); err == nil && session.AccessToken != cookie.Value {
+		setAuthCookies(c, session.AccessToken, secondsUntil(jwt.PeekExpiry(session.AccessToken)),
+			session.RefreshToken, secondsUntil(session.RefreshExpiry()))
+	} else {
+		setCookie(c, cookieCfg, middleware.AccessTokenCookie, cookie.Value, cookieMaxAge(secondsUntil(jwt.PeekExpiry(cookie.Value)), 0), true)
+	}
+	setCookie(c, cookieCfg, middleware.ImpersonatorCookie, "", -1, true)
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{
+		"actor": middleware.GetImpersonator(c),
+	})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// impersonationInfo UserInfo/Validate 응답에 표시할 대리 정보 (대리 토큰이 아니면 nil)
+func impersonationInfo(c echo.Context) map[string]interface{} {
+	if !middleware.IsImpersonated(c) {
+		return nil
+	}
+	return map[string]interface{}{
+		"actor":     middleware.GetImpersonator(c),
+		"read_only": middleware.IsImpersonationReadOnly(c),
+	}
+}
+
+// backendUserAuthHeader 백엔드(mc-iam-manager 등)에 사용자 자격으로 호출할 때 쓸 Authorization 값.
+// 대리 요청이면 관리자 세션 토큰을, 아니면 요청의 Authorization을 반환한다 (액세스 토큰이면 빈 값).
+func backendUserAuthHeader(c echo.Context) string {
+	if middleware.IsImpersonated(c) {
+		token, err := actorBackendToken(c, false)
+		if err != nil {
+			log.Printf("[Impersonation] actor session token unavailable (actor=%s): %v", middleware.GetImpersonator(c), err)
+			return ""
+		}
+		return "Bearer " + token
+	}
+	authHeader := c.Request().Header.Get("Authorization")
+	if authHeader == "" {
+		authHeader, _ = c.Get("Authorization").(string)
+	}
+	if authHeader == "" {
+		if token := middleware.RequestToken(c); token != "" {
+			authHeader = "Bearer " + token
+		}
+	}
+	// 액세스 토큰은 BFF 전용 자격 증명이므로 백엔드로 전달하지 않는다
+	if isAccessTokenHeader(authHeader) {
+		return ""
+	}
+	return authHeader
+}
+
+// actorTokenRefreshSkew 만료 직전의 관리자 토큰도 백엔드 호출 전에 갱신한다
+const actorTokenRefreshSkew = 30 * time.Second
+
+// impersonatorSession 대리 토큰을 발급한 관리자 세션
+func impersonatorSession(c echo.Context) (*model.UserSession, error) {
+	sessionID, _ := c.Get("impersonatorSessionId").(string)
+	sessionHash, _ := c.Get("impersonatorSessionHash").(string)
+	return service.ActorSession(middleware.GetImpersonator(c), sessionID, sessionHash)
+}
+
+// actorBackendToken 대리 요청의 백엔드 호출에 쓸 관리자 세션의 access token.
+// MCIAM 모드에서 토큰이 만료(임박)되었거나 force(백엔드 401)이면 세션의 refresh token으로 갱신해 세션에 저장한다.
+// 관리자 브라우저의 인증 쿠키는 대리 토큰이므로 쿠키는 바꾸지 않는다 (대리 종료 시 세션의 현재 토큰을 복원).
+func actorBackendToken(c echo.Context, force bool) (string, error) {
+	session, err := impersonatorSession(c)
+	if err != nil {
+		return "", err
+	}
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil || !cfg.MCIAM.Use {
+		return session.AccessToken, nil
+	}
+	if expiry := jwt.PeekExpiry(session.AccessToken); !force && (expiry.IsZero() || time.Until(expiry) > actorTokenRefreshSkew) {
+		return session.AccessToken, nil
+	}
+	refreshed, err := service.GetRefreshCoordinator().Refresh(session.UserID, session.AccessToken, func() (*service.RefreshedTokens, error) {
+		return callMCIAMRefreshAndRotate(cfg, c, session.UserID, session.RefreshToken, "impersonation")
+	})
+	if err != nil {
+		return "", fmt.Errorf("actor token refresh failed: %w", err)
+	}
+	return refreshed.AccessToken, nil
+}
+
+// impersonatedAction 대리 요청이 호출자 기준 mc-iam-manager 액션이면 대상 사용자 ID를 지정하는 액션의 ActionSpec을 반환한다.
+// 바꿀 액션이 없으면 403을 응답하고 false를 반환한다. 대리 요청이 아니거나 바꿀 필요가 없으면 (nil, true, nil).
+func impersonatedAction(c echo.Context, cfg *config.Config, subsystem, operation string) (*config.ActionSpec, bool, error) {
+	if !middleware.IsImpersonated(c) {
+		return nil, true, nil
+	}
+	replacement, err := service.ImpersonationBackendAction(subsystem, operation)
+	if err == nil && replacement == operation {
+		return nil, true, nil
+	}
+	iamUserID, _ := c.Get("iamUserId").(string)
+	var spec *config.ActionSpec
+	switch {
+	case err != nil:
+	case iamUserID == "":
+		err = fmt.Errorf("impersonation token has no MCIAM user id for %s", replacement)
+	default:
+		if _, spec, err = cfg.ApiSpec.GetAction(subsystem, replacement); err != nil {
+			err = fmt.Errorf("%s not found in api.yaml", replacement)
+		}
+	}
+	if err != nil {
+		log.Printf("[Impersonation] denied actor=%s user=%s %s/%s: %v", middleware.GetImpersonator(c), middleware.GetUserID(c), subsystem, operation, err)
+		resp := model.NewCommonResponse(http.StatusForbidden, "Forbidden: "+err.Error(),
+			map[string]interface{}{"subsystem": subsystem, "operation": operation})
+		return nil, false, c.JSON(resp.Status.Code, resp)
+	}
+	return spec, true, nil
+}
+
+// targetImpersonatedUser 바뀐 액션의 {userId}를 대상 사용자의 MCIAM ID로 지정한다 (조회 액션이면 요청 바디 제거)
+func targetImpersonatedUser(c echo.Context, req *model.CommonRequest, spec *config.ActionSpec) {
+	if req.PathParams == nil {
+		req.PathParams = map[string]string{}
+	}
+	req.PathParams["userId"], _ = c.Get("iamUserId").(string)
+	if service.IsReadOnlyMethod(spec.Method) {
+		req.Request = nil
+	}
+}
+
+// secondsUntil t까지 남은 초 (지났거나 zero면 0)
+func secondsUntil(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	if d := time.Until(t); d > 0 {
+		return d.Seconds()
+	}
+	return 0
+}
+
+// mciamImpersonationTarget mc-iam-manager Getuserbyname으로 대상 사용자 조회 (관리자 토큰 사용)
+func mciamImpersonationTarget(cfg *config.Config, authHeader, username string) (*service.ImpersonationTarget, error) {
+	svc, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", "Getuserbyname")
+	if err != nil {
+		return nil, fmt.Errorf("Getuserbyname not found in api.yaml: %w", err)
+	}
+	path := strings.ReplaceAll(actionSpec.ResourcePath, "{username}", url.PathEscape(username))
+	httpReq, err := http.NewRequest(strings.ToUpper(actionSpec.Method), svc.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", authHeader)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Getuserbyname call failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, service.ErrImpersonationTarget
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Getuserbyname returned %d", resp.StatusCode)
+	}
+
+	var user map[string]interface{}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("Getuserbyname response parse failed: %w", err)
+	}
+	if inner, ok := user["responseData"].(map[string]interface{}); ok {
+		user = inner
+	}
+	str := func(key string) string {
+		v, _ := user[key].(string)
+		return v
+	}
+	if str("username") == "" {
+		return nil, service.ErrImpersonationTarget
+	}
+
+	target := &service.ImpersonationTarget{
+		UserID:   str("username"),
+		UserName: strings.TrimSpace(str("firstName") + " " + str("lastName")),
+		Email:    str("email"),
+	}
+	if id, ok := user["id"]; ok && id != nil {
+		target.IAMUserID = fmt.Sprint(id)
+	}
+	for _, key := range []string{"platformRoles", "platform_roles", "roles"} {
+		if roles := roleNames(user[key]); len(roles) > 0 {
+			target.Role = roles[0]
+			break
+		}
+	}
+	if target.Role == "" {
+		// 역할을 알 수 없으면 인가에서 모든 정책이 거부되도록 빈 역할로 둔다
+		log.Printf("[Impersonation] no platform role found for %s; impersonated calls will be denied by policy", username)
+	}
+	return target, nil
+}
+
+// roleNames 문자열 배열 또는 {name: ...} 객체 배열에서 역할 이름 추출
+func roleNames(v interface{}) []string {
+	items, _ := v.([]interface{})
+	var names []string
+	for _, item := range items {
+		switch r := item.(type) {
+		case string:
+			names = append(names, r)
+		case map[string]interface{}:
+			if name, ok := r["name"].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// impersonationError 대리 서비스 에러를 HTTP 에러로 변환
+func impersonationError(err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrImpersonationTarget):
+		return errors.NewNotFound("Impersonation target not found")
+	case stderrors.Is(err, service.ErrImpersonationDisabled),
+		stderrors.Is(err, service.ErrImpersonationForbidden),
+		stderrors.Is(err, service.ErrImpersonationWrite):
+		return errors.NewForbidden(err.Error())
+	case stderrors.Is(err, service.ErrImpersonationSelf):
+		return errors.NewBadRequest(err.Error())
+	default:
+		return errors.NewInternalServerError("Impersonation failed", err)
+	}
+}
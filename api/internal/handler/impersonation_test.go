@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/repository/repotest"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// impersonationBackend mc-iam-manager 대역: loginrefresh는 refresh-1만, 나머지는 accessToken만 허용하고 호출을 기록한다
+type impersonationBackend struct {
+	mu    sync.Mutex
+	calls []string // "METHOD escapedPath Authorization"
+}
+
+func (b *impersonationBackend) recorded() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.calls...)
+}
+
+func newImpersonationTestConfig(t *testing.T, accessToken string) (*config.Config, *impersonationBackend) {
+	t.Helper()
+	backend := &impersonationBackend{}
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/auth/login/refresh" {
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["refresh_token"] != "refresh-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": accessToken, "expires_in": 300, "refresh_token": "refresh-2", "refresh_expires_in": 1800,
+			})
+			return
+		}
+		backend.mu.Lock()
+		backend.calls = append(backend.calls, r.Method+" "+r.URL.EscapedPath()+" "+r.Header.Get("Authorization"))
+		backend.mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"username":"user/01","id":42}`))
+	}))
+	t.Cleanup(iam.Close)
+
+	cfg := &config.Config{
+		ApiSpec: &config.ApiSpec{
+			Services: map[string]config.Service{
+				"mc-iam-manager": {BaseURL: iam.URL, Auth: config.AuthConfig{Type: "bearer"}},
+			},
+			ServiceActions: map[string]map[string]config.ActionSpec{
+				"mc-iam-manager": {
+					"loginrefresh":              {Method: "post", ResourcePath: "/api/auth/login/refresh"},
+					"listUserWorkspaces":        {Method: "post", ResourcePath: "/api/users/workspaces/list"},
+					"Getuserworkspacesbyuserid": {Method: "get", ResourcePath: "/api/users/id/{userId}/workspaces/list"},
+					"Getallavailablemenus":      {Method: "post", ResourcePath: "/api/users/menus/list"},
+					"Getuserbyname":             {Method: "get", ResourcePath: "/api/users/name/{username}"},
+				},
+			},
+		},
+	}
+	cfg.MCIAM.Use = true
+	return cfg, backend
+}
+
+// useImpersonatorSession 관리자 세션 session-1(admin01) 하나만 있는 가짜 DB를 repository.DB로 설정한다
+func useImpersonatorSession(t *testing.T, accessToken string) *repotest.DB {
+	t.Helper()
+	db, fake := repotest.Open(t)
+	refreshExpiresAt := time.Now().Add(time.Hour)
+	fake.Handle(`FROM "usersesses"`, func([]driver.Value) repotest.Result {
+		return repotest.Result{
+			Columns: []string{"id", "user_id", "access_token", "refresh_token", "access_token_hash", "refresh_expires_at", "created_at"},
+			Rows: [][]driver.Value{{"session-1", "admin01", accessToken, "refresh-1", jwt.TokenHash(accessToken),
+				refreshExpiresAt, time.Now().Add(-time.Minute)}},
+		}
+	})
+	previous := repository.DB
+	repository.DB = db
+	t.Cleanup(func() { repository.DB = previous })
+	return fake
+}
+
+func newImpersonatedProxyContext(t *testing.T, cfg *config.Config, operationID string) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	token, err := jwt.GenerateImpersonationToken(jwt.Claims{UserID: "user01", Role: "viewer", IAMUserID: "42"},
+		jwt.Actor{Subject: "admin01", SessionID: "session-1", ReadOnly: true}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/mc-iam-manager/"+operationID,
+		strings.NewReader(`{"pathParams":{"userId":"admin01"},"request":{"workspaceId":"ws1"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("subsystemName", "operationId")
+	c.SetParamValues("mc-iam-manager", operationID)
+	c.Set("config", cfg)
+	return c, rec
+}
+
+func captureAuditLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestImpersonatedProxyActsOnTargetUser(t *testing.T) {
+	adminToken, err := jwt.GenerateToken("admin01", "Admin", "admin@example.com", "admin", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, backend := newImpersonationTestConfig(t, adminToken)
+	useImpersonatorSession(t, adminToken)
+
+	t.Run("caller-scoped action targets the impersonated user", func(t *testing.T) {
+		logs := captureAuditLog(t)
+		c, rec := newImpersonatedProxyContext(t, cfg, "listUserWorkspaces")
+		if err := middleware.AuthMiddleware(SubsystemAnyController)(c); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+		}
+		want := "GET /api/users/id/42/workspaces/list Bearer " + adminToken
+		if calls := backend.recorded(); len(calls) != 1 || calls[0] != want {
+			t.Fatalf("backend calls = %v, want [%s]", calls, want)
+		}
+		if !strings.Contains(logs.String(), "action="+model.AuditActionImpersonatedCall) {
+			t.Fatalf("audit log = %q, want an impersonation.call entry", logs.String())
+		}
+	})
+
+	t.Run("action without a per-user equivalent is refused", func(t *testing.T) {
+		before := len(backend.recorded())
+		c, rec := newImpersonatedProxyContext(t, cfg, "Getallavailablemenus")
+		if err := middleware.AuthMiddleware(SubsystemAnyController)(c); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusForbidden || len(backend.recorded()) != before {
+			t.Fatalf("status = %d, backend calls = %d, want 403 without a backend call", rec.Code, len(backend.recorded())-before)
+		}
+	})
+}
+
+func TestImpersonatedProxyRefreshesActorToken(t *testing.T) {
+	stale, err := jwt.GenerateToken("admin01", "Admin", "admin@example.com", "admin", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := jwt.GenerateToken("admin01", "Admin", "admin@example.com", "admin", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, backend := newImpersonationTestConfig(t, fresh)
+	fake := useImpersonatorSession(t, stale)
+
+	// 관리자 토큰이 만료되어도 대리 토큰은 세션 ID로 확인되고, 백엔드에는 세션에서 갱신한 관리자 토큰이 전달된다
+	c, rec := newImpersonatedProxyContext(t, cfg, "listUserWorkspaces")
+	if err := middleware.AuthMiddleware(SubsystemAnyController)(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	if calls := backend.recorded(); len(calls) != 1 || !strings.HasSuffix(calls[0], "Bearer "+fresh) {
+		t.Fatalf("backend calls = %v, want one call with the refreshed admin token", calls)
+	}
+	if len(fake.Statements(`UPDATE "usersesses"`)) != 1 {
+		t.Fatalf("session updates = %d, want the refreshed token stored in the admin session", len(fake.Statements(`UPDATE "usersesses"`)))
+	}
+	// 브라우저의 인증 쿠키는 대리 토큰이므로 그대로 둔다
+	if cookies := rec.Header().Values("Set-Cookie"); len(cookies) != 0 {
+		t.Fatalf("Set-Cookie = %v, want no cookie changes while impersonating", cookies)
+	}
+}
+
+func TestMCIAMImpersonationTargetEscapesUsername(t *testing.T) {
+	cfg, backend := newImpersonationTestConfig(t, "admin-access")
+
+	target, err := mciamImpersonationTarget(cfg, "Bearer admin-access", "user/01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "GET /api/users/name/user%2F01 Bearer admin-access"
+	if calls := backend.recorded(); len(calls) != 1 || calls[0] != want {
+		t.Fatalf("backend calls = %v, want [%s]", calls, want)
+	}
+	if target.UserID != "user/01" || target.IAMUserID != "42" {
+		t.Fatalf("target = %+v", target)
+	}
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 로컬 사용자 저장소(MCIAM_USE=false) 전용 인증 보조 핸들러.
+// 이메일 인증, 비밀번호 변경/재설정을 제공한다. 메일 발송기가 구성되어 있지 않으므로
+// 발급된 토큰은 로그로 전달하며, development 환경에서는 응답에도 포함한다.
+
+// VerifyEmailRequest 이메일 인증 요청
+type VerifyEmailRequest struct {
+	Request struct {
+		Token string `json:"token"`
+	} `json:"request"`
+}
+
+// PasswordResetRequestRequest 비밀번호 재설정 토큰 발급 요청
+type PasswordResetRequestRequest struct {
+	Request struct {
+		Email string `json:"email"`
+	} `json:"request"`
+}
+
+// PasswordResetRequest 비밀번호 재설정 요청
+type PasswordResetRequest struct {
+	Request struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"newPassword"`
+	} `json:"request"`
+}
+
+// PasswordChangeRequest 비밀번호 변경 요청
+type PasswordChangeRequest struct {
+	Request struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	} `json:"request"`
+}
+
+// signupLocal 로컬 사용자 저장소에 회원가입
+func signupLocal(c echo.Context, req SignupRequestBody, cfg *config.Config) error {
+	userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+	user, verifyToken, err := userService.Signup(service.LocalSignupInput{
+		Email:        req.Email,
+		Password:     req.Password,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		Organization: req.Organization,
+	})
+	if err != nil {
+		return localAuthError(err)
+	}
+
+	data := map[string]interface{}{
+		"user_id":                     user.LoginID,
+		"email":                       user.Email,
+		"email_verification_required": verifyToken != "",
+	}
+	if verifyToken != "" {
+		deliverLocalUserToken(cfg, user.Email, model.LocalUserTokenEmailVerify, verifyToken, data)
+	}
+	emitAuthEvent(c, model.AuthEventSignup, model.SignupEventData{Email: user.Email, Status: "created"})
+
+	resp := model.CommonResponseStatusCreated(data)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// VerifyEmail 이메일 인증 핸들러
+// @Summary     Verify email
+// @Description Confirm a local account email with the verification token (MCIAM_USE=false only)
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body VerifyEmailRequest true "Verification token"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Router      /api/auth/verify-email [post]
+func VerifyEmail(c echo.Context) error {
+	cfg, err := localAuthConfig(c)
+	if err != nil {
+		return err
+	}
+
+	var req VerifyEmailRequest
+	if err := c.Bind(&req); err != nil || req.Request.Token == "" {
+		return errors.NewBadRequest("token is required")
+	}
+
+	userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+	user, err := userService.VerifyEmail(req.Request.Token)
+	if err != nil {
+		return localAuthError(err)
+	}
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{
+		"user_id":        user.LoginID,
+		"email_verified": true,
+	})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RequestPasswordReset 비밀번호 재설정 토큰 발급 핸들러.
+// 계정 존재 여부와 관계없이 동일한 응답을 반환한다.
+// @Summary     Request password reset
+// @Description Issue a password reset token for a local account (MCIAM_USE=false only)
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body PasswordResetRequestRequest true "Account email"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Router      /api/auth/password/reset-request [post]
+func RequestPasswordReset(c echo.Context) error {
+	cfg, err := localAuthConfig(c)
+	if err != nil {
+		return err
+	}
+
+	var req PasswordResetRequestRequest
+	if err := c.Bind(&req); err != nil || req.Request.Email == "" {
+		return errors.NewBadRequest("email is required")
+	}
+
+	userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+	user, token, err := userService.RequestPasswordReset(req.Request.Email)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to issue reset token", err)
+	}
+
+	data := map[string]interface{}{
+		"message": "If the account exists, a password reset link has been sent.",
+	}
+	if user != nil && token != "" {
+		deliverLocalUserToken(cfg, user.Email, model.LocalUserTokenPasswordReset, token, data)
+	}
+
+	resp := model.CommonResponseStatusOK(data)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ResetPassword 재설정 토큰으로 비밀번호 변경 핸들러
+// @Summary     Reset password
+// @Description Set a new password using a reset token (MCIAM_USE=false only)
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body PasswordResetRequest true "Reset token and new password"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Router      /api/auth/password/reset [post]
+func ResetPassword(c echo.Context) error {
+	cfg, err := localAuthConfig(c)
+	if err != nil {
+		return err
+	}
+
+	var req PasswordResetRequest
+	if err := c.Bind(&req); err != nil || req.Request.Token == "" || req.Request.NewPassword == "" {
+		return errors.NewBadRequest("token and newPassword are required")
+	}
+
+	userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+	user, err := userService.ResetPassword(req.Request.Token, req.Request.NewPassword)
+	if err != nil {
+		return localAuthError(err)
+	}
+	revokeUserCredentials(user.LoginID)
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{
+		"message": "Password has been reset",
+	})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ChangePassword 로그인 사용자 비밀번호 변경 핸들러
+// @Summary     Change password
+// @Description Change the password of the authenticated local account (MCIAM_USE=false only). Sessions and personal access tokens are revoked, so the client must log in again
+// @Tags        auth
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body PasswordChangeRequest true "Current and new password"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/auth/password/change [post]
+func ChangePassword(c echo.Context) error {
+	cfg, err := localAuthConfig(c)
+	if err != nil {
+		return err
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return errors.NewUnauthorized("Not authenticated")
+	}
+
+	var req PasswordChangeRequest
+	if err := c.Bind(&req); err != nil || req.Request.CurrentPassword == "" || req.Request.NewPassword == "" {
+		return errors.NewBadRequest("currentPassword and newPassword are required")
+	}
+
+	userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+	user, err := userService.ChangePassword(userID, req.Request.CurrentPassword, req.Request.NewPassword)
+	if err != nil {
+		return localAuthError(err)
+	}
+	// 현재 세션을 포함한 모든 세션이 폐기되므로 다시 로그인해야 한다
+	revokeUserCredentials(user.LoginID)
+	clearAuthCookies(c)
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{
+		"message":          "Password has been changed",
+		"relogin_required": true,
+	})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// revokeUserCredentials 비밀번호 변경/재설정 후 사용자의 세션(access/refresh token)과 개인 액세스 토큰 폐기
+func revokeUserCredentials(loginID string) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+	if err := service.NewSessionService(repository.NewSessionRepository(db)).RevokeSession(loginID); err != nil {
+		log.Printf("[LocalAuth] revoke session error (userID=%s): %v", loginID, err)
+	}
+	service.GetRefreshCoordinator().Forget(loginID)
+	if err := repository.NewAccessTokenRepository(db).RevokeByOwner(model.AccessTokenOwnerUser, loginID); err != nil {
+		log.Printf("[LocalAuth] revoke access tokens error (userID=%s): %v", loginID, err)
+	}
+}
+
+// localAuthConfig 로컬 사용자 저장소 사용 가능 여부 확인 후 config 반환.
+// MCIAM 모드이거나 DB가 없으면 에러를 반환한다.
+func localAuthConfig(c echo.Context) (*config.Config, error) {
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil {
+		return nil, errors.NewInternalServerError("config not available", nil)
+	}
+	if cfg.MCIAM.Use {
+		return nil, errors.NewBadRequest("Managed by mc-iam-manager in MCIAM mode")
+	}
+	if repository.GetDB() == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Local user store requires database", nil)
+	}
+	return cfg, nil
+}
+
+// localAuthError 로컬 사용자 서비스 에러를 HTTP 에러로 변환
+func localAuthError(err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrInvalidCredentials):
+		return errors.NewUnauthorized("Invalid id or password")
+	case stderrors.Is(err, service.ErrUserDisabled):
+		return errors.NewForbidden("User is disabled")
+	case stderrors.Is(err, service.ErrEmailNotVerified):
+		return errors.NewForbidden("Email is not verified")
+	case stderrors.Is(err, service.ErrUserExists):
+		return errors.New(http.StatusConflict, "User already exists", nil)
+	case stderrors.Is(err, service.ErrInvalidUserToken):
+		return errors.NewBadRequest("Invalid or expired token")
+	case stderrors.Is(err, service.ErrWeakPassword), stderrors.Is(err, service.ErrBreachedPassword):
+		return errors.NewBadRequest(err.Error())
+	case stderrors.Is(err, service.ErrPasswordCheckFailed):
+		return errors.New(http.StatusServiceUnavailable, "Password policy check is unavailable", err)
+	default:
+		return errors.NewInternalServerError("Local user store error", err)
+	}
+}
+
+// deliverLocalUserToken 메일 발송 대체: 토큰 발급 사실을 로그로 남기고,
+// development 환경에서는 로그와 응답(data)에 토큰 원문을 포함한다.
+func deliverLocalUserToken(cfg *config.Config, email, purpose, token string, data map[string]interface{}) {
+	if cfg.Server.Env == "development" {
+		log.Printf("[LocalUser] %s token for %s: %s", purpose, email, token)
+		data[purpose+"_token"] = token
+		return
+	}
+	log.Printf("[LocalUser] %s token issued for %s (mail delivery not configured)", purpose, email)
+}
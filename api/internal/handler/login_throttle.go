@@ -0,0 +1,144 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UnlockLoginRequest 로그인 잠금 해제 요청 (account, ip 중 하나 이상)
+type UnlockLoginRequest struct {
+	Request struct {
+		Account string `json:"account"`
+		IP      string `json:"ip"`
+	} `json:"request"`
+}
+
+// checkLoginThrottle 로그인 시도 전 잠금/지연 여부 확인. 거부 시 429와 Retry-After를 반환한다.
+func checkLoginThrottle(c echo.Context, throttle *service.LoginThrottle, account string) error {
+	if throttle == nil {
+		return nil
+	}
+	block := throttle.Check(account, c.RealIP())
+	if block == nil {
+		return nil
+	}
+
+	retryAfter := int(math.Ceil(block.RetryAfter.Seconds()))
+	c.Response().Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
+	if block.Locked {
+		return errors.New(http.StatusTooManyRequests,
+			fmt.Sprintf("Too many failed login attempts, temporarily locked. Retry after %d seconds", retryAfter), nil)
+	}
+	return errors.New(http.StatusTooManyRequests,
+		fmt.Sprintf("Too many failed login attempts. Retry after %d seconds", retryAfter), nil)
+}
+
+// recordLoginOutcome 로그인 처리 결과(응답 상태 코드)가 자격 증명/2차 코드 불일치(401)이면 실패로 기록한다.
+// 성공 기록(계정 카운터 초기화)은 MFA까지 끝나 세션이 발급될 때 finishLogin에서 한다.
+func recordLoginOutcome(c echo.Context, throttle *service.LoginThrottle, account string, err error) {
+	if throttle == nil {
+		return
+	}
+	if loginResponseStatus(c, err) == http.StatusUnauthorized {
+		throttle.RecordFailure(account, c.RealIP())
+	}
+}
+
+// recordLoginSuccess 로그인 완료 시 계정 실패 카운터 초기화
+func recordLoginSuccess(account string) {
+	if throttle := service.GetLoginThrottle(); throttle != nil && account != "" {
+		throttle.RecordSuccess(account)
+	}
+}
+
+// loginResponseStatus 로그인 핸들러가 반환한 에러 또는 이미 쓴 응답의 상태 코드 (알 수 없으면 0)
+func loginResponseStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+	var appErr *errors.AppError
+	var httpErr *echo.HTTPError
+	switch {
+	case stderrors.As(err, &appErr):
+		return appErr.Code
+	case stderrors.As(err, &httpErr):
+		return httpErr.Code
+	default:
+		return 0
+	}
+}
+
+// GetLoginLocks 잠금 중인 계정/IP 목록 핸들러
+// @Summary     Locked logins
+// @Description List accounts and IPs currently locked by failed-login protection
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=[]model.LoginAttempt}
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/admin/login-locks [get]
+func GetLoginLocks(c echo.Context) error {
+	throttle := service.GetLoginThrottle()
+	if throttle == nil {
+		resp := model.CommonResponseStatusOK([]model.LoginAttempt{})
+		return c.JSON(resp.Status.Code, resp)
+	}
+	locked, err := throttle.Locked()
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list login locks", err)
+	}
+	resp := model.CommonResponseStatusOK(locked)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// UnlockLogin 계정/IP 로그인 잠금 해제 핸들러 (감사 로그 기록)
+// @Summary     Unlock login
+// @Description Clear failed-login counters and lockout for an account and/or IP
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body UnlockLoginRequest true "Account and/or IP"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/admin/login-locks/unlock [post]
+func UnlockLogin(c echo.Context) error {
+	var req UnlockLoginRequest
+	if err := c.Bind(&req); err != nil || (req.Request.Account == "" && req.Request.IP == "") {
+		return errors.NewBadRequest("account or ip is required")
+	}
+	throttle := service.GetLoginThrottle()
+	if throttle == nil {
+		return errors.NewBadRequest("Login throttling is disabled")
+	}
+
+	actor := middleware.GetUserID(c)
+	unlocked := make([]string, 0, 2)
+	if req.Request.Account != "" {
+		key := service.AccountKey(req.Request.Account)
+		if err := throttle.Unlock(key, actor, c.RealIP()); err != nil {
+			return errors.NewInternalServerError("Failed to unlock account", err)
+		}
+		unlocked = append(unlocked, key)
+	}
+	if req.Request.IP != "" {
+		key := service.IPKey(req.Request.IP)
+		if err := throttle.Unlock(key, actor, c.RealIP()); err != nil {
+			return errors.NewInternalServerError("Failed to unlock ip", err)
+		}
+		unlocked = append(unlocked, key)
+	}
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"unlocked": unlocked})
+	return c.JSON(resp.Status.Code, resp)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// initTestLoginThrottle 첫 실패부터 1시간 지연되는 in-memory 제한기 (테스트 종료 시 비활성화)
+func initTestLoginThrottle(t *testing.T) *service.LoginThrottle {
+	t.Helper()
+	throttle := service.InitLoginThrottle(config.LoginThrottleConfig{
+		Enabled:            true,
+		MaxAccountFailures: 100,
+		MaxIPFailures:      100,
+		BaseDelay:          time.Hour,
+		MaxDelay:           time.Hour,
+		LockoutDuration:    time.Hour,
+		FailureWindow:      time.Hour,
+	}, nil)
+	t.Cleanup(func() { service.InitLoginThrottle(config.LoginThrottleConfig{}, nil) })
+	return throttle
+}
+
+func newTestLoginContext(path, body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.RemoteAddr = "192.0.2.10:40000"
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func responseStatus(t *testing.T, rec *httptest.ResponseRecorder, err error) int {
+	t.Helper()
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		return appErr.Code
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code
+}
+
+func TestRecordLoginOutcomeMFAPendingKeepsFailures(t *testing.T) {
+	throttle := initTestLoginThrottle(t)
+	throttle.RecordFailure("user01", "")
+
+	// MFA 대기 응답(200)은 로그인 완료가 아니므로 계정 카운터를 초기화하지 않는다
+	c, _ := newTestLoginContext("/api/auth/login", "")
+	if err := c.JSON(http.StatusOK, map[string]interface{}{"mfa_required": true}); err != nil {
+		t.Fatal(err)
+	}
+	recordLoginOutcome(c, throttle, "user01", nil)
+	if throttle.Check("user01", "") == nil {
+		t.Fatal("mfa_required response reset the account counter")
+	}
+
+	// 세션 발급(finishLogin)에서 초기화
+	c, _ = newTestLoginContext("/api/auth/login/mfa", "")
+	c.Set("config", &config.Config{})
+	if err := finishLogin(c, service.PendingLogin{LoginID: "user01", AccessToken: "token", StatusCode: http.StatusOK}); err != nil {
+		t.Fatal(err)
+	}
+	if block := throttle.Check("user01", ""); block != nil {
+		t.Errorf("completed login kept the account counter: %+v", block)
+	}
+}
+
+func TestLoginMFAThrottled(t *testing.T) {
+	for _, path := range []string{"/api/auth/login/mfa", "/api/auth/login/mfa/enroll"} {
+		t.Run(path, func(t *testing.T) {
+			throttle := initTestLoginThrottle(t)
+			handle := LoginMFA
+			if strings.HasSuffix(path, "/enroll") {
+				handle = LoginMFAEnroll
+			}
+			body := `{"request":{"mfa_token":"unknown","code":"123456"}}`
+
+			// 알 수 없는 챌린지는 IP 실패로 기록되어 다음 시도가 지연된다
+			c, rec := newTestLoginContext(path, body)
+			if status := responseStatus(t, rec, handle(c)); status != http.StatusUnauthorized {
+				t.Fatalf("unknown challenge status = %d, want 401", status)
+			}
+			c, rec = newTestLoginContext(path, body)
+			if status := responseStatus(t, rec, handle(c)); status != http.StatusTooManyRequests {
+				t.Fatalf("retry status = %d, want 429", status)
+			}
+
+			// IP 카운터가 없어도 챌린지 계정이 지연 중이면 거부
+			throttle.Unlock(service.IPKey("192.0.2.10"), "test", "")
+			throttle.RecordFailure("user01", "")
+			id, err := service.GetMFAChallengeStore().Create(service.PendingLogin{UserID: "u1", LoginID: "user01"}, true, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { service.GetMFAChallengeStore().Delete(id) })
+			c, rec = newTestLoginContext(path, `{"request":{"mfa_token":"`+id+`","code":"123456"}}`)
+			if status := responseStatus(t, rec, handle(c)); status != http.StatusTooManyRequests {
+				t.Errorf("throttled account status = %d, want 429", status)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 메뉴/메뉴 권한 선언 파일(webconsole_menu_resources.yaml, webconsole_menu_permissions.csv)을
+// mc-iam-manager에 반영하는 핸들러. 호출한 관리자의 토큰으로 mc-iam-manager를 호출한다.
+
+// ApplyMenuSyncRequest 메뉴 동기화 반영 요청
+type ApplyMenuSyncRequest struct {
+	Request struct {
+		DryRun bool `json:"dry_run"` // 계획만 계산
+		Prune  bool `json:"prune"`   // 정의에 없는 메뉴 삭제
+	} `json:"request"`
+}
+
+// PlanMenuSync 메뉴 동기화 계획 조회 핸들러
+// @Summary     Menu sync plan
+// @Description Validate the menu tree and permission CSV and compare them with the menus and grants in mc-iam-manager (dry-run)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       prune   query bool false "Plan deletion of menus missing from the definition"
+// @Param       offline query bool false "Compare with the menu export file instead of mc-iam-manager"
+// @Success     200 {object} model.CommonResponse{responseData=service.MenuSyncPlan}
+// @Failure     502 {object} model.CommonResponse
+// @Router      /api/admin/menu-sync/plan [get]
+func PlanMenuSync(c echo.Context) error {
+	syncer, err := menuSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	plan, err := syncer.Plan(c.Request().Context(), service.MenuSyncOptions{
+		AuthHeader: backendUserAuthHeader(c),
+		Prune:      c.QueryParam("prune") == "true",
+		Offline:    c.QueryParam("offline") == "true",
+	})
+	if err != nil {
+		return menuSyncError(err)
+	}
+	resp := model.CommonResponseStatusOK(plan)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ApplyMenuSync 메뉴 동기화 반영 핸들러
+// @Summary     Apply menu sync
+// @Description Create/update menus (and delete them with prune) and import the permission CSV so mc-iam-manager matches the definition. Idempotent.
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body ApplyMenuSyncRequest false "Options"
+// @Success     200 {object} model.CommonResponse{responseData=service.MenuSyncResult}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     502 {object} model.CommonResponse
+// @Router      /api/admin/menu-sync/apply [post]
+func ApplyMenuSync(c echo.Context) error {
+	syncer, err := menuSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	var req ApplyMenuSyncRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewBadRequest("invalid request body")
+	}
+	_, result, err := syncer.Apply(c.Request().Context(), service.MenuSyncOptions{
+		AuthHeader: backendUserAuthHeader(c),
+		Prune:      req.Request.Prune,
+		DryRun:     req.Request.DryRun,
+	})
+	if err != nil {
+		return menuSyncError(err)
+	}
+	if !result.DryRun && (len(result.Created)+len(result.Updated)+len(result.Deleted)+len(result.Failed) > 0 || result.PermissionsImported) {
+		service.RecordAudit(model.AuditEvent{
+			Action: model.AuditActionMenuSynced,
+			Actor:  middleware.GetUserID(c),
+			Target: "mc-iam-manager",
+			IP:     c.RealIP(),
+			Detail: fmt.Sprintf("created=%d updated=%d deleted=%d permissions=%t failed=%d",
+				len(result.Created), len(result.Updated), len(result.Deleted), result.PermissionsImported, len(result.Failed)),
+		})
+	}
+	resp := model.CommonResponseStatusOK(result)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+func menuSyncerFromContext() (*service.MenuSyncer, error) {
+	syncer := service.GetMenuSyncer()
+	if syncer == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Menu sync is not initialized", nil)
+	}
+	return syncer, nil
+}
+
+// menuSyncError service 오류를 HTTP 상태로 변환
+func menuSyncError(err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrMenuSyncInvalid), stderrors.Is(err, service.ErrMenuSyncOffline):
+		return errors.NewBadRequestWithError(err.Error(), err)
+	case stderrors.Is(err, service.ErrMenuSyncUnavailable):
+		return errors.New(http.StatusBadGateway, "Failed to read menus from mc-iam-manager", err)
+	default:
+		return errors.NewInternalServerError("Menu sync failed", err)
+	}
+}
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TOTP 2단계 인증(MFA) 핸들러.
+// 로그인 1차 인증(MCIAM 또는 로컬) 성공 후 MFA가 활성화되어 있거나 역할상 필수이면
+// 세션을 저장하지 않고 mfa_token을 반환하며, /api/auth/login/mfa에서 코드 확인 후 로그인을 완료한다.
+// MFA 정보는 DB(user_mfas)에 저장되므로 DB가 없으면 MFA는 적용되지 않는다.
+
+// MFALoginRequest 2차 인증 코드 제출 요청
+type MFALoginRequest struct {
+	Request struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	} `json:"request"`
+}
+
+// MFAEnrollLoginRequest 로그인 중 MFA 등록 시작 요청
+type MFAEnrollLoginRequest struct {
+	Request struct {
+		MFAToken string `json:"mfa_token"`
+	} `json:"request"`
+}
+
+// MFACodeRequest 로그인 사용자의 MFA 코드 요청 (등록 확정/해제)
+type MFACodeRequest struct {
+	Request struct {
+		Code string `json:"code"`
+	} `json:"request"`
+}
+
+// completeLogin 1차 인증 결과에 MFA를 적용한다.
+// MFA 대상이면 챌린지를 만들어 mfa_token을 반환하고, 아니면 세션 저장 후 로그인 응답을 그대로 반환한다.
+// MFA 서비스를 쓸 수 없는데(DB/암호화 키 없음) 역할상 필수이거나 이미 등록한 사용자이면 503으로 로그인을 거부한다.
+func completeLogin(c echo.Context, cfg *config.Config, login service.PendingLogin) error {
+	if cfg != nil {
+		if mfaSvc := newMFAServiceIfAvailable(cfg); mfaSvc != nil {
+			enabled, err := mfaSvc.IsEnabled(login.UserID)
+			if err != nil {
+				return errors.NewInternalServerError("Failed to check MFA status", err)
+			}
+			required := cfg.MFA.IsRequiredForRole(login.Roles...)
+			if enabled || required {
+				id, err := service.GetMFAChallengeStore().Create(login, !enabled, cfg.MFA.ChallengeTTL)
+				if err != nil {
+					return errors.NewInternalServerError("Failed to create MFA challenge", err)
+				}
+				resp := model.CommonResponseStatusOK(map[string]interface{}{
+					"mfa_required":        true,
+					"mfa_token":           id,
+					"enrollment_required": !enabled,
+					"expires_in":          cfg.MFA.ChallengeTTL.Seconds(),
+				})
+				return c.JSON(resp.Status.Code, resp)
+			}
+		} else if cfg.MFA.IsRequiredForRole(login.Roles...) || mfaEnrolledWithoutService(login.UserID) {
+			log.Printf("[MFA] MFA is required for user %s but not available (database or encryption key missing), login denied", login.UserID)
+			return errors.New(http.StatusServiceUnavailable, "MFA is required but not available, contact the administrator", nil)
+		}
+	}
+
+	return finishLogin(c, login)
+}
+
+// LoginMFA 2차 인증 코드 확인 후 로그인 완료 핸들러.
+// 로그인 중 등록(enrollment_required)인 경우 첫 코드 확인으로 등록을 확정한다.
+// @Summary     Complete login with MFA code
+// @Description Verify the TOTP (or recovery) code for a pending login and issue the session
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body MFALoginRequest true "MFA token and code"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Failure     429 {object} model.CommonResponse
+// @Router      /api/auth/login/mfa [post]
+func LoginMFA(c echo.Context) error {
+	var req MFALoginRequest
+	if err := c.Bind(&req); err != nil || req.Request.MFAToken == "" || req.Request.Code == "" {
+		return errors.NewBadRequest("mfa_token and code are required")
+	}
+
+	// 로그인과 같은 실패 제한을 적용한다: 챌린지 계정과 IP 카운터 (챌린지 자체는 mfaMaxAttempts 초과 시 폐기)
+	throttle := service.GetLoginThrottle()
+	store := service.GetMFAChallengeStore()
+	ch, ok := store.Get(req.Request.MFAToken)
+	if err := checkLoginThrottle(c, throttle, ch.Login.LoginID); err != nil {
+		return err
+	}
+	if !ok {
+		err := errors.NewUnauthorized("MFA challenge expired or invalid")
+		recordLoginOutcome(c, throttle, "", err)
+		return err
+	}
+
+	err := verifyLoginMFA(c, store, ch, req.Request.Code)
+	recordLoginOutcome(c, throttle, ch.Login.LoginID, err)
+	return err
+}
+
+// verifyLoginMFA 챌린지의 2차 코드(또는 등록 확인 코드)를 확인하고 로그인을 완료한다.
+func verifyLoginMFA(c echo.Context, store *service.MFAChallengeStore, ch service.MFAChallenge, code string) error {
+	mfaSvc, err := mfaServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if ch.EnrollmentRequired {
+		err = mfaSvc.ConfirmEnrollment(ch.Login.UserID, code)
+	} else {
+		err = mfaSvc.Verify(ch.Login.UserID, code)
+	}
+	if err != nil {
+		if stderrors.Is(err, service.ErrMFAInvalidCode) {
+			emitAuthEvent(c, model.AuthEventLoginFailed, model.LoginFailedEventData{
+				Account: ch.Login.Account,
+				Status:  http.StatusUnauthorized,
+				Reason:  "invalid mfa code",
+			})
+			if !store.RecordFailure(ch.ID) {
+				return errors.NewUnauthorized("Too many invalid MFA codes, please login again")
+			}
+			return errors.NewUnauthorized("Invalid MFA code")
+		}
+		return mfaError(err)
+	}
+
+	store.Delete(ch.ID)
+	login := ch.Login
+	login.MFA = true
+	return finishLogin(c, login)
+}
+
+// LoginMFAEnroll 로그인 중 MFA 등록 시작 핸들러 (역할상 MFA 필수이나 미등록 사용자)
+// @Summary     Start MFA enrollment during login
+// @Description Issue a TOTP secret and recovery codes for a pending login that requires MFA enrollment
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body MFAEnrollLoginRequest true "MFA token"
+// @Success     200 {object} model.CommonResponse{responseData=service.MFAEnrollment}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Failure     429 {object} model.CommonResponse
+// @Router      /api/auth/login/mfa/enroll [post]
+func LoginMFAEnroll(c echo.Context) error {
+	var req MFAEnrollLoginRequest
+	if err := c.Bind(&req); err != nil || req.Request.MFAToken == "" {
+		return errors.NewBadRequest("mfa_token is required")
+	}
+
+	throttle := service.GetLoginThrottle()
+	ch, ok := service.GetMFAChallengeStore().Get(req.Request.MFAToken)
+	if err := checkLoginThrottle(c, throttle, ch.Login.LoginID); err != nil {
+		return err
+	}
+	if !ok {
+		err := errors.NewUnauthorized("MFA challenge expired or invalid")
+		recordLoginOutcome(c, throttle, "", err)
+		return err
+	}
+	if !ch.EnrollmentRequired {
+		return errors.NewBadRequest("MFA is already enrolled")
+	}
+
+	mfaSvc, err := mfaServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	enrollment, err := mfaSvc.BeginEnrollment(ch.Login.UserID, ch.Login.Account)
+	if err != nil {
+		return mfaError(err)
+	}
+
+	resp := model.CommonResponseStatusOK(enrollment)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// GetMFAStatus 로그인 사용자의 MFA 상태 조회 핸들러
+// @Summary     MFA status
+// @Description Whether MFA is enabled for the authenticated user and whether it is required by role
+// @Tags        auth
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/auth/mfa [get]
+func GetMFAStatus(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return errors.NewUnauthorized("Not authenticated")
+	}
+	mfaSvc, err := mfaServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	enabled, err := mfaSvc.IsEnabled(userID)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to check MFA status", err)
+	}
+
+	cfg, _ := c.Get("config").(*config.Config)
+	resp := model.CommonResponseStatusOK(map[string]interface{}{
+		"enabled":  enabled,
+		"required": cfg.MFA.IsRequiredForRole(middleware.GetRoles(c)...),
+	})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// EnrollMFA 로그인 사용자의 MFA 등록 시작 핸들러
+// @Summary     Start MFA enrollment
+// @Description Issue a TOTP secret and recovery codes. Confirm with /api/auth/mfa/confirm.
+// @Tags        auth
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=service.MFAEnrollment}
+// @Failure     401 {object} model.CommonResponse
+// @Failure     409 {object} model.CommonResponse
+// @Router      /api/auth/mfa/enroll [post]
+func EnrollMFA(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return errors.NewUnauthorized("Not authenticated")
+	}
+	mfaSvc, err := mfaServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	account := middleware.GetEmail(c)
+	if account == "" {
+		account = userID
+	}
+	enrollment, err := mfaSvc.BeginEnrollment(userID, account)
+	if err != nil {
+		return mfaError(err)
+	}
+
+	resp := model.CommonResponseStatusOK(enrollment)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ConfirmMFA 인증 앱의 첫 코드로 MFA 등록 확정 핸들러
+// @Summary     Confirm MFA enrollment
+// @Description Activate MFA with the first TOTP code from the authenticator app
+// @Tags        auth
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body MFACodeRequest true "TOTP code"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/auth/mfa/confirm [post]
+func ConfirmMFA(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return errors.NewUnauthorized("Not authenticated")
+	}
+	var req MFACodeRequest
+	if err := c.Bind(&req); err != nil || req.Request.Code == "" {
+		return errors.NewBadRequest("code is required")
+	}
+	mfaSvc, err := mfaServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := mfaSvc.ConfirmEnrollment(userID, req.Request.Code); err != nil {
+		return mfaError(err)
+	}
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"enabled": true})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// DisableMFA 현재 코드 확인 후 MFA 해제 핸들러. 역할상 MFA가 필수인 사용자는 해제할 수 없다.
+// @Summary     Disable MFA
+// @Description Disable MFA after verifying a current TOTP or recovery code
+// @Tags        auth
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body MFACodeRequest true "TOTP or recovery code"
+// @Success     200 {object} model.CommonResponse
+// @Failure     400 {object} model.CommonResponse
+// @Failure     403 {object} model.CommonResponse
+// @Router      /api/auth/mfa/disable [post]
+func DisableMFA(c echo.Context) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return errors.NewUnauthorized("Not authenticated")
+	}
+	var req MFACodeRequest
+	if err := c.Bind(&req); err != nil || req.Request.Code == "" {
+		return errors.NewBadRequest("code is required")
+	}
+
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg != nil && cfg.MFA.IsRequiredForRole(middleware.GetRoles(c)...) {
+		return errors.NewForbidden("MFA is required for your role")
+	}
+	mfaSvc, err := mfaServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := mfaSvc.Disable(userID, req.Request.Code); err != nil {
+		return mfaError(err)
+	}
+
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"enabled": false})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// newMFAServiceIfAvailable DB와 암호화 키가 모두 있을 때만 MFA 서비스 반환 (없으면 nil)
+func newMFAServiceIfAvailable(cfg *config.Config) *service.MFAService {
+	db := repository.GetDB()
+	if db == nil {
+		return nil
+	}
+	svc, err := service.NewMFAService(repository.NewMFARepository(db), cfg.MFA)
+	if err != nil {
+		return nil
+	}
+	return svc
+}
+
+// mfaEnrolledWithoutService MFA 서비스 없이 DB에서 등록 여부만 확인 (조회 실패는 등록된 것으로 본다)
+func mfaEnrolledWithoutService(userID string) bool {
+	db := repository.GetDB()
+	if db == nil {
+		return false
+	}
+	mfa, err := repository.NewMFARepository(db).FindByUserID(userID)
+	if err != nil {
+		log.Printf("[MFA] failed to check enrollment of %s: %v", userID, err)
+		return true
+	}
+	return mfa != nil && mfa.Enabled
+}
+
+// mfaServiceFromContext MFA 핸들러용 서비스 생성. 사용 불가 시 503 반환.
+func mfaServiceFromContext(c echo.Context) (*service.MFAService, error) {
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil {
+		return nil, errors.NewInternalServerError("config not available", nil)
+	}
+	if repository.GetDB() == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "MFA requires database", nil)
+	}
+	svc, err := service.NewMFAService(repository.NewMFARepository(repository.GetDB()), cfg.MFA)
+	if err != nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "MFA is not configured", err)
+	}
+	return svc, nil
+}
+
+// mfaError MFA 서비스 에러를 HTTP 에러로 변환
+func mfaError(err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrMFAInvalidCode):
+		return errors.NewUnauthorized("Invalid MFA code")
+	case stderrors.Is(err, service.ErrMFANotEnrolled):
+		return errors.NewBadRequest("MFA is not enrolled")
+	case stderrors.Is(err, service.ErrMFAAlreadyEnabled):
+		return errors.New(http.StatusConflict, "MFA is already enabled", nil)
+	default:
+		return errors.NewInternalServerError("MFA error", err)
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestCompleteLoginRequiredRoleGating DB/암호화 키 없이 MFA 필수 역할의 로그인은 거부되고 나머지는 그대로 완료되는지
+func TestCompleteLoginRequiredRoleGating(t *testing.T) {
+	cfg := &config.Config{MFA: config.MFAConfig{RequiredRoles: []string{"platformAdmin"}}}
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		roles      []string
+		wantStatus int
+	}{
+		{name: "required role", cfg: cfg, roles: []string{"viewer", "platformAdmin"}, wantStatus: http.StatusServiceUnavailable},
+		{name: "required role, other case", cfg: cfg, roles: []string{"PLATFORMADMIN"}, wantStatus: http.StatusServiceUnavailable},
+		{name: "role not required", cfg: cfg, roles: []string{"viewer"}, wantStatus: http.StatusOK},
+		{name: "no roles", cfg: cfg, wantStatus: http.StatusOK},
+		{name: "mfa not configured", cfg: &config.Config{}, roles: []string{"platformAdmin"}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/auth/login", nil), rec)
+			c.Set("config", tt.cfg)
+
+			err := completeLogin(c, tt.cfg, service.PendingLogin{
+				UserID:     "user01",
+				Roles:      tt.roles,
+				StatusCode: http.StatusOK,
+				Payload:    map[string]string{"access_token": "token"},
+			})
+			status := rec.Code
+			var appErr *errors.AppError
+			if stderrors.As(err, &appErr) {
+				status = appErr.Code
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestDisableMFARequiredSecondaryRole MFA 필수 역할이 보조 역할이어도 해제할 수 없는지
+func TestDisableMFARequiredSecondaryRole(t *testing.T) {
+	cfg := &config.Config{MFA: config.MFAConfig{RequiredRoles: []string{"platformAdmin"}}}
+	c, rec := newTestLoginContext("/api/auth/mfa/disable", `{"request":{"code":"123456"}}`)
+	c.Set("config", cfg)
+	c.Set("userId", "user01")
+	c.Set("role", "viewer")
+	c.Set("roles", []string{"viewer", "platformAdmin"})
+
+	if status := responseStatus(t, rec, DisableMFA(c)); status != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", status)
+	}
+}
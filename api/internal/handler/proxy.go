@@ -15,16 +15,56 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
 	"mc_web_console_api/internal/model"
 	"mc_web_console_api/pkg/errors"
 
 	"github.com/labstack/echo/v4"
 )
 
+// buildTargetURL 백엔드 요청 URL 생성.
+// pathParams 값은 url.PathEscape로 경로 세그먼트 하나에만 들어가며 '/', '..', '?', '#'를 포함하면 거부한다.
+// queryParams는 string 또는 []interface{} 배열 값을 url.Values로 인코딩한다.
+func buildTargetURL(baseURL, resourcePath string, pathParams map[string]string, queryParams map[string]interface{}) (*url.URL, error) {
+	for k, v := range pathParams {
+		placeholder := "{" + k + "}"
+		if !strings.Contains(resourcePath, placeholder) {
+			continue
+		}
+		if strings.ContainsAny(v, "/?#\\") || strings.Contains(v, "..") {
+			return nil, fmt.Errorf("invalid pathParam %s: %q", k, v)
+		}
+		resourcePath = strings.ReplaceAll(resourcePath, placeholder, url.PathEscape(v))
+	}
+
+	target, err := url.Parse(baseURL + resourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend url: %w", err)
+	}
+	if len(queryParams) > 0 {
+		values := target.Query()
+		for k, v := range queryParams {
+			switch val := v.(type) {
+			case string:
+				values.Add(k, val)
+			case []interface{}:
+				for _, item := range val {
+					values.Add(k, fmt.Sprint(item))
+				}
+			default:
+				values.Add(k, fmt.Sprint(val))
+			}
+		}
+		target.RawQuery = values.Encode()
+	}
+	return target, nil
+}
+
 // SubsystemAnyController Buffalo SubsystemAnyController 호환 프록시 핸들러
 // @Summary     Proxy to backend service
 // @Description Forward request to backend service defined in conf/api.yaml by subsystemName and operationId
@@ -36,6 +76,8 @@ import (
 // @Param       operationId path string true "Operation ID from api.yaml serviceActions"
 // @Param       body body object false "Request payload forwarded to backend"
 // @Success     200 {object} object
+// @Failure     403 {object} model.CommonResponse{responseData=service.AuthzDecision}
+// @Failure     502 {object} model.CommonResponse
 // @Failure     404 {object} model.CommonResponse
 // @Router      /api/{subsystemName}/{operationId} [post]
 // @Router      /api/{subsystemName}/{operationId} [get]
@@ -97,7 +139,7 @@ func SubsystemAnyController(c echo.Context) error {
 
 	// BaseURL: 캐시 우선 → 없으면 api.yaml BaseURL (mc-iam-manager 고정 주소)
 	effectiveBaseURL := service.BaseURL
-	// ActionSpec: 캐시 우선 → 없으면 api.yaml ActionSpec
+	// ActionSpec: 캐시와 api.yaml을 필드 단위 병합 (Method/ResourcePath/Description은 캐시 우선, RequestCoerce는 api.yaml 우선)
 	effectiveActionSpec := actionSpec
 	if cfg.RegistryCache != nil {
 		if cfg.MCIAM.UseRegistryURL {
@@ -107,39 +149,49 @@ func SubsystemAnyController(c echo.Context) error {
 			}
 		}
 		if cachedSpec := cfg.RegistryCache.GetActionSpec(subsystemName, operationId); cachedSpec != nil {
-			effectiveActionSpec = cachedSpec
+			effectiveActionSpec = config.MergeActionSpec(actionSpec, cachedSpec)
 		}
 	}
 
+	// 대리 요청: 호출자 기준 mc-iam-manager 액션은 대상 사용자 ID를 지정하는 액션으로 바꾼다 (없으면 403)
+	impersonatedSpec, ok, err := impersonatedAction(c, cfg, subsystemName, operationId)
+	if !ok {
+		return err
+	}
+	if impersonatedSpec != nil {
+		effectiveActionSpec = impersonatedSpec
+	}
+
+	// 역할/정책 기반 인가 (webconsole_api_permissions.csv, 백엔드 액션 메서드 기준)
+	if ok, err := middleware.Authorize(c, subsystemName, operationId, effectiveActionSpec.Method); !ok {
+		return err
+	}
+
 	// CommonRequest 파싱
 	var commonRequest model.CommonRequest
 	if err := c.Bind(&commonRequest); err != nil {
 		commonRequest = *model.NewCommonRequest()
 	}
+	if impersonatedSpec != nil {
+		targetImpersonatedUser(c, &commonRequest, impersonatedSpec)
+	}
 
-	// PathParams 치환
-	resourcePath := effectiveActionSpec.ResourcePath
-	for k, v := range commonRequest.PathParams {
-		resourcePath = strings.ReplaceAll(resourcePath, "{"+k+"}", v)
+	// 백엔드 URL 생성 (pathParams는 세그먼트 하나로 escape, queryParams는 url.Values 인코딩)
+	target, err := buildTargetURL(effectiveBaseURL, effectiveActionSpec.ResourcePath, commonRequest.PathParams, commonRequest.QueryParams)
+	if err != nil {
+		return errors.NewBadRequestWithError(err.Error(), err)
 	}
+	targetURL := target.String()
 
-	// QueryParams 추가 (string 또는 []interface{} 배열 모두 처리)
-	targetURL := effectiveBaseURL + resourcePath
-	if len(commonRequest.QueryParams) > 0 {
-		params := []string{}
-		for k, v := range commonRequest.QueryParams {
-			switch val := v.(type) {
-			case string:
-				params = append(params, k+"="+val)
-			case []interface{}:
-				for _, item := range val {
-					params = append(params, k+"="+fmt.Sprint(item))
-				}
-			default:
-				params = append(params, k+"="+fmt.Sprint(val))
-			}
-		}
-		targetURL += "?" + strings.Join(params, "&")
+	// workspace/project 범위 검사 (최종 URL의 nsId 등이 사용자 소속 project 밖이면 403)
+	if ok, err := enforceScope(c, cfg, subsystemName, effectiveActionSpec.ResourcePath, target); !ok {
+		return err
+	}
+
+	// MCIAM 티켓 검증 모드: operation 권한이 담긴 RPT 확보 (권한 없으면 403)
+	ticket, ok, err := acquireTicket(c, cfg, subsystemName, operationId)
+	if !ok {
+		return err
 	}
 
 	// 요청 바디
@@ -168,7 +220,11 @@ func SubsystemAnyController(c echo.Context) error {
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// Authorization 헤더 설정
+	// 티켓 모드의 bearer 백엔드에는 사용자 토큰 대신 RPT를 전달한다
 	authHeader := buildAuthHeader(c, service)
+	if ticket != "" && service.Auth.Type == "bearer" {
+		authHeader = "Bearer " + ticket
+	}
 	if authHeader != "" {
 		httpReq.Header.Set("Authorization", authHeader)
 	}
@@ -197,6 +253,24 @@ func SubsystemAnyController(c echo.Context) error {
 	}
 	defer resp.Body.Close()
 
+	// 세션 중 MCIAM 토큰 만료로 백엔드가 401을 반환하면 토큰 갱신 후 멱등 호출을 1회 재시도
+	if resp.StatusCode == http.StatusUnauthorized && canRetryAfterRefresh(c, cfg, service, httpReq.Method, ticket) {
+		if refreshed, refreshErr := refreshBackendToken(cfg, c); refreshErr != nil {
+			log.Printf("[Proxy] token refresh after 401 failed (%s/%s): %v", subsystemName, operationId, refreshErr)
+		} else if retryReq, buildErr := http.NewRequest(httpReq.Method, targetURL, bytes.NewBuffer(bodyBytes)); buildErr == nil {
+			retryReq.Header = httpReq.Header.Clone()
+			retryReq.Header.Set("Authorization", "Bearer "+refreshed)
+			if retryResp, retryErr := client.Do(retryReq); retryErr != nil {
+				log.Printf("[Proxy] retry after token refresh failed (%s/%s): %v", subsystemName, operationId, retryErr)
+			} else {
+				log.Printf("[Proxy] token refreshed, retried %s/%s -> %d", subsystemName, operationId, retryResp.StatusCode)
+				resp.Body.Close()
+				resp = retryResp
+				defer resp.Body.Close()
+			}
+		}
+	}
+
 	respBody, _ := io.ReadAll(resp.Body)
 
 	var responseData interface{}
@@ -225,6 +299,9 @@ func SubsystemAnyController(c echo.Context) error {
 		}
 	}
 
+	// workspace/project 멤버십 변경 요청 성공 시 범위 캐시 무효화
+	invalidateScopeOnMembershipChange(subsystemName, operationId, effectiveActionSpec.Method, resp.StatusCode)
+
 	commonResp := model.NewCommonResponse(resp.StatusCode, http.StatusText(resp.StatusCode), responseData)
 	return c.JSON(resp.StatusCode, commonResp)
 }
@@ -388,6 +465,18 @@ func buildAuthHeader(c echo.Context, service *config.Service) string {
 		if authValue == "" {
 			authValue, _ = c.Get("Authorization").(string)
 		}
+		// 헤더가 없으면 인증 쿠키의 토큰 사용 (브라우저 요청)
+		if authValue == "" {
+			authValue = middleware.RequestToken(c)
+		}
+		// 액세스 토큰은 BFF 전용 자격 증명이므로 백엔드로 전달하지 않는다
+		if isAccessTokenHeader(authValue) {
+			return ""
+		}
+		// 대리 토큰도 BFF 전용이며 백엔드에는 관리자 세션 토큰을 전달한다
+		if middleware.IsImpersonated(c) {
+			return backendUserAuthHeader(c)
+		}
 		if authValue != "" {
 			if !strings.HasPrefix(authValue, "Bearer ") {
 				return "Bearer " + authValue
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"mc_web_console_api/internal/config"
+)
+
+func TestBuildTargetURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		pathParams map[string]string
+		query      map[string]interface{}
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "path and query",
+			path:       "/ns/{nsId}/mci/{mciId}",
+			pathParams: map[string]string{"nsId": "ns01", "mciId": "mci 1"},
+			query:      map[string]interface{}{"option": "id", "filter": []interface{}{"a", "b"}},
+			want:       "http://tb:1323/tumblebug/ns/ns01/mci/mci%201?filter=a&filter=b&option=id",
+		},
+		{
+			name:  "query value cannot add parameters",
+			path:  "/ns/{nsId}/mci",
+			query: map[string]interface{}{"option": "x&nsId=other"},
+			want:  "http://tb:1323/tumblebug/ns/%7BnsId%7D/mci?option=x%26nsId%3Dother",
+		},
+		{name: "slash", path: "/ns/{nsId}/mci", pathParams: map[string]string{"nsId": "ns01/../ns02"}, wantErr: true},
+		{name: "dot dot", path: "/ns/{nsId}/mci", pathParams: map[string]string{"nsId": ".."}, wantErr: true},
+		{name: "question mark", path: "/ns/{nsId}/mci", pathParams: map[string]string{"nsId": "ns01?nsId=ns02"}, wantErr: true},
+		{name: "hash", path: "/ns/{nsId}/mci", pathParams: map[string]string{"nsId": "ns01#"}, wantErr: true},
+		{name: "unused path param is ignored", path: "/ns", pathParams: map[string]string{"nsId": "../x"}, want: "http://tb:1323/tumblebug/ns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildTargetURL("http://tb:1323/tumblebug", tt.path, tt.pathParams, tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("buildTargetURL = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopedParamsFromTargetURL(t *testing.T) {
+	cfg := config.ScopeConfig{NamespaceParams: []string{"nsId"}}
+	path := "/ns/{nsId}/mci/{mciId}"
+	target, err := buildTargetURL("http://tb:1323/tumblebug", path,
+		map[string]string{"nsId": "ns 01", "mciId": "m1", "projectId": "not-in-path"},
+		map[string]interface{}{"nsId": []interface{}{"ns02", "ns03"}, "workspaceId": "ws01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	for _, p := range scopedParams(cfg, path, target) {
+		got[p.Kind] = append(got[p.Kind], p.Value)
+	}
+	want := map[string][]string{"namespace": {"ns 01", "ns02", "ns03"}, "workspace": {"ws01"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scopedParams = %v, want %v", got, want)
+	}
+
+	// 치환되지 않은 자리 표시자는 검사 대상이 아니다
+	target, _ = buildTargetURL("http://tb:1323", path, nil, nil)
+	if params := scopedParams(cfg, path, target); len(params) != 0 {
+		t.Errorf("unreplaced placeholder scoped: %v", params)
+	}
+}
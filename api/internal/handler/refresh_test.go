@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newRefreshRequestContext(refreshToken string) (echo.Context, *httptest.ResponseRecorder) {
+	body := `{"refresh_token":"` + refreshToken + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestRefreshLocalRequiresRefreshTokenType(t *testing.T) {
+	access, err := jwt.GenerateToken("user01", "User", "user01@example.com", "viewer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh, err := jwt.GenerateRefreshToken("user01", "User", "user01@example.com", "viewer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, rec := newRefreshRequestContext(access)
+	if status := responseStatus(t, rec, Refresh(c)); status != http.StatusUnauthorized {
+		t.Fatalf("refresh with access token: status = %d, want 401", status)
+	}
+
+	c, rec = newRefreshRequestContext(refresh)
+	if status := responseStatus(t, rec, Refresh(c)); status != http.StatusOK {
+		t.Fatalf("refresh with refresh token: status = %d, want 200 (body %s)", status, rec.Body.String())
+	}
+}
+
+func TestAuthMiddlewareRejectsRefreshToken(t *testing.T) {
+	refresh, err := jwt.GenerateRefreshToken("user01", "User", "user01@example.com", "viewer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer "+refresh)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	if status := responseStatus(t, rec, middleware.AuthMiddleware(UserInfo)(c)); status != http.StatusUnauthorized {
+		t.Fatalf("refresh token as access token: status = %d, want 401", status)
+	}
+}
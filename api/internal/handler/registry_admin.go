@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// mc-iam-manager 레지스트리 캐시 관리 핸들러.
+// 프록시 대상 BaseURL이 api.yaml과 레지스트리 중 어디에서 왔는지 확인하고 캐시를 갱신/무효화한다.
+
+// GetRegistry 레지스트리 캐시 조회 핸들러
+// @Summary     Registry cache
+// @Description Cached mc-iam-manager services and actions with source (live|persisted), age and freshness
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=service.RegistryCacheView}
+// @Router      /api/admin/registry [get]
+func GetRegistry(c echo.Context) error {
+	_, cache, err := registryCacheFromContext(c)
+	if err != nil {
+		return err
+	}
+	resp := model.CommonResponseStatusOK(cache.Inspect())
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// GetRegistryDiff 레지스트리 캐시와 api.yaml 비교 핸들러
+// @Summary     Registry diff
+// @Description Compare cached registry services/actions with conf/api.yaml and show the BaseURL/ActionSpec the proxy uses
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=service.RegistryDiff}
+// @Router      /api/admin/registry/diff [get]
+func GetRegistryDiff(c echo.Context) error {
+	cfg, cache, err := registryCacheFromContext(c)
+	if err != nil {
+		return err
+	}
+	resp := model.CommonResponseStatusOK(service.DiffRegistry(cfg.ApiSpec, cache, cfg.MCIAM.UseRegistryURL))
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RefreshRegistry 레지스트리 즉시 갱신 핸들러.
+// 서비스 계정 갱신기가 있으면 사용하고, 없으면 요청한 관리자의 토큰으로 ListMcmpApisServices를 호출한다.
+// @Summary     Refresh registry cache
+// @Description Reload ListMcmpApisServices into the registry cache now
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=service.RegistryCacheStatus}
+// @Failure     502 {object} model.CommonResponse
+// @Failure     503 {object} model.CommonResponse
+// @Router      /api/admin/registry/refresh [post]
+func RefreshRegistry(c echo.Context) error {
+	cfg, cache, err := registryCacheFromContext(c)
+	if err != nil {
+		return err
+	}
+	if !cfg.MCIAM.Use {
+		return errors.New(http.StatusServiceUnavailable, "Registry is only available with mc-iam-manager (MC_WEB_CONSOLE_USE_IAM=true)", nil)
+	}
+
+	via := "user"
+	if refresher := service.GetRegistryRefresher(); refresher != nil {
+		via = "service-account"
+		err = refresher.RefreshNow(c.Request().Context())
+	} else {
+		err = refreshRegistryCache(cfg, c)
+	}
+	if err != nil {
+		return errors.New(http.StatusBadGateway, "Registry refresh failed", err)
+	}
+
+	status := cache.Status()
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionRegistryRefreshed,
+		Actor:  middleware.GetUserID(c),
+		Target: "mc-iam-manager",
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("via=%s services=%d", via, status.Services),
+	})
+	resp := model.CommonResponseStatusOK(status)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// InvalidateRegistry 레지스트리 캐시 무효화 핸들러 (무효화 버스로 모든 replica에 전파)
+// @Summary     Invalidate registry cache
+// @Description Drop the cached registry on every replica; the proxy falls back to api.yaml until it is reloaded
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=service.RegistryCacheStatus}
+// @Router      /api/admin/registry/invalidate [post]
+func InvalidateRegistry(c echo.Context) error {
+	_, cache, err := registryCacheFromContext(c)
+	if err != nil {
+		return err
+	}
+	cache.Invalidate()
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionRegistryInvalidated,
+		Actor:  middleware.GetUserID(c),
+		Target: "mc-iam-manager",
+		IP:     c.RealIP(),
+	})
+	resp := model.CommonResponseStatusOK(cache.Status())
+	return c.JSON(resp.Status.Code, resp)
+}
+
+func registryCacheFromContext(c echo.Context) (*config.Config, *service.RegistryCache, error) {
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil {
+		return nil, nil, errors.NewInternalServerError("config not available", fmt.Errorf("config is nil"))
+	}
+	cache, ok := cfg.RegistryCache.(*service.RegistryCache)
+	if !ok || cache == nil {
+		return nil, nil, errors.New(http.StatusServiceUnavailable, "Registry cache is not configured", nil)
+	}
+	return cfg, cache, nil
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 원격 기준 파일(메뉴/API yaml, metainfo, 권한 CSV) 점검 핸들러.
+// 도달성, 문법, 로컬 conf/ 사본과의 차이, GitHub 이력 기준 뒤처진 버전 수를 보여준다.
+
+// ListRemoteAssets 설정된 모든 원격 자산 점검 핸들러
+// @Summary     Remote assets
+// @Description Check every configured remote asset: reachability, syntax, diff against the local conf/ copy and versions behind upstream
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=[]service.RemoteAssetReport}
+// @Router      /api/admin/remote-assets [get]
+func ListRemoteAssets(c echo.Context) error {
+	checker, err := remoteAssetCheckerFromContext()
+	if err != nil {
+		return err
+	}
+	resp := model.CommonResponseStatusOK(checker.CheckAll(c.Request().Context()))
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// GetRemoteAsset 원격 자산 하나 점검 핸들러
+// @Summary     Remote asset
+// @Description Check one remote asset by name (menu, api, metainfo, menu-permissions, api-permissions, ...)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       name path string true "Remote asset name"
+// @Success     200 {object} model.CommonResponse{responseData=service.RemoteAssetReport}
+// @Failure     400 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/remote-assets/{name} [get]
+func GetRemoteAsset(c echo.Context) error {
+	checker, err := remoteAssetCheckerFromContext()
+	if err != nil {
+		return err
+	}
+	report, err := checker.Check(c.Request().Context(), c.Param("name"))
+	switch {
+	case stderrors.Is(err, service.ErrRemoteAssetUnknown):
+		return errors.NewNotFound(fmt.Sprintf("unknown remote asset %q", c.Param("name")))
+	case stderrors.Is(err, service.ErrRemoteAssetNotConfigured):
+		return errors.NewBadRequest(fmt.Sprintf("remote asset %q has no url configured", c.Param("name")))
+	case err != nil:
+		return errors.NewInternalServerError("remote asset check failed", err)
+	}
+	resp := model.CommonResponseStatusOK(report)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+func remoteAssetCheckerFromContext() (*service.RemoteAssetChecker, error) {
+	checker := service.GetRemoteAssetChecker()
+	if checker == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Remote asset checker is not initialized", nil)
+	}
+	return checker, nil
+}
+
+// remoteAssetNames 설정된 원격 자산 이름 목록 (오류 메시지용)
+func remoteAssetNames(checker *service.RemoteAssetChecker) []string {
+	assets := checker.Assets()
+	names := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		names = append(names, asset.Name)
+	}
+	return names
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// scopedParam 범위 검사 대상 파라미터 (거부 시 403 응답 responseData)
+type scopedParam struct {
+	Param string `json:"param"`
+	Value string `json:"value"`
+	Kind  string `json:"kind"` // namespace | workspace | project
+}
+
+// enforceScope 프록시 요청의 nsId/workspaceId/projectId가 사용자의 MCIAM workspace/project 범위 안인지 검사한다.
+// 요청 파라미터가 아니라 실제로 호출할 target URL(resourcePath 템플릿 위치의 경로 값과 쿼리)을 검사한다.
+// 범위 밖이거나 액세스 토큰 요청이면 403, 범위를 조회할 수 없으면 503 CommonResponse를 응답하고 false를 반환한다 (err는 응답 쓰기 결과).
+func enforceScope(c echo.Context, cfg *config.Config, subsystem, resourcePath string, target *url.URL) (bool, error) {
+	if !cfg.MCIAM.Use || !cfg.Scope.Enabled || containsFold(cfg.Scope.ExemptSubsystems, subsystem) {
+		return true, nil
+	}
+	for _, role := range middleware.GetRoles(c) {
+		if containsFold(cfg.Scope.BypassRoles, role) {
+			return true, nil
+		}
+	}
+
+	targets := scopedParams(cfg.Scope, resourcePath, target)
+	if len(targets) == 0 {
+		return true, nil
+	}
+
+	// 액세스 토큰으로는 mc-iam-manager에서 소유자 범위를 조회할 수 없다 (토큰을 백엔드로 보내지 않는다)
+	if middleware.IsAccessTokenAuth(c) {
+		log.Printf("[Scope] denied token=%v user=%s %s: access tokens cannot resolve workspace/project scope",
+			c.Get("tokenId"), middleware.GetUserID(c), subsystem)
+		resp := model.NewCommonResponse(http.StatusForbidden,
+			"Forbidden: workspace/project-scoped calls are not allowed with an access token", targets)
+		return false, c.JSON(resp.Status.Code, resp)
+	}
+
+	scope, err := resolveUserScope(c, cfg)
+	if err != nil {
+		log.Printf("[Scope] resolve failed (user=%s): %v", middleware.GetUserID(c), err)
+		resp := model.NewCommonResponse(http.StatusServiceUnavailable, "Unable to resolve workspace/project scope", nil)
+		return false, c.JSON(resp.Status.Code, resp)
+	}
+
+	for _, t := range targets {
+		var allowed bool
+		switch t.Kind {
+		case "namespace":
+			allowed = scope.AllowsNamespace(t.Value)
+		case "workspace":
+			allowed = scope.AllowsWorkspace(t.Value)
+		case "project":
+			allowed = scope.AllowsProject(t.Value)
+		}
+		if !allowed {
+			log.Printf("[Scope] denied user=%s %s/%s=%s", middleware.GetUserID(c), subsystem, t.Param, t.Value)
+			msg := fmt.Sprintf("Forbidden: %s %q is outside your workspace/project scope", t.Param, t.Value)
+			resp := model.NewCommonResponse(http.StatusForbidden, msg, t)
+			return false, c.JSON(resp.Status.Code, resp)
+		}
+	}
+	return true, nil
+}
+
+// scopedParams target URL에서 범위 검사 대상 값을 추출한다.
+// 경로 값은 resourcePath 템플릿의 {param} 세그먼트 위치(URL 끝에서부터 정렬)에서, 쿼리는 모든 값을 꺼낸다.
+func scopedParams(cfg config.ScopeConfig, resourcePath string, target *url.URL) []scopedParam {
+	var targets []scopedParam
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		switch {
+		case containsFold(cfg.NamespaceParams, key):
+			targets = append(targets, scopedParam{Param: key, Value: value, Kind: "namespace"})
+		case strings.EqualFold(key, "workspaceId"):
+			targets = append(targets, scopedParam{Param: key, Value: value, Kind: "workspace"})
+		case strings.EqualFold(key, "projectId"):
+			targets = append(targets, scopedParam{Param: key, Value: value, Kind: "project"})
+		}
+	}
+
+	templateSegments := strings.Split(strings.SplitN(resourcePath, "?", 2)[0], "/")
+	pathSegments := strings.Split(target.EscapedPath(), "/")
+	if offset := len(pathSegments) - len(templateSegments); offset >= 0 {
+		for i, segment := range templateSegments {
+			start, end := strings.Index(segment, "{"), strings.LastIndex(segment, "}")
+			if start < 0 || end < start {
+				continue
+			}
+			key := segment[start+1 : end]
+			raw := pathSegments[offset+i]
+			if !strings.HasPrefix(raw, segment[:start]) || !strings.HasSuffix(raw, segment[end+1:]) {
+				continue
+			}
+			raw = strings.TrimSuffix(strings.TrimPrefix(raw, segment[:start]), segment[end+1:])
+			value, err := url.PathUnescape(raw)
+			if err != nil || value == "{"+key+"}" {
+				continue // 치환되지 않은 자리 표시자
+			}
+			add(key, value)
+		}
+	}
+	for k, values := range target.Query() {
+		for _, v := range values {
+			add(k, v)
+		}
+	}
+	return targets
+}
+
+// resolveUserScope 캐시 또는 mc-iam-manager listUserWorkspaces에서 사용자 범위 조회
+// 대리 요청은 관리자 토큰으로 대상 사용자의 워크스페이스 목록(Getuserworkspacesbyuserid)을 조회한다.
+func resolveUserScope(c echo.Context, cfg *config.Config) (*service.UserScope, error) {
+	authHeader := backendUserAuthHeader(c)
+	if authHeader == "" {
+		return nil, fmt.Errorf("no authorization token")
+	}
+
+	// 캐시 키: 인증된 사용자 ID (인가 비활성으로 AuthMiddleware가 없으면 토큰 해시)
+	cacheKey := middleware.GetUserID(c)
+	if cacheKey == "" {
+		cacheKey = "token:" + jwt.TokenHash(strings.TrimPrefix(authHeader, "Bearer "))
+	}
+	cache := service.GetScopeCache()
+	if scope, ok := cache.Get(cacheKey); ok {
+		return scope, nil
+	}
+
+	operation, reqBody := "listUserWorkspaces", "{}"
+	if middleware.IsImpersonated(c) {
+		operation, _ = service.ImpersonationBackendAction("mc-iam-manager", operation)
+		reqBody = ""
+	}
+	svc, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", operation)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found in api.yaml: %w", operation, err)
+	}
+	resourcePath := actionSpec.ResourcePath
+	if middleware.IsImpersonated(c) {
+		iamUserID, _ := c.Get("iamUserId").(string)
+		if iamUserID == "" {
+			return nil, fmt.Errorf("impersonation token has no MCIAM user id")
+		}
+		resourcePath = strings.ReplaceAll(resourcePath, "{userId}", iamUserID)
+	}
+	httpReq, err := http.NewRequest(strings.ToUpper(actionSpec.Method), svc.BaseURL+resourcePath, strings.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", authHeader)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", operation, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d", operation, resp.StatusCode)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("%s response parse failed: %w", operation, err)
+	}
+	scope := service.BuildUserScope(data)
+	cache.Put(cacheKey, scope)
+	return scope, nil
+}
+
+// invalidateScopeOnMembershipChange workspace/project 관련 IAM 변경 요청이 성공하면 범위 캐시를 비운다.
+// 대상 사용자를 응답에서 특정할 수 없으므로 전체를 무효화한다.
+func invalidateScopeOnMembershipChange(subsystem, operationID, method string, status int) {
+	if !strings.EqualFold(subsystem, "mc-iam-manager") || strings.EqualFold(method, http.MethodGet) || status >= 300 {
+		return
+	}
+	op := strings.ToLower(operationID)
+	if strings.Contains(op, "workspace") || strings.Contains(op, "project") {
+		service.GetScopeCache().Clear()
+	}
+}
+
+// containsFold 대소문자 무시 포함 여부
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
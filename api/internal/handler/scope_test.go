@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestScopedParams(t *testing.T) {
+	cfg := config.ScopeConfig{NamespaceParams: []string{"nsId", "ns_id"}}
+	tests := []struct {
+		name         string
+		resourcePath string
+		target       string
+		want         []scopedParam
+	}{
+		{
+			name:         "path segment aligned from the end",
+			resourcePath: "/ns/{nsId}/mci/{mciId}",
+			target:       "http://tb:1323/tumblebug/ns/ns01/mci/m1",
+			want:         []scopedParam{{Param: "nsId", Value: "ns01", Kind: "namespace"}},
+		},
+		{
+			name:         "prefix and suffix around placeholder",
+			resourcePath: "/ns/ns-{nsId}.json",
+			target:       "http://tb/ns/ns-ns01.json",
+			want:         []scopedParam{{Param: "nsId", Value: "ns01", Kind: "namespace"}},
+		},
+		{
+			name:         "segment without template prefix is ignored",
+			resourcePath: "/ns/ns-{nsId}",
+			target:       "http://tb/ns/other",
+		},
+		{
+			name:         "escaped path value",
+			resourcePath: "/workspaces/{workspaceId}/projects/{projectId}",
+			target:       "http://iam/api/workspaces/ws%2001/projects/p%2F1",
+			want: []scopedParam{
+				{Param: "workspaceId", Value: "ws 01", Kind: "workspace"},
+				{Param: "projectId", Value: "p/1", Kind: "project"},
+			},
+		},
+		{
+			name:         "unsubstituted placeholder",
+			resourcePath: "/ns/{nsId}/mci",
+			target:       "http://tb/ns/%7BnsId%7D/mci",
+		},
+		{
+			name:         "target shorter than template",
+			resourcePath: "/ns/{nsId}/mci/{mciId}",
+			target:       "http://tb/ns01",
+		},
+		{
+			name:         "template query string is not a segment",
+			resourcePath: "/ns/{nsId}/resources?option=id",
+			target:       "http://tb/ns/ns01/resources?option=id",
+			want:         []scopedParam{{Param: "nsId", Value: "ns01", Kind: "namespace"}},
+		},
+		{
+			name:         "every query value",
+			resourcePath: "/mci",
+			target:       "http://tb/mci?nsId=ns01&nsId=ns02&nsId=",
+			want: []scopedParam{
+				{Param: "nsId", Value: "ns01", Kind: "namespace"},
+				{Param: "nsId", Value: "ns02", Kind: "namespace"},
+			},
+		},
+		{
+			name:         "case folded keys",
+			resourcePath: "/ns/{NSID}",
+			target:       "http://tb/ns/ns01?WORKSPACEID=ws01&ProjectID=p01&Ns_Id=ns02",
+			want: []scopedParam{
+				{Param: "NSID", Value: "ns01", Kind: "namespace"},
+				{Param: "Ns_Id", Value: "ns02", Kind: "namespace"},
+				{Param: "ProjectID", Value: "p01", Kind: "project"},
+				{Param: "WORKSPACEID", Value: "ws01", Kind: "workspace"},
+			},
+		},
+		{
+			name:         "unrelated parameters",
+			resourcePath: "/mci/{mciId}",
+			target:       "http://tb/mci/m1?option=id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := url.Parse(tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := sortScopedParams(scopedParams(cfg, tt.resourcePath, target))
+			if !reflect.DeepEqual(got, sortScopedParams(tt.want)) {
+				t.Errorf("scopedParams = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// sortScopedParams 쿼리 순서(map 순회)에 영향받지 않도록 종류·파라미터·값 순으로 정렬
+func sortScopedParams(params []scopedParam) []scopedParam {
+	sorted := append([]scopedParam(nil), params...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Param != b.Param {
+			return a.Param < b.Param
+		}
+		return a.Value < b.Value
+	})
+	return sorted
+}
+
+func TestEnforceScope(t *testing.T) {
+	cfg := &config.Config{
+		MCIAM: config.MCIAMConfig{Use: true},
+		Scope: config.ScopeConfig{
+			Enabled:          true,
+			BypassRoles:      []string{"platformAdmin"},
+			NamespaceParams:  []string{"nsId"},
+			ExemptSubsystems: []string{"mc-iam-manager"},
+		},
+	}
+	service.GetScopeCache().Put("user01", &service.UserScope{
+		Workspaces: map[string]bool{"ws01": true},
+		Projects:   map[string]bool{"p01": true},
+		Namespaces: map[string]bool{"ns01": true},
+		FetchedAt:  time.Now(),
+	})
+	t.Cleanup(func() { service.GetScopeCache().InvalidateUser("user01") })
+
+	tests := []struct {
+		name      string
+		userID    string
+		roles     []string
+		pat       bool
+		subsystem string
+		target    string
+		want      int
+	}{
+		{"own namespace", "user01", []string{"viewer"}, false, "mc-infra-manager", "http://tb/ns/NS01/mci", http.StatusOK},
+		{"other namespace", "user01", []string{"viewer"}, false, "mc-infra-manager", "http://tb/ns/ns02/mci", http.StatusForbidden},
+		{"other namespace in query", "user01", []string{"viewer"}, false, "mc-infra-manager", "http://tb/ns/ns01/mci?nsId=ns02", http.StatusForbidden},
+		{"bypass role", "user01", []string{"viewer", "PlatformAdmin"}, false, "mc-infra-manager", "http://tb/ns/ns02/mci", http.StatusOK},
+		{"exempt subsystem", "user01", []string{"viewer"}, false, "mc-iam-manager", "http://tb/ns/ns02/mci", http.StatusOK},
+		{"no scoped parameter", "user02", []string{"viewer"}, false, "mc-infra-manager", "http://tb/ns", http.StatusOK},
+		// user02는 토큰이 없어 범위를 조회할 수 없다
+		{"scope unavailable", "user02", []string{"viewer"}, false, "mc-infra-manager", "http://tb/ns/ns01/mci", http.StatusServiceUnavailable},
+		// 캐시된 범위가 있어도 액세스 토큰 요청은 범위 검사 대상 호출을 할 수 없다
+		{"access token", "user01", []string{"viewer"}, true, "mc-infra-manager", "http://tb/ns/ns01/mci", http.StatusForbidden},
+		{"access token without scoped parameter", "user01", []string{"viewer"}, true, "mc-infra-manager", "http://tb/ns", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+			switch {
+			case tt.pat:
+				req.Header.Set("Authorization", "Bearer "+service.AccessTokenPrefix+"0123456789")
+			case tt.userID == "user01":
+				req.Header.Set("Authorization", "Bearer user01-token")
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			if tt.pat {
+				c.Set("tokenScopes", []string{"*"})
+			}
+			c.Set("userId", tt.userID)
+			c.Set("roles", tt.roles)
+			target, err := url.Parse(tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ok, err := enforceScope(c, cfg, tt.subsystem, "/ns/{nsId}/mci", target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := http.StatusOK
+			if !ok {
+				got = rec.Code
+			}
+			if got != tt.want {
+				t.Fatalf("status = %d, want %d: %s", got, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBackendUserAuthHeaderDropsAccessToken(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"Bearer user01-token", "Bearer user01-token"},
+		{"Bearer " + service.AccessTokenPrefix + "0123456789", ""},
+		{service.AccessTokenPrefix + "0123456789", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.Header.Set("Authorization", tt.header)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		if got := backendUserAuthHeader(c); got != tt.want {
+			t.Errorf("backendUserAuthHeader(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 회원가입 정책 적용과 관리자 승인 대기열/초대 코드 핸들러.
+// 승인 대기열은 webconsole_menu_resources.yaml의 approvals 메뉴에서 사용한다.
+
+// CreateInvitationRequest 초대 코드 발급 요청
+type CreateInvitationRequest struct {
+	Request struct {
+		Email         string `json:"email"`           // 지정 시 해당 이메일만 사용 가능
+		MaxUses       int    `json:"max_uses"`        // 0이면 1
+		ExpiresInDays int    `json:"expires_in_days"` // 0이면 기본 유효기간
+	} `json:"request"`
+}
+
+// CreatedInvitation 발급 응답. code 원문은 이 응답에서만 확인할 수 있다.
+type CreatedInvitation struct {
+	Code       string                  `json:"code"`
+	Invitation *model.SignupInvitation `json:"invitation"`
+}
+
+// SignupDecisionRequest 회원가입 승인/거절 요청
+type SignupDecisionRequest struct {
+	Request struct {
+		Reason string `json:"reason"`
+	} `json:"request"`
+}
+
+// enforceSignupPolicy 비밀번호 정책, 이메일 도메인, 초대 코드를 확인한다.
+// 관리자 승인이 필요하면 요청을 대기열에 저장(초대 사용 처리 포함)하고 202 응답을 보낸 뒤 true를 반환한다.
+// 바로 가입하는 경우 확인된 초대를 반환하며, 호출자는 가입이 성공한 뒤에만 consumeSignupInvitation으로 사용 처리한다.
+func enforceSignupPolicy(c echo.Context, req SignupRequestBody, cfg *config.Config) (bool, *model.SignupInvitation, error) {
+	policy := service.GetPasswordPolicy()
+	if err := policy.CheckEmailDomain(req.Email); err != nil {
+		return false, nil, signupError(err)
+	}
+	if err := policy.Validate(req.Password); err != nil {
+		return false, nil, signupError(err)
+	}
+
+	needsStore := cfg.Signup.InvitationRequired || cfg.Signup.ApprovalRequired || strings.TrimSpace(req.InvitationCode) != ""
+	if !needsStore {
+		return false, nil, nil
+	}
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return false, nil, err
+	}
+	if cfg.Signup.ApprovalRequired && !cfg.MCIAM.Use {
+		// 이미 가입된 이메일이면 대기열에 넣지 않는다 (초대 코드도 소모하지 않음)
+		userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+		exists, err := userService.ExistsUser(strings.TrimSpace(req.Email))
+		if err != nil {
+			return false, nil, errors.NewInternalServerError("Local user store error", err)
+		}
+		if exists {
+			return false, nil, localAuthError(service.ErrUserExists)
+		}
+	}
+
+	invitation, err := svc.CheckInvitation(req.InvitationCode, req.Email)
+	if err != nil {
+		return false, nil, signupError(err)
+	}
+	if !cfg.Signup.ApprovalRequired {
+		return false, invitation, nil
+	}
+
+	in := service.PendingSignupInput{
+		Email:        req.Email,
+		Password:     req.Password,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		Organization: req.Organization,
+		IP:           c.RealIP(),
+	}
+	if invitation != nil {
+		in.InvitationID = invitation.ID
+	}
+	pending, err := svc.Submit(in)
+	if err != nil {
+		return false, nil, signupError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionSignupRequested,
+		Actor:  pending.Email,
+		Target: pending.ID,
+		IP:     c.RealIP(),
+	})
+	emitAuthEvent(c, model.AuthEventSignup, model.SignupEventData{Email: pending.Email, Status: "pending"})
+
+	resp := model.NewCommonResponse(http.StatusAccepted, "Accepted", map[string]interface{}{
+		"email":             pending.Email,
+		"approval_required": true,
+	})
+	return true, nil, c.JSON(resp.Status.Code, resp)
+}
+
+// consumeSignupInvitation 가입 성공 후 초대 사용 처리. 가입은 이미 끝났으므로 실패는 로그만 남긴다.
+func consumeSignupInvitation(c echo.Context, invitation *model.SignupInvitation) {
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		log.Printf("[Signup] failed to consume invitation %s: %v", invitation.ID, err)
+		return
+	}
+	if err := svc.ConsumeInvitation(invitation.ID); err != nil {
+		log.Printf("[Signup] failed to consume invitation %s: %v", invitation.ID, err)
+	}
+}
+
+// ListSignups 회원가입 요청 목록 핸들러
+// @Summary     List signup requests
+// @Description List signup requests waiting for (or decided by) admin approval
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       status query string false "pending | approved | rejected (default: all)"
+// @Success     200 {object} model.CommonResponse{responseData=[]model.PendingSignup}
+// @Failure     401 {object} model.CommonResponse
+// @Router      /api/admin/signups [get]
+func ListSignups(c echo.Context) error {
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	list, err := svc.ListPending(c.QueryParam("status"))
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list signup requests", err)
+	}
+	resp := model.CommonResponseStatusOK(list)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ApproveSignup 회원가입 승인 핸들러
+// @Summary     Approve signup request
+// @Description Create the account of a pending signup (local store or mc-iam-manager)
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Signup request ID"
+// @Success     200 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Failure     409 {object} model.CommonResponse
+// @Router      /api/admin/signups/{id}/approve [post]
+func ApproveSignup(c echo.Context) error {
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	cfg := c.Get("config").(*config.Config)
+	pending, password, err := svc.GetPending(c.Param("id"))
+	if err != nil {
+		return signupError(err)
+	}
+
+	data := map[string]interface{}{"id": pending.ID, "email": pending.Email}
+	if cfg.MCIAM.Use {
+		status, body, err := callMCIAMSignup(cfg, SignupRequestBody{
+			Email:        pending.Email,
+			Password:     password,
+			FirstName:    pending.FirstName,
+			LastName:     pending.LastName,
+			Organization: pending.Organization,
+		})
+		if err != nil {
+			return err
+		}
+		if status >= http.StatusMultipleChoices {
+			return errors.New(status, fmt.Sprintf("MCIAM signup failed: %s", strings.TrimSpace(string(body))), nil)
+		}
+	} else {
+		userService := service.NewLocalUserService(repository.NewUserRepository(repository.GetDB()), cfg.LocalAuth)
+		user, verifyToken, err := userService.CreateApproved(pending)
+		if err != nil {
+			return localAuthError(err)
+		}
+		data["user_id"] = user.LoginID
+		data["email_verification_required"] = verifyToken != ""
+		if verifyToken != "" {
+			deliverLocalUserToken(cfg, user.Email, model.LocalUserTokenEmailVerify, verifyToken, data)
+		}
+	}
+
+	actor := middleware.GetUserID(c)
+	if err := svc.Decide(pending.ID, model.SignupStatusApproved, actor, ""); err != nil {
+		return signupError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionSignupApproved,
+		Actor:  actor,
+		Target: pending.Email,
+		IP:     c.RealIP(),
+		Detail: "signup=" + pending.ID,
+	})
+	resp := model.CommonResponseStatusOK(data)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RejectSignup 회원가입 거절 핸들러
+// @Summary     Reject signup request
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Signup request ID"
+// @Param       request body SignupDecisionRequest false "Reason"
+// @Success     200 {object} model.CommonResponse
+// @Failure     409 {object} model.CommonResponse
+// @Router      /api/admin/signups/{id}/reject [post]
+func RejectSignup(c echo.Context) error {
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	var req SignupDecisionRequest
+	_ = c.Bind(&req)
+
+	actor := middleware.GetUserID(c)
+	id := c.Param("id")
+	if err := svc.Decide(id, model.SignupStatusRejected, actor, req.Request.Reason); err != nil {
+		return signupError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionSignupRejected,
+		Actor:  actor,
+		Target: id,
+		IP:     c.RealIP(),
+		Detail: req.Request.Reason,
+	})
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"id": id, "status": model.SignupStatusRejected})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// ListInvitations 초대 코드 목록 핸들러
+// @Summary     List signup invitations
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=[]model.SignupInvitation}
+// @Router      /api/admin/invitations [get]
+func ListInvitations(c echo.Context) error {
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	list, err := svc.ListInvitations()
+	if err != nil {
+		return errors.NewInternalServerError("Failed to list invitations", err)
+	}
+	resp := model.CommonResponseStatusOK(list)
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// CreateInvitation 초대 코드 발급 핸들러
+// @Summary     Create signup invitation
+// @Description Issue an invitation code. The code is returned only in this response.
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       request body CreateInvitationRequest true "Invitation"
+// @Success     200 {object} model.CommonResponse{responseData=CreatedInvitation}
+// @Failure     400 {object} model.CommonResponse
+// @Router      /api/admin/invitations [post]
+func CreateInvitation(c echo.Context) error {
+	var req CreateInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewBadRequest("Invalid request body")
+	}
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	actor := middleware.GetUserID(c)
+	code, invitation, err := svc.CreateInvitation(service.InvitationInput{
+		Email:     req.Request.Email,
+		MaxUses:   req.Request.MaxUses,
+		ExpiresIn: time.Duration(req.Request.ExpiresInDays) * 24 * time.Hour,
+		CreatedBy: actor,
+	})
+	if err != nil {
+		return signupError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionInvitationCreated,
+		Actor:  actor,
+		Target: invitation.ID,
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("email=%s max_uses=%d", invitation.Email, invitation.MaxUses),
+	})
+	resp := model.CommonResponseStatusOK(CreatedInvitation{Code: code, Invitation: invitation})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// RevokeInvitation 초대 코드 폐기 핸들러
+// @Summary     Revoke signup invitation
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "Invitation ID"
+// @Success     200 {object} model.CommonResponse
+// @Failure     404 {object} model.CommonResponse
+// @Router      /api/admin/invitations/{id} [delete]
+func RevokeInvitation(c echo.Context) error {
+	svc, err := signupServiceFromContext(c)
+	if err != nil {
+		return err
+	}
+	if err := svc.RevokeInvitation(c.Param("id")); err != nil {
+		return signupError(err)
+	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionInvitationRevoked,
+		Actor:  middleware.GetUserID(c),
+		Target: c.Param("id"),
+		IP:     c.RealIP(),
+	})
+	resp := model.CommonResponseStatusOK(map[string]interface{}{"revoked": c.Param("id")})
+	return c.JSON(resp.Status.Code, resp)
+}
+
+// signupServiceFromContext 회원가입 서비스 생성. DB가 없으면 503 반환.
+func signupServiceFromContext(c echo.Context) (*service.SignupService, error) {
+	cfg, _ := c.Get("config").(*config.Config)
+	if cfg == nil {
+		return nil, errors.NewInternalServerError("config not available", nil)
+	}
+	db := repository.GetDB()
+	if db == nil {
+		return nil, errors.New(http.StatusServiceUnavailable, "Invitations and signup approval require database", nil)
+	}
+	return service.NewSignupService(repository.NewSignupRepository(db), cfg.Signup, cfg.MCIAM.Use), nil
+}
+
+// signupError 회원가입 정책/서비스 에러를 HTTP 에러로 변환
+func signupError(err error) error {
+	switch {
+	case stderrors.Is(err, service.ErrWeakPassword), stderrors.Is(err, service.ErrBreachedPassword):
+		return errors.NewBadRequest(err.Error())
+	case stderrors.Is(err, service.ErrPasswordCheckFailed):
+		return errors.New(http.StatusServiceUnavailable, "Password policy check is unavailable", err)
+	case stderrors.Is(err, service.ErrEmailDomainNotAllowed):
+		return errors.NewForbidden(err.Error())
+	case stderrors.Is(err, service.ErrInvitationRequired), stderrors.Is(err, service.ErrInvitationInvalid):
+		return errors.NewForbidden(err.Error())
+	case stderrors.Is(err, service.ErrInvitationMaxUses), stderrors.Is(err, service.ErrInvitationExpiry):
+		return errors.NewBadRequest(err.Error())
+	case stderrors.Is(err, service.ErrSignupPending), stderrors.Is(err, service.ErrSignupNotPending):
+		return errors.New(http.StatusConflict, err.Error(), nil)
+	case stderrors.Is(err, service.ErrSignupNotFound), stderrors.Is(err, service.ErrInvitationNotFound):
+		return errors.NewNotFound(err.Error())
+	case stderrors.Is(err, service.ErrSignupKeyMissing):
+		return errors.New(http.StatusServiceUnavailable, "Signup approval in MCIAM mode requires MC_WEB_CONSOLE_SIGNUP_ENCRYPTION_KEY", nil)
+	default:
+		return errors.NewInternalServerError("Signup operation failed", err)
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ticketOperation mc-iam-manager의 RPT 발급 액션 (api.yaml serviceActions)
+const ticketOperation = "Getpermissionticket"
+
+// ticketResponse mc-iam-manager /api/ticket 응답 (Keycloak UMA RPT)
+type ticketResponse struct {
+	AccessToken string  `json:"access_token"`
+	ExpiresIn   float64 `json:"expires_in"`
+}
+
+// acquireTicket MCIAM 티켓 검증 모드에서 사용자의 subsystem/operation RPT를 확보한다.
+// 캐시에 유효한 티켓이 있으면 재사용하고, 없으면 mc-iam-manager에서 발급받는다.
+// 권한이 없으면 권한 이름을 담은 403을 응답하고 false를 반환한다. 모드가 꺼져 있으면 ("", true, nil).
+func acquireTicket(c echo.Context, cfg *config.Config, subsystem, operation string) (string, bool, error) {
+	if !cfg.MCIAM.Use || !cfg.MCIAM.TicketUse || strings.EqualFold(subsystem, "mc-web-console") {
+		return "", true, nil
+	}
+	permission := service.PermissionName(subsystem, operation)
+
+	if middleware.IsAccessTokenAuth(c) {
+		return "", false, ticketDenied(c, permission, "access tokens cannot obtain MCIAM tickets")
+	}
+	// 대리 요청의 RPT는 관리자 토큰으로 발급되므로 대상 사용자가 아닌 관리자 키로 캐시한다
+	userID := middleware.GetUserID(c)
+	if middleware.IsImpersonated(c) {
+		userID = middleware.GetImpersonator(c)
+	}
+	authHeader := backendUserAuthHeader(c)
+	if userID == "" {
+		userID = jwt.PeekUserID(strings.TrimPrefix(authHeader, "Bearer "))
+	}
+	if authHeader == "" || userID == "" {
+		return "", false, errors.NewUnauthorized("Missing authorization token")
+	}
+
+	// 인가가 꺼져 있으면 userID는 검증되지 않은 값이므로 토큰 해시까지 일치해야 캐시를 재사용한다
+	tokenHash := jwt.TokenHash(strings.TrimPrefix(authHeader, "Bearer "))
+	cache := service.GetTicketCache()
+	if ticket, ok := cache.Get(userID, tokenHash, subsystem, operation); ok {
+		return ticket.Token, true, nil
+	}
+
+	ticket, status, err := requestTicket(cfg, authHeader, subsystem, operation)
+	if err != nil {
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			log.Printf("[Ticket] denied user=%s permission=%s: %v", userID, permission, err)
+			return "", false, ticketDenied(c, permission, "MCIAM denied the permission ticket")
+		}
+		return "", false, errors.New(http.StatusBadGateway, "Failed to obtain MCIAM permission ticket", err)
+	}
+	cache.Put(userID, tokenHash, subsystem, operation, ticket)
+	return ticket.Token, true, nil
+}
+
+// requestTicket mc-iam-manager에 framework/operationid 권한 RPT 발급 요청. 실패 시 백엔드 응답 상태 코드를 함께 반환한다.
+func requestTicket(cfg *config.Config, authHeader, subsystem, operation string) (service.MCIAMTicket, int, error) {
+	iam, actionSpec, err := cfg.ApiSpec.GetAction("mc-iam-manager", ticketOperation)
+	if err != nil {
+		return service.MCIAMTicket{}, 0, fmt.Errorf("%s not found in api.yaml: %w", ticketOperation, err)
+	}
+	body, _ := json.Marshal(map[string]string{"framework": subsystem, "operationid": operation})
+	req, err := http.NewRequest(strings.ToUpper(actionSpec.Method), iam.BaseURL+actionSpec.ResourcePath, bytes.NewReader(body))
+	if err != nil {
+		return service.MCIAMTicket{}, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", authHeader)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return service.MCIAMTicket{}, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return service.MCIAMTicket{}, resp.StatusCode, fmt.Errorf("ticket request returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed ticketResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil || parsed.AccessToken == "" {
+		return service.MCIAMTicket{}, resp.StatusCode, fmt.Errorf("ticket response has no access_token")
+	}
+	ticket := service.MCIAMTicket{Token: parsed.AccessToken, ExpiresAt: jwt.PeekExpiry(parsed.AccessToken)}
+	if ticket.ExpiresAt.IsZero() && parsed.ExpiresIn > 0 {
+		ticket.ExpiresAt = time.Now().Add(time.Duration(parsed.ExpiresIn) * time.Second)
+	}
+	return ticket, resp.StatusCode, nil
+}
+
+// ticketDenied 티켓 거부 403 응답 (요구 권한 이름 포함)
+func ticketDenied(c echo.Context, permission, reason string) error {
+	resp := model.NewCommonResponse(http.StatusForbidden, "Forbidden: missing permission "+permission,
+		map[string]interface{}{"permission": permission, "reason": reason})
+	return c.JSON(resp.Status.Code, resp)
+}
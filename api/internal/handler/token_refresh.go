@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 세션 중 MCIAM 토큰 만료 시 사용자 토큰 갱신.
+// 토큰은 유효하지만 백엔드가 401을 반환하면 프록시가 갱신 후 멱등 호출을 1회 재시도한다.
+// 만료된 토큰 자체는 AuthMiddleware에서 401로 거부되며, 클라이언트가 /api/auth/refresh로 갱신한다.
+// 새 토큰은 세션 DB와 Set-Cookie(front는 세션에 흡수)로 돌려준다.
+
+// refreshUserTokens 현재 요청 사용자의 staleToken을 갱신한다. 같은 사용자의 동시 갱신은 직렬화된다.
+// refresh token은 세션 DB(staleToken 해시로 조회) → RefreshToken 쿠키 순서로 찾는다.
+func refreshUserTokens(cfg *config.Config, c echo.Context, staleToken string) (*service.RefreshedTokens, error) {
+	if !cfg.MCIAM.Use {
+		return nil, fmt.Errorf("token refresh for backend calls requires MCIAM")
+	}
+	if staleToken == "" {
+		return nil, fmt.Errorf("no access token in request")
+	}
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		userID = jwt.PeekUserID(staleToken)
+	}
+
+	refreshed, err := service.GetRefreshCoordinator().Refresh(userID, staleToken, func() (*service.RefreshedTokens, error) {
+		refreshToken := sessionRefreshToken(staleToken)
+		if refreshToken == "" {
+			if cookie, cookieErr := c.Cookie(middleware.RefreshTokenCookie); cookieErr == nil {
+				refreshToken = cookie.Value
+			}
+		}
+		if refreshToken == "" {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return callMCIAMRefreshAndRotate(cfg, c, userID, refreshToken, "proxy")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	setAuthCookies(c, refreshed.AccessToken, refreshed.ExpiresIn, refreshed.RefreshToken, refreshed.RefreshExpiresIn)
+	c.Set("Authorization", "Bearer "+refreshed.AccessToken)
+	return refreshed, nil
+}
+
+// callMCIAMRefreshAndRotate mc-iam-manager loginrefresh로 토큰을 갱신하고 refresh token이 바인딩된 세션에 새 토큰을 저장한다
+func callMCIAMRefreshAndRotate(cfg *config.Config, c echo.Context, userID, refreshToken, source string) (*service.RefreshedTokens, error) {
+	status, body, err := callMCIAMRefresh(cfg, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("loginrefresh returned %d", status)
+	}
+	var resp LoginResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.AccessToken == "" {
+		return nil, fmt.Errorf("access_token not found in loginrefresh response")
+	}
+	rotateSessionAccessToken(refreshToken, resp.AccessToken, resp.ExpiresIn, resp.RefreshToken, resp.RefreshExpiresIn)
+	emitAuthEvent(c, model.AuthEventTokenRefreshed, model.TokenRefreshedEventData{UserID: userID, Source: source})
+	return &service.RefreshedTokens{
+		AccessToken:      resp.AccessToken,
+		ExpiresIn:        resp.ExpiresIn,
+		RefreshToken:     resp.RefreshToken,
+		RefreshExpiresIn: resp.RefreshExpiresIn,
+	}, nil
+}
+
+// refreshBackendToken 백엔드 401 후 재시도에 쓸 새 토큰. 대리 요청이면 관리자 세션 토큰을 갱신한다 (쿠키는 바꾸지 않음).
+func refreshBackendToken(cfg *config.Config, c echo.Context) (string, error) {
+	if middleware.IsImpersonated(c) {
+		return actorBackendToken(c, true)
+	}
+	refreshed, err := refreshUserTokens(cfg, c, middleware.RequestToken(c))
+	if err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
+// sessionRefreshToken access token에 바인딩된 세션의 refresh token (DB 비활성 또는 세션 없음이면 빈 값)
+func sessionRefreshToken(accessToken string) string {
+	db := repository.GetDB()
+	if db == nil {
+		return ""
+	}
+	session, err := service.NewSessionService(repository.NewSessionRepository(db)).GetSessionByTokenHash(jwt.TokenHash(accessToken))
+	if err != nil {
+		return ""
+	}
+	if session.IsRevoked() {
+		log.Printf("[TokenRefresh] session revoked (userID=%s)", session.UserID)
+		return ""
+	}
+	return session.RefreshToken
+}
+
+// canRetryAfterRefresh 백엔드 401을 토큰 갱신 후 재시도할 수 있는 호출인지 확인.
+// 멱등 메서드의 bearer 백엔드 호출만 대상이며, 액세스 토큰(PAT)·티켓(RPT) 호출은 제외한다.
+// 대리 호출은 관리자 세션 토큰을 갱신해 재시도한다.
+func canRetryAfterRefresh(c echo.Context, cfg *config.Config, svc *config.Service, method, ticket string) bool {
+	if !cfg.MCIAM.Use || svc.Auth.Type != "bearer" || ticket != "" {
+		return false
+	}
+	if middleware.IsAccessTokenAuth(c) {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
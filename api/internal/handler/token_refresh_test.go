@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/pkg/jwt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newRefreshTestConfig 401 → 갱신 → 재시도 흐름용 설정: backend는 fresh 토큰만 허용, mc-iam-manager는 loginrefresh만 응답
+func newRefreshTestConfig(t *testing.T, freshToken string) (*config.Config, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var backendAuth []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		backendAuth = append(backendAuth, r.Header.Get("Authorization"))
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer "+freshToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ns":[]}`))
+	}))
+	t.Cleanup(backend.Close)
+
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["refresh_token"] != "refresh-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":       freshToken,
+			"expires_in":         300,
+			"refresh_token":      "refresh-2",
+			"refresh_expires_in": 1800,
+		})
+	}))
+	t.Cleanup(iam.Close)
+
+	cfg := &config.Config{
+		ApiSpec: &config.ApiSpec{
+			Services: map[string]config.Service{
+				"mc-test-manager": {BaseURL: backend.URL, Auth: config.AuthConfig{Type: "bearer"}},
+				"mc-iam-manager":  {BaseURL: iam.URL},
+			},
+			ServiceActions: map[string]map[string]config.ActionSpec{
+				"mc-test-manager": {
+					"GetAllNs": {Method: "get", ResourcePath: "/ns"},
+					"PostNs":   {Method: "post", ResourcePath: "/ns"},
+				},
+				"mc-iam-manager": {"loginrefresh": {Method: "post", ResourcePath: "/api/auth/login/refresh"}},
+			},
+		},
+	}
+	cfg.MCIAM.Use = true
+	return cfg, &backendAuth
+}
+
+func newProxyTestContext(cfg *config.Config, operationID, staleToken string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/api/mc-test-manager/"+operationID, nil)
+	req.Header.Set("Authorization", "Bearer "+staleToken)
+	req.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: "refresh-1"})
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("subsystemName", "operationId")
+	c.SetParamValues("mc-test-manager", operationID)
+	c.Set("config", cfg)
+	return c, rec
+}
+
+func TestSubsystemAnyControllerRetriesAfterTokenRefresh(t *testing.T) {
+	cfg, backendAuth := newRefreshTestConfig(t, "fresh-access-retry")
+	c, rec := newProxyTestContext(cfg, "GetAllNs", "stale-access-retry")
+
+	if err := SubsystemAnyController(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after retry (body %s)", rec.Code, rec.Body.String())
+	}
+	want := []string{"Bearer stale-access-retry", "Bearer fresh-access-retry"}
+	if strings.Join(*backendAuth, ",") != strings.Join(want, ",") {
+		t.Fatalf("backend Authorization = %v, want %v", *backendAuth, want)
+	}
+	if cookies := strings.Join(rec.Header().Values("Set-Cookie"), ";"); !strings.Contains(cookies, "fresh-access-retry") ||
+		!strings.Contains(cookies, "refresh-2") {
+		t.Fatalf("Set-Cookie = %q, want refreshed tokens", cookies)
+	}
+}
+
+func TestSubsystemAnyControllerNoRetryForWrites(t *testing.T) {
+	cfg, backendAuth := newRefreshTestConfig(t, "fresh-access-post")
+	c, rec := newProxyTestContext(cfg, "PostNs", "stale-access-post")
+
+	if err := SubsystemAnyController(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnauthorized || len(*backendAuth) != 1 {
+		t.Fatalf("status = %d, backend calls = %d, want 401 without retry", rec.Code, len(*backendAuth))
+	}
+}
+
+func TestAuthMiddlewareRejectsExpiredToken(t *testing.T) {
+	stale, err := jwt.GenerateToken("user-mw", "User", "user-mw@example.com", "viewer", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, backendAuth := newRefreshTestConfig(t, "fresh-access-mw")
+	c, rec := newProxyTestContext(cfg, "GetAllNs", stale)
+
+	// 만료 토큰은 서버에서 갱신하지 않는다: 백엔드 호출도, refresh 쿠키 사용도 없이 401
+	err = middleware.AuthMiddleware(SubsystemAnyController)(c)
+	if status := responseStatus(t, rec, err); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401 for an expired token", status)
+	}
+	if len(*backendAuth) != 0 || len(rec.Header().Values("Set-Cookie")) != 0 {
+		t.Fatalf("backend calls = %v, Set-Cookie = %v, want neither", *backendAuth, rec.Header().Values("Set-Cookie"))
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"mc_web_console_api/internal/middleware"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetUserMenus 로그인 사용자에게 허용된 메뉴 핸들러 (front 서버 측 사이드바 렌더링/페이지 접근 차단용)
+// @Summary     User menus
+// @Description Full menu tree and the menu ids granted to the caller (mc-iam-manager, or the menu yaml and permission CSV in local mode)
+// @Tags        auth
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} model.CommonResponse{responseData=service.UserMenus}
+// @Failure     502 {object} model.CommonResponse
+// @Router      /api/auth/menus [get]
+func GetUserMenus(c echo.Context) error {
+	syncer, err := menuSyncerFromContext()
+	if err != nil {
+		return err
+	}
+	var menus *service.UserMenus
+	if middleware.IsImpersonated(c) {
+		// 백엔드에는 관리자 토큰이 전달되므로 대상 사용자 역할로 메뉴 권한 선언에서 계산한다
+		menus, err = syncer.DeclaredMenus(middleware.GetRoles(c))
+	} else {
+		menus, err = syncer.UserMenus(c.Request().Context(), backendUserAuthHeader(c), middleware.GetRoles(c))
+	}
+	if stderrors.Is(err, service.ErrMenuSyncUnavailable) {
+		return errors.New(http.StatusBadGateway, "Failed to read user menus from mc-iam-manager", err)
+	}
+	if err != nil {
+		return errors.NewInternalServerError("Failed to load user menus", err)
+	}
+	resp := model.CommonResponseStatusOK(menus)
+	return c.JSON(resp.Status.Code, resp)
+}
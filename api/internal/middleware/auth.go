@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	stderrors "errors"
+	"fmt"
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
 	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/service"
 	"mc_web_console_api/pkg/errors"
 	"mc_web_console_api/pkg/jwt"
 	"strings"
@@ -18,46 +23,158 @@ func AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return errors.NewUnauthorized("Missing authorization token")
 		}
 
-		// JWT 토큰 파싱 및 검증
-		claims, err := jwt.ParseToken(token)
+		// 개인 액세스 토큰/서비스 계정 토큰 (세션 바인딩 없음)
+		if service.IsAccessToken(token) {
+			if err := authenticateAccessToken(c, token); err != nil {
+				return err
+			}
+			return next(c)
+		}
+
+		// JWT 토큰 파싱 및 검증 (로컬 HS256 또는 MCIAM RS256)
+		claims, roles, err := parseAccessToken(token)
 		if err != nil {
 			return errors.NewUnauthorized("Invalid token")
 		}
 
-		// DB에서 세션 확인 (DB 사용 가능 시)
+		// 대리 토큰: 대상 사용자 세션 대신 발급한 관리자의 세션이 살아있는지 검증하고 호출마다 감사 로그를 남긴다
+		if claims.Act != nil {
+			db := repository.GetDB()
+			if db == nil {
+				return errors.NewUnauthorized("Impersonation requires database")
+			}
+			// 세션 ID가 있으면 ID로 확인한다 (관리자 access token이 갱신되어도 같은 세션). 이전 대리 토큰은 토큰 해시로 확인
+			sessionService := service.NewSessionService(repository.NewSessionRepository(db))
+			var sessionErr error
+			if claims.Act.SessionID != "" {
+				_, sessionErr = sessionService.ValidateSessionID(claims.Act.Subject, claims.Act.SessionID)
+			} else {
+				sessionErr = sessionService.ValidateTokenHash(claims.Act.Subject, claims.Act.SessionHash)
+			}
+			if sessionErr != nil {
+				return errors.NewUnauthorized("Impersonator " + strings.ToLower(sessionErrorMessage(sessionErr)))
+			}
+			setClaims(c, claims, roles)
+			c.Set("impersonator", claims.Act.Subject)
+			c.Set("impersonationReadOnly", claims.Act.ReadOnly)
+			c.Set("impersonatorSessionHash", claims.Act.SessionHash)
+			c.Set("impersonatorSessionId", claims.Act.SessionID)
+			c.Set("iamUserId", claims.IAMUserID)
+			err := next(c)
+			recordImpersonatedCall(c, err)
+			return err
+		}
+
+		// DB에서 세션 확인 (DB 사용 가능 시): 토큰 해시로 세션에 바인딩되어 있는지 검증
 		if db := repository.GetDB(); db != nil {
-			sessionRepo := repository.NewSessionRepository(db)
-			exists, err := sessionRepo.Exists(claims.UserID)
-			if err != nil || !exists {
-				return errors.NewUnauthorized("Session not found")
+			sessionService := service.NewSessionService(repository.NewSessionRepository(db))
+			if err := sessionService.ValidateAccessToken(claims.UserID, token); err != nil {
+				return errors.NewUnauthorized(sessionErrorMessage(err))
 			}
 		}
 
 		// Context에 사용자 정보 설정
-		c.Set("userId", claims.UserID)
-		c.Set("userName", claims.UserName)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
-		c.Set("Authorization", c.Request().Header.Get("Authorization"))
+		setClaims(c, claims, roles)
 
 		return next(c)
 	}
 }
 
-// extractToken Authorization 헤더에서 토큰 추출
-func extractToken(c echo.Context) string {
-	authHeader := c.Request().Header.Get("Authorization")
-	if authHeader == "" {
-		return ""
+// setClaims Context에 토큰의 사용자 정보 설정
+func setClaims(c echo.Context, claims *jwt.Claims, roles []string) {
+	c.Set("userId", claims.UserID)
+	c.Set("userName", claims.UserName)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+	c.Set("roles", roles)
+	c.Set("Authorization", "Bearer "+extractToken(c))
+}
+
+// recordImpersonatedCall 대리 호출 감사 로그 (관리자·대상 사용자·요청·결과 상태)
+func recordImpersonatedCall(c echo.Context, err error) {
+	status := c.Response().Status
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		status = appErr.Code
+	}
+	target := c.Request().URL.Path
+	if subsystem, operation := c.Param("subsystemName"), c.Param("operationId"); subsystem != "" && operation != "" {
+		target = subsystem + "/" + operation
 	}
+	service.RecordAudit(model.AuditEvent{
+		Action: model.AuditActionImpersonatedCall,
+		Actor:  GetImpersonator(c),
+		Target: GetUserID(c),
+		IP:     c.RealIP(),
+		Detail: fmt.Sprintf("%s %s status=%d", c.Request().Method, target, status),
+	})
+}
 
-	// "Bearer <token>" 형식에서 토큰 추출
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return ""
+// authenticateAccessToken 액세스 토큰을 DB에서 검증하고 Context에 주체 정보와 토큰 스코프를 설정한다.
+func authenticateAccessToken(c echo.Context, token string) error {
+	db := repository.GetDB()
+	cfg, _ := c.Get("config").(*config.Config)
+	if db == nil || cfg == nil {
+		return errors.NewUnauthorized("Access tokens require database")
+	}
+	// 로컬 사용자 모드에서는 소유자 상태/현재 역할을 확인한다 (비활성 사용자의 토큰 거부)
+	var users *repository.UserRepository
+	if !cfg.MCIAM.Use {
+		users = repository.NewUserRepository(db)
+	}
+	svc := service.NewAccessTokenService(repository.NewAccessTokenRepository(db), users, cfg.AccessToken)
+	principal, err := svc.Authenticate(token, c.RealIP())
+	if err != nil {
+		if stderrors.Is(err, service.ErrAccessTokenExpired) || stderrors.Is(err, service.ErrServiceAccountDisabled) ||
+			stderrors.Is(err, service.ErrAccessTokenOwner) {
+			return errors.NewUnauthorized("Access token expired or revoked")
+		}
+		return errors.NewUnauthorized("Invalid token")
 	}
 
-	return parts[1]
+	c.Set("userId", principal.UserID)
+	c.Set("userName", principal.UserName)
+	c.Set("email", principal.Email)
+	c.Set("role", principal.Role)
+	c.Set("roles", []string{principal.Role})
+	c.Set("tokenId", principal.TokenID)
+	c.Set("tokenScopes", principal.Scopes)
+	c.Set("serviceAccount", principal.ServiceAccount)
+	return nil
+}
+
+// parseAccessToken 토큰 검증. MCIAM 모드의 RS256 토큰은 mc-iam-manager 공개키(JWKS)로,
+// 그 외에는 BFF 로컬 HS256 키로 검증한다(refresh token 거부). 전체 역할 목록을 함께 반환한다.
+func parseAccessToken(token string) (*jwt.Claims, []string, error) {
+	if keySet := service.GetMCIAMKeySet(); keySet != nil && jwt.IsRS256(token) {
+		return keySet.ParseToken(token)
+	}
+	claims, err := jwt.ParseToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claims.TokenType == jwt.TokenTypeRefresh {
+		return nil, nil, fmt.Errorf("refresh token cannot be used as access token")
+	}
+	var roles []string
+	if claims.Role != "" {
+		roles = []string{claims.Role}
+	}
+	return claims, roles, nil
+}
+
+// sessionErrorMessage 세션 검증 실패 사유를 401 응답 메시지로 변환
+func sessionErrorMessage(err error) string {
+	switch {
+	case stderrors.Is(err, service.ErrSessionRevoked):
+		return "Session revoked"
+	case stderrors.Is(err, service.ErrSessionExpired):
+		return "Session expired"
+	case stderrors.Is(err, service.ErrSessionMismatch):
+		return "Token does not match session"
+	default:
+		return "Session not found"
+	}
 }
 
 // GetUserID Context에서 사용자 ID 조회
@@ -92,17 +209,72 @@ func GetRole(c echo.Context) string {
 	return ""
 }
 
+// GetRoles Context에서 역할 목록 조회 (MCIAM 토큰은 realm 역할 전체, 로컬 토큰은 단일 역할)
+func GetRoles(c echo.Context) []string {
+	if roles, ok := c.Get("roles").([]string); ok && len(roles) > 0 {
+		return roles
+	}
+	if role := GetRole(c); role != "" {
+		return []string{role}
+	}
+	return nil
+}
+
+// IsAccessTokenAuth 현재 요청이 개인 액세스 토큰/서비스 계정 토큰으로 인증되었는지 확인
+func IsAccessTokenAuth(c echo.Context) bool {
+	_, ok := c.Get("tokenScopes").([]string)
+	return ok
+}
+
+// GetTokenScopes Context에서 액세스 토큰 스코프 조회 (JWT 인증이면 nil)
+func GetTokenScopes(c echo.Context) []string {
+	scopes, _ := c.Get("tokenScopes").([]string)
+	return scopes
+}
+
+// GetImpersonator 대리 토큰이면 발급한 관리자 ID, 아니면 빈 문자열
+func GetImpersonator(c echo.Context) string {
+	impersonator, _ := c.Get("impersonator").(string)
+	return impersonator
+}
+
+// IsImpersonated 현재 요청이 대리 토큰으로 인증되었는지 확인
+func IsImpersonated(c echo.Context) bool {
+	return GetImpersonator(c) != ""
+}
+
+// IsImpersonationReadOnly 읽기 전용 대리 토큰인지 확인
+func IsImpersonationReadOnly(c echo.Context) bool {
+	readOnly, _ := c.Get("impersonationReadOnly").(bool)
+	return IsImpersonated(c) && readOnly
+}
+
+// RequireDirectLogin 사용자 본인의 세션 로그인에서만 허용되는 라우트용 미들웨어 (AuthMiddleware 뒤에 사용).
+// 로그아웃, 토큰 관리, 비밀번호/MFA 변경 등은 액세스 토큰이나 대리 토큰으로 호출할 수 없다.
+func RequireDirectLogin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if IsAccessTokenAuth(c) {
+			return errors.NewForbidden("This operation is not allowed with an access token")
+		}
+		if IsImpersonated(c) {
+			return errors.NewForbidden("This operation is not allowed while impersonating")
+		}
+		return next(c)
+	}
+}
+
 // OptionalAuthMiddleware 선택적 인증 미들웨어 (토큰 있으면 검증, 없어도 통과)
 func OptionalAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		token := extractToken(c)
-		if token != "" {
-			claims, err := jwt.ParseToken(token)
-			if err == nil {
+		if token != "" && !service.IsAccessToken(token) {
+			claims, roles, err := parseAccessToken(token)
+			if err == nil && claims.Act == nil {
 				c.Set("userId", claims.UserID)
 				c.Set("userName", claims.UserName)
 				c.Set("email", claims.Email)
 				c.Set("role", claims.Role)
+				c.Set("roles", roles)
 			}
 		}
 		return next(c)
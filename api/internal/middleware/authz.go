@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequirePermission 고정 BFF 라우트용 인가 미들웨어 (AuthMiddleware 뒤에 사용).
+// operation은 정책 CSV의 operation 컬럼 값(예: "admin:setup-yaml-check")이며 method는 요청 메서드를 사용한다.
+func RequirePermission(subsystem, operation string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if ok, err := Authorize(c, subsystem, operation, c.Request().Method); !ok {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// Authorize 현재 사용자 역할로 subsystem/operation/method 호출을 판정한다.
+// 거부되면 판정 근거를 담은 403 CommonResponse를 응답하고 false를 반환한다 (err는 응답 쓰기 결과).
+// 액세스 토큰 요청은 토큰 스코프를, 읽기 전용 대리 토큰은 메서드를 먼저 확인한다.
+// 인가가 비활성이면 역할 정책은 생략하지만 mc-web-console/admin:* 라우트는 관리자 역할만 허용한다.
+func Authorize(c echo.Context, subsystem, operation, method string) (bool, error) {
+	if IsAccessTokenAuth(c) && !service.TokenScopeAllows(GetTokenScopes(c), subsystem, operation) {
+		log.Printf("[Authz] denied token=%v user=%s %s/%s: outside token scopes",
+			c.Get("tokenId"), GetUserID(c), subsystem, operation)
+		resp := model.NewCommonResponse(http.StatusForbidden, "Forbidden: operation is outside the access token scopes",
+			map[string]interface{}{"subsystem": subsystem, "operation": operation, "scopes": GetTokenScopes(c)})
+		return false, c.JSON(resp.Status.Code, resp)
+	}
+
+	if IsImpersonationReadOnly(c) && !service.IsReadOnlyMethod(method) {
+		log.Printf("[Authz] denied impersonated write actor=%s user=%s %s %s/%s",
+			GetImpersonator(c), GetUserID(c), method, subsystem, operation)
+		resp := model.NewCommonResponse(http.StatusForbidden, "Forbidden: impersonation is read-only",
+			map[string]interface{}{"subsystem": subsystem, "operation": operation, "method": method})
+		return false, c.JSON(resp.Status.Code, resp)
+	}
+
+	var decision service.AuthzDecision
+	if authorizer := service.GetAuthorizer(); authorizer != nil {
+		decision = authorizer.Decide(GetRoles(c), subsystem, operation, method)
+	} else {
+		decision = service.DecideDisabled(GetRoles(c), subsystem, operation, method)
+	}
+	if decision.Allowed {
+		return true, nil
+	}
+
+	log.Printf("[Authz] denied user=%s roles=%v %s %s/%s: %s",
+		GetUserID(c), decision.Roles, decision.Method, subsystem, operation, decision.Reason)
+	resp := model.NewCommonResponse(http.StatusForbidden, "Forbidden: "+decision.Reason, decision)
+	return false, c.JSON(resp.Status.Code, resp)
+}
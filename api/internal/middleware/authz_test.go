@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequirePermissionAdminGateWithoutAuthorizer(t *testing.T) {
+	service.InitAuthorizer(nil, config.AuthzConfig{AdminRoles: []string{"platformAdmin", "admin"}})
+	t.Cleanup(func() { service.InitAuthorizer(nil, config.AuthzConfig{}) })
+
+	tests := []struct {
+		name      string
+		roles     []string
+		operation string
+		want      int
+	}{
+		{"admin", []string{"admin"}, "admin:menu-sync", http.StatusOK},
+		{"secondary admin role", []string{"viewer", "platformadmin"}, "admin:menu-sync", http.StatusOK},
+		{"operator", []string{"operator"}, "admin:menu-sync", http.StatusForbidden},
+		{"no role", nil, "admin:sessions", http.StatusForbidden},
+		{"operator on non-admin route", []string{"operator"}, "mfa:status", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/test", nil)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.Set("roles", tt.roles)
+
+			handler := RequirePermission("mc-web-console", tt.operation)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			if err := handler(c); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthorizeReadOnlyImpersonation(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"get", true},
+		{"HEAD", true},
+		{"post", false},
+		{"delete", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/mc-infra-manager/op", nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Set("roles", []string{"operator"})
+		c.Set("impersonator", "admin01")
+		c.Set("impersonationReadOnly", true)
+
+		ok, err := Authorize(c, "mc-infra-manager", "op", tt.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.want || (!ok && rec.Code != http.StatusForbidden) {
+			t.Fatalf("Authorize(%s) = %v (status %d), want %v", tt.method, ok, rec.Code, tt.want)
+		}
+	}
+}
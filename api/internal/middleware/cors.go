@@ -22,6 +22,7 @@ func CustomCORSConfig() echo.MiddlewareFunc {
 			echo.HeaderContentType,
 			echo.HeaderAccept,
 			echo.HeaderAuthorization,
+			CSRFHeader,
 		},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24시간
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 인증 쿠키/CSRF 이름 (front IsTokenExistMiddleware와 동일한 쿠키명 사용)
+const (
+	AccessTokenCookie  = "Authorization"
+	RefreshTokenCookie = "RefreshToken"
+	ImpersonatorCookie = "ImpersonatorToken"
+	CSRFCookie         = "mcwc_csrf"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// csrfExemptPaths 세션이 생기기 전 단계의 인증 엔드포인트 (이전 쿠키가 남아 있어도 로그인할 수 있도록 제외)
+var csrfExemptPaths = []string{
+	"/api/auth/login",
+	"/api/auth/signup",
+	"/api/auth/verify-email",
+	"/api/auth/password/reset",
+}
+
+// CSRFMiddleware 쿠키로 인증되는 상태 변경 요청의 CSRF 검증.
+// X-CSRF-Token 헤더가 mcwc_csrf 쿠키와 일치하거나(double-submit), Origin/Referer가 같은 호스트 또는 신뢰 목록이면 통과한다.
+// 인증 쿠키가 없는 요청(Authorization 헤더를 쓰는 API 클라이언트)은 CSRF 대상이 아니다.
+func CSRFMiddleware(cfg config.CookieConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if isSafeMethod(req.Method) || !hasAuthCookie(req) || isCSRFExempt(req.URL.Path) {
+				return next(c)
+			}
+			if token := req.Header.Get(CSRFHeader); token != "" {
+				if cookie, err := req.Cookie(CSRFCookie); err == nil &&
+					subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) == 1 {
+					return next(c)
+				}
+			}
+			if originTrusted(req, cfg.CSRFTrustedOrigins) {
+				return next(c)
+			}
+			return errors.NewForbidden("CSRF validation failed")
+		}
+	}
+}
+
+// extractToken 인증 토큰 추출: HttpOnly Authorization 쿠키, Authorization: Bearer 헤더(API 클라이언트) 순서
+func extractToken(c echo.Context) string {
+	if cookie, err := c.Cookie(AccessTokenCookie); err == nil && cookie.Value != "" {
+		value, unescapeErr := url.QueryUnescape(cookie.Value)
+		if unescapeErr != nil {
+			value = cookie.Value
+		}
+		if value = strings.TrimPrefix(value, "Bearer "); value != "" && value != "undefined" {
+			return value
+		}
+	}
+
+	authHeader := c.Request().Header.Get("Authorization")
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return ""
+	}
+	return parts[1]
+}
+
+// RequestToken 요청의 인증 토큰 (쿠키 또는 헤더). 백엔드 전달용 Authorization 구성에 사용한다.
+func RequestToken(c echo.Context) string {
+	return extractToken(c)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func hasAuthCookie(req *http.Request) bool {
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		if cookie, err := req.Cookie(name); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isCSRFExempt(path string) bool {
+	for _, p := range csrfExemptPaths {
+		if path == p || strings.HasPrefix(path, p+"/") || strings.HasPrefix(path, p+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// originTrusted Origin(없으면 Referer)의 호스트가 요청 호스트(X-Forwarded-Host 우선)이거나 신뢰 목록에 있는지 확인
+func originTrusted(req *http.Request, trusted []string) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" || origin == "null" {
+		referer, err := url.Parse(req.Header.Get("Referer"))
+		if err != nil || referer.Host == "" {
+			return false
+		}
+		origin = referer.Scheme + "://" + referer.Host
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	host := req.Header.Get("X-Forwarded-Host")
+	if host == "" {
+		host = req.Host
+	}
+	if strings.EqualFold(parsed.Host, host) {
+		return true
+	}
+	for _, t := range trusted {
+		if strings.EqualFold(strings.TrimSuffix(t, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// 액세스 토큰 소유자 종류
+const (
+	AccessTokenOwnerUser           = "user"
+	AccessTokenOwnerServiceAccount = "service_account"
+)
+
+// AccessToken 자동화용 개인 액세스 토큰(PAT) 또는 서비스 계정 토큰.
+// 원문은 발급 시 한 번만 반환하고 SHA-256 해시만 저장한다.
+type AccessToken struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	Name       string     `gorm:"not null" json:"name"`
+	TokenHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Prefix     string     `gorm:"type:varchar(32)" json:"prefix"` // 식별용 앞부분 (예: mcwc_pat_1a2b3c)
+	OwnerType  string     `gorm:"type:varchar(32);not null;index:idx_access_tokens_owner" json:"owner_type"`
+	OwnerID    string     `gorm:"not null;index:idx_access_tokens_owner" json:"owner_id"` // 사용자 ID 또는 서비스 계정 ID
+	UserName   string     `json:"user_name,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Role       string     `json:"role"`                    // 발급 시점의 역할 (서비스 계정은 계정 역할)
+	Scopes     string     `gorm:"type:text" json:"scopes"` // 콤마 구분 "subsystem/operation" 패턴
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at"` // nil이면 만료 없음
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (AccessToken) TableName() string {
+	return "access_tokens"
+}
+
+// ScopeList 스코프 패턴 목록
+func (t *AccessToken) ScopeList() []string {
+	var scopes []string
+	for _, s := range strings.Split(t.Scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// IsUsable 폐기/만료되지 않은 토큰인지 확인
+func (t *AccessToken) IsUsable(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// ServiceAccount 사람이 아닌 자동화 주체. 토큰은 AccessToken(OwnerType=service_account)으로 발급한다.
+type ServiceAccount struct {
+	ID          string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"uniqueIndex;not null" json:"name"`
+	Description string    `json:"description,omitempty"`
+	Role        string    `gorm:"not null" json:"role"`
+	Disabled    bool      `gorm:"not null;default:false" json:"disabled"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (ServiceAccount) TableName() string {
+	return "service_accounts"
+}
+
+// PrincipalID 인증 컨텍스트의 사용자 ID로 쓰이는 서비스 계정 식별자
+func (a *ServiceAccount) PrincipalID() string {
+	return "sa:" + a.Name
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// 감사 로그 이벤트 종류
+const (
+	AuditActionLoginLocked   = "login.locked"
+	AuditActionLoginUnlocked = "login.unlocked"
+
+	AuditActionTokenCreated          = "token.created"
+	AuditActionTokenRevoked          = "token.revoked"
+	AuditActionServiceAccountCreated = "service_account.created"
+	AuditActionServiceAccountUpdated = "service_account.updated"
+
+	AuditActionImpersonationStarted = "impersonation.started"
+	AuditActionImpersonatedCall     = "impersonation.call"
+	AuditActionImpersonationStopped = "impersonation.stopped"
+
+	AuditActionSignupRequested   = "signup.requested"
+	AuditActionSignupApproved    = "signup.approved"
+	AuditActionSignupRejected    = "signup.rejected"
+	AuditActionInvitationCreated = "invitation.created"
+	AuditActionInvitationRevoked = "invitation.revoked"
+
+	AuditActionRegistryRefreshed   = "registry.refreshed"
+	AuditActionRegistryInvalidated = "registry.invalidated"
+
+	AuditActionAssetSynced     = "asset.synced"
+	AuditActionAssetRolledBack = "asset.rolled_back"
+	AuditActionAssetReloaded   = "asset.reloaded"
+
+	AuditActionMenuSynced = "menu.synced"
+)
+
+// AuditEvent 보안 관련 감사 로그 이벤트
+type AuditEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Action    string    `gorm:"type:varchar(64);index;not null" json:"action"`
+	Actor     string    `gorm:"type:varchar(255)" json:"actor,omitempty"`  // 수행 주체 (관리자 ID, 시스템이면 "system")
+	Target    string    `gorm:"type:varchar(320)" json:"target,omitempty"` // 대상 (계정, IP 등)
+	IP        string    `gorm:"type:varchar(64)" json:"ip,omitempty"`
+	Detail    string    `gorm:"type:text" json:"detail,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
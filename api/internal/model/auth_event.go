@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// AuthEventSchemaVersion 인증 이벤트 envelope/data 스키마 버전.
+// 필드를 제거하거나 의미를 바꾸면 올리고, 필드 추가는 같은 버전에서 허용한다 (수신 측은 모르는 필드를 무시).
+const AuthEventSchemaVersion = 1
+
+// 인증 이벤트 종류 (webhook X-MCWC-Event 헤더, MC_WEB_CONSOLE_WEBHOOK_EVENTS 필터 값)
+const (
+	AuthEventLoginSucceeded = "auth.login.succeeded"
+	AuthEventLoginFailed    = "auth.login.failed"
+	AuthEventLogout         = "auth.logout"
+	AuthEventTokenRefreshed = "auth.token.refreshed"
+	AuthEventSignup         = "auth.signup"
+)
+
+// AuthEvent webhook으로 전송되는 인증 이벤트 envelope. Data는 Type별 *EventData 구조체다.
+type AuthEvent struct {
+	ID            string      `json:"id"`
+	Type          string      `json:"type"`
+	SchemaVersion int         `json:"schema_version"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+	IP            string      `json:"ip,omitempty"`
+	UserAgent     string      `json:"user_agent,omitempty"`
+	RequestID     string      `json:"request_id,omitempty"`
+	Data          interface{} `json:"data"`
+}
+
+// LoginSucceededEventData auth.login.succeeded
+type LoginSucceededEventData struct {
+	UserID  string `json:"user_id"`
+	Account string `json:"account"`
+	MFA     bool   `json:"mfa"` // 2차 인증을 거쳐 완료된 로그인
+}
+
+// LoginFailedEventData auth.login.failed
+type LoginFailedEventData struct {
+	Account string `json:"account"`
+	Status  int    `json:"status"` // 401 자격 증명 오류, 403 비활성/미인증, 429 잠금
+	Reason  string `json:"reason,omitempty"`
+}
+
+// LogoutEventData auth.logout
+type LogoutEventData struct {
+	UserID string `json:"user_id"`
+}
+
+// TokenRefreshedEventData auth.token.refreshed
+type TokenRefreshedEventData struct {
+	UserID string `json:"user_id"`
+	Source string `json:"source"` // client: /api/auth/refresh 호출, proxy: 프록시 401 후 자동 갱신
+}
+
+// SignupEventData auth.signup
+type SignupEventData struct {
+	Email  string `json:"email"`
+	Status string `json:"status"` // created | pending (관리자 승인 대기)
+}
+
+// WebhookDelivery webhook 전송 대기열 항목 (엔드포인트별 1건). 전송에 성공하면 삭제된다.
+type WebhookDelivery struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	EventID       string    `gorm:"type:varchar(64);index;not null" json:"event_id"`
+	EventType     string    `gorm:"type:varchar(64);not null" json:"event_type"`
+	Endpoint      string    `gorm:"type:text;not null" json:"endpoint"`
+	Payload       string    `gorm:"type:text;not null" json:"payload"`
+	Attempts      int       `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time `gorm:"index;not null" json:"next_attempt_at"`
+	LastError     string    `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeadLetter 최대 시도 횟수를 넘겨 전송을 포기한 항목. 관리자가 재전송할 수 있다.
+type WebhookDeadLetter struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EventID   string    `gorm:"type:varchar(64);index;not null" json:"event_id"`
+	EventType string    `gorm:"type:varchar(64);not null" json:"event_type"`
+	Endpoint  string    `gorm:"type:text;not null" json:"endpoint"`
+	Payload   string    `gorm:"type:text;not null" json:"payload"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (WebhookDeadLetter) TableName() string {
+	return "webhook_dead_letters"
+}
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// 로컬 사용자 상태
+const (
+	LocalUserStatusActive   = "active"
+	LocalUserStatusDisabled = "disabled"
+)
+
+// 로컬 사용자 1회용 토큰 용도
+const (
+	LocalUserTokenEmailVerify   = "email_verify"
+	LocalUserTokenPasswordReset = "password_reset"
+)
+
+// LocalUser MCIAM_USE=false 모드의 로컬 사용자 계정
+type LocalUser struct {
+	ID                string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	LoginID           string     `gorm:"uniqueIndex;not null" json:"login_id"`
+	Email             string     `gorm:"uniqueIndex;not null" json:"email"`
+	PasswordHash      string     `gorm:"type:text;not null" json:"-"`
+	FirstName         string     `json:"first_name"`
+	LastName          string     `json:"last_name"`
+	Organization      string     `json:"organization,omitempty"`
+	Role              string     `gorm:"not null" json:"role"`
+	Status            string     `gorm:"not null;default:'active'" json:"status"`
+	EmailVerified     bool       `gorm:"not null;default:false" json:"email_verified"`
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (LocalUser) TableName() string {
+	return "local_users"
+}
+
+// DisplayName 표시용 이름 (FirstName LastName, 없으면 LoginID)
+func (u *LocalUser) DisplayName() string {
+	name := u.FirstName
+	if u.LastName != "" {
+		if name != "" {
+			name += " "
+		}
+		name += u.LastName
+	}
+	if name == "" {
+		return u.LoginID
+	}
+	return name
+}
+
+// LocalUserToken 이메일 인증/비밀번호 재설정용 1회용 토큰 (원문은 저장하지 않고 해시만 저장)
+type LocalUserToken struct {
+	ID        string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    string     `gorm:"index;not null" json:"user_id"`
+	Purpose   string     `gorm:"index;not null" json:"purpose"`
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (LocalUserToken) TableName() string {
+	return "local_user_tokens"
+}
+
+// IsUsable 미사용 상태이며 만료되지 않은 토큰인지 확인
+func (t *LocalUserToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// LoginAttempt 로그인 실패 카운터 (계정 또는 IP 단위).
+// Key는 "account:<id>" 또는 "ip:<addr>" 형식이며, 여러 replica가 카운터를 공유할 때 사용된다.
+type LoginAttempt struct {
+	Key           string     `gorm:"primaryKey;type:varchar(320)" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	NextAllowedAt time.Time  `json:"next_allowed_at"` // 점진적 지연: 이 시각 전의 시도는 거부
+	LockedUntil   *time.Time `gorm:"index" json:"locked_until,omitempty"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked 주어진 시각에 잠금 상태인지 확인
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
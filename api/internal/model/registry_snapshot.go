@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// RegistrySnapshot 마지막으로 정상 수신한 mc-iam-manager 레지스트리 응답 (재시작 시 복원용)
+type RegistrySnapshot struct {
+	Name     string    `gorm:"primaryKey;type:varchar(64)" json:"name"`
+	Payload  string    `gorm:"type:text;not null" json:"payload"` // ListMcmpApisServices 응답 JSON
+	StoredAt time.Time `gorm:"not null" json:"stored_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (RegistrySnapshot) TableName() string {
+	return "registry_snapshots"
+}
@@ -9,14 +9,17 @@ import (
 // UserSession 사용자 세션 모델
 // Buffalo Pop의 Usersess 모델과 동일한 구조
 type UserSession struct {
-	ID               string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	UserID           string    `gorm:"index;not null" json:"user_id"`
-	AccessToken      string    `gorm:"type:text;not null" json:"access_token"`
-	ExpiresIn        float64   `gorm:"not null" json:"expires_in"`
-	RefreshToken     string    `gorm:"type:text;not null" json:"refresh_token"`
-	RefreshExpiresIn float64   `gorm:"not null" json:"refresh_expires_in"`
-	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID               string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID           string     `gorm:"index;not null" json:"user_id"`
+	AccessToken      string     `gorm:"type:text;not null" json:"access_token"`
+	ExpiresIn        float64    `gorm:"not null" json:"expires_in"`
+	RefreshToken     string     `gorm:"type:text;not null" json:"refresh_token"`
+	RefreshExpiresIn float64    `gorm:"not null" json:"refresh_expires_in"`
+	AccessTokenHash  string     `gorm:"type:varchar(64);index" json:"-"` // access token SHA-256 (요청 토큰 ↔ 세션 바인딩 키)
+	RevokedAt        *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	RefreshExpiresAt *time.Time `gorm:"index" json:"refresh_expires_at,omitempty"` // 세션 만료 시각 (refresh token 갱신 시 연장, 이전 행은 NULL)
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // TableName GORM 테이블명 지정 (Buffalo Pop과 동일)
@@ -38,8 +41,20 @@ func (s *UserSession) IsExpired() bool {
 	return time.Now().After(expiryTime)
 }
 
+// RefreshExpiry 세션(리프레시 토큰) 만료 시각. refresh_expires_at이 없는 이전 행은 생성 시각 기준으로 계산한다.
+func (s *UserSession) RefreshExpiry() time.Time {
+	if s.RefreshExpiresAt != nil {
+		return *s.RefreshExpiresAt
+	}
+	return s.CreatedAt.Add(time.Duration(s.RefreshExpiresIn) * time.Second)
+}
+
 // IsRefreshExpired 리프레시 토큰 만료 여부 확인
 func (s *UserSession) IsRefreshExpired() bool {
-	expiryTime := s.CreatedAt.Add(time.Duration(s.RefreshExpiresIn) * time.Second)
-	return time.Now().After(expiryTime)
+	return time.Now().After(s.RefreshExpiry())
+}
+
+// IsRevoked 로그아웃 등으로 폐기된 세션인지 확인
+func (s *UserSession) IsRevoked() bool {
+	return s.RevokedAt != nil
 }
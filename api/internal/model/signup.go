@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// 회원가입 승인 대기 상태
+const (
+	SignupStatusPending  = "pending"
+	SignupStatusApproved = "approved"
+	SignupStatusRejected = "rejected"
+)
+
+// SignupInvitation 초대 코드. 원문은 발급 시 한 번만 반환하고 SHA-256 해시만 저장한다.
+type SignupInvitation struct {
+	ID        string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	CodeHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Email     string     `json:"email,omitempty"` // 지정 시 해당 이메일만 사용 가능
+	MaxUses   int        `gorm:"not null;default:1" json:"max_uses"`
+	UsedCount int        `gorm:"not null;default:0" json:"used_count"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (SignupInvitation) TableName() string {
+	return "signup_invitations"
+}
+
+// IsUsable 폐기/만료/소진되지 않은 초대 코드인지 확인
+func (i *SignupInvitation) IsUsable(now time.Time) bool {
+	return i.RevokedAt == nil && now.Before(i.ExpiresAt) && i.UsedCount < i.MaxUses
+}
+
+// PendingSignup 관리자 승인을 기다리는 회원가입 요청.
+// 승인 시 계정을 생성해야 하므로 비밀번호는 로컬 모드면 bcrypt 해시, MCIAM 모드면 암호화된 원문으로 보관한다.
+type PendingSignup struct {
+	ID             string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	Email          string     `gorm:"index;not null" json:"email"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Organization   string     `json:"organization,omitempty"`
+	PasswordHash   string     `gorm:"type:text" json:"-"`
+	PasswordSealed string     `gorm:"type:text" json:"-"`
+	InvitationID   string     `json:"invitation_id,omitempty"`
+	Status         string     `gorm:"type:varchar(16);index;not null;default:'pending'" json:"status"`
+	IP             string     `gorm:"type:varchar(64)" json:"ip,omitempty"`
+	DecidedBy      string     `json:"decided_by,omitempty"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+	Reason         string     `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (PendingSignup) TableName() string {
+	return "pending_signups"
+}
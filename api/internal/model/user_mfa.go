@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// UserMFA 사용자 TOTP 2단계 인증 정보.
+// SecretEnc는 AES-GCM으로 암호화된 base32 시크릿이며, RecoveryCodes는 복구 코드 SHA-256 해시의 JSON 배열이다.
+type UserMFA struct {
+	UserID        string     `gorm:"primaryKey" json:"user_id"`
+	SecretEnc     string     `gorm:"type:text;not null" json:"-"`
+	Enabled       bool       `gorm:"not null;default:false" json:"enabled"`
+	RecoveryCodes string     `gorm:"type:text" json:"-"`
+	LastUsedStep  int64      `gorm:"not null;default:0" json:"-"` // 마지막으로 사용된 TOTP 스텝 (재사용 방지)
+	EnabledAt     *time.Time `json:"enabled_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName GORM 테이블명 지정
+func (UserMFA) TableName() string {
+	return "user_mfas"
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"time"
+
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AccessTokenRepository 액세스 토큰/서비스 계정 저장소
+type AccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessTokenRepository 새로운 액세스 토큰 저장소 생성
+func NewAccessTokenRepository(db *gorm.DB) *AccessTokenRepository {
+	return &AccessTokenRepository{db: db}
+}
+
+// Create 토큰 생성
+func (r *AccessTokenRepository) Create(token *model.AccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash 토큰 해시로 조회
+func (r *AccessTokenRepository) FindByHash(tokenHash string) (*model.AccessToken, error) {
+	var token model.AccessToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByID 토큰 ID로 조회
+func (r *AccessTokenRepository) FindByID(id string) (*model.AccessToken, error) {
+	var token model.AccessToken
+	if err := r.db.Where("id = ?", id).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByOwner 소유자의 토큰 목록 (최근 생성 순, 폐기 포함)
+func (r *AccessTokenRepository) ListByOwner(ownerType, ownerID string) ([]model.AccessToken, error) {
+	var tokens []model.AccessToken
+	err := r.db.Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Revoke 토큰 폐기
+func (r *AccessTokenRepository) Revoke(id string) error {
+	return r.db.Model(&model.AccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeByOwner 소유자의 모든 토큰 폐기 (서비스 계정 비활성화, 사용자 비밀번호 변경 시)
+func (r *AccessTokenRepository) RevokeByOwner(ownerType, ownerID string) error {
+	return r.db.Model(&model.AccessToken{}).
+		Where("owner_type = ? AND owner_id = ? AND revoked_at IS NULL", ownerType, ownerID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// TouchLastUsed 마지막 사용 시각/IP 갱신
+func (r *AccessTokenRepository) TouchLastUsed(id string, at time.Time, ip string) error {
+	return r.db.Model(&model.AccessToken{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
+
+// CreateServiceAccount 서비스 계정 생성
+func (r *AccessTokenRepository) CreateServiceAccount(account *model.ServiceAccount) error {
+	return r.db.Create(account).Error
+}
+
+// FindServiceAccount 서비스 계정 ID로 조회
+func (r *AccessTokenRepository) FindServiceAccount(id string) (*model.ServiceAccount, error) {
+	var account model.ServiceAccount
+	if err := r.db.Where("id = ?", id).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListServiceAccounts 서비스 계정 목록
+func (r *AccessTokenRepository) ListServiceAccounts() ([]model.ServiceAccount, error) {
+	var accounts []model.ServiceAccount
+	err := r.db.Order("name").Find(&accounts).Error
+	return accounts, err
+}
+
+// SetServiceAccountDisabled 서비스 계정 활성/비활성 변경
+func (r *AccessTokenRepository) SetServiceAccountDisabled(id string, disabled bool) error {
+	return r.db.Model(&model.ServiceAccount{}).Where("id = ?", id).Update("disabled", disabled).Error
+}
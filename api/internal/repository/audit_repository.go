@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository 감사 로그 저장소
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 새로운 감사 로그 저장소 생성
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create 감사 로그 이벤트 저장
+func (r *AuditRepository) Create(event *model.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// FindRecent 최근 감사 로그 조회 (action이 비어 있으면 전체)
+func (r *AuditRepository) FindRecent(action string, limit int) ([]model.AuditEvent, error) {
+	var events []model.AuditEvent
+	query := r.db.Order("created_at DESC").Limit(limit)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	err := query.Find(&events).Error
+	return events, err
+}
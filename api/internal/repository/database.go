@@ -58,6 +58,18 @@ func AutoMigrate() error {
 	// 모델 등록
 	models := []interface{}{
 		&model.UserSession{},
+		&model.LocalUser{},
+		&model.LocalUserToken{},
+		&model.UserMFA{},
+		&model.LoginAttempt{},
+		&model.AuditEvent{},
+		&model.AccessToken{},
+		&model.ServiceAccount{},
+		&model.SignupInvitation{},
+		&model.PendingSignup{},
+		&model.WebhookDelivery{},
+		&model.WebhookDeadLetter{},
+		&model.RegistrySnapshot{},
 	}
 
 	for _, model := range models {
@@ -85,6 +97,22 @@ func CloseDatabase() error {
 	return sqlDB.Close()
 }
 
+// WithAdvisoryLock Postgres 트랜잭션 advisory lock(pg_try_advisory_xact_lock)을 시도하고,
+// 획득한 경우에만 같은 트랜잭션 안에서 fn을 실행한다. 락은 트랜잭션 종료 시 자동 해제된다.
+// 여러 replica 중 하나만 작업을 수행해야 할 때 사용. acquired=false이면 다른 인스턴스가 수행 중.
+func WithAdvisoryLock(db *gorm.DB, key int64, fn func(tx *gorm.DB) error) (acquired bool, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if lockErr := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", key).Scan(&acquired).Error; lockErr != nil {
+			return fmt.Errorf("failed to acquire advisory lock %d: %w", key, lockErr)
+		}
+		if !acquired {
+			return nil
+		}
+		return fn(tx)
+	})
+	return acquired, err
+}
+
 // GetDB 데이터베이스 인스턴스 반환
 func GetDB() *gorm.DB {
 	return DB
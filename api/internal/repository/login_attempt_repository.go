@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository 로그인 실패 카운터 저장소
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository 새로운 로그인 실패 카운터 저장소 생성
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Find 키로 카운터 조회. 없으면 (nil, nil) 반환.
+func (r *LoginAttemptRepository) Find(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := r.db.Where("key = ?", key).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// loginAttemptIncrementSQL 실패 횟수를 원자적으로 1 늘린다. 잠금이 풀렸거나
+// (잠금 없이) 마지막 실패가 windowStart 이전이면 1부터 다시 센다.
+const loginAttemptIncrementSQL = `
+INSERT INTO login_attempts (key, failures, last_failure_at, next_allowed_at, updated_at)
+VALUES (@key, 1, @now, @zero, @now)
+ON CONFLICT (key) DO UPDATE SET
+	failures = CASE WHEN ` + loginAttemptResetSQL + ` THEN 1 ELSE login_attempts.failures + 1 END,
+	next_allowed_at = CASE WHEN ` + loginAttemptResetSQL + ` THEN @zero ELSE login_attempts.next_allowed_at END,
+	locked_until = CASE WHEN login_attempts.locked_until <= @now THEN NULL ELSE login_attempts.locked_until END,
+	last_failure_at = @now,
+	updated_at = @now
+RETURNING *`
+
+const loginAttemptResetSQL = `(login_attempts.locked_until <= @now OR (login_attempts.locked_until IS NULL AND login_attempts.last_failure_at < @windowStart))`
+
+// Increment 실패 횟수를 1 늘리고 갱신된 카운터를 반환한다 (동시 실패도 누락 없이 반영됨)
+func (r *LoginAttemptRepository) Increment(key string, now, windowStart time.Time) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := r.db.Raw(loginAttemptIncrementSQL, map[string]interface{}{
+		"key":         key,
+		"now":         now,
+		"zero":        time.Time{},
+		"windowStart": windowStart,
+	}).Scan(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Backoff 다음 시도 허용 시각을 until 이후로 늦춘다 (이미 더 늦으면 유지)
+func (r *LoginAttemptRepository) Backoff(key string, until time.Time) error {
+	return r.db.Model(&model.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("next_allowed_at", gorm.Expr("GREATEST(next_allowed_at, ?)", until)).Error
+}
+
+// Lock 잠기지 않은 카운터를 until까지 잠근다. 이미 잠겨 있으면 false (동시 호출 중 하나만 true).
+func (r *LoginAttemptRepository) Lock(key string, until, now time.Time) (bool, error) {
+	result := r.db.Model(&model.LoginAttempt{}).
+		Where("key = ? AND (locked_until IS NULL OR locked_until <= ?)", key, now).
+		Update("locked_until", until)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete 카운터 삭제 (로그인 성공 또는 관리자 잠금 해제)
+func (r *LoginAttemptRepository) Delete(key string) error {
+	return r.db.Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+}
+
+// FindLocked 현재 잠금 중인 카운터 목록 조회
+func (r *LoginAttemptRepository) FindLocked(now time.Time) ([]model.LoginAttempt, error) {
+	var attempts []model.LoginAttempt
+	err := r.db.Where("locked_until > ?", now).Order("locked_until DESC").Find(&attempts).Error
+	return attempts, err
+}
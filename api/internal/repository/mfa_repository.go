@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MFARepository 사용자 MFA 저장소
+type MFARepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository 새로운 MFA 저장소 생성
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// FindByUserID 사용자 MFA 정보 조회. 없으면 (nil, nil) 반환.
+func (r *MFARepository) FindByUserID(userID string) (*model.UserMFA, error) {
+	var mfa model.UserMFA
+	err := r.db.Where("user_id = ?", userID).First(&mfa).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// Save MFA 정보 생성 또는 갱신
+func (r *MFARepository) Save(mfa *model.UserMFA) error {
+	return r.db.Save(mfa).Error
+}
+
+// ClaimStep 사용된 TOTP 스텝을 기록한다. 같거나 더 새 스텝이 이미 기록되어 있으면 false
+// (조건부 UPDATE라 같은 코드를 동시에 검증해도 한 요청만 true를 받는다).
+func (r *MFARepository) ClaimStep(userID string, step int64) (bool, error) {
+	result := r.db.Model(&model.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete 사용자 MFA 정보 삭제 (MFA 해제)
+func (r *MFARepository) Delete(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.UserMFA{}).Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RegistrySnapshotRepository 레지스트리 스냅샷 저장소
+type RegistrySnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewRegistrySnapshotRepository 새로운 레지스트리 스냅샷 저장소 생성
+func NewRegistrySnapshotRepository(db *gorm.DB) *RegistrySnapshotRepository {
+	return &RegistrySnapshotRepository{db: db}
+}
+
+// Save 이름별 스냅샷 저장 (있으면 덮어씀)
+func (r *RegistrySnapshotRepository) Save(snapshot *model.RegistrySnapshot) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"payload", "stored_at"}),
+	}).Create(snapshot).Error
+}
+
+// Find 이름으로 스냅샷 조회
+func (r *RegistrySnapshotRepository) Find(name string) (*model.RegistrySnapshot, error) {
+	var snapshot model.RegistrySnapshot
+	if err := r.db.Where("name = ?", name).First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
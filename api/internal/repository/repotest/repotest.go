@@ -0,0 +1,170 @@
+// Package repotest 저장소/서비스 테스트용 가짜 Postgres 연결.
+//
+// 실제 DB 없이 GORM이 만든 SQL과 인자를 기록하고, SQL 일부 문자열로 등록한 핸들러가
+// 결과 행 또는 영향받은 행 수를 돌려준다. 등록되지 않은 문장은 빈 결과(0행)를 받는다.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Result 핸들러 응답. Columns가 있으면 조회 결과 행, 없으면 RowsAffected를 돌려준다.
+type Result struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	RowsAffected int64
+	Err          error
+}
+
+// Statement 실행된 SQL과 인자
+type Statement struct {
+	SQL  string
+	Args []driver.Value
+}
+
+// DB 가짜 연결 상태 (핸들러, 실행 기록)
+type DB struct {
+	mu         sync.Mutex
+	handlers   []handler
+	statements []Statement
+}
+
+type handler struct {
+	match string
+	fn    func(args []driver.Value) Result
+}
+
+// Open 가짜 연결을 쓰는 *gorm.DB
+func Open(t testing.TB) (*gorm.DB, *DB) {
+	t.Helper()
+	fake := &DB{}
+	sqlDB := sql.OpenDB(connector{fake})
+	t.Cleanup(func() { sqlDB.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger:               logger.Default.LogMode(logger.Silent),
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, fake
+}
+
+// Handle SQL에 match(대소문자 무시)가 포함된 문장의 결과를 정한다. 나중에 등록한 핸들러가 우선한다.
+func (d *DB) Handle(match string, fn func(args []driver.Value) Result) {
+	d.mu.Lock()
+	d.handlers = append(d.handlers, handler{match: strings.ToLower(match), fn: fn})
+	d.mu.Unlock()
+}
+
+// Statements match가 포함된 실행 기록 (match가 비어 있으면 전체). BEGIN/COMMIT/ROLLBACK도 기록된다.
+func (d *DB) Statements(match string) []Statement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var out []Statement
+	for _, stmt := range d.statements {
+		if strings.Contains(strings.ToLower(stmt.SQL), strings.ToLower(match)) {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
+
+func (d *DB) run(query string, args []driver.NamedValue) Result {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	d.mu.Lock()
+	d.statements = append(d.statements, Statement{SQL: query, Args: values})
+	var fn func(args []driver.Value) Result
+	lower := strings.ToLower(query)
+	for i := len(d.handlers) - 1; i >= 0; i-- {
+		if strings.Contains(lower, d.handlers[i].match) {
+			fn = d.handlers[i].fn
+			break
+		}
+	}
+	d.mu.Unlock()
+	if fn == nil {
+		return Result{}
+	}
+	return fn(values)
+}
+
+func (d *DB) record(query string) {
+	d.mu.Lock()
+	d.statements = append(d.statements, Statement{SQL: query})
+	d.mu.Unlock()
+}
+
+type connector struct{ db *DB }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return &conn{db: c.db}, nil }
+func (c connector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return nil, driver.ErrSkip }
+
+type conn struct{ db *DB }
+
+func (c *conn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *conn) Close() error                        { return nil }
+func (c *conn) Begin() (driver.Tx, error)           { return c.BeginTx(context.Background(), driver.TxOptions{}) }
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return tx{c.db}, nil
+}
+
+// CheckNamedValue 모든 인자 타입을 그대로 받는다
+func (c *conn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result := c.db.run(query, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return driver.RowsAffected(result.RowsAffected), nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result := c.db.run(query, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return &rows{columns: result.Columns, values: result.Rows}, nil
+}
+
+type tx struct{ db *DB }
+
+func (t tx) Commit() error   { t.db.record("COMMIT"); return nil }
+func (t tx) Rollback() error { t.db.record("ROLLBACK"); return nil }
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"time"
+
 	"mc_web_console_api/internal/model"
+	"mc_web_console_api/pkg/jwt"
 
 	"gorm.io/gorm"
 )
@@ -16,15 +19,39 @@ func NewSessionRepository(db *gorm.DB) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
-// Create 세션 생성
+// Create 세션 생성 (로그인 1회 = 1행, 같은 사용자의 다른 기기 세션은 유지된다)
 func (r *SessionRepository) Create(session *model.UserSession) error {
+	session.AccessTokenHash = jwt.TokenHash(session.AccessToken)
+	if session.RefreshExpiresAt == nil {
+		session.RefreshExpiresAt = refreshExpiresAt(session.RefreshExpiresIn)
+	}
 	return r.db.Create(session).Error
 }
 
-// FindByUserID 사용자 ID로 세션 조회
+// FindByUserID 사용자 ID로 가장 최근 세션 조회
 func (r *SessionRepository) FindByUserID(userID string) (*model.UserSession, error) {
 	var session model.UserSession
-	err := r.db.Where("user_id = ?", userID).First(&session).Error
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByAccessTokenHash access token 해시로 세션 조회
+func (r *SessionRepository) FindByAccessTokenHash(hash string) (*model.UserSession, error) {
+	var session model.UserSession
+	err := r.db.Where("access_token_hash = ?", hash).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByRefreshToken refresh token으로 폐기되지 않은 세션 조회
+func (r *SessionRepository) FindByRefreshToken(refreshToken string) (*model.UserSession, error) {
+	var session model.UserSession
+	err := r.db.Where("refresh_token = ? AND revoked_at IS NULL", refreshToken).First(&session).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,19 +70,40 @@ func (r *SessionRepository) FindByID(id string) (*model.UserSession, error) {
 
 // Update 세션 업데이트
 func (r *SessionRepository) Update(session *model.UserSession) error {
+	session.AccessTokenHash = jwt.TokenHash(session.AccessToken)
 	return r.db.Save(session).Error
 }
 
-// UpdateTokens 토큰만 업데이트
-func (r *SessionRepository) UpdateTokens(userID, accessToken, refreshToken string, expiresIn, refreshExpiresIn float64) error {
+// UpdateTokens 세션 하나의 토큰만 업데이트 (토큰 갱신).
+// refreshExpiresIn > 0이면 새 refresh token이 발급된 것으로 보고 세션 만료 시각을 지금부터 다시 계산한다.
+func (r *SessionRepository) UpdateTokens(sessionID, accessToken, refreshToken string, expiresIn, refreshExpiresIn float64) error {
+	updates := map[string]interface{}{
+		"access_token":      accessToken,
+		"access_token_hash": jwt.TokenHash(accessToken),
+		"refresh_token":     refreshToken,
+		"expires_in":        expiresIn,
+	}
+	if refreshExpiresIn > 0 {
+		updates["refresh_expires_in"] = refreshExpiresIn
+		updates["refresh_expires_at"] = *refreshExpiresAt(refreshExpiresIn)
+	}
+	return r.db.Model(&model.UserSession{}).
+		Where("id = ?", sessionID).
+		Updates(updates).Error
+}
+
+// Revoke 사용자의 모든 세션을 폐기 상태로 표시 (비밀번호 변경 등). 폐기된 세션은 인증에 사용할 수 없다.
+func (r *SessionRepository) Revoke(userID string) error {
 	return r.db.Model(&model.UserSession{}).
-		Where("user_id = ?", userID).
-		Updates(map[string]interface{}{
-			"access_token":        accessToken,
-			"refresh_token":       refreshToken,
-			"expires_in":          expiresIn,
-			"refresh_expires_in":  refreshExpiresIn,
-		}).Error
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeByAccessTokenHash access token이 바인딩된 세션 하나만 폐기 (로그아웃)
+func (r *SessionRepository) RevokeByAccessTokenHash(userID, hash string) error {
+	return r.db.Model(&model.UserSession{}).
+		Where("user_id = ? AND access_token_hash = ? AND revoked_at IS NULL", userID, hash).
+		Update("revoked_at", time.Now()).Error
 }
 
 // Delete 세션 삭제
@@ -78,10 +126,27 @@ func (r *SessionRepository) Exists(userID string) (bool, error) {
 	return count > 0, nil
 }
 
-// DeleteExpired 만료된 세션 삭제 (정리 작업)
-// refresh_expires_in(초) 기준으로 created_at 이후 만료된 세션을 삭제한다.
-func (r *SessionRepository) DeleteExpired() error {
-	return r.db.Exec(
-		"DELETE FROM usersesses WHERE created_at + (refresh_expires_in * interval '1 second') < NOW()",
-	).Error
+// sessionExpirySQL 세션 만료 시각 (refresh_expires_at이 없는 이전 행은 created_at + refresh_expires_in)
+const sessionExpirySQL = "COALESCE(refresh_expires_at, created_at + (refresh_expires_in * interval '1 second'))"
+
+// DeleteExpired 만료되었거나 폐기된 세션 삭제 (정리 작업). 삭제된 행 수를 반환한다.
+func (r *SessionRepository) DeleteExpired() (int64, error) {
+	result := r.db.Exec(
+		"DELETE FROM usersesses WHERE " + sessionExpirySQL + " < NOW() OR revoked_at IS NOT NULL",
+	)
+	return result.RowsAffected, result.Error
+}
+
+// CountActive 만료·폐기되지 않은 세션 수
+func (r *SessionRepository) CountActive() (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserSession{}).
+		Where("revoked_at IS NULL AND " + sessionExpirySQL + " >= NOW()").
+		Count(&count).Error
+	return count, err
+}
+
+func refreshExpiresAt(refreshExpiresIn float64) *time.Time {
+	expiresAt := time.Now().Add(time.Duration(refreshExpiresIn * float64(time.Second)))
+	return &expiresAt
 }
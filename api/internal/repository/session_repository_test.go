@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository/repotest"
+)
+
+// latestArgTime 문장 인자 중 가장 늦은 time.Time 값 (없으면 zero)
+func latestArgTime(args []driver.Value) time.Time {
+	var latest time.Time
+	for _, arg := range args {
+		if t, ok := arg.(time.Time); ok && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+func TestSessionRepositoryUpdateTokensExtendsSession(t *testing.T) {
+	db, fake := repotest.Open(t)
+	repo := NewSessionRepository(db)
+
+	// 새 refresh token: 세션 하나만, 만료 시각을 지금부터 다시 계산
+	if err := repo.UpdateTokens("session-1", "access-2", "refresh-2", 300, 3600); err != nil {
+		t.Fatal(err)
+	}
+	updates := fake.Statements(`UPDATE "usersesses"`)
+	if len(updates) != 1 {
+		t.Fatalf("updates = %+v", updates)
+	}
+	stmt := updates[0]
+	if !strings.Contains(stmt.SQL, `"refresh_expires_at"=`) || !strings.Contains(stmt.SQL, "WHERE id = $") {
+		t.Fatalf("UPDATE = %s, want refresh_expires_at set on one session", stmt.SQL)
+	}
+	if stmt.Args[len(stmt.Args)-1] != "session-1" {
+		t.Fatalf("UPDATE args = %v, want session-1 last", stmt.Args)
+	}
+	if expiresAt := latestArgTime(stmt.Args); time.Until(expiresAt) < 59*time.Minute || time.Until(expiresAt) > time.Hour {
+		t.Fatalf("refresh_expires_at = %v, want about an hour from now", expiresAt)
+	}
+
+	// refresh token 유지: 만료 시각은 바꾸지 않는다
+	if err := repo.UpdateTokens("session-1", "access-3", "refresh-2", 300, 0); err != nil {
+		t.Fatal(err)
+	}
+	if updates = fake.Statements(`UPDATE "usersesses"`); strings.Contains(updates[1].SQL, "refresh_expires") {
+		t.Fatalf("UPDATE = %s, want refresh expiry untouched", updates[1].SQL)
+	}
+}
+
+func TestSessionRepositoryCreateKeepsOtherSessions(t *testing.T) {
+	db, fake := repotest.Open(t)
+	repo := NewSessionRepository(db)
+
+	session := &model.UserSession{UserID: "user01", AccessToken: "access-1", RefreshToken: "refresh-1", RefreshExpiresIn: 3600}
+	if err := repo.Create(session); err != nil {
+		t.Fatal(err)
+	}
+	if session.RefreshExpiresAt == nil || time.Until(*session.RefreshExpiresAt) < 59*time.Minute {
+		t.Fatalf("RefreshExpiresAt = %v, want about an hour from now", session.RefreshExpiresAt)
+	}
+	if deletes := fake.Statements("DELETE"); len(deletes) != 0 {
+		t.Fatalf("Create deleted rows: %+v", deletes)
+	}
+	if inserts := fake.Statements(`INSERT INTO "usersesses"`); len(inserts) != 1 {
+		t.Fatalf("inserts = %+v", inserts)
+	}
+}
+
+func TestSessionRepositoryExpiryUsesRefreshExpiresAt(t *testing.T) {
+	db, fake := repotest.Open(t)
+	repo := NewSessionRepository(db)
+
+	if _, err := repo.DeleteExpired(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CountActive(); err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range append(fake.Statements("DELETE FROM usersesses"), fake.Statements("SELECT count(*)")...) {
+		if !strings.Contains(stmt.SQL, "COALESCE(refresh_expires_at,") {
+			t.Errorf("%s does not use refresh_expires_at", stmt.SQL)
+		}
+	}
+}
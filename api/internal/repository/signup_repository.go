@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"time"
+
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// SignupRepository 초대 코드/승인 대기 회원가입 저장소
+type SignupRepository struct {
+	db *gorm.DB
+}
+
+// NewSignupRepository 새로운 회원가입 저장소 생성
+func NewSignupRepository(db *gorm.DB) *SignupRepository {
+	return &SignupRepository{db: db}
+}
+
+// CreateInvitation 초대 코드 생성
+func (r *SignupRepository) CreateInvitation(invitation *model.SignupInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+// FindInvitationByHash 코드 해시로 초대 조회
+func (r *SignupRepository) FindInvitationByHash(codeHash string) (*model.SignupInvitation, error) {
+	var invitation model.SignupInvitation
+	if err := r.db.Where("code_hash = ?", codeHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListInvitations 초대 목록 (최근 생성 순)
+func (r *SignupRepository) ListInvitations() ([]model.SignupInvitation, error) {
+	var invitations []model.SignupInvitation
+	err := r.db.Order("created_at DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+// UseInvitation 사용 횟수 1 증가. 동시 사용으로 한도를 넘지 않도록 조건부로 갱신하며, 갱신되지 않으면 false
+func (r *SignupRepository) UseInvitation(id string, now time.Time) (bool, error) {
+	result := r.db.Model(&model.SignupInvitation{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ? AND used_count < max_uses", id, now).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+// RevokeInvitation 초대 폐기
+func (r *SignupRepository) RevokeInvitation(id string) (bool, error) {
+	result := r.db.Model(&model.SignupInvitation{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// CreatePending 승인 대기 회원가입 저장
+func (r *SignupRepository) CreatePending(pending *model.PendingSignup) error {
+	return r.db.Create(pending).Error
+}
+
+// CreatePendingWithInvitation 초대 사용 처리와 승인 대기 회원가입 저장을 한 트랜잭션으로 수행.
+// 초대를 사용할 수 없으면 저장하지 않고 false
+func (r *SignupRepository) CreatePendingWithInvitation(pending *model.PendingSignup, invitationID string, now time.Time) (bool, error) {
+	used := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SignupInvitation{}).
+			Where("id = ? AND revoked_at IS NULL AND expires_at > ? AND used_count < max_uses", invitationID, now).
+			Update("used_count", gorm.Expr("used_count + 1"))
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+		if err := tx.Create(pending).Error; err != nil {
+			return err
+		}
+		used = true
+		return nil
+	})
+	return used, err
+}
+
+// FindPendingByID ID로 승인 대기 회원가입 조회
+func (r *SignupRepository) FindPendingByID(id string) (*model.PendingSignup, error) {
+	var pending model.PendingSignup
+	if err := r.db.Where("id = ?", id).First(&pending).Error; err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// ExistsPendingEmail 같은 이메일의 대기 중 요청 존재 여부
+func (r *SignupRepository) ExistsPendingEmail(email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.PendingSignup{}).
+		Where("LOWER(email) = LOWER(?) AND status = ?", email, model.SignupStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListPending 상태별 회원가입 요청 목록 (status가 비어 있으면 전체, 최근 순)
+func (r *SignupRepository) ListPending(status string) ([]model.PendingSignup, error) {
+	var list []model.PendingSignup
+	query := r.db.Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+// Decide 대기 중 요청을 승인/거절로 전환. 이미 처리된 요청이면 false.
+// 처리 후에는 보관하던 비밀번호를 지운다.
+func (r *SignupRepository) Decide(id, status, decidedBy, reason string) (bool, error) {
+	result := r.db.Model(&model.PendingSignup{}).
+		Where("id = ? AND status = ?", id, model.SignupStatusPending).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"decided_by":      decidedBy,
+			"decided_at":      time.Now(),
+			"reason":          reason,
+			"password_hash":   "",
+			"password_sealed": "",
+		})
+	return result.RowsAffected == 1, result.Error
+}
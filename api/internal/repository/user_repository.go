@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"time"
+
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository 로컬 사용자 저장소
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository 새로운 로컬 사용자 저장소 생성
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create 사용자 생성
+func (r *UserRepository) Create(user *model.LocalUser) error {
+	return r.db.Create(user).Error
+}
+
+// FindByID ID로 사용자 조회
+func (r *UserRepository) FindByID(id string) (*model.LocalUser, error) {
+	var user model.LocalUser
+	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByLoginID 로그인 ID로 사용자 조회 (토큰 주체 확인용)
+func (r *UserRepository) FindByLoginID(loginID string) (*model.LocalUser, error) {
+	var user model.LocalUser
+	if err := r.db.Where("login_id = ?", loginID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByLoginIDOrEmail 로그인 ID 또는 이메일로 사용자 조회 (로그인 시 둘 다 허용)
+func (r *UserRepository) FindByLoginIDOrEmail(identifier string) (*model.LocalUser, error) {
+	var user model.LocalUser
+	err := r.db.Where("login_id = ? OR LOWER(email) = LOWER(?)", identifier, identifier).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ExistsByLoginIDOrEmail 로그인 ID 또는 이메일 중복 여부 확인
+func (r *UserRepository) ExistsByLoginIDOrEmail(loginID, email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.LocalUser{}).
+		Where("login_id = ? OR LOWER(email) = LOWER(?)", loginID, email).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update 사용자 업데이트
+func (r *UserRepository) Update(user *model.LocalUser) error {
+	return r.db.Save(user).Error
+}
+
+// UpdatePassword 비밀번호 해시 교체
+func (r *UserRepository) UpdatePassword(userID, passwordHash string) error {
+	now := time.Now()
+	return r.db.Model(&model.LocalUser{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password_hash":       passwordHash,
+			"password_changed_at": now,
+		}).Error
+}
+
+// MarkEmailVerified 이메일 인증 완료 처리
+func (r *UserRepository) MarkEmailVerified(userID string) error {
+	return r.db.Model(&model.LocalUser{}).Where("id = ?", userID).Update("email_verified", true).Error
+}
+
+// CreateToken 1회용 토큰 저장
+func (r *UserRepository) CreateToken(token *model.LocalUserToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindToken 용도와 해시로 토큰 조회
+func (r *UserRepository) FindToken(purpose, tokenHash string) (*model.LocalUserToken, error) {
+	var token model.LocalUserToken
+	err := r.db.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkTokenUsed 미사용 토큰을 사용 처리. 이미 사용된 토큰이면 false (동시 요청 중 하나만 true를 받는다).
+func (r *UserRepository) MarkTokenUsed(id string) (bool, error) {
+	result := r.db.Model(&model.LocalUserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteUnusedTokens 사용자의 특정 용도 미사용 토큰 삭제 (재발급 시 이전 토큰 무효화)
+func (r *UserRepository) DeleteUnusedTokens(userID, purpose string) error {
+	return r.db.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Delete(&model.LocalUserToken{}).Error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"time"
+
+	"mc_web_console_api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository webhook 전송 대기열/dead-letter 저장소
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository 새로운 webhook 저장소 생성
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Enqueue 전송 대기열에 추가
+func (r *WebhookRepository) Enqueue(delivery *model.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// ClaimDue 전송 시각이 된 항목을 limit개까지 가져오고 lease 동안 다른 replica가 가져가지 않도록 다음 시도 시각을 미룬다.
+// 행 잠금은 SKIP LOCKED로 잡아 replica끼리 같은 항목을 동시에 전송하지 않는다.
+func (r *WebhookRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	var due []model.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(due))
+		for i, d := range due {
+			ids[i] = d.ID
+		}
+		return tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return due, err
+}
+
+// Delete 전송 완료 항목 삭제
+func (r *WebhookRepository) Delete(id uint) error {
+	return r.db.Delete(&model.WebhookDelivery{}, id).Error
+}
+
+// Reschedule 전송 실패 항목의 시도 횟수/다음 시도 시각/마지막 오류 갱신
+func (r *WebhookRepository) Reschedule(id uint, attempts int, next time.Time, lastErr string) error {
+	return r.db.Model(&model.WebhookDelivery{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        attempts,
+		"next_attempt_at": next,
+		"last_error":      lastErr,
+	}).Error
+}
+
+// MoveToDeadLetter 대기열 항목을 dead-letter 테이블로 옮긴다
+func (r *WebhookRepository) MoveToDeadLetter(delivery *model.WebhookDelivery) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		dead := &model.WebhookDeadLetter{
+			EventID:   delivery.EventID,
+			EventType: delivery.EventType,
+			Endpoint:  delivery.Endpoint,
+			Payload:   delivery.Payload,
+			Attempts:  delivery.Attempts,
+			LastError: delivery.LastError,
+		}
+		if err := tx.Create(dead).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.WebhookDelivery{}, delivery.ID).Error
+	})
+}
+
+// ListDeadLetters dead-letter 목록 (최근 순)
+func (r *WebhookRepository) ListDeadLetters(limit int) ([]model.WebhookDeadLetter, error) {
+	var list []model.WebhookDeadLetter
+	err := r.db.Order("created_at DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// RequeueDeadLetter dead-letter 항목을 시도 횟수 0으로 대기열에 되돌린다. 없으면 false
+func (r *WebhookRepository) RequeueDeadLetter(id uint, now time.Time) (bool, error) {
+	requeued := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var dead model.WebhookDeadLetter
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Limit(1).Find(&dead)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		delivery := &model.WebhookDelivery{
+			EventID:       dead.EventID,
+			EventType:     dead.EventType,
+			Endpoint:      dead.Endpoint,
+			Payload:       dead.Payload,
+			NextAttemptAt: now,
+		}
+		if err := tx.Create(delivery).Error; err != nil {
+			return err
+		}
+		requeued = true
+		return tx.Delete(&model.WebhookDeadLetter{}, dead.ID).Error
+	})
+	return requeued, err
+}
+
+// Counts 대기열/dead-letter 건수
+func (r *WebhookRepository) Counts() (queued, dead int64, err error) {
+	if err = r.db.Model(&model.WebhookDelivery{}).Count(&queued).Error; err != nil {
+		return
+	}
+	err = r.db.Model(&model.WebhookDeadLetter{}).Count(&dead).Error
+	return
+}
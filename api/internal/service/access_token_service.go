@@ -0,0 +1,322 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/pkg/jwt"
+)
+
+// AccessTokenPrefix 액세스 토큰 원문 접두사. AuthMiddleware는 이 접두사로 JWT와 구분한다.
+const AccessTokenPrefix = "mcwc_pat_"
+
+// accessTokenTouchInterval last_used 갱신 최소 간격 (요청마다 UPDATE하지 않도록)
+const accessTokenTouchInterval = time.Minute
+
+// 액세스 토큰 처리 실패 사유
+var (
+	ErrAccessTokenInvalid      = errors.New("invalid access token")
+	ErrAccessTokenExpired      = errors.New("access token expired or revoked")
+	ErrAccessTokenTTL          = errors.New("requested expiry exceeds maximum")
+	ErrAccessTokenScope        = errors.New("invalid scope pattern")
+	ErrServiceAccountDisabled  = errors.New("service account is disabled")
+	ErrAccessTokenOwner        = errors.New("access token owner is disabled or no longer exists")
+	ErrServiceAccountNameTaken = errors.New("service account name already exists")
+)
+
+// AccessTokenService 개인 액세스 토큰(PAT)과 서비스 계정 토큰 발급/검증 서비스
+type AccessTokenService struct {
+	repo  *repository.AccessTokenRepository
+	users *repository.UserRepository // 로컬 사용자 저장소 (MCIAM 모드에서는 nil)
+	cfg   config.AccessTokenConfig
+}
+
+// NewAccessTokenService 새로운 액세스 토큰 서비스 생성.
+// users가 있으면(MCIAM_USE=false) 개인 토큰 인증 시 소유자 상태와 현재 역할을 로컬 사용자 저장소에서 확인한다.
+// MCIAM 모드의 사용자는 mc-iam-manager가 관리하므로 발급 시점의 역할을 사용하며, 대신 유효 기간을 MCIAMMaxTTL로 제한한다.
+func NewAccessTokenService(repo *repository.AccessTokenRepository, users *repository.UserRepository, cfg config.AccessTokenConfig) *AccessTokenService {
+	return &AccessTokenService{repo: repo, users: users, cfg: cfg}
+}
+
+// AccessTokenPrincipal 액세스 토큰으로 인증된 주체
+type AccessTokenPrincipal struct {
+	TokenID        string
+	UserID         string
+	UserName       string
+	Email          string
+	Role           string
+	Scopes         []string
+	ServiceAccount bool
+}
+
+// AccessTokenIssueInput 토큰 발급 입력
+type AccessTokenIssueInput struct {
+	Name      string
+	Scopes    []string      // "subsystem/operation" glob 패턴. 비어 있으면 "*" (역할 권한 전체)
+	ExpiresIn time.Duration // 0이면 DefaultTTL (최대 유효 기간을 넘으면 최대 유효 기간)
+	CreatedBy string
+}
+
+// IsAccessToken 토큰 문자열이 액세스 토큰 형식인지 확인
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, AccessTokenPrefix)
+}
+
+// IssueUserToken 로그인 사용자의 개인 액세스 토큰 발급. 원문 토큰은 반환값으로만 제공된다.
+func (s *AccessTokenService) IssueUserToken(userID, userName, email, role string, in AccessTokenIssueInput) (string, *model.AccessToken, error) {
+	token := &model.AccessToken{
+		OwnerType: model.AccessTokenOwnerUser,
+		OwnerID:   userID,
+		UserName:  userName,
+		Email:     email,
+		Role:      role,
+	}
+	return s.issue(token, in)
+}
+
+// IssueServiceAccountToken 서비스 계정 토큰 발급
+func (s *AccessTokenService) IssueServiceAccountToken(accountID string, in AccessTokenIssueInput) (string, *model.AccessToken, error) {
+	account, err := s.repo.FindServiceAccount(accountID)
+	if err != nil {
+		return "", nil, err
+	}
+	if account.Disabled {
+		return "", nil, ErrServiceAccountDisabled
+	}
+	token := &model.AccessToken{
+		OwnerType: model.AccessTokenOwnerServiceAccount,
+		OwnerID:   account.ID,
+		UserName:  account.Name,
+		Role:      account.Role,
+	}
+	return s.issue(token, in)
+}
+
+func (s *AccessTokenService) issue(token *model.AccessToken, in AccessTokenIssueInput) (string, *model.AccessToken, error) {
+	scopes, err := normalizeTokenScopes(in.Scopes)
+	if err != nil {
+		return "", nil, err
+	}
+	maxTTL := s.maxTTL(token)
+	ttl := in.ExpiresIn
+	if ttl == 0 {
+		ttl = s.cfg.DefaultTTL
+		if maxTTL > 0 && ttl > maxTTL {
+			ttl = maxTTL
+		}
+	}
+	if ttl < 0 || (maxTTL > 0 && ttl > maxTTL) {
+		return "", nil, ErrAccessTokenTTL
+	}
+
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	plain := AccessTokenPrefix + hex.EncodeToString(raw)
+	expiresAt := time.Now().Add(ttl)
+
+	token.Name = in.Name
+	token.TokenHash = jwt.TokenHash(plain)
+	token.Prefix = plain[:len(AccessTokenPrefix)+6]
+	token.Scopes = strings.Join(scopes, ",")
+	token.ExpiresAt = &expiresAt
+	token.CreatedBy = in.CreatedBy
+	if err := s.repo.Create(token); err != nil {
+		return "", nil, fmt.Errorf("failed to store access token: %w", err)
+	}
+	return plain, token, nil
+}
+
+// Authenticate 원문 토큰 검증 후 인증 주체 반환. 마지막 사용 시각/IP를 갱신한다.
+func (s *AccessTokenService) Authenticate(plain, ip string) (*AccessTokenPrincipal, error) {
+	if !IsAccessToken(plain) {
+		return nil, ErrAccessTokenInvalid
+	}
+	token, err := s.repo.FindByHash(jwt.TokenHash(plain))
+	if err != nil {
+		return nil, ErrAccessTokenInvalid
+	}
+	now := time.Now()
+	if !token.IsUsable(now) {
+		return nil, ErrAccessTokenExpired
+	}
+	// 제한 도입 전에 발급된 MCIAM 개인 토큰도 발급 후 MCIAMMaxTTL이 지나면 거부한다
+	if s.unverifiedOwner(token) && s.cfg.MCIAMMaxTTL > 0 && now.Sub(token.CreatedAt) > s.cfg.MCIAMMaxTTL {
+		return nil, ErrAccessTokenExpired
+	}
+
+	principal := &AccessTokenPrincipal{
+		TokenID:  token.ID,
+		UserID:   token.OwnerID,
+		UserName: token.UserName,
+		Email:    token.Email,
+		Role:     token.Role,
+		Scopes:   token.ScopeList(),
+	}
+	if token.OwnerType == model.AccessTokenOwnerUser && s.users != nil {
+		user, err := s.users.FindByLoginID(token.OwnerID)
+		if err != nil {
+			return nil, ErrAccessTokenOwner
+		}
+		if err := applyTokenOwner(principal, user); err != nil {
+			return nil, err
+		}
+	}
+	if token.OwnerType == model.AccessTokenOwnerServiceAccount {
+		account, err := s.repo.FindServiceAccount(token.OwnerID)
+		if err != nil {
+			return nil, ErrAccessTokenInvalid
+		}
+		if account.Disabled {
+			return nil, ErrServiceAccountDisabled
+		}
+		principal.ServiceAccount = true
+		principal.UserID = account.PrincipalID()
+		principal.UserName = account.Name
+		principal.Role = account.Role
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > accessTokenTouchInterval {
+		if err := s.repo.TouchLastUsed(token.ID, now, ip); err != nil {
+			log.Printf("[AccessToken] touch last used error (id=%s): %v", token.ID, err)
+		}
+	}
+	return principal, nil
+}
+
+// unverifiedOwner 인증 시 소유자 상태와 역할을 다시 확인할 수 없는 토큰인지 (MCIAM 모드 개인 토큰)
+func (s *AccessTokenService) unverifiedOwner(token *model.AccessToken) bool {
+	return token.OwnerType == model.AccessTokenOwnerUser && s.users == nil
+}
+
+// maxTTL 토큰에 적용할 최대 유효 기간 (0이면 제한 없음)
+func (s *AccessTokenService) maxTTL(token *model.AccessToken) time.Duration {
+	maxTTL := s.cfg.MaxTTL
+	if s.unverifiedOwner(token) && s.cfg.MCIAMMaxTTL > 0 && (maxTTL == 0 || s.cfg.MCIAMMaxTTL < maxTTL) {
+		maxTTL = s.cfg.MCIAMMaxTTL
+	}
+	return maxTTL
+}
+
+// applyTokenOwner 개인 토큰 주체에 로컬 사용자의 현재 이름/이메일/역할을 반영 (비활성 사용자면 ErrAccessTokenOwner)
+func applyTokenOwner(principal *AccessTokenPrincipal, user *model.LocalUser) error {
+	if user == nil || user.Status != model.LocalUserStatusActive {
+		return ErrAccessTokenOwner
+	}
+	principal.UserName = user.DisplayName()
+	principal.Email = user.Email
+	principal.Role = user.Role
+	return nil
+}
+
+// ListUserTokens 사용자의 개인 액세스 토큰 목록
+func (s *AccessTokenService) ListUserTokens(userID string) ([]model.AccessToken, error) {
+	return s.repo.ListByOwner(model.AccessTokenOwnerUser, userID)
+}
+
+// ListServiceAccountTokens 서비스 계정 토큰 목록
+func (s *AccessTokenService) ListServiceAccountTokens(accountID string) ([]model.AccessToken, error) {
+	return s.repo.ListByOwner(model.AccessTokenOwnerServiceAccount, accountID)
+}
+
+// RevokeUserToken 사용자 본인 토큰 폐기 (다른 소유자의 토큰이면 ErrAccessTokenInvalid)
+func (s *AccessTokenService) RevokeUserToken(userID, tokenID string) error {
+	token, err := s.repo.FindByID(tokenID)
+	if err != nil || token.OwnerType != model.AccessTokenOwnerUser || token.OwnerID != userID {
+		return ErrAccessTokenInvalid
+	}
+	return s.repo.Revoke(tokenID)
+}
+
+// RevokeServiceAccountToken 서비스 계정 토큰 폐기
+func (s *AccessTokenService) RevokeServiceAccountToken(accountID, tokenID string) error {
+	token, err := s.repo.FindByID(tokenID)
+	if err != nil || token.OwnerType != model.AccessTokenOwnerServiceAccount || token.OwnerID != accountID {
+		return ErrAccessTokenInvalid
+	}
+	return s.repo.Revoke(tokenID)
+}
+
+// CreateServiceAccount 서비스 계정 생성
+func (s *AccessTokenService) CreateServiceAccount(name, description, role, createdBy string) (*model.ServiceAccount, error) {
+	account := &model.ServiceAccount{
+		Name:        strings.TrimSpace(name),
+		Description: description,
+		Role:        role,
+		CreatedBy:   createdBy,
+	}
+	if err := s.repo.CreateServiceAccount(account); err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "unique") {
+			return nil, ErrServiceAccountNameTaken
+		}
+		return nil, fmt.Errorf("failed to create service account: %w", err)
+	}
+	return account, nil
+}
+
+// ListServiceAccounts 서비스 계정 목록
+func (s *AccessTokenService) ListServiceAccounts() ([]model.ServiceAccount, error) {
+	return s.repo.ListServiceAccounts()
+}
+
+// SetServiceAccountDisabled 서비스 계정 비활성화(발급된 토큰 전부 폐기) 또는 재활성화
+func (s *AccessTokenService) SetServiceAccountDisabled(accountID string, disabled bool) error {
+	if _, err := s.repo.FindServiceAccount(accountID); err != nil {
+		return err
+	}
+	if err := s.repo.SetServiceAccountDisabled(accountID, disabled); err != nil {
+		return err
+	}
+	if disabled {
+		return s.repo.RevokeByOwner(model.AccessTokenOwnerServiceAccount, accountID)
+	}
+	return nil
+}
+
+// TokenScopeAllows 토큰 스코프가 subsystem/operation 호출을 포함하는지 확인 (대소문자 무시 glob)
+func TokenScopeAllows(scopes []string, subsystem, operation string) bool {
+	target := strings.ToLower(subsystem + "/" + operation)
+	for _, scope := range scopes {
+		if scope == "*" {
+			return true
+		}
+		if ok, _ := path.Match(strings.ToLower(scope), target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTokenScopes 스코프 패턴 검증. "subsystem/operation" 형식 또는 "*"만 허용한다.
+func normalizeTokenScopes(scopes []string) ([]string, error) {
+	var normalized []string
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if scope != "*" {
+			if strings.Count(scope, "/") != 1 || strings.Contains(scope, ",") {
+				return nil, fmt.Errorf("%w: %q (expected subsystem/operation)", ErrAccessTokenScope, scope)
+			}
+			if _, err := path.Match(scope, ""); err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrAccessTokenScope, scope)
+			}
+		}
+		normalized = append(normalized, scope)
+	}
+	if len(normalized) == 0 {
+		normalized = []string{"*"}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/repository/repotest"
+	"mc_web_console_api/pkg/jwt"
+)
+
+func TestApplyTokenOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *model.LocalUser
+		wantErr  error
+		wantRole string
+	}{
+		{
+			name:     "current role replaces the issued role",
+			user:     &model.LocalUser{LoginID: "user01", FirstName: "Kim", Email: "kim@example.com", Role: "viewer", Status: model.LocalUserStatusActive},
+			wantRole: "viewer",
+		},
+		{
+			name:    "disabled owner",
+			user:    &model.LocalUser{LoginID: "user01", Role: "platformAdmin", Status: model.LocalUserStatusDisabled},
+			wantErr: ErrAccessTokenOwner,
+		},
+		{
+			name:    "unknown status",
+			user:    &model.LocalUser{LoginID: "user01", Role: "platformAdmin"},
+			wantErr: ErrAccessTokenOwner,
+		},
+		{
+			name:    "deleted owner",
+			wantErr: ErrAccessTokenOwner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := &AccessTokenPrincipal{UserID: "user01", UserName: "old", Email: "old@example.com", Role: "platformAdmin"}
+			err := applyTokenOwner(principal, tt.user)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyTokenOwner error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if principal.Role != tt.wantRole || principal.UserName != tt.user.DisplayName() || principal.Email != tt.user.Email {
+				t.Errorf("principal = %+v, want role %s of %+v", principal, tt.wantRole, tt.user)
+			}
+		})
+	}
+}
+
+func TestTokenScopeAllows(t *testing.T) {
+	tests := []struct {
+		scopes    []string
+		subsystem string
+		operation string
+		want      bool
+	}{
+		{[]string{"*"}, "mc-infra-manager", "GetAllNs", true},
+		{[]string{"mc-infra-manager/Get*"}, "MC-INFRA-MANAGER", "GetAllNs", true},
+		{[]string{"mc-infra-manager/Get*"}, "mc-infra-manager", "DelAllNs", false},
+		{[]string{"mc-iam-manager/*"}, "mc-infra-manager", "GetAllNs", false},
+		{nil, "mc-infra-manager", "GetAllNs", false},
+	}
+	for _, tt := range tests {
+		if got := TokenScopeAllows(tt.scopes, tt.subsystem, tt.operation); got != tt.want {
+			t.Errorf("TokenScopeAllows(%v, %s/%s) = %t, want %t", tt.scopes, tt.subsystem, tt.operation, got, tt.want)
+		}
+	}
+}
+
+var testAccessTokenConfig = config.AccessTokenConfig{
+	DefaultTTL:  90 * 24 * time.Hour,
+	MaxTTL:      365 * 24 * time.Hour,
+	MCIAMMaxTTL: 7 * 24 * time.Hour,
+}
+
+func TestAccessTokenMCIAMMaxTTL(t *testing.T) {
+	db, fake := repotest.Open(t)
+	fake.Handle(`FROM "service_accounts"`, func([]driver.Value) repotest.Result {
+		return repotest.Result{Columns: []string{"id", "name", "role", "disabled"}, Rows: [][]driver.Value{{"sa-1", "ci-bot", "operator", false}}}
+	})
+	// MCIAM 모드: 로컬 사용자 저장소가 없어 소유자 역할을 다시 확인할 수 없다
+	svc := NewAccessTokenService(repository.NewAccessTokenRepository(db), nil, testAccessTokenConfig)
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		want      time.Duration
+		wantErr   error
+	}{
+		{name: "default TTL is capped", want: 7 * 24 * time.Hour},
+		{name: "within the cap", expiresIn: 24 * time.Hour, want: 24 * time.Hour},
+		{name: "over the cap", expiresIn: 30 * 24 * time.Hour, wantErr: ErrAccessTokenTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, token, err := svc.IssueUserToken("user01", "user01", "", "admin", AccessTokenIssueInput{Name: "ci", ExpiresIn: tt.expiresIn})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueUserToken error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if ttl := time.Until(*token.ExpiresAt); ttl > tt.want || ttl < tt.want-time.Minute {
+				t.Fatalf("expires in %v, want %v", ttl, tt.want)
+			}
+		})
+	}
+
+	// 서비스 계정 역할은 BFF가 관리하므로 MCIAM 제한을 받지 않는다
+	_, token, err := svc.IssueServiceAccountToken("sa-1", AccessTokenIssueInput{Name: "ci", ExpiresIn: 30 * 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(*token.ExpiresAt) < 29*24*time.Hour {
+		t.Fatalf("service account token expires at %v, want 30 days", token.ExpiresAt)
+	}
+}
+
+func TestAccessTokenAuthenticateMCIAMMaxTTL(t *testing.T) {
+	const plain = AccessTokenPrefix + "0123456789"
+	db, fake := repotest.Open(t)
+	var createdAt time.Time
+	fake.Handle(`FROM "access_tokens"`, func(args []driver.Value) repotest.Result {
+		columns := []string{"id", "name", "token_hash", "owner_type", "owner_id", "role", "scopes", "expires_at", "created_at"}
+		if args[0] != jwt.TokenHash(plain) {
+			return repotest.Result{Columns: columns}
+		}
+		return repotest.Result{Columns: columns, Rows: [][]driver.Value{
+			{"token-1", "ci", jwt.TokenHash(plain), model.AccessTokenOwnerUser, "user01", "admin", "*", time.Now().Add(300 * 24 * time.Hour), createdAt},
+		}}
+	})
+	svc := NewAccessTokenService(repository.NewAccessTokenRepository(db), nil, testAccessTokenConfig)
+
+	createdAt = time.Now().Add(-24 * time.Hour)
+	principal, err := svc.Authenticate(plain, "192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principal.UserID != "user01" || principal.Role != "admin" {
+		t.Fatalf("principal = %+v", principal)
+	}
+
+	// 제한 도입 전에 긴 만료로 발급된 토큰도 MCIAMMaxTTL이 지나면 거부한다
+	createdAt = time.Now().Add(-8 * 24 * time.Hour)
+	if _, err := svc.Authenticate(plain, "192.0.2.10"); !errors.Is(err, ErrAccessTokenExpired) {
+		t.Fatalf("old token: err = %v, want ErrAccessTokenExpired", err)
+	}
+}
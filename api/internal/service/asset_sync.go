@@ -0,0 +1,652 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// 동기화 가능한 원격 자산
+const (
+	AssetSyncApi  = "api"  // conf/api.yaml (MC_ADMIN_CLI_APIYAML)
+	AssetSyncMenu = "menu" // conf/webconsole_menu_resources.yaml (MC_WEB_CONSOLE_MENUYAML)
+)
+
+var (
+	ErrAssetSyncUnsupported = errors.New("asset does not support sync")
+	ErrAssetFetchFailed     = errors.New("failed to fetch upstream asset")
+	ErrAssetSyncInvalid     = errors.New("upstream asset failed validation")
+	ErrAssetSyncPlanChanged = errors.New("upstream asset changed since the plan was made")
+	ErrAssetBackupNotFound  = errors.New("asset backup not found")
+)
+
+const assetBackupTimeLayout = "20060102T150405.000Z"
+
+// AssetSyncer 원격 api.yaml / 메뉴 yaml을 받아 검증하고, 로컬 conf/ 사본에 반영한다 (conf/getapiyaml.sh 대체).
+//
+// 반영 순서: 원격 본문 검증 → 로컬 baseurl 유지 병합 → 기존 파일 백업 → 임시 파일 작성 후 rename → hot reload.
+// 롤백은 백업 파일을 같은 절차로 다시 반영한다 (롤백 직전 파일도 백업).
+type AssetSyncer struct {
+	checker    *RemoteAssetChecker
+	backupDir  string
+	backupKeep int
+
+	mu        sync.Mutex // apply/rollback/reload 직렬화
+	reloaders map[string]func(data []byte) error
+	bus       InvalidationBus
+}
+
+// AssetSyncPlan 동기화 계획 (dry-run 결과)
+type AssetSyncPlan struct {
+	Name         string              `json:"name"`
+	URL          string              `json:"url"`
+	LocalPath    string              `json:"localPath"`
+	UpstreamETag string              `json:"upstreamEtag,omitempty"`
+	LocalSHA     string              `json:"localSha,omitempty"`  // 현재 로컬 파일 git blob SHA
+	ResultSHA    string              `json:"resultSha,omitempty"` // 반영될 내용 git blob SHA (apply 시 expectSha로 전달)
+	UpToDate     bool                `json:"upToDate"`
+	Valid        bool                `json:"valid"`
+	Issues       []string            `json:"issues,omitempty"`    // 반영을 막는 검증 오류
+	Preserved    []AssetPreservedURL `json:"preserved,omitempty"` // 유지되는 로컬 baseurl
+	Services     *AssetSyncChanges   `json:"services,omitempty"`  // api
+	Actions      *AssetSyncChanges   `json:"actions,omitempty"`   // api ("service/operationId")
+	Menus        *AssetSyncChanges   `json:"menus,omitempty"`     // menu
+	PlannedAt    time.Time           `json:"plannedAt"`
+
+	content []byte
+}
+
+// AssetPreservedURL 원격 값 대신 유지하는 로컬 baseurl
+type AssetPreservedURL struct {
+	Service  string `json:"service"`
+	Local    string `json:"local"`
+	Upstream string `json:"upstream"`
+}
+
+// AssetSyncChanges 항목 단위 구조 비교
+type AssetSyncChanges struct {
+	Added   []string          `json:"added"`
+	Removed []string          `json:"removed"`
+	Changed []AssetSyncChange `json:"changed"`
+}
+
+// AssetSyncChange 양쪽에 있지만 값이 다른 항목
+type AssetSyncChange struct {
+	Key    string             `json:"key"`
+	Fields []AssetFieldChange `json:"fields"`
+}
+
+// AssetFieldChange 필드 단위 변경
+type AssetFieldChange struct {
+	Field    string `json:"field"`
+	Local    string `json:"local"`
+	Upstream string `json:"upstream"`
+}
+
+// AssetSyncResult apply/rollback/reload 결과
+type AssetSyncResult struct {
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`          // 로컬 파일이 바뀌었는지
+	Reloaded  bool      `json:"reloaded"`         // 실행 중인 설정에 반영되었는지
+	Backup    string    `json:"backup,omitempty"` // 반영 직전 파일 백업 ID
+	SHA       string    `json:"sha"`              // 반영 후 로컬 파일 git blob SHA
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// AssetBackup 백업 파일
+type AssetBackup struct {
+	ID        string    `json:"id"`
+	Size      int64     `json:"size"`
+	SHA       string    `json:"sha"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+var assetSyncer *AssetSyncer
+
+// InitAssetSyncer AssetSyncer 생성 후 전역 등록
+func InitAssetSyncer(cfg config.RemoteAssetsConfig, checker *RemoteAssetChecker) *AssetSyncer {
+	if cfg.BackupKeep <= 0 {
+		cfg.BackupKeep = 10
+	}
+	assetSyncer = &AssetSyncer{
+		checker:    checker,
+		backupDir:  cfg.BackupDir,
+		backupKeep: cfg.BackupKeep,
+		reloaders:  make(map[string]func(data []byte) error),
+	}
+	return assetSyncer
+}
+
+// GetAssetSyncer 전역 AssetSyncer (미초기화 시 nil)
+func GetAssetSyncer() *AssetSyncer {
+	return assetSyncer
+}
+
+// OnApply 자산이 반영/롤백/재적재될 때 실행 중인 설정을 교체하는 함수 등록 (hot reload)
+func (s *AssetSyncer) OnApply(name string, reload func(data []byte) error) {
+	s.mu.Lock()
+	s.reloaders[name] = reload
+	s.mu.Unlock()
+}
+
+// SetInvalidationBus 반영 사실을 다른 replica에 알리고, 다른 replica의 반영 알림을 받으면 로컬 파일을 다시 읽는다
+// (conf/가 공유 볼륨인 경우 모든 replica가 같은 내용을 사용하게 됨).
+func (s *AssetSyncer) SetInvalidationBus(bus InvalidationBus) {
+	s.mu.Lock()
+	s.bus = bus
+	s.mu.Unlock()
+	bus.Subscribe(InvalidationTopicAsset, func(name string) {
+		names := []string{name}
+		if name == "" {
+			names = []string{AssetSyncApi, AssetSyncMenu}
+		}
+		for _, name := range names {
+			if _, err := s.Reload(name); err != nil && !errors.Is(err, ErrAssetSyncUnsupported) {
+				log.Printf("[AssetSync] reload %s from another replica failed: %v", name, err)
+			}
+		}
+	})
+}
+
+// Plan 원격 자산을 받아 검증하고 로컬 사본과의 구조 차이를 계산한다 (파일은 바꾸지 않음)
+func (s *AssetSyncer) Plan(ctx context.Context, name string) (*AssetSyncPlan, error) {
+	asset, err := s.syncAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	report := RemoteAssetReport{Name: asset.Name, RemoteAssetProbe: RemoteAssetProbe{URL: asset.URL}}
+	upstream, err := s.checker.fetch(ctx, asset, &report)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrAssetFetchFailed, asset.URL, err)
+	}
+	local, err := os.ReadFile(asset.LocalPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	plan := &AssetSyncPlan{
+		Name:         asset.Name,
+		URL:          asset.URL,
+		LocalPath:    asset.LocalPath,
+		UpstreamETag: report.ETag,
+		PlannedAt:    time.Now(),
+	}
+	if local != nil {
+		plan.LocalSHA = gitBlobSHA(local)
+	}
+	switch asset.Name {
+	case AssetSyncApi:
+		s.planApi(plan, local, upstream)
+	case AssetSyncMenu:
+		s.planMenu(plan, local, upstream)
+	}
+	plan.Valid = len(plan.Issues) == 0
+	if plan.Valid {
+		plan.ResultSHA = gitBlobSHA(plan.content)
+		plan.UpToDate = plan.ResultSHA == plan.LocalSHA
+	}
+	return plan, nil
+}
+
+// Apply 계획을 다시 계산해 로컬 파일에 반영한다. expectSHA가 있으면 계획 결과가 같을 때만 반영한다.
+func (s *AssetSyncer) Apply(ctx context.Context, name, expectSHA string) (*AssetSyncPlan, *AssetSyncResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, err := s.Plan(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !plan.Valid {
+		return plan, nil, fmt.Errorf("%w: %s", ErrAssetSyncInvalid, strings.Join(plan.Issues, "; "))
+	}
+	if expectSHA != "" && expectSHA != plan.ResultSHA {
+		return plan, nil, ErrAssetSyncPlanChanged
+	}
+	result := &AssetSyncResult{Name: plan.Name, SHA: plan.ResultSHA, AppliedAt: time.Now()}
+	if plan.UpToDate {
+		return plan, result, nil
+	}
+	asset, _ := s.syncAsset(name)
+	if err := s.writeLocked(asset, plan.content, result); err != nil {
+		return plan, nil, err
+	}
+	return plan, result, nil
+}
+
+// Rollback 백업 파일을 다시 반영한다. backupID가 비어 있으면 가장 최근 백업을 사용한다.
+func (s *AssetSyncer) Rollback(name, backupID string) (*AssetSyncResult, error) {
+	asset, err := s.syncAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backups, err := s.Backups(asset.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) == 0 {
+		return nil, ErrAssetBackupNotFound
+	}
+	if backupID == "" {
+		backupID = backups[0].ID
+	}
+	found := false
+	for _, backup := range backups {
+		found = found || backup.ID == backupID
+	}
+	if !found {
+		return nil, ErrAssetBackupNotFound
+	}
+	data, err := os.ReadFile(filepath.Join(s.backupDir, backupID))
+	if err != nil {
+		return nil, err
+	}
+	if issues := validateSyncContent(asset.Name, data); len(issues) > 0 {
+		return nil, fmt.Errorf("%w: backup %s: %s", ErrAssetSyncInvalid, backupID, strings.Join(issues, "; "))
+	}
+	result := &AssetSyncResult{Name: asset.Name, SHA: gitBlobSHA(data), AppliedAt: time.Now()}
+	if err := s.writeLocked(asset, data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Reload 로컬 파일을 다시 읽어 실행 중인 설정에 반영한다 (CLI로 반영했거나 다른 replica가 반영한 경우)
+func (s *AssetSyncer) Reload(name string) (*AssetSyncResult, error) {
+	asset, err := s.syncAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(asset.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	if issues := validateSyncContent(asset.Name, data); len(issues) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAssetSyncInvalid, strings.Join(issues, "; "))
+	}
+	result := &AssetSyncResult{Name: asset.Name, SHA: gitBlobSHA(data), AppliedAt: time.Now()}
+	s.mu.Lock()
+	reload := s.reloaders[asset.Name]
+	s.mu.Unlock()
+	if reload != nil {
+		if err := reload(data); err != nil {
+			return nil, err
+		}
+		result.Reloaded = true
+	}
+	return result, nil
+}
+
+// Backups 자산의 백업 목록 (최신순)
+func (s *AssetSyncer) Backups(name string) ([]AssetBackup, error) {
+	asset, err := s.syncAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.backupDir)
+	if os.IsNotExist(err) {
+		return []AssetBackup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix := filepath.Base(asset.LocalPath) + "."
+	backups := []AssetBackup{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		createdAt, err := time.Parse(assetBackupTimeLayout, strings.TrimPrefix(entry.Name(), prefix))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.backupDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, AssetBackup{ID: entry.Name(), Size: int64(len(data)), SHA: gitBlobSHA(data), CreatedAt: createdAt})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// writeLocked 기존 파일 백업 → 원자적 교체 → hot reload → 다른 replica 알림. s.mu를 잡은 상태에서 호출한다.
+func (s *AssetSyncer) writeLocked(asset config.RemoteAsset, data []byte, result *AssetSyncResult) error {
+	if current, err := os.ReadFile(asset.LocalPath); err == nil {
+		backupID, err := s.backup(asset, current)
+		if err != nil {
+			return fmt.Errorf("backup %s: %w", asset.LocalPath, err)
+		}
+		result.Backup = backupID
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := writeFileAtomic(asset.LocalPath, data); err != nil {
+		return fmt.Errorf("write %s: %w", asset.LocalPath, err)
+	}
+	result.Applied = true
+	log.Printf("[AssetSync] %s written to %s (sha=%s backup=%s)", asset.Name, asset.LocalPath, result.SHA, result.Backup)
+
+	if reload := s.reloaders[asset.Name]; reload != nil {
+		if err := reload(data); err != nil {
+			return fmt.Errorf("%s written but reload failed: %w", asset.LocalPath, err)
+		}
+		result.Reloaded = true
+	}
+	if s.bus != nil {
+		if err := s.bus.Publish(InvalidationTopicAsset, asset.Name); err != nil {
+			log.Printf("[AssetSync] failed to publish invalidation: %v", err)
+		}
+	}
+	return nil
+}
+
+// backup 현재 파일을 <backupDir>/<파일명>.<UTC 시각>으로 복사하고 오래된 백업을 정리한다
+func (s *AssetSyncer) backup(asset config.RemoteAsset, data []byte) (string, error) {
+	if err := os.MkdirAll(s.backupDir, 0o755); err != nil {
+		return "", err
+	}
+	id := filepath.Base(asset.LocalPath) + "." + time.Now().UTC().Format(assetBackupTimeLayout)
+	if err := writeFileAtomic(filepath.Join(s.backupDir, id), data); err != nil {
+		return "", err
+	}
+	backups, err := s.Backups(asset.Name)
+	if err != nil {
+		return id, nil
+	}
+	for _, old := range backups[min(len(backups), s.backupKeep):] {
+		if err := os.Remove(filepath.Join(s.backupDir, old.ID)); err != nil {
+			log.Printf("[AssetSync] failed to remove old backup %s: %v", old.ID, err)
+		}
+	}
+	return id, nil
+}
+
+func (s *AssetSyncer) syncAsset(name string) (config.RemoteAsset, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name != AssetSyncApi && name != AssetSyncMenu {
+		return config.RemoteAsset{}, ErrAssetSyncUnsupported
+	}
+	return s.checker.Asset(name)
+}
+
+// planApi 원격 api.yaml 검증 + 로컬 baseurl 유지 병합 + 서비스/action 구조 비교
+func (s *AssetSyncer) planApi(plan *AssetSyncPlan, local, upstream []byte) {
+	if plan.Issues = validateSyncContent(AssetSyncApi, upstream); len(plan.Issues) > 0 {
+		return
+	}
+	upstreamSpec, _ := config.ParseApiSpec(upstream)
+	localServices := map[string]config.Service{}
+	localActions := map[string]map[string]config.ActionSpec{}
+	if local != nil {
+		localSpec, err := config.ParseApiSpec(local)
+		if err != nil {
+			log.Printf("[AssetSync] local %s is not a valid api.yaml, baseurl overrides are not kept: %v", plan.LocalPath, err)
+		} else {
+			localServices, localActions = localSpec.Snapshot()
+		}
+	}
+
+	// 로컬에 설정된 baseurl은 유지 (원격 api.yaml은 placeholder URL을 담고 있음)
+	overrides := map[string]string{}
+	upstreamServices, upstreamActions := upstreamSpec.Snapshot()
+	for name, svc := range upstreamServices {
+		if localSvc, ok := localServices[name]; ok && localSvc.BaseURL != "" && localSvc.BaseURL != svc.BaseURL {
+			overrides[name] = localSvc.BaseURL
+			plan.Preserved = append(plan.Preserved, AssetPreservedURL{Service: name, Local: localSvc.BaseURL, Upstream: svc.BaseURL})
+		}
+	}
+	sort.Slice(plan.Preserved, func(i, j int) bool { return plan.Preserved[i].Service < plan.Preserved[j].Service })
+
+	content, err := overrideApiBaseURLs(upstream, overrides)
+	if err != nil {
+		plan.Issues = append(plan.Issues, err.Error())
+		return
+	}
+	plan.content = content
+
+	merged := make(map[string]config.Service, len(upstreamServices))
+	for name, svc := range upstreamServices {
+		if url, ok := overrides[name]; ok {
+			svc.BaseURL = url
+		}
+		merged[name] = svc
+	}
+	plan.Services = diffAssetItems(localServices, merged, func(svc config.Service) map[string]string {
+		return map[string]string{"version": svc.Version, "baseurl": svc.BaseURL, "auth.type": svc.Auth.Type}
+	})
+	plan.Actions = diffAssetItems(flattenActions(localActions), flattenActions(upstreamActions), func(action config.ActionSpec) map[string]string {
+		return map[string]string{"method": action.Method, "resourcePath": action.ResourcePath}
+	})
+}
+
+// planMenu 원격 메뉴 yaml 검증 + 메뉴 id 단위 구조 비교
+func (s *AssetSyncer) planMenu(plan *AssetSyncPlan, local, upstream []byte) {
+	if plan.Issues = validateSyncContent(AssetSyncMenu, upstream); len(plan.Issues) > 0 {
+		return
+	}
+	plan.content = upstream
+	upstreamMenus, _ := config.ParseMenuResources(upstream)
+	localById := map[string]config.MenuResource{}
+	if local != nil {
+		if localMenus, err := config.ParseMenuResources(local); err == nil {
+			localById = localMenus.ById()
+		}
+	}
+	plan.Menus = diffAssetItems(localById, upstreamMenus.ById(), func(menu config.MenuResource) map[string]string {
+		return map[string]string{
+			"parentid":    menu.ParentId,
+			"displayname": menu.DisplayName,
+			"restype":     menu.ResType,
+			"isaction":    strconv.FormatBool(menu.IsAction),
+			"priority":    strconv.Itoa(menu.Priority),
+			"menunumber":  strconv.Itoa(menu.MenuNumber),
+		}
+	})
+}
+
+// validateSyncContent 반영 전 검증. 반환값이 있으면 반영하지 않는다.
+func validateSyncContent(name string, data []byte) []string {
+	if err := validateRemoteAsset("yaml", data); err != nil {
+		return []string{err.Error()}
+	}
+	switch name {
+	case AssetSyncApi:
+		spec, err := config.ParseApiSpec(data)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		services, actions := spec.Snapshot()
+		if len(services) == 0 {
+			return []string{"api.yaml has no services"}
+		}
+		var issues []string
+		for _, svcName := range sortedKeys(actions) {
+			if _, ok := services[svcName]; !ok {
+				issues = append(issues, fmt.Sprintf("serviceActions.%s: service is not defined", svcName))
+			}
+			for _, opId := range sortedKeys(actions[svcName]) {
+				if err := validateRegistryAction(registryActionV1(actions[svcName][opId])); err != nil {
+					issues = append(issues, fmt.Sprintf("serviceActions.%s.%s: %v", svcName, opId, err))
+				}
+			}
+		}
+		return issues
+	case AssetSyncMenu:
+		menus, err := config.ParseMenuResources(data)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return menus.Validate()
+	}
+	return nil
+}
+
+// overrideApiBaseURLs 원격 api.yaml의 services.<name>.baseurl 값을 바꾼다.
+// baseurl 줄만 고쳐 쓰므로 나머지 내용(주석, 따옴표, 순서)은 원격과 같다. 바꾼 값은 항상 큰따옴표로 감싼다.
+// baseurl 키가 없는 서비스가 있으면 문서를 다시 직렬화한다.
+func overrideApiBaseURLs(upstream []byte, overrides map[string]string) ([]byte, error) {
+	if len(overrides) == 0 {
+		return upstream, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(upstream, &doc); err != nil {
+		return nil, err
+	}
+	_, services := yamlMappingEntry(doc.Content[0], "services")
+	if services == nil {
+		return nil, fmt.Errorf("api.yaml has no services mapping")
+	}
+	lines := strings.Split(string(upstream), "\n")
+	reencode := false
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		url, ok := overrides[strings.ToLower(services.Content[i].Value)]
+		if !ok {
+			continue
+		}
+		svc := services.Content[i+1]
+		key, value := yamlMappingEntry(svc, "baseurl")
+		switch {
+		case key != nil && value.Kind == yaml.ScalarNode && (value.Line == key.Line || value.Value == "") && key.Line <= len(lines):
+			line := lines[key.Line-1]
+			quoted, err := yamlQuote(url)
+			if err != nil {
+				return nil, err
+			}
+			replaced := line[:key.Column-1] + key.Value + ": " + quoted
+			if comment := firstNonEmpty(value.LineComment, key.LineComment); comment != "" {
+				replaced += " " + comment
+			}
+			if strings.HasSuffix(line, "\r") {
+				replaced += "\r"
+			}
+			lines[key.Line-1] = replaced
+			value.Kind, value.Tag, value.Style, value.Value = yaml.ScalarNode, "!!str", yaml.DoubleQuotedStyle, url
+		case key != nil:
+			value.Kind, value.Tag, value.Style, value.Value = yaml.ScalarNode, "!!str", yaml.DoubleQuotedStyle, url
+			reencode = true
+		case svc.Kind == yaml.MappingNode:
+			svc.Content = append(svc.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "baseurl"},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: url})
+			reencode = true
+		}
+	}
+	if !reencode {
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlQuote 값을 yaml 큰따옴표 스칼라로 (URL의 "#", ": " 등이 주석/매핑으로 읽히지 않도록)
+func yamlQuote(value string) (string, error) {
+	out, err := yaml.Marshal(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: value})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// yamlMappingEntry 매핑 노드에서 key(대소문자 무시)의 키/값 노드
+func yamlMappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func flattenActions(actions map[string]map[string]config.ActionSpec) map[string]config.ActionSpec {
+	flat := make(map[string]config.ActionSpec)
+	for svcName, ops := range actions {
+		for opId, action := range ops {
+			flat[svcName+"/"+opId] = action
+		}
+	}
+	return flat
+}
+
+// diffAssetItems 키 기준 추가/삭제/변경 항목 (fields로 비교할 필드를 뽑음)
+func diffAssetItems[V any](local, upstream map[string]V, fields func(V) map[string]string) *AssetSyncChanges {
+	changes := &AssetSyncChanges{Added: []string{}, Removed: []string{}, Changed: []AssetSyncChange{}}
+	for _, key := range sortedKeys(upstream) {
+		localItem, ok := local[key]
+		if !ok {
+			changes.Added = append(changes.Added, key)
+			continue
+		}
+		localFields, upstreamFields := fields(localItem), fields(upstream[key])
+		change := AssetSyncChange{Key: key}
+		for _, field := range sortedKeys(upstreamFields) {
+			if localFields[field] != upstreamFields[field] {
+				change.Fields = append(change.Fields, AssetFieldChange{Field: field, Local: localFields[field], Upstream: upstreamFields[field]})
+			}
+		}
+		if len(change.Fields) > 0 {
+			changes.Changed = append(changes.Changed, change)
+		}
+	}
+	for _, key := range sortedKeys(local) {
+		if _, ok := upstream[key]; !ok {
+			changes.Removed = append(changes.Removed, key)
+		}
+	}
+	return changes
+}
+
+// writeFileAtomic 같은 디렉터리의 임시 파일에 쓰고 rename (읽는 쪽은 이전/새 내용 중 하나만 봄)
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
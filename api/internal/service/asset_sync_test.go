@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+const testUpstreamApiYaml = `# upstream api.yaml
+services:
+  mc-infra-manager:
+    version: 0.12.9
+    baseurl: http://mc-infra-manager:1323/tumblebug # placeholder
+    auth:
+      type: basic
+  mc-iam-manager:
+    version: 0.2.11
+    baseurl: http://mc-iam-manager:5000
+serviceActions:
+  mc-infra-manager:
+    GetAllNs:
+      method: get
+      resourcePath: /ns
+`
+
+// newTestAssetSyncer upstream 본문을 응답하는 원격 api 자산과 임시 디렉터리의 로컬 사본/백업 위치를 쓰는 AssetSyncer
+func newTestAssetSyncer(t *testing.T, upstream *atomic.Value) (*AssetSyncer, string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(upstream.Load().(string)))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "api.yaml")
+	cfg := config.RemoteAssetsConfig{
+		Assets:    []config.RemoteAsset{{Name: AssetSyncApi, URL: server.URL + "/conf/api.yaml", LocalPath: localPath, Format: "yaml"}},
+		BackupDir: filepath.Join(dir, "backup"),
+	}
+	return InitAssetSyncer(cfg, InitRemoteAssetChecker(cfg)), localPath
+}
+
+func TestOverrideApiBaseURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+		url      string
+		wantLine string
+	}{
+		{
+			name:     "keeps the line comment",
+			upstream: "services:\n  mc-infra-manager:\n    baseurl: http://placeholder # upstream\n",
+			url:      "http://10.0.0.1:1323/tumblebug",
+			wantLine: `    baseurl: "http://10.0.0.1:1323/tumblebug" # upstream`,
+		},
+		{
+			name:     "quotes yaml special characters",
+			upstream: "services:\n  mc-infra-manager:\n    baseurl: http://placeholder\n",
+			url:      "http://host:1323/a #b: c",
+			wantLine: `    baseurl: "http://host:1323/a #b: c"`,
+		},
+		{
+			name:     "empty value",
+			upstream: "services:\n  mc-infra-manager:\n    baseurl:\n    version: main\n",
+			url:      "http://10.0.0.1:1323",
+			wantLine: `    baseurl: "http://10.0.0.1:1323"`,
+		},
+	}
+	for _, tt := range tests {
+		out, err := overrideApiBaseURLs([]byte(tt.upstream), map[string]string{"mc-infra-manager": tt.url})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !strings.Contains(string(out), tt.wantLine+"\n") {
+			t.Errorf("%s: output =\n%s\nwant line %s", tt.name, out, tt.wantLine)
+		}
+		spec, err := config.ParseApiSpec(out)
+		if err != nil {
+			t.Fatalf("%s: result is not a valid api.yaml: %v", tt.name, err)
+		}
+		if got := spec.Services["mc-infra-manager"].BaseURL; got != tt.url {
+			t.Errorf("%s: baseurl = %q, want %q", tt.name, got, tt.url)
+		}
+	}
+}
+
+func TestOverrideApiBaseURLsReencode(t *testing.T) {
+	// baseurl 키가 없는 서비스는 문서를 다시 직렬화해 키를 추가한다
+	upstream := "services:\n  mc-infra-manager:\n    version: main\n  mc-iam-manager:\n    baseurl: http://mc-iam-manager:5000\n"
+	url := "http://host:1323/a #b"
+	out, err := overrideApiBaseURLs([]byte(upstream), map[string]string{"mc-infra-manager": url})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := config.ParseApiSpec(out)
+	if err != nil {
+		t.Fatalf("result is not a valid api.yaml: %v\n%s", err, out)
+	}
+	if got := spec.Services["mc-infra-manager"].BaseURL; got != url {
+		t.Errorf("mc-infra-manager baseurl = %q, want %q", got, url)
+	}
+	if got := spec.Services["mc-iam-manager"].BaseURL; got != "http://mc-iam-manager:5000" {
+		t.Errorf("mc-iam-manager baseurl = %q", got)
+	}
+}
+
+func TestAssetSyncApplyBackupRollback(t *testing.T) {
+	var upstream atomic.Value
+	upstream.Store(testUpstreamApiYaml)
+	syncer, localPath := newTestAssetSyncer(t, &upstream)
+
+	local := strings.Replace(testUpstreamApiYaml, "http://mc-infra-manager:1323/tumblebug", "http://10.0.0.1:1323/tumblebug", 1)
+	if err := os.WriteFile(localPath, []byte(local), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// 첫 반영: 로컬 baseurl 유지, 기존 파일 백업
+	upstream.Store(strings.Replace(testUpstreamApiYaml, "version: 0.12.9", "version: 0.13.0", 1))
+	plan, result, err := syncer.Apply(context.Background(), AssetSyncApi, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Preserved) != 1 || plan.Preserved[0].Service != "mc-infra-manager" {
+		t.Fatalf("preserved = %+v", plan.Preserved)
+	}
+	if !result.Applied || result.Backup == "" {
+		t.Fatalf("result = %+v, want applied with a backup", result)
+	}
+	applied, err := config.LoadApiSpec(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc := applied.Services["mc-infra-manager"]; svc.BaseURL != "http://10.0.0.1:1323/tumblebug" || svc.Version != "0.13.0" {
+		t.Fatalf("applied mc-infra-manager = %+v", svc)
+	}
+	if info, err := os.Stat(localPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("local file mode changed: %v %v", info.Mode(), err)
+	}
+
+	// 같은 내용은 다시 쓰지 않는다
+	if _, again, err := syncer.Apply(context.Background(), AssetSyncApi, ""); err != nil || again.Applied {
+		t.Fatalf("second apply: result = %+v, err = %v", again, err)
+	}
+
+	// 두 번째 반영 후 백업은 최신순
+	time.Sleep(2 * time.Millisecond)
+	upstream.Store(strings.Replace(testUpstreamApiYaml, "version: 0.12.9", "version: 0.14.0", 1))
+	_, second, err := syncer.Apply(context.Background(), AssetSyncApi, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, err := syncer.Backups(AssetSyncApi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].ID != second.Backup || backups[1].ID != result.Backup {
+		t.Fatalf("backups = %+v, want [%s %s]", backups, second.Backup, result.Backup)
+	}
+	if backups[1].SHA != gitBlobSHA([]byte(local)) {
+		t.Fatalf("first backup does not hold the original file")
+	}
+
+	// 지정한 백업으로 롤백: 원래 파일 복원, 롤백 직전 파일도 백업
+	time.Sleep(2 * time.Millisecond)
+	rolled, err := syncer.Rollback(AssetSyncApi, result.Backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(localPath); string(data) != local {
+		t.Fatalf("rollback did not restore the original file:\n%s", data)
+	}
+	if rolled.Backup == "" {
+		t.Fatal("rollback did not back up the replaced file")
+	}
+	if backups, _ := syncer.Backups(AssetSyncApi); len(backups) != 3 || backups[0].ID != rolled.Backup {
+		t.Fatalf("backups after rollback = %+v", backups)
+	}
+
+	if _, err := syncer.Rollback(AssetSyncApi, "api.yaml.unknown"); err != ErrAssetBackupNotFound {
+		t.Fatalf("unknown backup: err = %v, want ErrAssetBackupNotFound", err)
+	}
+}
+
+func TestAssetSyncApplyWithoutLocalFile(t *testing.T) {
+	var upstream atomic.Value
+	upstream.Store(testUpstreamApiYaml)
+	syncer, localPath := newTestAssetSyncer(t, &upstream)
+
+	_, result, err := syncer.Apply(context.Background(), AssetSyncApi, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Applied || result.Backup != "" {
+		t.Fatalf("result = %+v, want applied without a backup", result)
+	}
+	if data, _ := os.ReadFile(localPath); string(data) != testUpstreamApiYaml {
+		t.Fatalf("local file =\n%s", data)
+	}
+	if _, err := syncer.Rollback(AssetSyncApi, ""); err != ErrAssetBackupNotFound {
+		t.Fatalf("rollback without backups: err = %v, want ErrAssetBackupNotFound", err)
+	}
+}
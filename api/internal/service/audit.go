@@ -0,0 +1,26 @@
+package service
+
+import (
+	"log"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+)
+
+// AuditActorSystem 자동 처리(잠금 등)의 감사 로그 수행 주체
+const AuditActorSystem = "system"
+
+// RecordAudit 감사 로그 기록. 항상 서버 로그에 남기고, DB가 활성화된 경우 audit_events에도 저장한다.
+// 감사 로그 저장 실패가 요청 처리를 막지 않도록 에러는 로그로만 남긴다.
+func RecordAudit(event model.AuditEvent) {
+	log.Printf("[Audit] action=%s actor=%s target=%s ip=%s detail=%q",
+		event.Action, event.Actor, event.Target, event.IP, event.Detail)
+
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+	if err := repository.NewAuditRepository(db).Create(&event); err != nil {
+		log.Printf("[Audit] failed to store event %s: %v", event.Action, err)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"mc_web_console_api/internal/config"
+)
+
+// Authorizer 역할 → 정책 컬럼 → API 정책 테이블(webconsole_api_permissions.csv) 기반 인가 판정기
+type Authorizer struct {
+	perms *config.ApiPermissions
+	authz config.AuthzConfig
+}
+
+// AuthzDecision 인가 판정 결과. 거부 응답과 explain 엔드포인트에 그대로 노출된다.
+type AuthzDecision struct {
+	Allowed   bool                   `json:"allowed"`
+	Subsystem string                 `json:"subsystem"`
+	Operation string                 `json:"operation"`
+	Method    string                 `json:"method"`
+	Roles     []string               `json:"roles"`
+	Policies  []string               `json:"policies"`          // 역할에서 도출된 정책 컬럼
+	Rule      *config.PermissionRule `json:"rule,omitempty"`    // 결정을 내린 규칙 (없으면 기본 거부)
+	Granted   []string               `json:"granted,omitempty"` // 규칙에서 허용된 정책 중 사용자가 가진 것
+	Reason    string                 `json:"reason"`
+}
+
+var (
+	authorizer  *Authorizer
+	authzConfig config.AuthzConfig
+)
+
+// InitAuthorizer 전역 인가 판정기 초기화. 인가 비활성(perms == nil)이면 nil로 둔다.
+func InitAuthorizer(perms *config.ApiPermissions, authz config.AuthzConfig) *Authorizer {
+	authzConfig = authz
+	if perms == nil {
+		authorizer = nil
+		return nil
+	}
+	authorizer = &Authorizer{perms: perms, authz: authz}
+	return authorizer
+}
+
+// GetAuthorizer 전역 인가 판정기 반환 (비활성이면 nil)
+func GetAuthorizer() *Authorizer {
+	return authorizer
+}
+
+// Decide 역할 목록이 subsystem/operation/method 호출을 허용받는지 판정한다.
+// 처음 일치한 규칙에서 사용자 정책 중 하나라도 TRUE이면 허용, 일치하는 규칙이 없으면 거부.
+func (a *Authorizer) Decide(roles []string, subsystem, operation, method string) AuthzDecision {
+	method = strings.ToUpper(method)
+	d := AuthzDecision{
+		Subsystem: subsystem,
+		Operation: operation,
+		Method:    method,
+		Roles:     roles,
+		Policies:  a.authz.PoliciesForRoles(roles...),
+	}
+
+	rule := a.perms.Match(subsystem, operation, method)
+	if rule == nil {
+		d.Reason = fmt.Sprintf("no rule in %s matches %s %s/%s (default deny)", a.perms.Source, method, subsystem, operation)
+		return d
+	}
+	d.Rule = rule
+
+	for _, policy := range d.Policies {
+		if rule.Allowed[policy] {
+			d.Granted = append(d.Granted, policy)
+		}
+	}
+	ruleDesc := fmt.Sprintf("rule line %d (%s,%s,%s)", rule.Line, rule.Framework, rule.Operation, strings.Join(rule.Methods, "|"))
+	switch {
+	case len(d.Granted) > 0:
+		d.Allowed = true
+		d.Reason = fmt.Sprintf("allowed by %s for %s", ruleDesc, strings.Join(d.Granted, ","))
+	case len(d.Policies) == 0:
+		d.Reason = fmt.Sprintf("roles %v map to no policy; %s requires one of %s", roles, ruleDesc, strings.Join(a.allowedPolicies(rule), ","))
+	default:
+		d.Reason = fmt.Sprintf("%s does not grant %s (allowed: %s)", ruleDesc, strings.Join(d.Policies, ","), strings.Join(a.allowedPolicies(rule), ","))
+	}
+	return d
+}
+
+// DecideDisabled 인가 비활성(정책 테이블 없음) 시 판정.
+// 역할 정책은 적용하지 않지만 mc-web-console/admin:* BFF 관리 라우트는 AdminRoles 역할만 허용한다 (fail closed).
+func DecideDisabled(roles []string, subsystem, operation, method string) AuthzDecision {
+	d := AuthzDecision{
+		Subsystem: subsystem,
+		Operation: operation,
+		Method:    strings.ToUpper(method),
+		Roles:     roles,
+	}
+	if !isAdminOperation(subsystem, operation) {
+		d.Allowed = true
+		d.Reason = "authorization is disabled"
+		return d
+	}
+	if hasAnyRoleFold(roles, authzConfig.AdminRoles) {
+		d.Allowed = true
+		d.Reason = fmt.Sprintf("authorization is disabled; admin route allowed for roles %v", roles)
+		return d
+	}
+	d.Reason = fmt.Sprintf("authorization is disabled; admin routes require one of roles %s", strings.Join(authzConfig.AdminRoles, ","))
+	return d
+}
+
+// isAdminOperation mc-web-console/admin:* BFF 관리 라우트 여부 (대소문자 무시)
+func isAdminOperation(subsystem, operation string) bool {
+	return strings.EqualFold(subsystem, "mc-web-console") && strings.HasPrefix(strings.ToLower(operation), "admin:")
+}
+
+// allowedPolicies 규칙에서 허용된 정책 컬럼 목록 (CSV 컬럼 순서)
+func (a *Authorizer) allowedPolicies(rule *config.PermissionRule) []string {
+	policies := make([]string, 0, len(a.perms.Policies))
+	for _, policy := range a.perms.Policies {
+		if rule.Allowed[policy] {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return []string{"none"}
+	}
+	return policies
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mc_web_console_api/internal/config"
+)
+
+// loadTestApiPermissions 임시 CSV로 정책 테이블 로드
+func loadTestApiPermissions(t *testing.T, csv string) *config.ApiPermissions {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "webconsole_api_permissions.csv")
+	if err := os.WriteFile(file, []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	perms, err := config.LoadApiPermissions(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return perms
+}
+
+var testAuthzConfig = config.AuthzConfig{
+	RolePolicies: map[string]string{"admin": "adminPolicy", "operator": "operatorPolicy", "viewer": "viewerPolicy"},
+	AdminRoles:   []string{"platformAdmin", "admin"},
+}
+
+func TestAuthorizerDecideFirstMatch(t *testing.T) {
+	perms := loadTestApiPermissions(t, `framework,operation,methods,adminPolicy,operatorPolicy,viewerPolicy
+# 구체적인 규칙이 먼저 와야 와일드카드 규칙보다 우선한다
+mc-infra-manager,DeleteMci,DELETE,TRUE,FALSE,FALSE
+mc-infra-manager,Get*,GET,TRUE,TRUE,TRUE
+mc-infra-manager,*,*,TRUE,TRUE,FALSE
+mc-web-console,admin:*,*,TRUE,FALSE,FALSE
+mc-infra-manager,GetNs,GET,FALSE,FALSE,FALSE
+`)
+	authz := &Authorizer{perms: perms, authz: testAuthzConfig}
+
+	tests := []struct {
+		name                         string
+		roles                        []string
+		subsystem, operation, method string
+		wantAllowed                  bool
+		wantLine                     int
+	}{
+		{"specific rule before wildcard", []string{"operator"}, "mc-infra-manager", "DeleteMci", "DELETE", false, 3},
+		{"glob operation", []string{"viewer"}, "mc-infra-manager", "GetMci", "GET", true, 4},
+		// 아래쪽 GetNs 규칙은 Get* 규칙에 가려져 평가되지 않는다
+		{"later rule shadowed", []string{"viewer"}, "mc-infra-manager", "GetNs", "get", true, 4},
+		{"method falls through", []string{"viewer"}, "mc-infra-manager", "GetMci", "POST", false, 5},
+		{"case folding", []string{"ADMIN"}, "MC-Infra-Manager", "deletemci", "DELETE", true, 3},
+		{"admin route", []string{"operator"}, "mc-web-console", "admin:menu-sync", "POST", false, 6},
+		{"no rule", []string{"admin"}, "mc-iam-manager", "Listusers", "POST", false, 0},
+		{"unknown role", []string{"guest"}, "mc-infra-manager", "GetMci", "GET", false, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := authz.Decide(tt.roles, tt.subsystem, tt.operation, tt.method)
+			if d.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v (%s), want %v", d.Allowed, d.Reason, tt.wantAllowed)
+			}
+			line := 0
+			if d.Rule != nil {
+				line = d.Rule.Line
+			}
+			if line != tt.wantLine {
+				t.Fatalf("rule line = %d, want %d", line, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestDecideDisabledAdminGate(t *testing.T) {
+	if InitAuthorizer(nil, testAuthzConfig) != nil {
+		t.Fatal("InitAuthorizer(nil) returned an authorizer")
+	}
+	t.Cleanup(func() { InitAuthorizer(nil, config.AuthzConfig{}) })
+
+	tests := []struct {
+		name                 string
+		roles                []string
+		subsystem, operation string
+		want                 bool
+	}{
+		{"proxy route without policies", []string{"viewer"}, "mc-infra-manager", "DeleteMci", true},
+		{"non-admin BFF route", nil, "mc-web-console", "GetMfaStatus", true},
+		{"admin route for admin", []string{"viewer", "PlatformAdmin"}, "mc-web-console", "admin:menu-sync", true},
+		{"admin route for operator", []string{"operator"}, "mc-web-console", "admin:menu-sync", false},
+		{"admin route without roles", nil, "MC-Web-Console", "Admin:sessions", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := DecideDisabled(tt.roles, tt.subsystem, tt.operation, "POST"); d.Allowed != tt.want {
+				t.Fatalf("Allowed = %v (%s), want %v", d.Allowed, d.Reason, tt.want)
+			}
+		})
+	}
+
+	// 관리자 역할 설정이 비어 있으면 관리 라우트는 모두 거부된다
+	InitAuthorizer(nil, config.AuthzConfig{})
+	if d := DecideDisabled([]string{"admin"}, "mc-web-console", "admin:menu-sync", "POST"); d.Allowed {
+		t.Fatalf("admin route allowed without AdminRoles: %s", d.Reason)
+	}
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+// 백엔드 서비스 상태
+const (
+	HealthStatusUnknown  = "unknown"  // 아직 점검 전
+	HealthStatusUp       = "up"       // 점검 경로 2xx/3xx
+	HealthStatusDegraded = "degraded" // 연속 실패(연결 실패, 4xx/5xx)가 임계치 미만이거나 인증서 만료 임박
+	HealthStatusDown     = "down"     // 연속 실패 임계치 이상
+)
+
+// HealthMonitor api.yaml의 모든 서비스(레지스트리 BaseURL override 포함)를 주기적으로 점검하는 백그라운드 작업.
+//
+// 점검 경로: MC_WEB_CONSOLE_HEALTH_PATHS > api.yaml의 /readyz action > MC_WEB_CONSOLE_HEALTH_PATH.
+// 결과는 /readyz(전체 상태만)와 관리자 상태 화면(GET /api/admin/health)에 제공된다.
+type HealthMonitor struct {
+	cfg      config.HealthConfig
+	apiSpec  *config.ApiSpec
+	registry config.RegistryCacheInterface // USE_REGISTRY_URL일 때만 설정
+	client   *http.Client
+	exclude  map[string]bool
+
+	runMu sync.Mutex // RunOnce 직렬화
+
+	mu       sync.RWMutex
+	backends map[string]*BackendHealth
+	lastRun  time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// BackendHealth 서비스별 점검 결과
+type BackendHealth struct {
+	Name                string          `json:"name"`
+	Status              string          `json:"status"`
+	BaseURL             string          `json:"baseUrl"`
+	Source              string          `json:"source"` // registry | api.yaml
+	URL                 string          `json:"url"`    // 점검 URL
+	StatusCode          int             `json:"statusCode,omitempty"`
+	LatencyMs           int64           `json:"latencyMs"`
+	Error               string          `json:"error,omitempty"`
+	Reason              string          `json:"reason,omitempty"` // degraded/down 사유
+	ConsecutiveFailures int             `json:"consecutiveFailures"`
+	Uptime              float64         `json:"uptime"` // 보관 이력 중 up 비율 (0~1)
+	CheckedAt           time.Time       `json:"checkedAt,omitempty"`
+	ChangedAt           time.Time       `json:"changedAt,omitempty"` // 상태가 마지막으로 바뀐 시각
+	Certificate         *HealthCertInfo `json:"certificate,omitempty"`
+	History             []HealthSample  `json:"history"`
+}
+
+// HealthCertInfo HTTPS 서비스의 서버 인증서
+type HealthCertInfo struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"notAfter"`
+	DaysLeft int       `json:"daysLeft"`
+}
+
+// HealthSample 점검 1회 결과
+type HealthSample struct {
+	At         time.Time `json:"at"`
+	Up         bool      `json:"up"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	LatencyMs  int64     `json:"latencyMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// HealthSummary 전체 상태 요약 (/readyz, 상태 화면 헤더)
+type HealthSummary struct {
+	Status       string         `json:"status"` // up | degraded | down | unknown
+	CheckedAt    time.Time      `json:"checkedAt,omitempty"`
+	Counts       map[string]int `json:"counts"`
+	Degraded     []string       `json:"degraded,omitempty"`
+	Down         []string       `json:"down,omitempty"`
+	RequiredDown []string       `json:"requiredDown,omitempty"` // MC_WEB_CONSOLE_HEALTH_REQUIRED 중 down
+}
+
+var healthMonitor *HealthMonitor
+
+// InitHealthMonitor HealthMonitor 생성 후 전역 등록. 점검 주기가 0이면 nil
+func InitHealthMonitor(cfg *config.Config) *HealthMonitor {
+	if cfg.Health.Interval <= 0 {
+		return nil
+	}
+	healthCfg := cfg.Health
+	if healthCfg.FailThreshold < 1 {
+		// 0 이하이면 정상 응답 중에도 down으로 판정되므로 1회 실패를 down 기준으로 본다
+		healthCfg.FailThreshold = 1
+	}
+	monitor := &HealthMonitor{
+		cfg:      healthCfg,
+		apiSpec:  cfg.ApiSpec,
+		exclude:  make(map[string]bool, len(cfg.Health.Exclude)),
+		backends: make(map[string]*BackendHealth),
+		client: &http.Client{
+			Timeout: healthCfg.Timeout,
+			// 리다이렉트는 따라가지 않고 3xx 자체를 응답으로 본다 (로그인 페이지 등)
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+	if cfg.MCIAM.Use && cfg.MCIAM.UseRegistryURL {
+		monitor.registry = cfg.RegistryCache
+	}
+	for _, name := range healthCfg.Exclude {
+		monitor.exclude[strings.ToLower(name)] = true
+	}
+	healthMonitor = monitor
+	RegisterStatusProvider("health", func() interface{} { return monitor.Summary() })
+	return monitor
+}
+
+// GetHealthMonitor 전역 HealthMonitor 반환 (미설정이면 nil)
+func GetHealthMonitor() *HealthMonitor {
+	return healthMonitor
+}
+
+// Start 점검 goroutine 시작. 시작 직후 1회 실행 후 interval마다 반복한다.
+func (m *HealthMonitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.cfg.Interval)
+		defer ticker.Stop()
+
+		m.RunOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[HealthMonitor] stopped")
+				return
+			case <-ticker.C:
+				m.RunOnce(ctx)
+			}
+		}
+	}()
+	log.Printf("[HealthMonitor] started (interval=%s, timeout=%s)", m.cfg.Interval, m.cfg.Timeout)
+}
+
+// Stop 점검 goroutine 종료 후 진행 중인 점검이 끝날 때까지 대기 (graceful shutdown)
+func (m *HealthMonitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+}
+
+// healthTarget 점검 대상
+type healthTarget struct {
+	name    string
+	baseURL string
+	source  string
+	url     string
+}
+
+// RunOnce 모든 대상을 병렬로 1회 점검한다
+func (m *HealthMonitor) RunOnce(ctx context.Context) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	targets := m.targets()
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target healthTarget) {
+			defer wg.Done()
+			sample, cert := m.probe(ctx, target.url)
+			if ctx.Err() != nil {
+				return // 종료/요청 취소로 중단된 점검은 기록하지 않는다
+			}
+			m.record(target, sample, cert)
+		}(target)
+	}
+	wg.Wait()
+
+	// api.yaml에서 빠진 서비스 결과 정리
+	m.mu.Lock()
+	for name := range m.backends {
+		found := false
+		for _, target := range targets {
+			if target.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(m.backends, name)
+		}
+	}
+	m.lastRun = time.Now()
+	m.mu.Unlock()
+}
+
+func (m *HealthMonitor) targets() []healthTarget {
+	services, actions := m.apiSpec.Snapshot()
+	targets := make([]healthTarget, 0, len(services))
+	for name, svc := range services {
+		if m.exclude[strings.ToLower(name)] {
+			continue
+		}
+		target := healthTarget{name: name, baseURL: svc.BaseURL, source: RegistrySourceApiYaml}
+		if m.registry != nil {
+			if override := m.registry.GetBaseURL(name, ""); override != "" {
+				target.baseURL, target.source = override, RegistrySourceRegistry
+			}
+		}
+		if target.baseURL == "" {
+			continue
+		}
+		target.url = strings.TrimRight(target.baseURL, "/") + m.healthPath(name, actions[name])
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// healthPath 서비스 점검 경로: 설정 > api.yaml의 경로 변수 없는 /readyz action > 기본 경로
+func (m *HealthMonitor) healthPath(name string, actions map[string]config.ActionSpec) string {
+	if path, ok := m.cfg.Paths[strings.ToLower(name)]; ok {
+		return path
+	}
+	for _, opId := range sortedKeys(actions) {
+		path := strings.SplitN(actions[opId].ResourcePath, "?", 2)[0]
+		if strings.EqualFold(actions[opId].Method, http.MethodGet) && strings.HasSuffix(path, "/readyz") && !strings.Contains(path, "{") {
+			return path
+		}
+	}
+	return m.cfg.DefaultPath
+}
+
+func (m *HealthMonitor) probe(ctx context.Context, url string) (HealthSample, *HealthCertInfo) {
+	sample := HealthSample{At: time.Now()}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		sample.Error = err.Error()
+		return sample, nil
+	}
+	start := time.Now()
+	resp, err := m.client.Do(req)
+	sample.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		sample.Error = err.Error()
+		return sample, nil
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	sample.StatusCode = resp.StatusCode
+	sample.Up = resp.StatusCode < 400
+	return sample, certInfo(resp.TLS)
+}
+
+func certInfo(state *tls.ConnectionState) *HealthCertInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := state.PeerCertificates[0]
+	return &HealthCertInfo{
+		Subject:  leaf.Subject.CommonName,
+		Issuer:   leaf.Issuer.CommonName,
+		NotAfter: leaf.NotAfter,
+		DaysLeft: int(time.Until(leaf.NotAfter).Hours() / 24),
+	}
+}
+
+// record 점검 결과를 이력에 추가하고 상태를 다시 계산한다
+func (m *HealthMonitor) record(target healthTarget, sample HealthSample, cert *HealthCertInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.backends[target.name]
+	if !ok {
+		b = &BackendHealth{Name: target.name, Status: HealthStatusUnknown}
+		m.backends[target.name] = b
+	}
+	b.BaseURL, b.Source, b.URL = target.baseURL, target.source, target.url
+	b.StatusCode, b.LatencyMs, b.Error, b.CheckedAt = sample.StatusCode, sample.LatencyMs, sample.Error, sample.At
+	if cert != nil {
+		b.Certificate = cert
+	} else if sample.StatusCode != 0 {
+		b.Certificate = nil // HTTP 응답을 받았는데 인증서가 없으면 평문 서비스
+	}
+
+	b.History = append(b.History, sample)
+	if limit := m.cfg.History; limit > 0 && len(b.History) > limit {
+		b.History = b.History[len(b.History)-limit:]
+	}
+	up := 0
+	for _, h := range b.History {
+		if h.Up {
+			up++
+		}
+	}
+	b.Uptime = float64(up) / float64(len(b.History))
+
+	status, reason := HealthStatusUp, ""
+	switch {
+	case sample.StatusCode == 0:
+		b.ConsecutiveFailures++
+		reason = "unreachable"
+	case !sample.Up:
+		b.ConsecutiveFailures++
+		reason = fmt.Sprintf("health path returned %d", sample.StatusCode)
+	default:
+		b.ConsecutiveFailures = 0
+	}
+	switch {
+	case b.ConsecutiveFailures >= m.cfg.FailThreshold:
+		status = HealthStatusDown
+		reason = fmt.Sprintf("%s (%d consecutive failures)", reason, b.ConsecutiveFailures)
+	case b.ConsecutiveFailures > 0:
+		status = HealthStatusDegraded
+	case b.Certificate != nil && time.Until(b.Certificate.NotAfter) < m.cfg.CertWarn:
+		status = HealthStatusDegraded
+		reason = fmt.Sprintf("TLS certificate expires in %d days", b.Certificate.DaysLeft)
+	}
+	if status != b.Status {
+		if b.Status != HealthStatusUnknown {
+			log.Printf("[HealthMonitor] %s: %s → %s %s", target.name, b.Status, status, reason)
+		}
+		b.Status = status
+		b.ChangedAt = sample.At
+	}
+	b.Reason = reason
+}
+
+// Backends 서비스별 점검 결과 (이름순)
+func (m *HealthMonitor) Backends() []BackendHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]BackendHealth, 0, len(m.backends))
+	for _, name := range sortedKeys(m.backends) {
+		b := *m.backends[name]
+		b.History = append([]HealthSample(nil), b.History...)
+		result = append(result, b)
+	}
+	return result
+}
+
+// Summary 전체 상태 요약. down이 있으면 down, degraded가 있으면 degraded, 점검 전이면 unknown.
+func (m *HealthMonitor) Summary() HealthSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	summary := HealthSummary{Status: HealthStatusUp, CheckedAt: m.lastRun, Counts: map[string]int{}}
+	required := make(map[string]bool, len(m.cfg.Required))
+	for _, name := range m.cfg.Required {
+		required[strings.ToLower(name)] = true
+	}
+	for _, name := range sortedKeys(m.backends) {
+		b := m.backends[name]
+		summary.Counts[b.Status]++
+		switch b.Status {
+		case HealthStatusDown:
+			summary.Down = append(summary.Down, name)
+			if required[strings.ToLower(name)] {
+				summary.RequiredDown = append(summary.RequiredDown, name)
+			}
+		case HealthStatusDegraded:
+			summary.Degraded = append(summary.Degraded, name)
+		}
+	}
+	switch {
+	case m.lastRun.IsZero():
+		summary.Status = HealthStatusUnknown
+	case len(summary.Down) > 0:
+		summary.Status = HealthStatusDown
+	case len(summary.Degraded) > 0:
+		summary.Status = HealthStatusDegraded
+	}
+	return summary
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+func TestHealthMonitorClampsFailThreshold(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	defer func(prev *HealthMonitor) { healthMonitor = prev }(healthMonitor)
+	monitor := InitHealthMonitor(&config.Config{
+		Health: config.HealthConfig{Interval: time.Minute, Timeout: time.Second, DefaultPath: "/readyz", FailThreshold: 0},
+		ApiSpec: &config.ApiSpec{Services: map[string]config.Service{
+			"healthy": {BaseURL: healthy.URL},
+			"failing": {BaseURL: failing.URL},
+		}},
+	})
+	monitor.RunOnce(context.Background())
+
+	statuses := map[string]string{}
+	for _, b := range monitor.Backends() {
+		statuses[b.Name] = b.Status
+	}
+	if statuses["healthy"] != HealthStatusUp {
+		t.Fatalf("healthy backend status = %q, want up", statuses["healthy"])
+	}
+	if statuses["failing"] != HealthStatusDown {
+		t.Fatalf("failing backend status = %q, want down after one failure", statuses["failing"])
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/pkg/jwt"
+)
+
+// 대리 토큰 발급 실패 사유
+var (
+	ErrImpersonationDisabled  = errors.New("impersonation is disabled")
+	ErrImpersonationForbidden = errors.New("role is not allowed to impersonate")
+	ErrImpersonationSelf      = errors.New("cannot impersonate yourself")
+	ErrImpersonationWrite     = errors.New("write impersonation is not allowed")
+	ErrImpersonationTarget    = errors.New("impersonation target not found")
+	ErrImpersonationAction    = errors.New("operation acts on the caller's own identity and is not available while impersonating")
+)
+
+// impersonationSelfActions 호출자 토큰의 사용자를 대상으로 동작하는 mc-iam-manager 액션 (소문자).
+// 대리 요청의 백엔드 호출에는 관리자 토큰이 쓰이므로, 값이 있으면 대상 사용자 ID({userId})를 지정하는 그 액션으로 바꾸고
+// 빈 값이면 관리자 본인의 데이터·메뉴·권한이 대상 사용자의 것으로 보이지 않도록 거부한다.
+var impersonationSelfActions = map[string]string{
+	"listuserworkspaces":         "Getuserworkspacesbyuserid",
+	"getuserinfo":                "getUserByID",
+	"getallavailablemenus":       "",
+	"getallpermissions":          "",
+	"getpermissionticket":        "",
+	"gettokeninfo":               "",
+	"authgetuservalidate":        "",
+	"gettempcredentialproviders": "",
+	"updateuseinfonotuse":        "",
+	"changemypassword":           "",
+	"loginrefresh":               "",
+	"logout":                     "",
+}
+
+// ImpersonationTarget 대리 대상 사용자
+type ImpersonationTarget struct {
+	UserID    string `json:"user_id"`
+	UserName  string `json:"user_name"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	IAMUserID string `json:"iam_user_id,omitempty"`
+}
+
+// ImpersonationGrant 발급된 대리 토큰
+type ImpersonationGrant struct {
+	AccessToken string              `json:"access_token"`
+	ExpiresIn   float64             `json:"expires_in"`
+	ReadOnly    bool                `json:"read_only"`
+	Actor       string              `json:"actor"`
+	Target      ImpersonationTarget `json:"target"`
+}
+
+// IsReadOnlyMethod 읽기 전용 대리에서 허용되는 HTTP 메서드인지 확인
+func IsReadOnlyMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// IssueImpersonationToken 관리자(actorID, actorRoles)가 target으로 동작하는 대리 토큰을 발급한다.
+// actorToken은 관리자 본인의 access token, actorSessionID는 그 토큰이 바인딩된 세션 ID이며 둘 다 대리 토큰에 기록된다.
+// 관리자 세션이 폐기·만료되면 대리 토큰도 거부되고, 관리자 access token이 갱신되어도 세션 ID로 계속 확인된다.
+func IssueImpersonationToken(cfg config.ImpersonationConfig, actorID string, actorRoles []string, actorToken, actorSessionID string,
+	target ImpersonationTarget, allowWrite bool) (*ImpersonationGrant, error) {
+	if !cfg.Enabled {
+		return nil, ErrImpersonationDisabled
+	}
+	if !hasAnyRoleFold(actorRoles, cfg.AdminRoles) {
+		return nil, ErrImpersonationForbidden
+	}
+	if strings.EqualFold(actorID, target.UserID) {
+		return nil, ErrImpersonationSelf
+	}
+	if allowWrite && !cfg.AllowWrite {
+		return nil, ErrImpersonationWrite
+	}
+
+	readOnly := !allowWrite
+	token, err := jwt.GenerateImpersonationToken(jwt.Claims{
+		UserID:    target.UserID,
+		UserName:  target.UserName,
+		Email:     target.Email,
+		Role:      target.Role,
+		IAMUserID: target.IAMUserID,
+	}, jwt.Actor{
+		Subject:     actorID,
+		SessionHash: jwt.TokenHash(actorToken),
+		SessionID:   actorSessionID,
+		ReadOnly:    readOnly,
+	}, cfg.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	RecordAudit(model.AuditEvent{
+		Action: model.AuditActionImpersonationStarted,
+		Actor:  actorID,
+		Target: target.UserID,
+		Detail: fmt.Sprintf("role=%s read_only=%t ttl=%s", target.Role, readOnly, cfg.TTL),
+	})
+	return &ImpersonationGrant{
+		AccessToken: token,
+		ExpiresIn:   cfg.TTL.Seconds(),
+		ReadOnly:    readOnly,
+		Actor:       actorID,
+		Target:      target,
+	}, nil
+}
+
+// LocalImpersonationTarget 로컬 사용자 저장소에서 활성 사용자를 대리 대상으로 조회
+func LocalImpersonationTarget(identifier string) (*ImpersonationTarget, error) {
+	db := repository.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	user, err := repository.NewUserRepository(db).FindByLoginIDOrEmail(identifier)
+	if err != nil || user.Status != model.LocalUserStatusActive {
+		return nil, ErrImpersonationTarget
+	}
+	return &ImpersonationTarget{UserID: user.LoginID, UserName: user.DisplayName(), Email: user.Email, Role: user.Role}, nil
+}
+
+// ActorSession 대리 토큰을 발급한 관리자 세션 조회. 세션 ID가 있으면 ID로, 없으면(이전 대리 토큰) access token 해시로 찾는다.
+// 대리 토큰 자체는 BFF 전용이므로 백엔드 호출에는 이 세션의 관리자 자격 증명을 쓰고, BFF가 대상 사용자 기준 인가/범위/읽기 전용을 적용한다.
+func ActorSession(actorID, sessionID, sessionHash string) (*model.UserSession, error) {
+	db := repository.GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	sessions := NewSessionService(repository.NewSessionRepository(db))
+	if sessionID != "" {
+		return sessions.ValidateSessionID(actorID, sessionID)
+	}
+	session, err := sessions.GetSessionByTokenHash(sessionHash)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.UserID != actorID {
+		return nil, ErrSessionMismatch
+	}
+	if session.IsRevoked() {
+		return nil, ErrSessionRevoked
+	}
+	return session, nil
+}
+
+// ImpersonationBackendAction 대리 요청에서 subsystem/operation 대신 호출할 액션.
+// 호출자 기준 mc-iam-manager 액션은 대상 사용자 ID를 지정하는 액션으로 바꾸고, 바꿀 액션이 없으면 ErrImpersonationAction.
+// 그 밖의 액션은 operation을 그대로 반환한다.
+func ImpersonationBackendAction(subsystem, operation string) (string, error) {
+	if !strings.EqualFold(subsystem, "mc-iam-manager") {
+		return operation, nil
+	}
+	replacement, ok := impersonationSelfActions[strings.ToLower(operation)]
+	switch {
+	case !ok:
+		return operation, nil
+	case replacement == "":
+		return "", ErrImpersonationAction
+	}
+	return replacement, nil
+}
+
+func hasAnyRoleFold(roles, allowed []string) bool {
+	for _, role := range roles {
+		for _, a := range allowed {
+			if strings.EqualFold(role, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
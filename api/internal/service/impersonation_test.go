@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/pkg/jwt"
+)
+
+var testImpersonationConfig = config.ImpersonationConfig{
+	Enabled:    true,
+	TTL:        15 * time.Minute,
+	AdminRoles: []string{"platformAdmin", "admin"},
+}
+
+func TestIssueImpersonationToken(t *testing.T) {
+	target := ImpersonationTarget{UserID: "user01", UserName: "User One", Role: "viewer", IAMUserID: "42"}
+	writeEnabled := testImpersonationConfig
+	writeEnabled.AllowWrite = true
+	disabled := testImpersonationConfig
+	disabled.Enabled = false
+
+	tests := []struct {
+		name       string
+		cfg        config.ImpersonationConfig
+		actorID    string
+		actorRoles []string
+		allowWrite bool
+		wantErr    error
+	}{
+		{name: "read-only", cfg: testImpersonationConfig, actorID: "admin01", actorRoles: []string{"viewer", "PlatformAdmin"}},
+		{name: "write enabled", cfg: writeEnabled, actorID: "admin01", actorRoles: []string{"admin"}, allowWrite: true},
+		{name: "disabled", cfg: disabled, actorID: "admin01", actorRoles: []string{"admin"}, wantErr: ErrImpersonationDisabled},
+		{name: "not an admin", cfg: testImpersonationConfig, actorID: "operator01", actorRoles: []string{"operator"}, wantErr: ErrImpersonationForbidden},
+		{name: "self", cfg: testImpersonationConfig, actorID: "USER01", actorRoles: []string{"admin"}, wantErr: ErrImpersonationSelf},
+		{name: "write not allowed", cfg: testImpersonationConfig, actorID: "admin01", actorRoles: []string{"admin"}, allowWrite: true, wantErr: ErrImpersonationWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureAuditLog(t)
+			grant, err := IssueImpersonationToken(tt.cfg, tt.actorID, tt.actorRoles, "admin-access", "session-1", target, tt.allowWrite)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueImpersonationToken error = %v, want %v", err, tt.wantErr)
+			}
+			started := strings.Count(logs.String(), "action="+model.AuditActionImpersonationStarted)
+			if err != nil {
+				if started != 0 {
+					t.Fatalf("refused impersonation was audited as started")
+				}
+				return
+			}
+			if started != 1 || !strings.Contains(logs.String(), "actor="+tt.actorID) || !strings.Contains(logs.String(), "target=user01") {
+				t.Fatalf("audit log = %q, want one impersonation.started by %s for user01", logs.String(), tt.actorID)
+			}
+
+			if grant.ReadOnly != !tt.allowWrite || grant.ExpiresIn != tt.cfg.TTL.Seconds() {
+				t.Fatalf("grant = %+v", grant)
+			}
+			claims, err := jwt.ParseToken(grant.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.UserID != "user01" || claims.Role != "viewer" || claims.IAMUserID != "42" {
+				t.Fatalf("claims = %+v, want the target user", claims)
+			}
+			act := claims.Act
+			if act == nil || act.Subject != tt.actorID || act.SessionID != "session-1" ||
+				act.SessionHash != jwt.TokenHash("admin-access") || act.ReadOnly != !tt.allowWrite {
+				t.Fatalf("act = %+v, want actor %s bound to session-1", act, tt.actorID)
+			}
+			if ttl := time.Until(claims.ExpiresAt.Time); ttl > tt.cfg.TTL || ttl < tt.cfg.TTL-time.Minute {
+				t.Fatalf("token expires in %v, want %v", ttl, tt.cfg.TTL)
+			}
+		})
+	}
+}
+
+func TestImpersonationBackendAction(t *testing.T) {
+	tests := []struct {
+		subsystem, operation string
+		want                 string
+		wantErr              error
+	}{
+		{"mc-iam-manager", "listUserWorkspaces", "Getuserworkspacesbyuserid", nil},
+		{"MC-IAM-MANAGER", "getuserinfo", "getUserByID", nil},
+		{"mc-iam-manager", "Getallavailablemenus", "", ErrImpersonationAction},
+		{"mc-iam-manager", "Getpermissionticket", "", ErrImpersonationAction},
+		{"mc-iam-manager", "ChangeMyPassword", "", ErrImpersonationAction},
+		{"mc-iam-manager", "Listusers", "Listusers", nil},
+		{"mc-infra-manager", "listUserWorkspaces", "listUserWorkspaces", nil},
+	}
+	for _, tt := range tests {
+		got, err := ImpersonationBackendAction(tt.subsystem, tt.operation)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("ImpersonationBackendAction(%s, %s) = %q, %v; want %q, %v", tt.subsystem, tt.operation, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
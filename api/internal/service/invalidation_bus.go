@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// 캐시 무효화 토픽. key가 비어 있으면 토픽의 캐시 전체를 무효화한다.
+const (
+	InvalidationTopicRegistry = "registry" // RegistryCache (key 미사용)
+	InvalidationTopicSession  = "session"  // SessionCache (key: userID)
+	InvalidationTopicScope    = "scope"    // ScopeCache (key: userID)
+	InvalidationTopicTicket   = "ticket"   // TicketCache (key: userID)
+	InvalidationTopicAsset    = "asset"    // AssetSyncer 로컬 파일 재적재 (key: 자산 이름)
+)
+
+// InvalidationMessage replica 간 전달되는 무효화 메시지
+type InvalidationMessage struct {
+	Topic  string    `json:"topic"`
+	Key    string    `json:"key,omitempty"`
+	Origin string    `json:"origin"` // 발행 노드 ID (자기 메시지는 이미 로컬에 반영되었으므로 무시)
+	SentAt time.Time `json:"sentAt"`
+}
+
+// InvalidationHandler 무효화 메시지 처리 함수. 다른 노드가 발행한 메시지만 전달된다.
+type InvalidationHandler func(key string)
+
+// InvalidationBus replica 간 캐시 무효화 버스.
+//
+// 캐시는 로컬 항목을 먼저 제거한 뒤 Publish하고, 다른 replica는 Subscribe한 핸들러로 같은 항목을 제거한다.
+// 연결이 끊겼다 복구된 경우처럼 메시지 유실 가능성이 있으면 모든 토픽에 key ""(전체 무효화)를 전달한다.
+type InvalidationBus interface {
+	// Kind 버스 종류 (postgres | memory)
+	Kind() string
+	// Publish 무효화 메시지 발행
+	Publish(topic, key string) error
+	// Subscribe 토픽 핸들러 등록 (Start 전에 등록)
+	Subscribe(topic string, handler InvalidationHandler)
+	Start(ctx context.Context)
+	Stop()
+	Stats() InvalidationBusStats
+}
+
+// InvalidationBusStats 무효화 버스 상태 (GET /api/admin/status → invalidation)
+type InvalidationBusStats struct {
+	Kind        string    `json:"kind"`
+	NodeID      string    `json:"nodeId"`
+	Connected   bool      `json:"connected"`
+	Published   int64     `json:"published"`
+	Received    int64     `json:"received"`
+	Resyncs     int64     `json:"resyncs"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt,omitempty"`
+}
+
+var (
+	invalidationBusMu sync.RWMutex
+	invalidationBus   InvalidationBus
+)
+
+// InitInvalidationBus 설정에 맞는 무효화 버스를 생성해 전역으로 등록하고 캐시 핸들러를 연결한다.
+// auto는 DB가 있으면 postgres, 없으면 memory를 사용한다.
+func InitInvalidationBus(cfg config.InvalidationConfig, dsn string, db *gorm.DB) InvalidationBus {
+	kind := cfg.Bus
+	if kind == "auto" || kind == "" {
+		kind = "memory"
+		if db != nil {
+			kind = "postgres"
+		}
+	}
+
+	var bus InvalidationBus
+	switch kind {
+	case "postgres":
+		if db == nil {
+			log.Println("⚠️  MC_WEB_CONSOLE_INVALIDATION_BUS=postgres but database is not configured, using in-process bus")
+			bus = NewMemoryInvalidationBus()
+		} else {
+			bus = NewPostgresInvalidationBus(dsn, cfg.Channel, db, cfg.ReconnectDelay)
+		}
+	case "memory":
+		bus = NewMemoryInvalidationBus()
+	default:
+		log.Printf("⚠️  unknown MC_WEB_CONSOLE_INVALIDATION_BUS %q, using in-process bus", cfg.Bus)
+		bus = NewMemoryInvalidationBus()
+	}
+
+	subscribeCacheInvalidations(bus)
+
+	invalidationBusMu.Lock()
+	invalidationBus = bus
+	invalidationBusMu.Unlock()
+	RegisterStatusProvider("invalidation", func() interface{} { return bus.Stats() })
+	return bus
+}
+
+// GetInvalidationBus 전역 무효화 버스 반환 (초기화 전이면 nil)
+func GetInvalidationBus() InvalidationBus {
+	invalidationBusMu.RLock()
+	defer invalidationBusMu.RUnlock()
+	return invalidationBus
+}
+
+// PublishInvalidation 전역 버스로 무효화 메시지 발행. 버스가 없으면 무시하고, 실패는 로그만 남긴다 (TTL로 수렴).
+func PublishInvalidation(topic, key string) {
+	bus := GetInvalidationBus()
+	if bus == nil {
+		return
+	}
+	if err := bus.Publish(topic, key); err != nil {
+		log.Printf("[InvalidationBus] publish %s failed: %v", topic, err)
+	}
+}
+
+// subscribeCacheInvalidations 사용자 단위 in-memory 캐시를 버스에 연결
+func subscribeCacheInvalidations(bus InvalidationBus) {
+	bus.Subscribe(InvalidationTopicSession, func(key string) {
+		if key == "" {
+			sessionCache.clearLocal()
+			return
+		}
+		sessionCache.invalidateUserLocal(key)
+	})
+	bus.Subscribe(InvalidationTopicScope, func(key string) {
+		if key == "" {
+			scopeCache.clearLocal()
+			return
+		}
+		scopeCache.invalidateUserLocal(key)
+	})
+	bus.Subscribe(InvalidationTopicTicket, func(key string) {
+		if key == "" {
+			ticketCache.clearLocal()
+			return
+		}
+		ticketCache.invalidateUserLocal(key)
+	})
+}
+
+// invalidationDispatcher 버스 구현 공통부: 노드 ID, 토픽 핸들러, 통계
+type invalidationDispatcher struct {
+	nodeID string
+
+	mu       sync.RWMutex
+	handlers map[string][]InvalidationHandler
+	stats    InvalidationBusStats
+}
+
+func newInvalidationDispatcher(kind string) invalidationDispatcher {
+	nodeID := uuid.NewString()
+	return invalidationDispatcher{
+		nodeID:   nodeID,
+		handlers: make(map[string][]InvalidationHandler),
+		stats:    InvalidationBusStats{Kind: kind, NodeID: nodeID},
+	}
+}
+
+func (d *invalidationDispatcher) Subscribe(topic string, handler InvalidationHandler) {
+	d.mu.Lock()
+	d.handlers[topic] = append(d.handlers[topic], handler)
+	d.mu.Unlock()
+}
+
+func (d *invalidationDispatcher) Stats() InvalidationBusStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.stats
+}
+
+func (d *invalidationDispatcher) newMessage(topic, key string) InvalidationMessage {
+	return InvalidationMessage{Topic: topic, Key: key, Origin: d.nodeID, SentAt: time.Now()}
+}
+
+// dispatch 다른 노드가 발행한 메시지를 토픽 핸들러에 전달
+func (d *invalidationDispatcher) dispatch(msg InvalidationMessage) {
+	if msg.Origin == d.nodeID {
+		return
+	}
+	d.mu.Lock()
+	d.stats.Received++
+	handlers := d.handlers[msg.Topic]
+	d.mu.Unlock()
+	for _, handler := range handlers {
+		handler(msg.Key)
+	}
+}
+
+// resync 유실 가능성이 있을 때 모든 토픽을 전체 무효화
+func (d *invalidationDispatcher) resync() {
+	d.mu.Lock()
+	d.stats.Resyncs++
+	var all []InvalidationHandler
+	for _, handlers := range d.handlers {
+		all = append(all, handlers...)
+	}
+	d.mu.Unlock()
+	for _, handler := range all {
+		handler("")
+	}
+}
+
+func (d *invalidationDispatcher) recordPublish(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.recordErrorLocked(err)
+		return
+	}
+	d.stats.Published++
+}
+
+func (d *invalidationDispatcher) recordError(err error) {
+	d.mu.Lock()
+	d.recordErrorLocked(err)
+	d.mu.Unlock()
+}
+
+func (d *invalidationDispatcher) recordErrorLocked(err error) {
+	d.stats.LastError = err.Error()
+	d.stats.LastErrorAt = time.Now()
+}
+
+func (d *invalidationDispatcher) setConnected(connected bool) {
+	d.mu.Lock()
+	d.stats.Connected = connected
+	d.mu.Unlock()
+}
+
+// MemoryInvalidationHub 프로세스 내부 무효화 버스 묶음. Join한 버스끼리 메시지를 동기 전달한다.
+// 단일 인스턴스 배포와 여러 replica를 한 프로세스에서 흉내 내는 테스트에 사용한다.
+type MemoryInvalidationHub struct {
+	mu    sync.RWMutex
+	nodes []*memoryInvalidationBus
+}
+
+// NewMemoryInvalidationHub MemoryInvalidationHub 생성
+func NewMemoryInvalidationHub() *MemoryInvalidationHub {
+	return &MemoryInvalidationHub{}
+}
+
+// Join 허브에 연결된 새 버스(노드) 생성
+func (h *MemoryInvalidationHub) Join() InvalidationBus {
+	bus := &memoryInvalidationBus{invalidationDispatcher: newInvalidationDispatcher("memory"), hub: h}
+	bus.stats.Connected = true
+	h.mu.Lock()
+	h.nodes = append(h.nodes, bus)
+	h.mu.Unlock()
+	return bus
+}
+
+// NewMemoryInvalidationBus 단독 프로세스 내부 버스 생성 (다른 replica로 전파되지 않음)
+func NewMemoryInvalidationBus() InvalidationBus {
+	return NewMemoryInvalidationHub().Join()
+}
+
+type memoryInvalidationBus struct {
+	invalidationDispatcher
+	hub *MemoryInvalidationHub
+}
+
+func (b *memoryInvalidationBus) Kind() string { return "memory" }
+
+func (b *memoryInvalidationBus) Publish(topic, key string) error {
+	msg := b.newMessage(topic, key)
+	b.hub.mu.RLock()
+	nodes := append([]*memoryInvalidationBus(nil), b.hub.nodes...)
+	b.hub.mu.RUnlock()
+	for _, node := range nodes {
+		node.dispatch(msg)
+	}
+	b.recordPublish(nil)
+	return nil
+}
+
+func (b *memoryInvalidationBus) Start(ctx context.Context) {}
+
+func (b *memoryInvalidationBus) Stop() {}
+
+// postgresInvalidationBus Postgres LISTEN/NOTIFY 버스.
+// 발행은 gorm 연결 풀의 pg_notify, 수신은 전용 pgx 연결의 LISTEN으로 처리한다.
+type postgresInvalidationBus struct {
+	invalidationDispatcher
+	dsn            string
+	channel        string
+	db             *gorm.DB
+	reconnectDelay time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPostgresInvalidationBus Postgres LISTEN/NOTIFY 버스 생성
+func NewPostgresInvalidationBus(dsn, channel string, db *gorm.DB, reconnectDelay time.Duration) InvalidationBus {
+	if reconnectDelay <= 0 {
+		reconnectDelay = 5 * time.Second
+	}
+	return &postgresInvalidationBus{
+		invalidationDispatcher: newInvalidationDispatcher("postgres"),
+		dsn:                    dsn,
+		channel:                channel,
+		db:                     db,
+		reconnectDelay:         reconnectDelay,
+	}
+}
+
+func (b *postgresInvalidationBus) Kind() string { return "postgres" }
+
+func (b *postgresInvalidationBus) Publish(topic, key string) error {
+	payload, err := json.Marshal(b.newMessage(topic, key))
+	if err == nil {
+		err = b.db.Exec("SELECT pg_notify(?, ?)", b.channel, string(payload)).Error
+	}
+	b.recordPublish(err)
+	return err
+}
+
+// Start LISTEN goroutine 시작. 연결이 끊기면 reconnectDelay 후 재연결하고, 재연결 시 전체 무효화(resync)한다.
+func (b *postgresInvalidationBus) Start(ctx context.Context) {
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		connectedBefore := false
+		for {
+			err := b.listen(ctx, &connectedBefore)
+			b.setConnected(false)
+			if ctx.Err() != nil {
+				log.Printf("[InvalidationBus] stopped")
+				return
+			}
+			b.recordError(err)
+			log.Printf("[InvalidationBus] listen failed, reconnecting in %s: %v", b.reconnectDelay, err)
+			select {
+			case <-ctx.Done():
+				log.Printf("[InvalidationBus] stopped")
+				return
+			case <-time.After(b.reconnectDelay):
+			}
+		}
+	}()
+	log.Printf("[InvalidationBus] started (postgres channel=%s, node=%s)", b.channel, b.nodeID)
+}
+
+// Stop LISTEN goroutine 종료 (graceful shutdown)
+func (b *postgresInvalidationBus) Stop() {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.wg.Wait()
+}
+
+func (b *postgresInvalidationBus) listen(ctx context.Context, connectedBefore *bool) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	b.setConnected(true)
+	if *connectedBefore {
+		// 연결이 끊긴 동안의 메시지는 유실되었을 수 있으므로 전체 무효화
+		log.Printf("[InvalidationBus] reconnected, invalidating all caches")
+		b.resync()
+	}
+	*connectedBefore = true
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var msg InvalidationMessage
+		if err := json.Unmarshal([]byte(notification.Payload), &msg); err != nil {
+			log.Printf("[InvalidationBus] invalid payload: %v", err)
+			continue
+		}
+		b.dispatch(msg)
+	}
+}
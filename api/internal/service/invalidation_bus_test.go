@@ -0,0 +1,160 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// echoRecorder 발행 노드에 자기 메시지가 되돌아오는지 기록하는 핸들러
+type echoRecorder struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *echoRecorder) handle(key string) {
+	r.mu.Lock()
+	r.keys = append(r.keys, key)
+	r.mu.Unlock()
+}
+
+func (r *echoRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.keys)
+}
+
+// newTestInvalidationNodes 허브에 연결된 두 노드. publisher는 기록용 핸들러만, receiver는 전역 캐시 핸들러를 구독한다.
+func newTestInvalidationNodes(t *testing.T) (publisher, receiver InvalidationBus, echoes *echoRecorder) {
+	t.Helper()
+	hub := NewMemoryInvalidationHub()
+	publisher, receiver = hub.Join(), hub.Join()
+	echoes = &echoRecorder{}
+	for _, topic := range []string{InvalidationTopicSession, InvalidationTopicScope, InvalidationTopicTicket, InvalidationTopicRegistry} {
+		publisher.Subscribe(topic, echoes.handle)
+	}
+	subscribeCacheInvalidations(receiver)
+
+	sessionCache.clearLocal()
+	scopeCache.clearLocal()
+	ticketCache.clearLocal()
+	t.Cleanup(func() {
+		sessionCache.clearLocal()
+		scopeCache.clearLocal()
+		ticketCache.clearLocal()
+	})
+	return publisher, receiver, echoes
+}
+
+func TestMemoryInvalidationHubAppliesOnOtherNode(t *testing.T) {
+	publisher, receiver, echoes := newTestInvalidationNodes(t)
+	expires := time.Now().Add(time.Hour)
+
+	sessionCache.Put("hash-user01", "user01", "session-1", expires)
+	sessionCache.Put("hash-user02", "user02", "session-2", expires)
+	scopeCache.Put("user01", &UserScope{FetchedAt: time.Now()})
+	scopeCache.Put("user02", &UserScope{FetchedAt: time.Now()})
+	ticketCache.Put("user01", "hash-a", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt-1"})
+	ticketCache.Put("user01", "hash-b", "mc-infra-manager", "DelAllNs", MCIAMTicket{Token: "rpt-2"})
+	ticketCache.Put("user010", "hash-a", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt-3"})
+
+	for _, topic := range []string{InvalidationTopicSession, InvalidationTopicScope, InvalidationTopicTicket} {
+		if err := publisher.Publish(topic, "user01"); err != nil {
+			t.Fatalf("Publish(%s): %v", topic, err)
+		}
+	}
+
+	if _, ok := sessionCache.Get("hash-user01"); ok {
+		t.Error("session of user01 survived the invalidation")
+	}
+	if _, ok := sessionCache.Get("hash-user02"); !ok {
+		t.Error("session of user02 was removed")
+	}
+	if _, ok := scopeCache.Get("user01"); ok {
+		t.Error("scope of user01 survived the invalidation")
+	}
+	if _, ok := scopeCache.Get("user02"); !ok {
+		t.Error("scope of user02 was removed")
+	}
+	if _, ok := ticketCache.Get("user01", "hash-a", "mc-infra-manager", "GetAllNs"); ok {
+		t.Error("ticket GetAllNs of user01 survived the invalidation")
+	}
+	if _, ok := ticketCache.Get("user01", "hash-b", "mc-infra-manager", "DelAllNs"); ok {
+		t.Error("ticket DelAllNs of user01 survived the invalidation")
+	}
+	// 키 접두사가 user ID 구분자까지 포함하므로 user010의 티켓은 남아야 한다
+	if _, ok := ticketCache.Get("user010", "hash-a", "mc-infra-manager", "GetAllNs"); !ok {
+		t.Error("ticket of user010 was removed by the user01 prefix")
+	}
+
+	if got := echoes.count(); got != 0 {
+		t.Errorf("publisher received %d of its own messages", got)
+	}
+	if got := publisher.Stats(); got.Published != 3 || got.Received != 0 {
+		t.Errorf("publisher stats = %+v, want 3 published and 0 received", got)
+	}
+	if got := receiver.Stats().Received; got != 3 {
+		t.Errorf("receiver received %d messages, want 3", got)
+	}
+}
+
+func TestMemoryInvalidationHubClearsAll(t *testing.T) {
+	publisher, _, echoes := newTestInvalidationNodes(t)
+
+	scopeCache.Put("user01", &UserScope{FetchedAt: time.Now()})
+	scopeCache.Put("user02", &UserScope{FetchedAt: time.Now()})
+	ticketCache.Put("user01", "hash-a", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt-1"})
+
+	if err := publisher.Publish(InvalidationTopicScope, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := scopeCache.Len(); got != 0 {
+		t.Errorf("scope cache has %d entries after clear", got)
+	}
+	if got := ticketCache.Len(); got != 1 {
+		t.Errorf("ticket cache has %d entries, want 1 (other topic untouched)", got)
+	}
+	if got := echoes.count(); got != 0 {
+		t.Errorf("publisher received %d of its own messages", got)
+	}
+}
+
+func TestMemoryInvalidationHubRegistry(t *testing.T) {
+	hub := NewMemoryInvalidationHub()
+	busA, busB := hub.Join(), hub.Join()
+	payload := map[string]interface{}{
+		"Services": map[string]interface{}{
+			"mc-infra-manager": map[string]interface{}{"Version": "0.11.0", "BaseURL": "http://mc-infra-manager:1323"},
+		},
+	}
+
+	cacheA, cacheB := NewRegistryCache(time.Hour), NewRegistryCache(time.Hour)
+	cacheA.SetInvalidationBus(busA)
+	cacheB.SetInvalidationBus(busB)
+	cacheA.Store(payload)
+	cacheB.Store(payload)
+	if cacheB.GetAllServices() == nil {
+		t.Fatal("registry cache B was not populated")
+	}
+
+	cacheA.Invalidate()
+	if cacheA.GetAllServices() != nil {
+		t.Error("registry cache A was not invalidated locally")
+	}
+	if cacheB.GetAllServices() != nil {
+		t.Error("registry cache B was not invalidated by node A")
+	}
+	if got := busA.Stats().Received; got != 0 {
+		t.Errorf("node A received %d of its own messages", got)
+	}
+	if got := busB.Stats().Received; got != 1 {
+		t.Errorf("node B received %d messages, want 1", got)
+	}
+
+	// B가 다시 적재한 뒤 A의 재적재는 B를 건드리지 않는다
+	cacheB.Store(payload)
+	cacheA.Store(payload)
+	if cacheB.GetAllServices() == nil {
+		t.Error("registry cache B was invalidated by a store on node A")
+	}
+}
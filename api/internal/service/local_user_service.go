@@ -0,0 +1,351 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/pkg/jwt"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 로컬 사용자 처리 실패 사유
+var (
+	ErrInvalidCredentials = errors.New("invalid id or password")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrUserExists         = errors.New("user already exists")
+	ErrInvalidUserToken   = errors.New("invalid or expired token")
+	ErrWeakPassword       = errors.New("password does not meet policy")
+)
+
+// minLocalPasswordLength 비밀번호 정책 미설정 시 최소 길이
+const minLocalPasswordLength = 8
+
+// LocalUserService MCIAM_USE=false 모드의 로컬 사용자 저장소 서비스 (bcrypt 해시)
+type LocalUserService struct {
+	repo *repository.UserRepository
+	cfg  config.LocalAuthConfig
+}
+
+// NewLocalUserService 새로운 로컬 사용자 서비스 생성
+func NewLocalUserService(repo *repository.UserRepository, cfg config.LocalAuthConfig) *LocalUserService {
+	return &LocalUserService{repo: repo, cfg: cfg}
+}
+
+// LocalSignupInput 로컬 회원가입 입력
+type LocalSignupInput struct {
+	Email        string
+	Password     string
+	FirstName    string
+	LastName     string
+	Organization string
+}
+
+// Authenticate 로그인 ID(또는 이메일)와 비밀번호 검증
+func (s *LocalUserService) Authenticate(identifier, password string) (*model.LocalUser, error) {
+	user, err := s.repo.FindByLoginIDOrEmail(identifier)
+	if err != nil {
+		// 사용자 존재 여부가 응답 시간으로 드러나지 않도록 더미 비교 수행
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if user.Status != model.LocalUserStatusActive {
+		return nil, ErrUserDisabled
+	}
+	if s.cfg.RequireEmailVerification && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	return user, nil
+}
+
+// Signup 로컬 사용자 생성 후 이메일 인증 토큰 발급. 반환된 토큰 원문은 메일 발송에만 사용한다.
+func (s *LocalUserService) Signup(in LocalSignupInput) (*model.LocalUser, string, error) {
+	email := strings.TrimSpace(in.Email)
+	if err := validateLocalPassword(in.Password); err != nil {
+		return nil, "", err
+	}
+
+	exists, err := s.repo.ExistsByLoginIDOrEmail(email, email)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check user: %w", err)
+	}
+	if exists {
+		return nil, "", ErrUserExists
+	}
+
+	hash, err := HashPassword(in.Password)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user := &model.LocalUser{
+		LoginID:       email,
+		Email:         email,
+		PasswordHash:  hash,
+		FirstName:     in.FirstName,
+		LastName:      in.LastName,
+		Organization:  in.Organization,
+		Role:          s.cfg.DefaultRole,
+		Status:        model.LocalUserStatusActive,
+		EmailVerified: !s.cfg.RequireEmailVerification,
+	}
+	if err := s.repo.Create(user); err != nil {
+		return nil, "", fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if user.EmailVerified {
+		return user, "", nil
+	}
+	token, err := s.issueToken(user.ID, model.LocalUserTokenEmailVerify, s.cfg.VerifyTokenTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// CreateApproved 관리자가 승인한 회원가입 요청으로 사용자 생성. 비밀번호는 요청 시 해시된 값을 그대로 사용하며,
+// 승인이 곧 본인 확인을 대신하지 않으므로 이메일 인증 설정은 일반 가입과 같다.
+func (s *LocalUserService) CreateApproved(pending *model.PendingSignup) (*model.LocalUser, string, error) {
+	exists, err := s.repo.ExistsByLoginIDOrEmail(pending.Email, pending.Email)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check user: %w", err)
+	}
+	if exists {
+		return nil, "", ErrUserExists
+	}
+	user := &model.LocalUser{
+		LoginID:       pending.Email,
+		Email:         pending.Email,
+		PasswordHash:  pending.PasswordHash,
+		FirstName:     pending.FirstName,
+		LastName:      pending.LastName,
+		Organization:  pending.Organization,
+		Role:          s.cfg.DefaultRole,
+		Status:        model.LocalUserStatusActive,
+		EmailVerified: !s.cfg.RequireEmailVerification,
+	}
+	if err := s.repo.Create(user); err != nil {
+		return nil, "", fmt.Errorf("failed to create user: %w", err)
+	}
+	if user.EmailVerified {
+		return user, "", nil
+	}
+	token, err := s.issueToken(user.ID, model.LocalUserTokenEmailVerify, s.cfg.VerifyTokenTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// ActiveUser 토큰 갱신 시 사용자 상태 확인. 없으면 ErrInvalidCredentials, 비활성이면 ErrUserDisabled
+func (s *LocalUserService) ActiveUser(loginID string) (*model.LocalUser, error) {
+	user, err := s.repo.FindByLoginID(loginID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user.Status != model.LocalUserStatusActive {
+		return nil, ErrUserDisabled
+	}
+	return user, nil
+}
+
+// ExistsUser 로그인 ID 또는 이메일로 사용자 존재 여부 확인
+func (s *LocalUserService) ExistsUser(email string) (bool, error) {
+	return s.repo.ExistsByLoginIDOrEmail(email, email)
+}
+
+// VerifyEmail 이메일 인증 토큰 확인 후 인증 완료 처리
+func (s *LocalUserService) VerifyEmail(token string) (*model.LocalUser, error) {
+	record, err := s.consumeToken(model.LocalUserTokenEmailVerify, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkEmailVerified(record.UserID); err != nil {
+		return nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+	return s.repo.FindByID(record.UserID)
+}
+
+// ChangePassword 현재 비밀번호 확인 후 새 비밀번호로 교체. 호출자는 반환된 사용자의 토큰/세션을 폐기해야 한다.
+func (s *LocalUserService) ChangePassword(loginID, currentPassword, newPassword string) (*model.LocalUser, error) {
+	user, err := s.repo.FindByLoginIDOrEmail(loginID)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.setPassword(user.ID, newPassword); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// RequestPasswordReset 비밀번호 재설정 토큰 발급.
+// 존재하지 않거나 비활성 계정이면 (nil, "", nil)을 반환하여 계정 존재 여부를 노출하지 않는다.
+func (s *LocalUserService) RequestPasswordReset(email string) (*model.LocalUser, string, error) {
+	user, err := s.repo.FindByLoginIDOrEmail(strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to find user: %w", err)
+	}
+	if user.Status != model.LocalUserStatusActive {
+		return nil, "", nil
+	}
+	token, err := s.issueToken(user.ID, model.LocalUserTokenPasswordReset, s.cfg.ResetTokenTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// ResetPassword 재설정 토큰으로 비밀번호 교체. 호출자는 반환된 사용자의 토큰/세션을 폐기해야 한다.
+func (s *LocalUserService) ResetPassword(token, newPassword string) (*model.LocalUser, error) {
+	if err := validateLocalPassword(newPassword); err != nil {
+		return nil, err
+	}
+	record, err := s.consumeToken(model.LocalUserTokenPasswordReset, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setPassword(record.UserID, newPassword); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(record.UserID)
+}
+
+// EnsureBootstrapUser selfiamauthsetting.yaml의 관리자 계정이 없으면 생성한다 (이메일 인증 완료 상태).
+// 이미 존재하면 비밀번호를 덮어쓰지 않는다.
+func (s *LocalUserService) EnsureBootstrapUser(setting *config.SelfIAMSetting) error {
+	if setting == nil || setting.User.ID == "" || setting.User.Password == "" {
+		return nil
+	}
+	u := setting.User
+	email := u.Email
+	if email == "" {
+		email = u.ID
+	}
+
+	exists, err := s.repo.ExistsByLoginIDOrEmail(u.ID, email)
+	if err != nil {
+		return fmt.Errorf("failed to check bootstrap user: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	hash, err := HashPassword(u.Password)
+	if err != nil {
+		return err
+	}
+	role := u.Role
+	if role == "" {
+		role = "platformAdmin"
+	}
+	user := &model.LocalUser{
+		LoginID:       u.ID,
+		Email:         email,
+		PasswordHash:  hash,
+		FirstName:     u.FirstName,
+		LastName:      u.LastName,
+		Role:          role,
+		Status:        model.LocalUserStatusActive,
+		EmailVerified: true,
+	}
+	if err := s.repo.Create(user); err != nil {
+		return fmt.Errorf("failed to create bootstrap user: %w", err)
+	}
+	log.Printf("✅ Bootstrap user created: %s (role=%s)", u.ID, role)
+	return nil
+}
+
+func (s *LocalUserService) setPassword(userID, newPassword string) error {
+	if err := validateLocalPassword(newPassword); err != nil {
+		return err
+	}
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(userID, hash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+// issueToken 1회용 토큰 발급 (같은 용도의 이전 미사용 토큰은 삭제)
+func (s *LocalUserService) issueToken(userID, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := s.repo.DeleteUnusedTokens(userID, purpose); err != nil {
+		log.Printf("[LocalUser] delete previous %s tokens error (userID=%s): %v", purpose, userID, err)
+	}
+	record := &model.LocalUserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: jwt.TokenHash(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.repo.CreateToken(record); err != nil {
+		return "", fmt.Errorf("failed to store token: %w", err)
+	}
+	return token, nil
+}
+
+// consumeToken 토큰 확인 후 사용 처리. 사용 처리는 조건부 UPDATE라 같은 토큰을 동시에 제출해도 한 번만 성공한다.
+func (s *LocalUserService) consumeToken(purpose, token string) (*model.LocalUserToken, error) {
+	if token == "" {
+		return nil, ErrInvalidUserToken
+	}
+	record, err := s.repo.FindToken(purpose, jwt.TokenHash(token))
+	if err != nil || !record.IsUsable() {
+		return nil, ErrInvalidUserToken
+	}
+	marked, err := s.repo.MarkTokenUsed(record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume token: %w", err)
+	}
+	if !marked {
+		return nil, ErrInvalidUserToken
+	}
+	return record, nil
+}
+
+// HashPassword bcrypt 비밀번호 해시 생성
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// validateLocalPassword 로컬 계정 비밀번호 정책 확인 (PasswordPolicy)
+func validateLocalPassword(password string) error {
+	return GetPasswordPolicy().Validate(password)
+}
+
+// dummyPasswordHash 존재하지 않는 사용자 로그인 시 타이밍 균등화용 해시
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("mc-web-console-dummy-password"), bcrypt.DefaultCost)
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/repository/repotest"
+	"mc_web_console_api/pkg/jwt"
+)
+
+func TestConsumeTokenOnce(t *testing.T) {
+	db, fake := repotest.Open(t)
+	hash := jwt.TokenHash("reset-token")
+	fake.Handle(`FROM "local_user_tokens"`, func(args []driver.Value) repotest.Result {
+		columns := []string{"id", "user_id", "purpose", "token_hash", "expires_at", "used_at", "created_at"}
+		if args[0] != model.LocalUserTokenPasswordReset || args[1] != hash {
+			return repotest.Result{Columns: columns}
+		}
+		// 두 요청 모두 조회 시점에는 미사용 토큰을 본다
+		return repotest.Result{Columns: columns, Rows: [][]driver.Value{
+			{"token-1", "user01", model.LocalUserTokenPasswordReset, hash, time.Now().Add(time.Hour), nil, time.Now()},
+		}}
+	})
+	// used_at IS NULL 조건부 UPDATE: 첫 요청만 행을 바꾼다
+	var mu sync.Mutex
+	used := false
+	fake.Handle(`UPDATE "local_user_tokens"`, func([]driver.Value) repotest.Result {
+		mu.Lock()
+		defer mu.Unlock()
+		if used {
+			return repotest.Result{}
+		}
+		used = true
+		return repotest.Result{RowsAffected: 1}
+	})
+	svc := NewLocalUserService(repository.NewUserRepository(db), config.LocalAuthConfig{})
+
+	const requests = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.consumeToken(model.LocalUserTokenPasswordReset, "reset-token")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrInvalidUserToken):
+			t.Fatalf("consumeToken error = %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("token redeemed %d times, want 1", succeeded)
+	}
+	for _, stmt := range fake.Statements(`UPDATE "local_user_tokens"`) {
+		if !strings.Contains(stmt.SQL, "used_at IS NULL") {
+			t.Fatalf("UPDATE = %s, want it conditional on used_at IS NULL", stmt.SQL)
+		}
+	}
+
+	if _, err := svc.consumeToken(model.LocalUserTokenPasswordReset, "other-token"); !errors.Is(err, ErrInvalidUserToken) {
+		t.Fatalf("unknown token: err = %v, want ErrInvalidUserToken", err)
+	}
+}
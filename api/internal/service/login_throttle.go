@@ -0,0 +1,357 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// loginThrottleMaxEntries in-memory 카운터 수 상한. 초과 시 만료된 항목을 정리한다.
+const loginThrottleMaxEntries = 10000
+
+// LoginBlock 로그인 시도 거부 사유
+type LoginBlock struct {
+	Key        string        // 거부 원인 카운터 키 ("account:..." 또는 "ip:...")
+	Locked     bool          // true: 잠금, false: 점진적 지연
+	RetryAfter time.Duration // 재시도 가능까지 남은 시간
+}
+
+// LoginThrottle 계정/IP 단위 로그인 실패 카운터.
+//
+// 실패가 FreeAttempts를 넘으면 BaseDelay부터 2배씩 늘어나는 지연 동안 시도를 거부하고,
+// 계정은 MaxAccountFailures, IP는 MaxIPFailures에 도달하면 LockoutDuration 동안 잠근다.
+// DB가 있으면 login_attempts를 기준으로 replica 간 카운터를 공유하고, 없으면 프로세스 메모리만 사용한다.
+// DB 카운터는 원자적 UPSERT로 증가시키므로 동시 실패가 누락되지 않으며, DB 호출 중에는 잠금을 잡지 않는다.
+type LoginThrottle struct {
+	cfg  config.LoginThrottleConfig
+	repo *repository.LoginAttemptRepository // nil이면 in-memory 전용
+
+	mu      sync.Mutex // entries 보호 (DB 호출 중에는 잡지 않음)
+	entries map[string]*model.LoginAttempt
+}
+
+// LoginThrottleStats 로그인 실패 제한 상태 (GET /api/admin/status → loginThrottle)
+type LoginThrottleStats struct {
+	Persistent         bool   `json:"persistent"`
+	Entries            int    `json:"entries"`
+	LockedEntries      int    `json:"lockedEntries"`
+	MaxAccountFailures int    `json:"maxAccountFailures"`
+	MaxIPFailures      int    `json:"maxIpFailures"`
+	LockoutDuration    string `json:"lockoutDuration"`
+}
+
+var loginThrottle *LoginThrottle
+
+// InitLoginThrottle 전역 로그인 실패 제한기 초기화. db가 nil이면 in-memory로 동작한다.
+func InitLoginThrottle(cfg config.LoginThrottleConfig, db *gorm.DB) *LoginThrottle {
+	if !cfg.Enabled {
+		loginThrottle = nil
+		return nil
+	}
+	t := &LoginThrottle{cfg: cfg, entries: make(map[string]*model.LoginAttempt)}
+	if db != nil {
+		t.repo = repository.NewLoginAttemptRepository(db)
+	}
+	loginThrottle = t
+	RegisterStatusProvider("loginThrottle", func() interface{} { return t.Stats() })
+	return t
+}
+
+// GetLoginThrottle 전역 로그인 실패 제한기 반환 (비활성이면 nil)
+func GetLoginThrottle() *LoginThrottle {
+	return loginThrottle
+}
+
+// AccountKey 계정 카운터 키
+func AccountKey(account string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+// IPKey IP 카운터 키
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check 로그인 시도 허용 여부 확인. 허용이면 nil 반환.
+func (t *LoginThrottle) Check(account, ip string) *LoginBlock {
+	now := time.Now()
+	for _, key := range t.keys(account, ip) {
+		a := t.load(key, now)
+		if a == nil {
+			continue
+		}
+		if a.IsLocked(now) {
+			return &LoginBlock{Key: key, Locked: true, RetryAfter: a.LockedUntil.Sub(now)}
+		}
+		if now.Before(a.NextAllowedAt) {
+			return &LoginBlock{Key: key, RetryAfter: a.NextAllowedAt.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// RecordFailure 로그인 실패 기록. 임계치 도달 시 잠금 후 감사 로그를 남긴다 (잠금을 건 replica만 기록).
+func (t *LoginThrottle) RecordFailure(account, ip string) {
+	now := time.Now()
+	for _, key := range t.keys(account, ip) {
+		a := t.increment(key, now)
+		if over := a.Failures - t.cfg.FreeAttempts; over > 0 {
+			t.backoff(key, now.Add(t.delay(over)))
+		}
+
+		limit := t.cfg.MaxAccountFailures
+		if strings.HasPrefix(key, "ip:") {
+			limit = t.cfg.MaxIPFailures
+		}
+		if limit > 0 && a.Failures >= limit && !a.IsLocked(now) {
+			until := now.Add(t.cfg.LockoutDuration)
+			if t.lock(key, until, now) {
+				RecordAudit(model.AuditEvent{
+					Action: model.AuditActionLoginLocked,
+					Actor:  AuditActorSystem,
+					Target: key,
+					IP:     ip,
+					Detail: fmt.Sprintf("%d consecutive failed logins, locked until %s", a.Failures, until.Format(time.RFC3339)),
+				})
+			}
+		}
+	}
+}
+
+// RecordSuccess 로그인 성공 시 계정 카운터 초기화 (IP 카운터는 유지)
+func (t *LoginThrottle) RecordSuccess(account string) {
+	t.delete(AccountKey(account))
+}
+
+// Unlock 관리자 잠금 해제. key는 AccountKey/IPKey 결과.
+func (t *LoginThrottle) Unlock(key, actor, ip string) error {
+	if err := t.delete(key); err != nil {
+		return err
+	}
+	RecordAudit(model.AuditEvent{
+		Action: model.AuditActionLoginUnlocked,
+		Actor:  actor,
+		Target: key,
+		IP:     ip,
+	})
+	return nil
+}
+
+// Locked 현재 잠금 중인 카운터 목록
+func (t *LoginThrottle) Locked() ([]model.LoginAttempt, error) {
+	now := time.Now()
+	if t.repo != nil {
+		return t.repo.FindLocked(now)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	locked := make([]model.LoginAttempt, 0)
+	for _, a := range t.entries {
+		if a.IsLocked(now) {
+			locked = append(locked, *a)
+		}
+	}
+	return locked, nil
+}
+
+// Stats 현재 상태 반환
+func (t *LoginThrottle) Stats() LoginThrottleStats {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := LoginThrottleStats{
+		Persistent:         t.repo != nil,
+		Entries:            len(t.entries),
+		MaxAccountFailures: t.cfg.MaxAccountFailures,
+		MaxIPFailures:      t.cfg.MaxIPFailures,
+		LockoutDuration:    t.cfg.LockoutDuration.String(),
+	}
+	for _, a := range t.entries {
+		if a.IsLocked(now) {
+			stats.LockedEntries++
+		}
+	}
+	return stats
+}
+
+func (t *LoginThrottle) keys(account, ip string) []string {
+	keys := make([]string, 0, 2)
+	if account != "" {
+		keys = append(keys, AccountKey(account))
+	}
+	if ip != "" {
+		keys = append(keys, IPKey(ip))
+	}
+	return keys
+}
+
+// delay over번째 초과 실패에 대한 지연 (BaseDelay * 2^(over-1), MaxDelay 상한)
+func (t *LoginThrottle) delay(over int) time.Duration {
+	d := t.cfg.BaseDelay
+	for i := 1; i < over && d < t.cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	if t.cfg.MaxDelay > 0 && d > t.cfg.MaxDelay {
+		d = t.cfg.MaxDelay
+	}
+	return d
+}
+
+// load 카운터 조회 (사본). DB가 있으면 DB를 기준으로 하며 조회 실패 시 메모리 값을 사용한다.
+// 잠금이 풀렸거나 FailureWindow가 지난 카운터는 초기화된 것으로 본다 (nil 반환).
+func (t *LoginThrottle) load(key string, now time.Time) *model.LoginAttempt {
+	var a *model.LoginAttempt
+	if t.repo != nil {
+		stored, err := t.repo.Find(key)
+		if err == nil {
+			t.remember(key, stored)
+			a = stored
+		} else {
+			log.Printf("[LoginThrottle] load %s error: %v", key, err)
+			a = t.cached(key)
+		}
+	} else {
+		a = t.cached(key)
+	}
+	if a == nil {
+		return nil
+	}
+	if t.expired(a, now) {
+		t.delete(key)
+		return nil
+	}
+	return a
+}
+
+// increment 실패 횟수를 1 늘린 카운터. 만료된 카운터는 1부터 다시 센다.
+// DB 갱신이 실패하면 이 replica의 메모리 카운터로 계속 제한한다.
+func (t *LoginThrottle) increment(key string, now time.Time) *model.LoginAttempt {
+	if t.repo != nil {
+		a, err := t.repo.Increment(key, now, now.Add(-t.cfg.FailureWindow))
+		if err == nil {
+			t.remember(key, a)
+			return a
+		}
+		log.Printf("[LoginThrottle] increment %s error: %v", key, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a := t.entries[key]
+	if a == nil || t.expired(a, now) {
+		a = &model.LoginAttempt{Key: key}
+		t.entries[key] = a
+	}
+	if a.LockedUntil != nil && !a.IsLocked(now) {
+		a.LockedUntil = nil
+	}
+	a.Failures++
+	a.LastFailureAt = now
+	if len(t.entries) > loginThrottleMaxEntries {
+		t.pruneLocked(now)
+	}
+	copied := *a
+	return &copied
+}
+
+// backoff 다음 시도 허용 시각을 늦춘다 (이미 더 늦으면 유지)
+func (t *LoginThrottle) backoff(key string, until time.Time) {
+	if t.repo != nil {
+		if err := t.repo.Backoff(key, until); err != nil {
+			log.Printf("[LoginThrottle] backoff %s error: %v", key, err)
+		}
+	}
+	t.mu.Lock()
+	if a := t.entries[key]; a != nil && until.After(a.NextAllowedAt) {
+		a.NextAllowedAt = until
+	}
+	t.mu.Unlock()
+}
+
+// lock 잠기지 않은 카운터를 until까지 잠근다. 이 호출이 잠금을 걸었으면 true
+// (DB 조건부 UPDATE라 여러 replica가 동시에 임계치에 도달해도 하나만 true).
+func (t *LoginThrottle) lock(key string, until, now time.Time) bool {
+	dbLocked := false
+	if t.repo != nil {
+		locked, err := t.repo.Lock(key, until, now)
+		if err == nil && !locked {
+			return false
+		}
+		if err != nil {
+			log.Printf("[LoginThrottle] lock %s error: %v", key, err)
+		}
+		dbLocked = err == nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a := t.entries[key]
+	if a == nil {
+		return dbLocked
+	}
+	if !dbLocked && a.IsLocked(now) {
+		return false
+	}
+	a.LockedUntil = &until
+	return true
+}
+
+func (t *LoginThrottle) delete(key string) error {
+	t.mu.Lock()
+	delete(t.entries, key)
+	t.mu.Unlock()
+	if t.repo != nil {
+		if err := t.repo.Delete(key); err != nil {
+			log.Printf("[LoginThrottle] delete %s error: %v", key, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// cached 메모리 카운터 사본
+func (t *LoginThrottle) cached(key string) *model.LoginAttempt {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if a := t.entries[key]; a != nil {
+		copied := *a
+		return &copied
+	}
+	return nil
+}
+
+// remember DB에서 읽은 카운터를 메모리에 반영 (nil이면 제거)
+func (t *LoginThrottle) remember(key string, a *model.LoginAttempt) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if a == nil {
+		delete(t.entries, key)
+		return
+	}
+	copied := *a
+	t.entries[key] = &copied
+}
+
+// expired 잠금이 풀렸거나, 잠금 없이 FailureWindow가 지난 카운터
+func (t *LoginThrottle) expired(a *model.LoginAttempt, now time.Time) bool {
+	expiredLock := a.LockedUntil != nil && !a.IsLocked(now)
+	staleWindow := a.LockedUntil == nil && now.Sub(a.LastFailureAt) > t.cfg.FailureWindow
+	return expiredLock || staleWindow
+}
+
+// pruneLocked 잠금이 아니면서 FailureWindow가 지난 in-memory 항목 정리 (DB 행은 다음 조회 시 정리)
+func (t *LoginThrottle) pruneLocked(now time.Time) {
+	for key, a := range t.entries {
+		if !a.IsLocked(now) && now.Sub(a.LastFailureAt) > t.cfg.FailureWindow {
+			delete(t.entries, key)
+		}
+	}
+}
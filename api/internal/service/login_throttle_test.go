@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository/repotest"
+
+	"gorm.io/gorm"
+)
+
+var testLoginThrottleConfig = config.LoginThrottleConfig{
+	Enabled:            true,
+	FreeAttempts:       1,
+	MaxAccountFailures: 5,
+	MaxIPFailures:      100,
+	BaseDelay:          time.Second,
+	MaxDelay:           time.Minute,
+	LockoutDuration:    time.Hour,
+	FailureWindow:      time.Hour,
+}
+
+func newTestLoginThrottle(t *testing.T, db *gorm.DB) *LoginThrottle {
+	t.Helper()
+	throttle := InitLoginThrottle(testLoginThrottleConfig, db)
+	t.Cleanup(func() { InitLoginThrottle(config.LoginThrottleConfig{}, nil) })
+	return throttle
+}
+
+// captureAuditLog 테스트 동안 기록된 로그 (감사 로그 횟수 확인용)
+func captureAuditLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+// recordFailures 같은 계정의 실패를 동시에 n번 기록
+func recordFailures(throttle *LoginThrottle, account string, n int) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			throttle.RecordFailure(account, "")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoginThrottleConcurrentFailuresInMemory(t *testing.T) {
+	logs := captureAuditLog(t)
+	throttle := newTestLoginThrottle(t, nil)
+
+	recordFailures(throttle, "user01", 20)
+
+	if got := throttle.cached(AccountKey("user01")); got == nil || got.Failures != 20 {
+		t.Fatalf("counter = %+v, want 20 failures", got)
+	}
+	if block := throttle.Check("user01", ""); block == nil || !block.Locked {
+		t.Fatalf("Check = %+v, want locked", block)
+	}
+	if got := strings.Count(logs.String(), "action="+model.AuditActionLoginLocked); got != 1 {
+		t.Fatalf("lock audited %d times, want once", got)
+	}
+}
+
+// newTestLoginAttemptDB 원자적 UPSERT와 조건부 잠금을 흉내 내는 login_attempts 가짜 DB
+func newTestLoginAttemptDB(t *testing.T) (*gorm.DB, *repotest.DB, func(key string) int64) {
+	t.Helper()
+	db, fake := repotest.Open(t)
+	var mu sync.Mutex
+	failures := map[string]int64{}
+	locked := map[string]bool{}
+	fake.Handle("INSERT INTO login_attempts", func(args []driver.Value) repotest.Result {
+		mu.Lock()
+		defer mu.Unlock()
+		key := args[0].(string)
+		failures[key]++
+		return repotest.Result{
+			Columns: []string{"key", "failures", "last_failure_at", "next_allowed_at", "locked_until", "updated_at"},
+			Rows:    [][]driver.Value{{key, failures[key], time.Now(), time.Time{}, nil, time.Now()}},
+		}
+	})
+	fake.Handle(`UPDATE "login_attempts" SET "locked_until"`, func(args []driver.Value) repotest.Result {
+		mu.Lock()
+		defer mu.Unlock()
+		key := args[len(args)-2].(string)
+		if locked[key] {
+			return repotest.Result{}
+		}
+		locked[key] = true
+		return repotest.Result{RowsAffected: 1}
+	})
+	return db, fake, func(key string) int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return failures[key]
+	}
+}
+
+func TestLoginThrottleConcurrentFailuresInDB(t *testing.T) {
+	logs := captureAuditLog(t)
+	db, fake, failures := newTestLoginAttemptDB(t)
+	throttle := newTestLoginThrottle(t, db)
+
+	recordFailures(throttle, "user01", 20)
+
+	if got := failures(AccountKey("user01")); got != 20 {
+		t.Fatalf("stored failures = %d, want 20", got)
+	}
+	// 카운터는 조회 후 저장하지 않고 UPSERT 한 번으로 늘린다
+	if reads := fake.Statements(`FROM "login_attempts"`); len(reads) != 0 {
+		t.Fatalf("RecordFailure read the counter before writing: %d selects", len(reads))
+	}
+	for _, stmt := range fake.Statements("INSERT INTO login_attempts") {
+		if !strings.Contains(stmt.SQL, "login_attempts.failures + 1") || !strings.Contains(stmt.SQL, "ON CONFLICT (key)") {
+			t.Fatalf("increment = %s, want an atomic upsert", stmt.SQL)
+		}
+	}
+	if got := strings.Count(logs.String(), "action="+model.AuditActionLoginLocked); got != 1 {
+		t.Fatalf("lock audited %d times, want once", got)
+	}
+}
+
+func TestLoginThrottleNoGlobalLockDuringIO(t *testing.T) {
+	db, fake := repotest.Open(t)
+	release := make(chan struct{})
+	fake.Handle(`FROM "login_attempts" WHERE key = $1`, func(args []driver.Value) repotest.Result {
+		if args[0] == AccountKey("slow") {
+			<-release
+		}
+		return repotest.Result{Columns: []string{"key"}}
+	})
+	throttle := newTestLoginThrottle(t, db)
+
+	slow := make(chan struct{})
+	go func() {
+		defer close(slow)
+		throttle.Check("slow", "")
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// 다른 계정의 확인은 느린 DB 조회를 기다리지 않는다
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		throttle.Check("fast", "")
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Check of another account waited for a slow DB call")
+	}
+	close(release)
+	<-slow
+}
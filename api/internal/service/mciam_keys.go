@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/pkg/jwt"
+)
+
+const (
+	// mciamKeysTTL 공개키 재조회 주기
+	mciamKeysTTL = time.Hour
+	// mciamKeysMinRefresh 알 수 없는 kid로 인한 강제 재조회 최소 간격 (조회 폭주 방지)
+	mciamKeysMinRefresh = time.Minute
+)
+
+// MCIAMKeySet mc-iam-manager 토큰 서명 공개키(JWKS) 캐시.
+// api.yaml의 mc-iam-manager/Getcerts(/api/auth/certs)에서 키를 가져오며,
+// 키 회전으로 kid가 바뀌면 최소 간격을 두고 재조회한다.
+type MCIAMKeySet struct {
+	cfg    *config.Config
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        jwt.KeySet
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	lastErr     error
+}
+
+var mciamKeySet *MCIAMKeySet
+
+// InitMCIAMKeySet 전역 MCIAM 공개키 캐시 초기화 (MCIAM 모드에서만 호출)
+func InitMCIAMKeySet(cfg *config.Config) *MCIAMKeySet {
+	mciamKeySet = &MCIAMKeySet{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+	return mciamKeySet
+}
+
+// GetMCIAMKeySet 전역 MCIAM 공개키 캐시 반환 (MCIAM 모드가 아니면 nil)
+func GetMCIAMKeySet() *MCIAMKeySet {
+	return mciamKeySet
+}
+
+// ParseToken RS256 토큰 검증. kid가 캐시에 없으면 키를 재조회한 뒤 한 번 더 시도한다.
+func (k *MCIAMKeySet) ParseToken(tokenString string) (*jwt.Claims, []string, error) {
+	keys, err := k.get(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	claims, roles, err := jwt.ParseTokenRS256(tokenString, keys)
+	if err != nil && errors.Is(err, jwt.ErrUnknownKeyID) {
+		if keys, refreshErr := k.get(true); refreshErr == nil {
+			return jwt.ParseTokenRS256(tokenString, keys)
+		}
+	}
+	return claims, roles, err
+}
+
+// get 캐시된 키 반환. 만료되었거나 force이면 재조회하며, 재조회 실패 시 기존 키를 유지한다.
+func (k *MCIAMKeySet) get(force bool) (jwt.KeySet, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	stale := k.keys == nil || now.Sub(k.fetchedAt) > mciamKeysTTL
+	if (stale || force) && now.Sub(k.lastAttempt) >= mciamKeysMinRefresh {
+		k.lastAttempt = now
+		keys, err := k.fetch()
+		if err != nil {
+			log.Printf("[MCIAMKeySet] fetch certs failed: %v", err)
+			k.lastErr = err
+		} else {
+			k.keys = keys
+			k.fetchedAt = now
+			k.lastErr = nil
+		}
+	}
+	if k.keys == nil {
+		return nil, fmt.Errorf("mciam signing keys unavailable: %w", k.lastErr)
+	}
+	return k.keys, nil
+}
+
+// fetch mc-iam-manager에서 JWKS 조회. CommonResponse(responseData) 래핑 응답도 허용한다.
+func (k *MCIAMKeySet) fetch() (jwt.KeySet, error) {
+	svc, actionSpec, err := k.cfg.ApiSpec.GetAction("mc-iam-manager", "Getcerts")
+	if err != nil {
+		return nil, fmt.Errorf("certs endpoint not configured: %w", err)
+	}
+
+	resp, err := k.client.Get(svc.BaseURL + actionSpec.ResourcePath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("certs endpoint returned %d", resp.StatusCode)
+	}
+
+	keys, err := jwt.ParseJWKS(body)
+	if err == nil {
+		return keys, nil
+	}
+	var wrapped struct {
+		ResponseData json.RawMessage `json:"responseData"`
+	}
+	if jsonErr := json.Unmarshal(body, &wrapped); jsonErr == nil && len(wrapped.ResponseData) > 0 {
+		return jwt.ParseJWKS(wrapped.ResponseData)
+	}
+	return nil, err
+}
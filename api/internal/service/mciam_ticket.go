@@ -0,0 +1,120 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// MCIAM 티켓(UMA RPT) 캐시.
+// MC_WEB_CONSOLE_USE_TICKET_VALID=true이면 프록시 호출마다 mc-iam-manager에서 subsystem/operation 권한이 담긴
+// RPT를 발급받아야 하므로, 사용자·operation 단위로 만료 시각까지 재사용한다.
+// 티켓은 발급에 사용한 bearer 토큰의 해시로도 구분한다: 인가(AuthMiddleware)가 꺼져 있으면 사용자 ID는 검증되지 않은
+// 토큰에서 읽으므로, 같은 사용자 ID를 담은 위조 토큰이 다른 토큰으로 발급된 티켓을 재사용하지 못하게 한다.
+
+// MCIAMTicket 발급된 RPT와 만료 시각
+type MCIAMTicket struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TicketCache 사용자+토큰 해시+subsystem+operation → RPT 캐시
+type TicketCache struct {
+	mu      sync.Mutex
+	maxTTL  time.Duration
+	entries map[string]MCIAMTicket
+}
+
+var ticketCache = &TicketCache{maxTTL: 5 * time.Minute, entries: make(map[string]MCIAMTicket)}
+
+// SetTicketCacheTTL 티켓 캐시 최대 유지 시간 설정 및 상태 노출 등록
+func SetTicketCacheTTL(ttl time.Duration) {
+	ticketCache.mu.Lock()
+	ticketCache.maxTTL = ttl
+	ticketCache.mu.Unlock()
+	RegisterStatusProvider("mciamTicket", func() interface{} {
+		return map[string]interface{}{"cachedTickets": ticketCache.Len(), "maxTTL": ttl.String()}
+	})
+}
+
+// GetTicketCache 전역 티켓 캐시 반환
+func GetTicketCache() *TicketCache {
+	return ticketCache
+}
+
+// PermissionName 티켓이 인가하는 권한 이름 (subsystem:operation, 403 응답에 노출)
+func PermissionName(subsystem, operation string) string {
+	return strings.ToLower(subsystem) + ":" + operation
+}
+
+func ticketKey(userID, tokenHash, subsystem, operation string) string {
+	return userID + "\x00" + tokenHash + "\x00" + PermissionName(subsystem, operation)
+}
+
+// Get 만료되지 않은 티켓 조회 (만료 임박 10초 이내는 재발급 대상으로 본다). tokenHash는 jwt.TokenHash(bearer 토큰).
+func (c *TicketCache) Get(userID, tokenHash, subsystem, operation string) (MCIAMTicket, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := ticketKey(userID, tokenHash, subsystem, operation)
+	ticket, ok := c.entries[key]
+	if !ok {
+		return MCIAMTicket{}, false
+	}
+	if time.Until(ticket.ExpiresAt) < 10*time.Second {
+		delete(c.entries, key)
+		return MCIAMTicket{}, false
+	}
+	return ticket, true
+}
+
+// Put 티켓 저장. 만료 시각이 없거나 최대 유지 시간보다 늦으면 최대 유지 시간으로 제한한다.
+func (c *TicketCache) Put(userID, tokenHash, subsystem, operation string, ticket MCIAMTicket) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	limit := time.Now().Add(c.maxTTL)
+	if ticket.ExpiresAt.IsZero() || ticket.ExpiresAt.After(limit) {
+		ticket.ExpiresAt = limit
+	}
+	c.entries[ticketKey(userID, tokenHash, subsystem, operation)] = ticket
+	c.evictExpiredLocked()
+}
+
+// InvalidateUser 사용자의 티켓 전체 제거 (로그아웃 시). 다른 replica에도 전파한다.
+func (c *TicketCache) InvalidateUser(userID string) {
+	c.invalidateUserLocal(userID)
+	PublishInvalidation(InvalidationTopicTicket, userID)
+}
+
+func (c *TicketCache) invalidateUserLocal(userID string) {
+	prefix := userID + "\x00"
+	c.mu.Lock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+func (c *TicketCache) clearLocal() {
+	c.mu.Lock()
+	c.entries = make(map[string]MCIAMTicket)
+	c.mu.Unlock()
+}
+
+// Len 캐시 항목 수 (만료 항목 정리 후)
+func (c *TicketCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpiredLocked()
+	return len(c.entries)
+}
+
+func (c *TicketCache) evictExpiredLocked() {
+	now := time.Now()
+	for key, ticket := range c.entries {
+		if now.After(ticket.ExpiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
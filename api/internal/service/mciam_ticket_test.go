@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTicketCacheKeyedByToken(t *testing.T) {
+	cache := &TicketCache{maxTTL: time.Minute, entries: make(map[string]MCIAMTicket)}
+	cache.Put("user01", "hash-a", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt-a"})
+
+	tests := []struct {
+		name      string
+		userID    string
+		tokenHash string
+		operation string
+		want      bool
+	}{
+		{"same token", "user01", "hash-a", "GetAllNs", true},
+		{"other token with the same user id", "user01", "hash-forged", "GetAllNs", false},
+		{"other user", "user02", "hash-a", "GetAllNs", false},
+		{"other operation", "user01", "hash-a", "DelAllNs", false},
+	}
+	for _, tt := range tests {
+		if ticket, ok := cache.Get(tt.userID, tt.tokenHash, "mc-infra-manager", tt.operation); ok != tt.want || (ok && ticket.Token != "rpt-a") {
+			t.Errorf("%s: Get = (%+v, %t), want %t", tt.name, ticket, ok, tt.want)
+		}
+	}
+
+	cache.Put("user01", "hash-b", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt-b"})
+	cache.invalidateUserLocal("user01")
+	if cache.Len() != 0 {
+		t.Errorf("InvalidateUser left %d tickets", cache.Len())
+	}
+}
+
+func TestTicketCacheExpiry(t *testing.T) {
+	cache := &TicketCache{maxTTL: time.Minute, entries: make(map[string]MCIAMTicket)}
+	cache.Put("user01", "hash-a", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt", ExpiresAt: time.Now().Add(5 * time.Second)})
+	if _, ok := cache.Get("user01", "hash-a", "mc-infra-manager", "GetAllNs"); ok {
+		t.Error("ticket expiring within 10s reused")
+	}
+	cache.Put("user01", "hash-a", "mc-infra-manager", "GetAllNs", MCIAMTicket{Token: "rpt", ExpiresAt: time.Now().Add(time.Hour)})
+	cache.mu.Lock()
+	expiresAt := cache.entries[ticketKey("user01", "hash-a", "mc-infra-manager", "GetAllNs")].ExpiresAt
+	cache.mu.Unlock()
+	if time.Until(expiresAt) > time.Minute {
+		t.Errorf("ticket cached past maxTTL: %s", expiresAt)
+	}
+}
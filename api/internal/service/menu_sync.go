@@ -0,0 +1,544 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+// MenuSyncer 메뉴 트리(webconsole_menu_resources.yaml)와 역할별 메뉴 권한(webconsole_menu_permissions.csv)을
+// mc-iam-manager에 선언적으로 반영한다.
+//
+// Plan은 로컬 정의를 검증한 뒤 mc-iam-manager의 현재 메뉴/권한과 비교한 변경 목록을 만들고,
+// Apply는 같은 계획을 다시 계산해 필요한 호출만 실행하므로 여러 번 실행해도 결과가 같다.
+// mc-iam-manager에 없는 메뉴는 기본적으로 unmanaged로 보고만 하고, Prune일 때만 삭제한다.
+type MenuSyncer struct {
+	cfg    *config.Config
+	client *http.Client
+
+	applyMu sync.Mutex // Apply 직렬화
+}
+
+var (
+	// ErrMenuSyncInvalid 로컬 메뉴/권한 정의 검증 실패
+	ErrMenuSyncInvalid = errors.New("menu definitions are invalid")
+	// ErrMenuSyncUnavailable mc-iam-manager 메뉴/권한 조회 실패
+	ErrMenuSyncUnavailable = errors.New("failed to read menus from mc-iam-manager")
+	// ErrMenuSyncOffline 내보내기 파일 기준 계획은 반영할 수 없음
+	ErrMenuSyncOffline = errors.New("offline plan cannot be applied")
+)
+
+// MenuSyncBaselineExport 계획 기준이 mc-iam-manager가 아닌 내보내기 파일일 때의 Baseline 접두사
+const MenuSyncBaselineExport = "export:"
+
+// MenuSyncOptions 계획/반영 옵션
+type MenuSyncOptions struct {
+	// AuthHeader mc-iam-manager 호출에 사용할 Authorization 값. 비어 있으면 서비스 계정으로 로그인한다.
+	AuthHeader string
+	// Prune 정의에 없는 mc-iam-manager 메뉴 삭제
+	Prune bool
+	// DryRun 계획만 계산하고 호출하지 않음
+	DryRun bool
+	// Offline mc-iam-manager 대신 내보내기 파일(selfiammenu.yaml)의 메뉴를 현재 상태로 사용 (권한 비교 없음, 반영 불가)
+	Offline bool
+}
+
+// MenuSyncPlan 메뉴 동기화 계획
+type MenuSyncPlan struct {
+	Framework   string                     `json:"framework"`
+	Baseline    string                     `json:"baseline"` // "mc-iam-manager" 또는 "export:<path>"
+	MenusFile   string                     `json:"menusFile"`
+	Permissions string                     `json:"permissionsFile"`
+	Valid       bool                       `json:"valid"`
+	UpToDate    bool                       `json:"upToDate"`
+	Issues      []string                   `json:"issues,omitempty"`   // 반영을 막는 정의 오류
+	Warnings    []string                   `json:"warnings,omitempty"` // 반영은 되지만 확인이 필요한 항목
+	Menus       MenuSyncMenuChanges        `json:"menus"`
+	Grants      *MenuSyncPermissionChanges `json:"grants,omitempty"` // Offline이면 nil
+	PlannedAt   time.Time                  `json:"plannedAt"`
+
+	desired         config.MenuResources
+	perms           *config.MenuPermissions
+	grantMap        map[string][]string
+	current         map[string]config.MenuResource
+	currentPolicies []string            // mc-iam-manager 권한 CSV의 정책 컬럼
+	currentGrants   map[string][]string // mc-iam-manager의 메뉴 id → 허용 정책 (정의에 없는 메뉴 포함)
+}
+
+// MenuSyncMenuChanges 메뉴 변경 목록
+type MenuSyncMenuChanges struct {
+	Create    []config.MenuResource `json:"create"`
+	Update    []MenuSyncUpdate      `json:"update"`
+	Delete    []string              `json:"delete"`    // Prune일 때만
+	Unmanaged []string              `json:"unmanaged"` // 정의에 없지만 Prune이 아니라 유지되는 메뉴
+}
+
+// MenuSyncUpdate 값이 달라진 메뉴
+type MenuSyncUpdate struct {
+	Id     string            `json:"id"`
+	Fields []MenuFieldChange `json:"fields"`
+}
+
+// MenuFieldChange 메뉴 필드 하나의 현재/목표 값
+type MenuFieldChange struct {
+	Field   string `json:"field"`
+	Current string `json:"current"`
+	Desired string `json:"desired"`
+}
+
+// MenuSyncPermissionChanges 역할별 메뉴 권한 변경 목록
+type MenuSyncPermissionChanges struct {
+	Grant  []MenuGrant `json:"grant"`
+	Revoke []MenuGrant `json:"revoke"`
+}
+
+// MenuGrant 메뉴 하나에 대한 정책 하나
+type MenuGrant struct {
+	Menu   string `json:"menu"`
+	Policy string `json:"policy"`
+}
+
+// MenuSyncResult 반영 결과
+type MenuSyncResult struct {
+	DryRun              bool              `json:"dryRun"`
+	Created             []string          `json:"created"`
+	Updated             []string          `json:"updated"`
+	Deleted             []string          `json:"deleted"`
+	PermissionsImported bool              `json:"permissionsImported"`
+	Failed              []MenuSyncFailure `json:"failed,omitempty"`
+	AppliedAt           time.Time         `json:"appliedAt"`
+}
+
+// MenuSyncFailure 실패한 호출
+type MenuSyncFailure struct {
+	Target string `json:"target"` // 메뉴 id 또는 "permissions"
+	Error  string `json:"error"`
+}
+
+var menuSyncer *MenuSyncer
+
+// InitMenuSyncer MenuSyncer 생성 후 전역 등록
+func InitMenuSyncer(cfg *config.Config) *MenuSyncer {
+	menuSyncer = &MenuSyncer{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.MenuSync.Timeout},
+	}
+	return menuSyncer
+}
+
+// GetMenuSyncer 전역 MenuSyncer 반환 (미설정이면 nil)
+func GetMenuSyncer() *MenuSyncer {
+	return menuSyncer
+}
+
+// Plan 로컬 정의 검증 후 현재 상태와 비교한 계획 반환. 정의가 잘못되었으면 Valid=false인 계획을 반환한다.
+func (s *MenuSyncer) Plan(ctx context.Context, opts MenuSyncOptions) (*MenuSyncPlan, error) {
+	conf := s.cfg.MenuSync
+	plan := &MenuSyncPlan{
+		Framework:   conf.Framework,
+		Baseline:    "mc-iam-manager",
+		MenusFile:   conf.MenusFile,
+		Permissions: conf.PermissionsFile,
+		PlannedAt:   time.Now().UTC(),
+	}
+
+	menus, err := config.LoadMenuResources(conf.MenusFile)
+	if err != nil {
+		plan.Issues = append(plan.Issues, err.Error())
+		return plan, nil
+	}
+	plan.Issues = append(plan.Issues, menus.Validate()...)
+	perms, err := config.LoadMenuPermissions(conf.PermissionsFile)
+	if err != nil {
+		plan.Issues = append(plan.Issues, err.Error())
+		return plan, nil
+	}
+	issues, warnings := perms.Validate(conf.Framework, menus)
+	plan.Issues = append(plan.Issues, issues...)
+	plan.Warnings = append(plan.Warnings, warnings...)
+	if len(plan.Issues) > 0 {
+		return plan, nil
+	}
+	plan.Valid = true
+	plan.desired, plan.perms = menus, perms
+	plan.grantMap = managedGrants(perms.Grants(conf.Framework), menus)
+
+	if opts.Offline {
+		export, err := config.LoadMenuExport(conf.ExportFile)
+		if err != nil {
+			return nil, err
+		}
+		plan.Baseline = MenuSyncBaselineExport + conf.ExportFile
+		for _, issue := range export.Menus.Validate() {
+			plan.Warnings = append(plan.Warnings, "export: "+issue)
+		}
+		plan.current = export.Menus.ById()
+	} else {
+		authHeader, err := s.authHeader(ctx, opts.AuthHeader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMenuSyncUnavailable, err)
+		}
+		current, err := s.listMenus(ctx, authHeader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMenuSyncUnavailable, err)
+		}
+		plan.current = current.ById()
+		currentPerms, err := s.currentPermissions(ctx, authHeader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMenuSyncUnavailable, err)
+		}
+		plan.currentPolicies = currentPerms.Policies
+		plan.currentGrants = currentPerms.Grants(conf.Framework)
+		plan.Grants = diffMenuGrants(perms.Policies, plan.grantMap, managedGrants(plan.currentGrants, menus))
+	}
+
+	plan.Menus = diffMenus(menus, plan.current, opts.Prune)
+	plan.UpToDate = len(plan.Menus.Create) == 0 && len(plan.Menus.Update) == 0 && len(plan.Menus.Delete) == 0 &&
+		(plan.Grants == nil || len(plan.Grants.Grant) == 0 && len(plan.Grants.Revoke) == 0)
+	return plan, nil
+}
+
+// Apply 계획을 다시 계산해 반영한다. 순서: 메뉴 생성(부모 먼저) → 수정 → 권한 CSV 가져오기 → 삭제(자식 먼저).
+// 권한 CSV는 framework 권한 전체를 교체하므로 정의에 없는 메뉴의 현재 허용도 함께 올린다 (uploadPermissions).
+// 개별 호출 실패는 중단하지 않고 result.Failed에 모은다.
+func (s *MenuSyncer) Apply(ctx context.Context, opts MenuSyncOptions) (*MenuSyncPlan, *MenuSyncResult, error) {
+	if opts.Offline && !opts.DryRun {
+		return nil, nil, ErrMenuSyncOffline
+	}
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	plan, err := s.Plan(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !plan.Valid {
+		return plan, nil, fmt.Errorf("%w: %s", ErrMenuSyncInvalid, strings.Join(plan.Issues, "; "))
+	}
+	result := &MenuSyncResult{DryRun: opts.DryRun, AppliedAt: time.Now().UTC()}
+	if opts.DryRun || plan.UpToDate {
+		return plan, result, nil
+	}
+	authHeader, err := s.authHeader(ctx, opts.AuthHeader)
+	if err != nil {
+		return plan, nil, fmt.Errorf("%w: %v", ErrMenuSyncUnavailable, err)
+	}
+
+	byId := plan.desired.ById()
+	creates := append([]config.MenuResource{}, plan.Menus.Create...)
+	sort.SliceStable(creates, func(i, j int) bool {
+		return config.MenuDepth(byId, creates[i].Id) < config.MenuDepth(byId, creates[j].Id)
+	})
+	for _, menu := range creates {
+		if err := s.saveMenu(ctx, authHeader, "Createmenu", menu); err != nil {
+			result.Failed = append(result.Failed, MenuSyncFailure{Target: menu.Id, Error: err.Error()})
+			continue
+		}
+		result.Created = append(result.Created, menu.Id)
+	}
+	for _, update := range plan.Menus.Update {
+		if err := s.saveMenu(ctx, authHeader, "Updatemenu", byId[update.Id]); err != nil {
+			result.Failed = append(result.Failed, MenuSyncFailure{Target: update.Id, Error: err.Error()})
+			continue
+		}
+		result.Updated = append(result.Updated, update.Id)
+	}
+
+	if len(plan.Grants.Grant) > 0 || len(plan.Grants.Revoke) > 0 {
+		policies, menus, grants := uploadPermissions(plan)
+		csv, err := config.EncodeMenuPermissions(plan.Framework, policies, menus, grants)
+		if err == nil {
+			err = s.importPermissions(ctx, authHeader, csv)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, MenuSyncFailure{Target: "permissions", Error: err.Error()})
+		} else {
+			result.PermissionsImported = true
+		}
+	}
+
+	deletes := append([]string{}, plan.Menus.Delete...)
+	sort.SliceStable(deletes, func(i, j int) bool {
+		return config.MenuDepth(plan.current, deletes[i]) > config.MenuDepth(plan.current, deletes[j])
+	})
+	for _, id := range deletes {
+		if err := s.deleteMenu(ctx, authHeader, id); err != nil {
+			result.Failed = append(result.Failed, MenuSyncFailure{Target: id, Error: err.Error()})
+			continue
+		}
+		result.Deleted = append(result.Deleted, id)
+	}
+	return plan, result, nil
+}
+
+// managedGrants 정의에 있는 메뉴의 허용만 남긴다 (존재하지 않는 메뉴의 권한 행은 반영하지 않음)
+func managedGrants(grants map[string][]string, menus config.MenuResources) map[string][]string {
+	byId := menus.ById()
+	managed := make(map[string][]string, len(grants))
+	for id, policies := range grants {
+		if _, ok := byId[id]; ok {
+			managed[id] = policies
+		}
+	}
+	return managed
+}
+
+// uploadPermissions Importpermissionbycsv로 올릴 정책 컬럼, 메뉴 행, 허용 목록.
+// 정의 메뉴는 로컬 CSV의 허용을 쓰고, 로컬 CSV에 없는 정책 컬럼과 정의에 없는(unmanaged) 메뉴의 행은
+// mc-iam-manager의 현재 허용을 그대로 유지한다. Prune으로 삭제하는 메뉴의 행은 올리지 않는다.
+func uploadPermissions(plan *MenuSyncPlan) ([]string, config.MenuResources, map[string][]string) {
+	policies := append([]string{}, plan.perms.Policies...)
+	var extra []string
+	for _, policy := range plan.currentPolicies {
+		if !containsPolicy(policies, policy) {
+			policies = append(policies, policy)
+			extra = append(extra, policy)
+		}
+	}
+	// 현재 허용의 정책 이름을 업로드 컬럼 이름으로 맞춘다 (대소문자만 다른 경우)
+	current := func(id string, only []string) []string {
+		var granted []string
+		for _, policy := range only {
+			if containsPolicy(plan.currentGrants[id], policy) {
+				granted = append(granted, policy)
+			}
+		}
+		return granted
+	}
+
+	grants := make(map[string][]string, len(plan.grantMap)+len(plan.currentGrants))
+	for id, granted := range plan.grantMap {
+		grants[id] = append(append([]string{}, granted...), current(id, extra)...)
+	}
+	menus := append(config.MenuResources{}, plan.desired...)
+	desired := plan.desired.ById()
+	deleted := make(map[string]bool, len(plan.Menus.Delete))
+	for _, id := range plan.Menus.Delete {
+		deleted[id] = true
+	}
+	unmanaged := make([]string, 0, len(plan.currentGrants))
+	for id := range plan.currentGrants {
+		if _, ok := desired[id]; !ok && !deleted[id] {
+			unmanaged = append(unmanaged, id)
+		}
+	}
+	sort.Strings(unmanaged)
+	for _, id := range unmanaged {
+		menus = append(menus, config.MenuResource{Id: id})
+		grants[id] = current(id, policies)
+	}
+	return policies, menus, grants
+}
+
+// diffMenus 정의 메뉴와 현재 메뉴 비교 (정의 파일 순서 유지, 삭제/unmanaged는 id 순)
+func diffMenus(desired config.MenuResources, current map[string]config.MenuResource, prune bool) MenuSyncMenuChanges {
+	changes := MenuSyncMenuChanges{
+		Create:    []config.MenuResource{},
+		Update:    []MenuSyncUpdate{},
+		Delete:    []string{},
+		Unmanaged: []string{},
+	}
+	byId := desired.ById()
+	for _, menu := range desired {
+		existing, ok := current[menu.Id]
+		if !ok {
+			changes.Create = append(changes.Create, menu)
+			continue
+		}
+		if fields := menuFieldChanges(existing, menu); len(fields) > 0 {
+			changes.Update = append(changes.Update, MenuSyncUpdate{Id: menu.Id, Fields: fields})
+		}
+	}
+	for id := range current {
+		if _, ok := byId[id]; ok {
+			continue
+		}
+		if prune {
+			changes.Delete = append(changes.Delete, id)
+		} else {
+			changes.Unmanaged = append(changes.Unmanaged, id)
+		}
+	}
+	sort.Strings(changes.Delete)
+	sort.Strings(changes.Unmanaged)
+	return changes
+}
+
+func menuFieldChanges(current, desired config.MenuResource) []MenuFieldChange {
+	var fields []MenuFieldChange
+	compare := func(field, currentValue, desiredValue string) {
+		if currentValue != desiredValue {
+			fields = append(fields, MenuFieldChange{Field: field, Current: currentValue, Desired: desiredValue})
+		}
+	}
+	compare("parentId", current.ParentId, desired.ParentId)
+	compare("displayName", current.DisplayName, desired.DisplayName)
+	compare("resType", current.ResType, desired.ResType)
+	compare("isAction", fmt.Sprint(current.IsAction), fmt.Sprint(desired.IsAction))
+	compare("priority", fmt.Sprint(current.Priority), fmt.Sprint(desired.Priority))
+	compare("menuNumber", fmt.Sprint(current.MenuNumber), fmt.Sprint(desired.MenuNumber))
+	return fields
+}
+
+// diffMenuGrants 정의 메뉴에 대해 정책 컬럼별 허용 차이 계산 (메뉴 id, 정책 순)
+func diffMenuGrants(policies []string, desired, current map[string][]string) *MenuSyncPermissionChanges {
+	changes := &MenuSyncPermissionChanges{Grant: []MenuGrant{}, Revoke: []MenuGrant{}}
+	ids := make([]string, 0, len(desired)+len(current))
+	for id := range desired {
+		ids = append(ids, id)
+	}
+	for id := range current {
+		if _, ok := desired[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, policy := range policies {
+			want, have := containsPolicy(desired[id], policy), containsPolicy(current[id], policy)
+			switch {
+			case want && !have:
+				changes.Grant = append(changes.Grant, MenuGrant{Menu: id, Policy: policy})
+			case !want && have:
+				changes.Revoke = append(changes.Revoke, MenuGrant{Menu: id, Policy: policy})
+			}
+		}
+	}
+	return changes
+}
+
+func containsPolicy(policies []string, policy string) bool {
+	for _, p := range policies {
+		if strings.EqualFold(p, policy) {
+			return true
+		}
+	}
+	return false
+}
+
+// authHeader 요청자 Authorization이 없으면 서비스 계정으로 로그인
+func (s *MenuSyncer) authHeader(ctx context.Context, authHeader string) (string, error) {
+	if authHeader != "" {
+		return authHeader, nil
+	}
+	token, _, err := loginServiceAccount(ctx, s.client, s.cfg)
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + token, nil
+}
+
+// listMenus mc-iam-manager listMenus (배열 또는 {menus|responseData: 배열} 응답)
+func (s *MenuSyncer) listMenus(ctx context.Context, authHeader string) (config.MenuResources, error) {
+	body, err := s.call(ctx, authHeader, "listMenus", nil, "application/json", []byte("{}"))
+	if err != nil {
+		return nil, err
+	}
+	menus, err := decodeMenuList(body)
+	if err != nil {
+		return nil, fmt.Errorf("listMenus response parse failed: %w", err)
+	}
+	return menus, nil
+}
+
+// currentPermissions mc-iam-manager Getcurrentpermissioncsv (CSV 본문 또는 JSON 문자열 응답)
+func (s *MenuSyncer) currentPermissions(ctx context.Context, authHeader string) (*config.MenuPermissions, error) {
+	body, err := s.call(ctx, authHeader, "Getcurrentpermissioncsv", map[string]string{"framework": s.cfg.MenuSync.Framework}, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && (trimmed[0] == '"' || trimmed[0] == '{') {
+		var text string
+		var wrapped struct {
+			ResponseData string `json:"responseData"`
+		}
+		if json.Unmarshal(trimmed, &text) == nil {
+			body = []byte(text)
+		} else if json.Unmarshal(trimmed, &wrapped) == nil {
+			body = []byte(wrapped.ResponseData)
+		}
+	}
+	return config.ParseMenuPermissions(body)
+}
+
+// saveMenu Createmenu 또는 Updatemenu 호출
+func (s *MenuSyncer) saveMenu(ctx context.Context, authHeader, operation string, menu config.MenuResource) error {
+	body, err := json.Marshal(menu)
+	if err != nil {
+		return err
+	}
+	_, err = s.call(ctx, authHeader, operation, map[string]string{"menuId": menu.Id}, "application/json", body)
+	return err
+}
+
+func (s *MenuSyncer) deleteMenu(ctx context.Context, authHeader, id string) error {
+	_, err := s.call(ctx, authHeader, "Deletemenu", map[string]string{"menuId": id}, "", nil)
+	return err
+}
+
+// importPermissions Importpermissionbycsv에 권한 CSV를 multipart "file"로 업로드
+func (s *MenuSyncer) importPermissions(ctx context.Context, authHeader string, csv []byte) error {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", s.cfg.MenuSync.Framework+"_menu_permissions.csv")
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(csv); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	_, err = s.call(ctx, authHeader, "Importpermissionbycsv", map[string]string{"framework": s.cfg.MenuSync.Framework}, writer.FormDataContentType(), buf.Bytes())
+	return err
+}
+
+// call api.yaml의 mc-iam-manager operation 호출. 2xx가 아니면 상태 코드와 응답 일부를 오류로 반환한다.
+func (s *MenuSyncer) call(ctx context.Context, authHeader, operation string, pathParams map[string]string, contentType string, body []byte) ([]byte, error) {
+	svc, action, err := s.cfg.ApiSpec.GetAction("mc-iam-manager", operation)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found in api.yaml: %w", operation, err)
+	}
+	path := action.ResourcePath
+	for key, value := range pathParams {
+		path = strings.ReplaceAll(path, "{"+key+"}", url.PathEscape(value))
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(action.Method), svc.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", authHeader)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", operation, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s response read failed: %w", operation, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := strings.TrimSpace(string(respBody))
+		if len(snippet) > 200 {
+			snippet = snippet[:200] + "..."
+		}
+		return nil, fmt.Errorf("%s returned %d: %s", operation, resp.StatusCode, snippet)
+	}
+	return respBody, nil
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+const testSyncMenuYaml = `
+menus:
+  - id: workloads
+    parentid: manage
+    displayname: Workloads
+    restype: menu
+    isaction: true
+    priority: 1
+    menunumber: 120
+  - id: manage
+    parentid: operations
+    displayname: Manage
+    restype: menu
+    isaction: false
+    priority: 1
+    menunumber: 110
+  - id: operations
+    parentid: home
+    displayname: Operations
+    restype: menu
+    isaction: false
+    priority: 1
+    menunumber: 100
+  - id: settings
+    parentid: home
+    displayname: Settings
+    restype: menu
+    isaction: false
+    priority: 2
+    menunumber: 200
+`
+
+const testSyncMenuPermissions = `framework,resource,adminPolicy,viewerPolicy
+mc-web-console,operationsmenu,TRUE,TRUE
+mc-web-console,managemenu,TRUE,TRUE
+mc-web-console,workloadsmenu,TRUE,
+mc-web-console,settingsmenu,TRUE,
+`
+
+// fakeMenuIAM 메뉴/메뉴 권한 API만 흉내 내는 mc-iam-manager
+type fakeMenuIAM struct {
+	mu    sync.Mutex
+	menus map[string]config.MenuResource
+	csv   string
+	calls []string // "METHOD escaped-path"
+}
+
+func (f *fakeMenuIAM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := r.URL.EscapedPath()
+	f.calls = append(f.calls, r.Method+" "+path)
+	switch {
+	case r.Method == http.MethodPost && path == "/api/menus/list":
+		list := make(config.MenuResources, 0, len(f.menus))
+		for _, id := range sortedKeys(f.menus) {
+			list = append(list, f.menus[id])
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case path == "/api/permission/file/framework/mc-web-console" && r.Method == http.MethodGet:
+		_, _ = io.WriteString(w, f.csv)
+	case path == "/api/permission/file/framework/mc-web-console" && r.Method == http.MethodPost:
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		f.csv = string(data)
+	case r.Method == http.MethodPost && path == "/api/menus":
+		var menu config.MenuResource
+		if err := json.NewDecoder(r.Body).Decode(&menu); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if menu.ParentId != config.MenuRootId {
+			if _, ok := f.menus[menu.ParentId]; !ok {
+				http.Error(w, "parent not found", http.StatusBadRequest)
+				return
+			}
+		}
+		f.menus[menu.Id] = menu
+	case strings.HasPrefix(r.URL.Path, "/api/menus/id/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/menus/id/")
+		if _, ok := f.menus[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			for _, menu := range f.menus {
+				if menu.ParentId == id {
+					http.Error(w, "menu has children", http.StatusConflict)
+					return
+				}
+			}
+			delete(f.menus, id)
+			return
+		}
+		var menu config.MenuResource
+		if err := json.NewDecoder(r.Body).Decode(&menu); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.menus[id] = menu
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// takeCalls 기록된 호출 중 조회가 아닌 호출만 반환하고 기록을 비운다
+func (f *fakeMenuIAM) takeCalls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var writes []string
+	for _, call := range f.calls {
+		if call != "POST /api/menus/list" && !strings.HasPrefix(call, "GET ") {
+			writes = append(writes, call)
+		}
+	}
+	f.calls = nil
+	return writes
+}
+
+func newTestMenuSyncer(t *testing.T, iam *fakeMenuIAM) *MenuSyncer {
+	t.Helper()
+	server := httptest.NewServer(iam)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	menusFile := filepath.Join(dir, "menus.yaml")
+	permsFile := filepath.Join(dir, "menu_permissions.csv")
+	if err := os.WriteFile(menusFile, []byte(testSyncMenuYaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(permsFile, []byte(testSyncMenuPermissions), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		MenuSync: config.MenuSyncConfig{Framework: "mc-web-console", MenusFile: menusFile, PermissionsFile: permsFile, Timeout: 5 * time.Second},
+		ApiSpec: &config.ApiSpec{
+			Services: map[string]config.Service{"mc-iam-manager": {BaseURL: server.URL}},
+			ServiceActions: map[string]map[string]config.ActionSpec{
+				"mc-iam-manager": {
+					"listMenus":               {Method: "post", ResourcePath: "/api/menus/list"},
+					"Createmenu":              {Method: "post", ResourcePath: "/api/menus"},
+					"Updatemenu":              {Method: "put", ResourcePath: "/api/menus/id/{menuId}"},
+					"Deletemenu":              {Method: "delete", ResourcePath: "/api/menus/id/{menuId}"},
+					"Getcurrentpermissioncsv": {Method: "get", ResourcePath: "/api/permission/file/framework/{framework}"},
+					"Importpermissionbycsv":   {Method: "post", ResourcePath: "/api/permission/file/framework/{framework}"},
+				},
+			},
+		},
+	}
+	return &MenuSyncer{cfg: cfg, client: &http.Client{Timeout: cfg.MenuSync.Timeout}}
+}
+
+func newFakeMenuIAM() *fakeMenuIAM {
+	return &fakeMenuIAM{
+		menus: map[string]config.MenuResource{
+			"settings": {Id: "settings", ParentId: "home", DisplayName: "Old settings", ResType: "menu", Priority: 2, MenuNumber: 200},
+			"legacy":   {Id: "legacy", ParentId: "home", DisplayName: "Legacy", ResType: "menu", Priority: 9, MenuNumber: 900},
+			"legacy/x": {Id: "legacy/x", ParentId: "legacy", DisplayName: "Legacy child", ResType: "menu", IsAction: true, Priority: 1, MenuNumber: 910},
+		},
+		csv: "framework,resource,adminPolicy,viewerPolicy,auditorPolicy\n" +
+			"mc-web-console,settingsmenu,TRUE,TRUE,TRUE\n" +
+			"mc-web-console,legacymenu,TRUE,,TRUE\n" +
+			"mc-web-console,legacy/xmenu,,TRUE,\n",
+	}
+}
+
+func TestDiffMenus(t *testing.T) {
+	desired := config.MenuResources{
+		{Id: "operations", ParentId: "home", DisplayName: "Operations", Priority: 1},
+		{Id: "settings", ParentId: "home", DisplayName: "Settings", Priority: 2},
+	}
+	current := map[string]config.MenuResource{
+		"settings": {Id: "settings", ParentId: "home", DisplayName: "Old", Priority: 3},
+		"zeta":     {Id: "zeta", ParentId: "home"},
+		"alpha":    {Id: "alpha", ParentId: "home"},
+	}
+
+	tests := []struct {
+		name          string
+		prune         bool
+		wantDelete    []string
+		wantUnmanaged []string
+	}{
+		{"keep unmanaged", false, []string{}, []string{"alpha", "zeta"}},
+		{"prune", true, []string{"alpha", "zeta"}, []string{}},
+	}
+	for _, tt := range tests {
+		changes := diffMenus(desired, current, tt.prune)
+		if len(changes.Create) != 1 || changes.Create[0].Id != "operations" {
+			t.Errorf("%s: Create = %+v, want operations", tt.name, changes.Create)
+		}
+		wantFields := []MenuFieldChange{
+			{Field: "displayName", Current: "Old", Desired: "Settings"},
+			{Field: "priority", Current: "3", Desired: "2"},
+		}
+		if len(changes.Update) != 1 || changes.Update[0].Id != "settings" || !reflect.DeepEqual(changes.Update[0].Fields, wantFields) {
+			t.Errorf("%s: Update = %+v", tt.name, changes.Update)
+		}
+		if !reflect.DeepEqual(changes.Delete, tt.wantDelete) || !reflect.DeepEqual(changes.Unmanaged, tt.wantUnmanaged) {
+			t.Errorf("%s: Delete = %v, Unmanaged = %v", tt.name, changes.Delete, changes.Unmanaged)
+		}
+	}
+}
+
+func TestDiffMenuGrants(t *testing.T) {
+	policies := []string{"adminPolicy", "viewerPolicy"}
+	tests := []struct {
+		name       string
+		desired    map[string][]string
+		current    map[string][]string
+		wantGrant  []MenuGrant
+		wantRevoke []MenuGrant
+	}{
+		{
+			name:       "identical",
+			desired:    map[string][]string{"settings": {"adminPolicy"}},
+			current:    map[string][]string{"settings": {"adminPolicy"}},
+			wantGrant:  []MenuGrant{},
+			wantRevoke: []MenuGrant{},
+		},
+		{
+			name:       "policy names compared without case",
+			desired:    map[string][]string{"settings": {"adminPolicy"}},
+			current:    map[string][]string{"settings": {"AdminPolicy"}},
+			wantGrant:  []MenuGrant{},
+			wantRevoke: []MenuGrant{},
+		},
+		{
+			name:       "grant and revoke",
+			desired:    map[string][]string{"settings": {"adminPolicy", "viewerPolicy"}, "operations": {"adminPolicy"}},
+			current:    map[string][]string{"settings": {"adminPolicy"}, "operations": {"viewerPolicy"}},
+			wantGrant:  []MenuGrant{{Menu: "operations", Policy: "adminPolicy"}, {Menu: "settings", Policy: "viewerPolicy"}},
+			wantRevoke: []MenuGrant{{Menu: "operations", Policy: "viewerPolicy"}},
+		},
+		{
+			name:       "row missing in desired revokes everything",
+			desired:    map[string][]string{},
+			current:    map[string][]string{"settings": {"adminPolicy"}},
+			wantGrant:  []MenuGrant{},
+			wantRevoke: []MenuGrant{{Menu: "settings", Policy: "adminPolicy"}},
+		},
+	}
+	for _, tt := range tests {
+		changes := diffMenuGrants(policies, tt.desired, tt.current)
+		if !reflect.DeepEqual(changes.Grant, tt.wantGrant) || !reflect.DeepEqual(changes.Revoke, tt.wantRevoke) {
+			t.Errorf("%s: Grant = %v, Revoke = %v", tt.name, changes.Grant, changes.Revoke)
+		}
+	}
+}
+
+func TestMenuSyncApply(t *testing.T) {
+	iam := newFakeMenuIAM()
+	syncer := newTestMenuSyncer(t, iam)
+	opts := MenuSyncOptions{AuthHeader: "Bearer admin"}
+
+	_, result, err := syncer.Apply(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(result.Failed) > 0 {
+		t.Fatalf("Failed = %+v", result.Failed)
+	}
+	wantCalls := []string{
+		"POST /api/menus", "POST /api/menus", "POST /api/menus",
+		"PUT /api/menus/id/settings",
+		"POST /api/permission/file/framework/mc-web-console",
+	}
+	if calls := iam.takeCalls(); !reflect.DeepEqual(calls, wantCalls) {
+		t.Fatalf("calls = %v, want %v", calls, wantCalls)
+	}
+	if !reflect.DeepEqual(result.Created, []string{"operations", "manage", "workloads"}) {
+		t.Fatalf("Created = %v, want parents first", result.Created)
+	}
+	if !reflect.DeepEqual(result.Updated, []string{"settings"}) || len(result.Deleted) != 0 {
+		t.Fatalf("Updated = %v, Deleted = %v", result.Updated, result.Deleted)
+	}
+
+	// 정의에 없는 메뉴와 로컬 CSV에 없는 정책 컬럼의 허용은 유지된다
+	uploaded, err := config.ParseMenuPermissions([]byte(iam.csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	grants := uploaded.Grants("mc-web-console")
+	want := map[string][]string{
+		"operations": {"adminPolicy", "viewerPolicy"},
+		"manage":     {"adminPolicy", "viewerPolicy"},
+		"workloads":  {"adminPolicy"},
+		"settings":   {"adminPolicy", "auditorPolicy"},
+		"legacy":     {"adminPolicy", "auditorPolicy"},
+		"legacy/x":   {"viewerPolicy"},
+	}
+	if !reflect.DeepEqual(grants, want) {
+		t.Fatalf("uploaded grants = %v, want %v", grants, want)
+	}
+
+	// 두 번째 실행은 아무것도 바꾸지 않는다
+	plan, result, err := syncer.Apply(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("second Apply: %v", err)
+	}
+	if !plan.UpToDate || len(result.Created)+len(result.Updated)+len(result.Deleted) != 0 || result.PermissionsImported {
+		t.Fatalf("second Apply changed something: plan=%+v result=%+v", plan.Menus, result)
+	}
+	if calls := iam.takeCalls(); len(calls) != 0 {
+		t.Fatalf("second Apply made write calls %v", calls)
+	}
+}
+
+func TestMenuSyncApplyPrune(t *testing.T) {
+	iam := newFakeMenuIAM()
+	syncer := newTestMenuSyncer(t, iam)
+
+	_, result, err := syncer.Apply(context.Background(), MenuSyncOptions{AuthHeader: "Bearer admin", Prune: true})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(result.Failed) > 0 {
+		t.Fatalf("Failed = %+v", result.Failed)
+	}
+	// 자식 먼저 삭제하고, 경로 문자는 이스케이프한다
+	calls := iam.takeCalls()
+	var deletes []string
+	for _, call := range calls {
+		if strings.HasPrefix(call, "DELETE ") {
+			deletes = append(deletes, call)
+		}
+	}
+	if want := []string{"DELETE /api/menus/id/legacy%2Fx", "DELETE /api/menus/id/legacy"}; !reflect.DeepEqual(deletes, want) {
+		t.Fatalf("deletes = %v, want %v", deletes, want)
+	}
+	if !reflect.DeepEqual(result.Deleted, []string{"legacy/x", "legacy"}) {
+		t.Fatalf("Deleted = %v", result.Deleted)
+	}
+
+	uploaded, err := config.ParseMenuPermissions([]byte(iam.csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resources []string
+	for id := range uploaded.Grants("mc-web-console") {
+		resources = append(resources, id)
+	}
+	sort.Strings(resources)
+	if want := []string{"manage", "operations", "settings", "workloads"}; !reflect.DeepEqual(resources, want) {
+		t.Fatalf("uploaded menus = %v, want pruned menus dropped", resources)
+	}
+}
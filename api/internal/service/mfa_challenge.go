@@ -0,0 +1,118 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// mfaMaxAttempts 챌린지 당 허용되는 2차 코드 입력 실패 횟수
+const mfaMaxAttempts = 5
+
+// PendingLogin 1차 인증은 통과했으나 2차 인증을 기다리는 로그인 결과.
+// 2차 인증 성공 시 세션을 저장하고 Payload를 그대로 클라이언트에 반환한다.
+type PendingLogin struct {
+	UserID           string
+	LoginID          string // 로그인 요청에 입력한 ID (로그인 실패 제한 계정 키)
+	Account          string // 인증 앱에 표시할 계정명 (이메일 또는 로그인 ID)
+	Roles            []string
+	AccessToken      string
+	ExpiresIn        float64
+	RefreshToken     string
+	RefreshExpiresIn float64
+	StatusCode       int
+	Payload          interface{}
+	MFA              bool // 2차 인증을 거쳐 완료됨 (인증 이벤트용)
+}
+
+// MFAChallenge 2차 인증 대기 상태
+type MFAChallenge struct {
+	ID                 string
+	Login              PendingLogin
+	EnrollmentRequired bool // 역할상 MFA 필수이나 미등록 → 로그인 중 등록 필요
+	ExpiresAt          time.Time
+	Attempts           int
+}
+
+// MFAChallengeStore 2차 인증 대기 챌린지 in-memory 저장소.
+// 챌린지는 수 분 내에 소멸하므로 프로세스 메모리에 보관한다
+// (여러 replica 운용 시 로그인 요청은 sticky session으로 같은 인스턴스에 라우팅되어야 한다).
+type MFAChallengeStore struct {
+	mu         sync.Mutex
+	challenges map[string]*MFAChallenge
+}
+
+var mfaChallenges = &MFAChallengeStore{challenges: make(map[string]*MFAChallenge)}
+
+// GetMFAChallengeStore 전역 챌린지 저장소 반환
+func GetMFAChallengeStore() *MFAChallengeStore {
+	return mfaChallenges
+}
+
+// Create 챌린지 생성 후 ID(mfa_token) 반환
+func (s *MFAChallengeStore) Create(login PendingLogin, enrollmentRequired bool, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(raw)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpiredLocked()
+	s.challenges[id] = &MFAChallenge{
+		ID:                 id,
+		Login:              login,
+		EnrollmentRequired: enrollmentRequired,
+		ExpiresAt:          time.Now().Add(ttl),
+	}
+	return id, nil
+}
+
+// Get 유효한 챌린지 조회 (복사본 반환)
+func (s *MFAChallengeStore) Get(id string) (MFAChallenge, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch, ok := s.challenges[id]
+	if !ok {
+		return MFAChallenge{}, false
+	}
+	if time.Now().After(ch.ExpiresAt) {
+		delete(s.challenges, id)
+		return MFAChallenge{}, false
+	}
+	return *ch, true
+}
+
+// RecordFailure 코드 불일치 기록. 허용 횟수를 넘으면 챌린지를 폐기하고 false 반환.
+func (s *MFAChallengeStore) RecordFailure(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch, ok := s.challenges[id]
+	if !ok {
+		return false
+	}
+	ch.Attempts++
+	if ch.Attempts >= mfaMaxAttempts {
+		delete(s.challenges, id)
+		return false
+	}
+	return true
+}
+
+// Delete 챌린지 제거 (인증 완료 시)
+func (s *MFAChallengeStore) Delete(id string) {
+	s.mu.Lock()
+	delete(s.challenges, id)
+	s.mu.Unlock()
+}
+
+func (s *MFAChallengeStore) evictExpiredLocked() {
+	now := time.Now()
+	for id, ch := range s.challenges {
+		if now.After(ch.ExpiresAt) {
+			delete(s.challenges, id)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestChallengeStore() *MFAChallengeStore {
+	return &MFAChallengeStore{challenges: make(map[string]*MFAChallenge)}
+}
+
+func TestMFAChallengeStore(t *testing.T) {
+	store := newTestChallengeStore()
+	login := PendingLogin{UserID: "user01", Roles: []string{"platformAdmin"}}
+	id, err := store.Create(login, true, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := store.Create(login, false, time.Minute)
+	if id == other || len(id) != 64 {
+		t.Fatalf("challenge ids %q, %q are not unique random tokens", id, other)
+	}
+
+	ch, ok := store.Get(id)
+	if !ok || ch.Login.UserID != "user01" || !ch.EnrollmentRequired {
+		t.Fatalf("Get = %+v, %t", ch, ok)
+	}
+	if _, ok := store.Get("unknown"); ok {
+		t.Error("unknown challenge found")
+	}
+
+	store.Delete(id)
+	if _, ok := store.Get(id); ok {
+		t.Error("deleted challenge found")
+	}
+	if _, ok := store.Get(other); !ok {
+		t.Error("Delete removed another challenge")
+	}
+}
+
+func TestMFAChallengeExpiry(t *testing.T) {
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		wait   time.Duration
+		wantOK bool
+	}{
+		{"valid", time.Minute, 0, true},
+		{"expired", 10 * time.Millisecond, 20 * time.Millisecond, false},
+		{"already expired", -time.Second, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestChallengeStore()
+			id, err := store.Create(PendingLogin{UserID: "user01"}, false, tt.ttl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(tt.wait)
+			if _, ok := store.Get(id); ok != tt.wantOK {
+				t.Errorf("Get ok = %t, want %t", ok, tt.wantOK)
+			}
+			if !tt.wantOK && store.RecordFailure(id) {
+				t.Error("RecordFailure accepted an expired challenge")
+			}
+		})
+	}
+}
+
+func TestMFAChallengeEvictsExpiredOnCreate(t *testing.T) {
+	store := newTestChallengeStore()
+	expired, _ := store.Create(PendingLogin{}, false, -time.Second)
+	if _, err := store.Create(PendingLogin{}, false, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	store.mu.Lock()
+	_, ok := store.challenges[expired]
+	store.mu.Unlock()
+	if ok {
+		t.Error("expired challenge kept after Create")
+	}
+}
+
+func TestMFAChallengeAttempts(t *testing.T) {
+	store := newTestChallengeStore()
+	id, _ := store.Create(PendingLogin{UserID: "user01"}, false, time.Minute)
+	for i := 1; i < mfaMaxAttempts; i++ {
+		if !store.RecordFailure(id) {
+			t.Fatalf("challenge discarded after %d failures", i)
+		}
+		if ch, _ := store.Get(id); ch.Attempts != i {
+			t.Fatalf("Attempts = %d, want %d", ch.Attempts, i)
+		}
+	}
+	if store.RecordFailure(id) {
+		t.Fatalf("challenge kept after %d failures", mfaMaxAttempts)
+	}
+	if _, ok := store.Get(id); ok {
+		t.Error("challenge still valid after too many failures")
+	}
+}
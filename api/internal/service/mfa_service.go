@@ -0,0 +1,245 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/pkg/jwt"
+	"mc_web_console_api/pkg/secretbox"
+	"mc_web_console_api/pkg/totp"
+)
+
+// MFA 처리 실패 사유
+var (
+	ErrMFANotConfigured  = errors.New("mfa encryption key is not configured")
+	ErrMFANotEnrolled    = errors.New("mfa is not enrolled")
+	ErrMFAAlreadyEnabled = errors.New("mfa is already enabled")
+	ErrMFAInvalidCode    = errors.New("invalid mfa code")
+)
+
+const (
+	// mfaRecoveryCodeCount 발급할 복구 코드 수
+	mfaRecoveryCodeCount = 10
+	// mfaValidationSkew 시계 오차 허용 스텝 수 (±30초)
+	mfaValidationSkew = 1
+)
+
+// MFAService TOTP 2단계 인증 등록/검증 서비스
+type MFAService struct {
+	repo   *repository.MFARepository
+	box    *secretbox.Box
+	issuer string
+}
+
+// MFAEnrollment 등록 시작 결과. Secret/RecoveryCodes 원문은 이 응답에서만 노출된다.
+type MFAEnrollment struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	RecoveryCodes   []string `json:"recovery_codes"`
+}
+
+// NewMFAService 새로운 MFA 서비스 생성. 암호화 키가 없으면 ErrMFANotConfigured 반환.
+func NewMFAService(repo *repository.MFARepository, cfg config.MFAConfig) (*MFAService, error) {
+	if cfg.EncryptionKey == "" {
+		return nil, ErrMFANotConfigured
+	}
+	box, err := secretbox.New(cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &MFAService{repo: repo, box: box, issuer: cfg.Issuer}, nil
+}
+
+// IsEnabled 사용자가 MFA 등록을 완료했는지 확인
+func (s *MFAService) IsEnabled(userID string) (bool, error) {
+	mfa, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	return mfa != nil && mfa.Enabled, nil
+}
+
+// BeginEnrollment 새 시크릿과 복구 코드를 발급하고 미확정 상태로 저장한다.
+// ConfirmEnrollment로 첫 코드를 확인해야 활성화된다.
+func (s *MFAService) BeginEnrollment(userID, account string) (*MFAEnrollment, error) {
+	existing, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.box.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	mfa := &model.UserMFA{
+		UserID:        userID,
+		SecretEnc:     sealed,
+		Enabled:       false,
+		RecoveryCodes: hashes,
+	}
+	if existing != nil {
+		mfa.CreatedAt = existing.CreatedAt
+	}
+	if err := s.repo.Save(mfa); err != nil {
+		return nil, fmt.Errorf("failed to save mfa enrollment: %w", err)
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, account, secret),
+		RecoveryCodes:   codes,
+	}, nil
+}
+
+// ConfirmEnrollment 인증 앱의 첫 코드로 등록을 확정하고 MFA를 활성화한다.
+func (s *MFAService) ConfirmEnrollment(userID, code string) error {
+	mfa, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil {
+		return ErrMFANotEnrolled
+	}
+	if mfa.Enabled {
+		return ErrMFAAlreadyEnabled
+	}
+	if err := s.useTOTP(mfa, code); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	mfa.Enabled = true
+	mfa.EnabledAt = &now
+	return s.repo.Save(mfa)
+}
+
+// Verify 활성화된 MFA에 대해 TOTP 코드 또는 복구 코드를 검증한다. 복구 코드는 1회 사용 후 폐기된다.
+func (s *MFAService) Verify(userID, code string) error {
+	mfa, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return ErrMFANotEnrolled
+	}
+
+	err = s.useTOTP(mfa, code)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrMFAInvalidCode) {
+		return err
+	}
+	if s.consumeRecoveryCode(mfa, code) {
+		return s.repo.Save(mfa)
+	}
+	return ErrMFAInvalidCode
+}
+
+// Disable 현재 코드(또는 복구 코드)를 확인한 뒤 MFA를 해제한다.
+func (s *MFAService) Disable(userID, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	return s.repo.Delete(userID)
+}
+
+// verifyTOTP 코드 검증 후 재사용 방지를 위해 LastUsedStep을 갱신한다 (저장은 호출자 책임).
+func (s *MFAService) verifyTOTP(mfa *model.UserMFA, code string) error {
+	secret, err := s.box.Open(mfa.SecretEnc)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt mfa secret: %w", err)
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), mfaValidationSkew)
+	if !ok || step <= mfa.LastUsedStep {
+		return ErrMFAInvalidCode
+	}
+	mfa.LastUsedStep = step
+	return nil
+}
+
+// useTOTP 코드를 검증한 뒤 사용한 스텝을 DB에 조건부로 기록한다.
+// 같은 코드를 동시에 제출하면 먼저 기록한 요청만 통과하고 나머지는 ErrMFAInvalidCode.
+func (s *MFAService) useTOTP(mfa *model.UserMFA, code string) error {
+	previous := mfa.LastUsedStep
+	if err := s.verifyTOTP(mfa, code); err != nil {
+		return err
+	}
+	claimed, err := s.repo.ClaimStep(mfa.UserID, mfa.LastUsedStep)
+	if err != nil || !claimed {
+		mfa.LastUsedStep = previous
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record mfa step: %w", err)
+	}
+	if !claimed {
+		return ErrMFAInvalidCode
+	}
+	return nil
+}
+
+// consumeRecoveryCode 일치하는 복구 코드를 목록에서 제거 (저장은 호출자 책임)
+func (s *MFAService) consumeRecoveryCode(mfa *model.UserMFA, code string) bool {
+	var hashes []string
+	if err := json.Unmarshal([]byte(mfa.RecoveryCodes), &hashes); err != nil {
+		return false
+	}
+	target := jwt.TokenHash(normalizeRecoveryCode(code))
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(target)) == 1 {
+			hashes = append(hashes[:i], hashes[i+1:]...)
+			encoded, _ := json.Marshal(hashes)
+			mfa.RecoveryCodes = string(encoded)
+			return true
+		}
+	}
+	return false
+}
+
+// generateRecoveryCodes 복구 코드 원문 목록과 해시 JSON 배열 생성 (형식: xxxxx-xxxxx)
+func generateRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	hashes := make([]string, 0, mfaRecoveryCodeCount)
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, "", fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		h := hex.EncodeToString(raw)
+		code := h[:5] + "-" + h[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, jwt.TokenHash(normalizeRecoveryCode(code)))
+	}
+	encoded, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(encoded), nil
+}
+
+// normalizeRecoveryCode 입력 편의를 위해 공백/하이픈 제거 및 소문자화
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
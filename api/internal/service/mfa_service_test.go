@@ -0,0 +1,223 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/repository/repotest"
+	"mc_web_console_api/pkg/totp"
+)
+
+func newTestMFAService(t *testing.T) *MFAService {
+	t.Helper()
+	svc, err := NewMFAService(nil, config.MFAConfig{Issuer: "mc-web-console", EncryptionKey: "test-mfa-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+// newTestUserMFA 시크릿을 암호화해 저장한 등록 정보
+func newTestUserMFA(t *testing.T, svc *MFAService) (*model.UserMFA, string) {
+	t.Helper()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := svc.box.Seal(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &model.UserMFA{UserID: "user01", SecretEnc: sealed, Enabled: true}, secret
+}
+
+func TestNewMFAServiceRequiresKey(t *testing.T) {
+	if _, err := NewMFAService(nil, config.MFAConfig{}); !errors.Is(err, ErrMFANotConfigured) {
+		t.Errorf("NewMFAService without key error = %v, want ErrMFANotConfigured", err)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	svc := newTestMFAService(t)
+	current := totp.Step(time.Now())
+
+	tests := []struct {
+		name     string
+		lastUsed int64
+		step     int64
+		wantErr  bool
+	}{
+		{name: "current step", step: current},
+		{name: "previous step within skew", step: current - 1},
+		{name: "next step within skew", step: current + 1},
+		{name: "outside skew", step: current - 3, wantErr: true},
+		{name: "replay of the last used step", lastUsed: current, step: current, wantErr: true},
+		{name: "step older than the last used", lastUsed: current, step: current - 1, wantErr: true},
+		{name: "newer step after use", lastUsed: current - 1, step: current},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mfa, secret := newTestUserMFA(t, svc)
+			mfa.LastUsedStep = tt.lastUsed
+			code, err := totp.CodeAt(secret, tt.step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = svc.verifyTOTP(mfa, code)
+			if tt.wantErr {
+				if !errors.Is(err, ErrMFAInvalidCode) {
+					t.Fatalf("verifyTOTP error = %v, want ErrMFAInvalidCode", err)
+				}
+				if mfa.LastUsedStep != tt.lastUsed {
+					t.Errorf("LastUsedStep changed to %d on failure", mfa.LastUsedStep)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mfa.LastUsedStep != tt.step {
+				t.Errorf("LastUsedStep = %d, want %d", mfa.LastUsedStep, tt.step)
+			}
+		})
+	}
+}
+
+func TestVerifyTOTPReplay(t *testing.T) {
+	svc := newTestMFAService(t)
+	mfa, secret := newTestUserMFA(t, svc)
+	code, _ := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err := svc.verifyTOTP(mfa, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.verifyTOTP(mfa, code); !errors.Is(err, ErrMFAInvalidCode) {
+		t.Errorf("second use of the same code error = %v, want ErrMFAInvalidCode", err)
+	}
+}
+
+func TestVerifyTOTPUndecryptableSecret(t *testing.T) {
+	svc := newTestMFAService(t)
+	other, err := NewMFAService(nil, config.MFAConfig{EncryptionKey: "other-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfa, secret := newTestUserMFA(t, other)
+	code, _ := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err := svc.verifyTOTP(mfa, code); err == nil || errors.Is(err, ErrMFAInvalidCode) {
+		t.Errorf("verifyTOTP with another key error = %v, want decrypt error", err)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	svc := newTestMFAService(t)
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != mfaRecoveryCodeCount {
+		t.Fatalf("generated %d codes, want %d", len(codes), mfaRecoveryCodeCount)
+	}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' || seen[code] {
+			t.Errorf("bad or duplicate recovery code %q", code)
+		}
+		seen[code] = true
+	}
+
+	mfa := &model.UserMFA{RecoveryCodes: hashes}
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"exact", codes[0], true},
+		{"already used", codes[0], false},
+		{"upper case without hyphen", " " + strings.ToUpper(strings.ReplaceAll(codes[1], "-", "")) + " ", true},
+		{"unknown", "00000-00000", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		if got := svc.consumeRecoveryCode(mfa, tt.code); got != tt.want {
+			t.Errorf("%s: consumeRecoveryCode = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+	for _, code := range codes[2:] {
+		if !svc.consumeRecoveryCode(mfa, code) {
+			t.Errorf("unused code %s rejected", code)
+		}
+	}
+	if mfa.RecoveryCodes != "[]" {
+		t.Errorf("RecoveryCodes = %s, want []", mfa.RecoveryCodes)
+	}
+	if svc.consumeRecoveryCode(&model.UserMFA{RecoveryCodes: "not json"}, codes[0]) {
+		t.Error("corrupted recovery code list accepted a code")
+	}
+}
+
+func TestVerifyConcurrentSameCode(t *testing.T) {
+	db, fake := repotest.Open(t)
+	svc, err := NewMFAService(repository.NewMFARepository(db), config.MFAConfig{Issuer: "mc-web-console", EncryptionKey: "test-mfa-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, secret := newTestUserMFA(t, svc)
+	fake.Handle(`FROM "user_mfas"`, func([]driver.Value) repotest.Result {
+		return repotest.Result{
+			Columns: []string{"user_id", "secret_enc", "enabled", "recovery_codes", "last_used_step"},
+			Rows:    [][]driver.Value{{stored.UserID, stored.SecretEnc, true, "[]", int64(0)}},
+		}
+	})
+	// last_used_step < ? 조건부 UPDATE: 한 스텝은 한 번만 기록된다
+	var mu sync.Mutex
+	var lastStep int64
+	fake.Handle(`UPDATE "user_mfas" SET "last_used_step"`, func(args []driver.Value) repotest.Result {
+		mu.Lock()
+		defer mu.Unlock()
+		step := args[0].(int64)
+		if step <= lastStep {
+			return repotest.Result{}
+		}
+		lastStep = step
+		return repotest.Result{RowsAffected: 1}
+	})
+
+	code, _ := totp.CodeAt(secret, totp.Step(time.Now()))
+	const requests = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.Verify("user01", code)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	passed := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			passed++
+		case !errors.Is(err, ErrMFAInvalidCode):
+			t.Fatalf("Verify error = %v", err)
+		}
+	}
+	if passed != 1 {
+		t.Fatalf("same code accepted %d times, want 1", passed)
+	}
+	for _, stmt := range fake.Statements(`UPDATE "user_mfas"`) {
+		if !strings.Contains(stmt.SQL, "last_used_step < $") {
+			t.Fatalf("UPDATE = %s, want it conditional on last_used_step", stmt.SQL)
+		}
+	}
+}
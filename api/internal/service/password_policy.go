@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"mc_web_console_api/internal/config"
+)
+
+// 비밀번호 정책/이메일 도메인 검사 실패 사유
+var (
+	ErrBreachedPassword      = errors.New("password appears in a known data breach")
+	ErrPasswordCheckFailed   = errors.New("breached password list is not available")
+	ErrEmailDomainNotAllowed = errors.New("email domain is not allowed")
+)
+
+// breachedRangePrefixLen k-anonymity 범위 파일 접두사 길이 (SHA-1 hex 앞 5자리)
+const breachedRangePrefixLen = 5
+
+// PasswordPolicy 비밀번호 길이/문자 종류/유출 여부 검사와 회원가입 이메일 도메인 검사.
+// 유출 비밀번호 목록은 HIBP Pwned Passwords 형식(SHA-1 대문자 hex)으로,
+// 디렉터리면 접두사 5자리 범위 파일 하나만 읽어 전체 목록을 메모리에 올리지 않는다.
+type PasswordPolicy struct {
+	cfg config.SignupConfig
+
+	rangeDir   string // 범위 파일 디렉터리 (<PREFIX>.txt)
+	singleFile string // 단일 해시 목록 파일
+}
+
+var passwordPolicy *PasswordPolicy
+
+// InitPasswordPolicy 전역 비밀번호 정책 초기화. 유출 목록 경로가 없으면 유출 검사는 생략한다.
+// 경로를 지정했는데 읽을 수 없으면 검사를 끄지 않고, 목록이 준비될 때까지 비밀번호 설정을 거부한다.
+func InitPasswordPolicy(cfg config.SignupConfig) *PasswordPolicy {
+	if cfg.PasswordMinLength <= 0 {
+		cfg.PasswordMinLength = minLocalPasswordLength
+	}
+	p := &PasswordPolicy{cfg: cfg}
+	if cfg.BreachedPasswordPath != "" {
+		info, err := os.Stat(cfg.BreachedPasswordPath)
+		switch {
+		case err != nil:
+			log.Printf("⚠️  breached password list not available (%s): %v", cfg.BreachedPasswordPath, err)
+			p.singleFile = cfg.BreachedPasswordPath
+		case info.IsDir():
+			p.rangeDir = cfg.BreachedPasswordPath
+		default:
+			p.singleFile = cfg.BreachedPasswordPath
+		}
+	}
+	passwordPolicy = p
+	RegisterStatusProvider("passwordPolicy", p.Status)
+	return p
+}
+
+// GetPasswordPolicy 전역 비밀번호 정책 반환. 초기화 전이면 기본 정책(최소 길이만)을 사용한다.
+func GetPasswordPolicy() *PasswordPolicy {
+	if passwordPolicy == nil {
+		return &PasswordPolicy{cfg: config.SignupConfig{PasswordMinLength: minLocalPasswordLength}}
+	}
+	return passwordPolicy
+}
+
+// Validate 비밀번호 정책 검사. 실패 시 ErrWeakPassword 또는 ErrBreachedPassword를 감싼 에러 반환.
+// 유출 목록을 읽지 못하면 ErrPasswordCheckFailed를 감싼 에러를 반환한다 (fail closed).
+func (p *PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.cfg.PasswordMinLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, p.cfg.PasswordMinLength)
+	}
+	if classes := passwordClasses(password); classes < p.cfg.PasswordMinClasses {
+		return fmt.Errorf("%w: must contain at least %d of lowercase, uppercase, digit, other letter, symbol", ErrWeakPassword, p.cfg.PasswordMinClasses)
+	}
+	breached, err := p.isBreached(password)
+	if err != nil {
+		log.Printf("[PasswordPolicy] breached password lookup error: %v", err)
+		return fmt.Errorf("%w: %v", ErrPasswordCheckFailed, err)
+	}
+	if breached {
+		return fmt.Errorf("%w: choose a different password", ErrBreachedPassword)
+	}
+	return nil
+}
+
+// CheckEmailDomain 회원가입 이메일 도메인 허용/거부 목록 검사. 거부 목록이 우선한다.
+// 목록 항목은 정확한 도메인 또는 ".example.com" 형식의 하위 도메인 접미사다.
+func (p *PasswordPolicy) CheckEmailDomain(email string) error {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return fmt.Errorf("%w: invalid email", ErrEmailDomainNotAllowed)
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	if matchDomain(domain, p.cfg.EmailDomainDeny) {
+		return fmt.Errorf("%w: %s", ErrEmailDomainNotAllowed, domain)
+	}
+	if len(p.cfg.EmailDomainAllow) > 0 && !matchDomain(domain, p.cfg.EmailDomainAllow) {
+		return fmt.Errorf("%w: %s", ErrEmailDomainNotAllowed, domain)
+	}
+	return nil
+}
+
+// Status /api/admin/status 용 정책 요약 (목록 내용은 노출하지 않음)
+func (p *PasswordPolicy) Status() interface{} {
+	return map[string]interface{}{
+		"minLength":          p.cfg.PasswordMinLength,
+		"minClasses":         p.cfg.PasswordMinClasses,
+		"breachedCheck":      p.rangeDir != "" || p.singleFile != "",
+		"emailDomainAllow":   len(p.cfg.EmailDomainAllow),
+		"emailDomainDeny":    len(p.cfg.EmailDomainDeny),
+		"invitationRequired": p.cfg.InvitationRequired,
+		"approvalRequired":   p.cfg.ApprovalRequired,
+	}
+}
+
+// isBreached SHA-1 해시가 유출 목록에 있는지 확인
+func (p *PasswordPolicy) isBreached(password string) (bool, error) {
+	if p.rangeDir == "" && p.singleFile == "" {
+		return false, nil
+	}
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	if p.rangeDir != "" {
+		prefix, suffix := hash[:breachedRangePrefixLen], hash[breachedRangePrefixLen:]
+		found, err := scanHashFile(filepath.Join(p.rangeDir, prefix+".txt"), suffix)
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return found, err
+	}
+	return scanHashFile(p.singleFile, hash)
+}
+
+// scanHashFile "HASH[:COUNT]" 줄 목록에서 hash를 찾는다 (대소문자 무시)
+func scanHashFile(path, hash string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.EqualFold(line, hash) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// passwordClasses 포함된 문자 종류 수 (소문자, 대문자, 숫자, 대소문자가 없는 문자(한글 등), 특수문자).
+// 유니코드 범주로 구분하며 제어 문자 등 어느 범주에도 속하지 않는 문자는 세지 않는다.
+func passwordClasses(password string) int {
+	var lower, upper, digit, letter, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsNumber(r):
+			digit = true
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	count := 0
+	for _, ok := range []bool{lower, upper, digit, letter, symbol} {
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+func matchDomain(domain string, list []string) bool {
+	for _, entry := range list {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.HasPrefix(entry, ".") {
+			if strings.HasSuffix(domain, entry) {
+				return true
+			}
+			continue
+		}
+		if domain == entry {
+			return true
+		}
+	}
+	return false
+}
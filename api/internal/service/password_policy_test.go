@@ -0,0 +1,96 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mc_web_console_api/internal/config"
+)
+
+func TestPasswordClasses(t *testing.T) {
+	tests := []struct {
+		password string
+		want     int
+	}{
+		{"abcdefgh", 1},
+		{"abcdEFGH", 2},
+		{"abcdEF12", 3},
+		{"abcdEF1!", 4},
+		{"비밀번호입니다", 1},
+		{"비밀번호1234", 2},
+		{"비밀번호ab12!", 4},
+		{"비밀Ab1!", 5},
+		{"pass word", 2},
+		{"abc\x00def", 1},
+	}
+	for _, tt := range tests {
+		if got := passwordClasses(tt.password); got != tt.want {
+			t.Errorf("passwordClasses(%q) = %d, want %d", tt.password, got, tt.want)
+		}
+	}
+}
+
+func sha1Upper(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestPasswordPolicyValidateBreached(t *testing.T) {
+	dir := t.TempDir()
+	leaked := "correct horse battery"
+	hash := sha1Upper(leaked)
+
+	list := filepath.Join(dir, "pwned.txt")
+	if err := os.WriteFile(list, []byte(hash+":42\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rangeDir := filepath.Join(dir, "ranges")
+	if err := os.Mkdir(rangeDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	rangeFile := filepath.Join(rangeDir, hash[:breachedRangePrefixLen]+".txt")
+	if err := os.WriteFile(rangeFile, []byte(hash[breachedRangePrefixLen:]+":42\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.SignupConfig{PasswordMinLength: 8}
+	for name, p := range map[string]*PasswordPolicy{
+		"single file": {cfg: cfg, singleFile: list},
+		"range dir":   {cfg: cfg, rangeDir: rangeDir},
+	} {
+		if err := p.Validate(leaked); !errors.Is(err, ErrBreachedPassword) {
+			t.Errorf("%s: Validate(leaked) = %v, want ErrBreachedPassword", name, err)
+		}
+		if err := p.Validate("another long password"); err != nil {
+			t.Errorf("%s: Validate(clean) = %v, want nil", name, err)
+		}
+	}
+}
+
+func TestPasswordPolicyValidateFailsClosed(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	p := InitPasswordPolicy(config.SignupConfig{PasswordMinLength: 8, BreachedPasswordPath: missing})
+	t.Cleanup(func() { passwordPolicy = nil })
+
+	if err := p.Validate("long enough password"); !errors.Is(err, ErrPasswordCheckFailed) {
+		t.Fatalf("Validate with unreadable list = %v, want ErrPasswordCheckFailed", err)
+	}
+	if err := p.Validate("short"); !errors.Is(err, ErrWeakPassword) {
+		t.Fatalf("Validate(short) = %v, want ErrWeakPassword", err)
+	}
+}
+
+func TestPasswordPolicyMinClasses(t *testing.T) {
+	p := &PasswordPolicy{cfg: config.SignupConfig{PasswordMinLength: 4, PasswordMinClasses: 2}}
+	if err := p.Validate("비밀번호입니다"); !errors.Is(err, ErrWeakPassword) {
+		t.Errorf("Validate(hangul only) = %v, want ErrWeakPassword", err)
+	}
+	if err := p.Validate("비밀번호12"); err != nil {
+		t.Errorf("Validate(hangul+digit) = %v, want nil", err)
+	}
+}
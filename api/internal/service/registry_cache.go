@@ -10,44 +10,145 @@ import (
 	"mc_web_console_api/internal/config"
 )
 
-// RegistryCache mc-iam-manager 서비스 레지스트리 Passive 캐시.
+// RegistryCache mc-iam-manager 서비스 레지스트리 캐시.
 //
-// 캐시 적재: proxy.go가 ListMcmpApisServices 응답 통과 시 자동 저장 (TTL 60s)
-// 캐시 무효화: proxy.go가 UpdateFrameworkService 성공 시 자동 무효화
+// 캐시 적재: proxy.go가 ListMcmpApisServices 응답 통과 시 자동 저장, RegistryRefresher가 주기적으로 갱신
+// 캐시 무효화: proxy.go가 UpdateFrameworkService 성공 시 자동 무효화, InvalidationBus로 모든 replica에 전파
 //
 // 플로우:
-//  1. Bootstrap (캐시 비어있음): api.yaml의 mc-iam-manager 주소로 readyz 체크 (자동 fallback)
-//  2. ListMcmpApisServices 응답 수신: BaseURL + ServiceActions 캐시에 저장
-//  3. 이후 모든 프록시: 캐시 BaseURL + 캐시 ActionSpec 우선, 없으면 api.yaml fallback
-//  4. mc-iam-manager URL 변경 후: cache invalidate → ListMcmpApisServices 재적재 → 갱신
+//  1. Bootstrap (캐시 비어있음): 보관된 마지막 정상 레지스트리(RegistrySnapshotStore)를 복원, 없으면 api.yaml 사용
+//  2. ListMcmpApisServices 응답 수신: 스키마 버전 확인 후 엄격 파싱(parseRegistryPayload),
+//     BaseURL + ServiceActions 캐시에 저장하고 스냅샷 보관
+//  3. 이후 모든 프록시: 캐시 BaseURL 우선, ActionSpec은 api.yaml과 필드 단위 병합(config.MergeActionSpec)
+//  4. TTL 경과: 재검증기(RegistryRefresher)가 있으면 MaxStale까지 기존 값을 제공하며 백그라운드 갱신 (stale-while-revalidate),
+//     없으면 만료로 보고 api.yaml 사용
+//  5. mc-iam-manager URL 변경 후: cache invalidate → ListMcmpApisServices 재적재 → 갱신
 //
 // 예외: ListMcmpApisServices, UpdateFrameworkService 는 항상 mc-iam-manager 고정 주소 사용
 // (api.yaml의 mc-iam-manager BaseURL = mc-iam-manager 고정/bootstrap 주소)
 type RegistryCache struct {
-	mu        sync.RWMutex
-	services  map[string]config.Service                // serviceName → Service(BaseURL, Auth)
-	actions   map[string]map[string]config.ActionSpec  // serviceName → operationId → ActionSpec
-	storedAt  time.Time
-	ttl       time.Duration
+	mu       sync.RWMutex
+	services map[string]config.Service               // serviceName → Service(BaseURL, Auth)
+	actions  map[string]map[string]config.ActionSpec // serviceName → operationId → ActionSpec
+	storedAt time.Time
+	source   string // live: 레지스트리 응답, persisted: 보관된 스냅샷 복원
+	ttl      time.Duration
+	maxStale time.Duration
+
+	revalidate func()
+	snapshots  RegistrySnapshotStore
+	bus        InvalidationBus
+	persistErr string
+	issues     []RegistryPayloadIssue // 마지막 파싱의 스키마 불일치 항목
+
+	expiredLogged bool // 현재 값의 TTL 만료를 이미 로그로 남겼는지 (재적재 시 초기화)
 }
 
+// 캐시 상태
+const (
+	RegistryStateEmpty   = "empty"
+	RegistryStateFresh   = "fresh"
+	RegistryStateStale   = "stale"   // TTL 경과, 재검증 중 제공
+	RegistryStateExpired = "expired" // 제공 안 함 (api.yaml 사용)
+)
+
 // NewRegistryCache RegistryCache 생성 (ttl: 캐시 유효 시간)
 func NewRegistryCache(ttl time.Duration) *RegistryCache {
 	return &RegistryCache{ttl: ttl}
 }
 
+// SetRevalidator TTL 경과 시 호출할 백그라운드 재검증 함수와 stale 제공 최대 시간 설정.
+// revalidate는 즉시 반환해야 한다 (RegistryRefresher.Trigger).
+func (rc *RegistryCache) SetRevalidator(revalidate func(), maxStale time.Duration) {
+	rc.mu.Lock()
+	rc.revalidate = revalidate
+	rc.maxStale = maxStale
+	rc.mu.Unlock()
+}
+
+// SetSnapshotStore 마지막 정상 레지스트리 보관소 설정
+func (rc *RegistryCache) SetSnapshotStore(store RegistrySnapshotStore) {
+	rc.mu.Lock()
+	rc.snapshots = store
+	rc.mu.Unlock()
+}
+
+// Restore 보관된 스냅샷으로 캐시를 채운다. 보관 시각을 그대로 사용하므로 오래된 스냅샷은 stale 상태로 시작한다.
+func (rc *RegistryCache) Restore() error {
+	rc.mu.RLock()
+	store := rc.snapshots
+	rc.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+	snapshot, err := store.Load()
+	if err != nil || snapshot == nil {
+		return err
+	}
+	var responseData interface{}
+	if err := json.Unmarshal([]byte(snapshot.Payload), &responseData); err != nil {
+		return err
+	}
+	services, actions, issues := extractDefinitions(responseData)
+	rc.mu.Lock()
+	rc.issues = issues
+	rc.mu.Unlock()
+	if len(services) == 0 {
+		return nil
+	}
+	rc.mu.Lock()
+	rc.services = services
+	rc.actions = actions
+	rc.storedAt = snapshot.StoredAt
+	rc.source = "persisted"
+	rc.expiredLogged = false
+	rc.mu.Unlock()
+	log.Printf("[RegistryCache] restored %d services from %s snapshot (stored %s ago)",
+		len(services), store.Kind(), time.Since(snapshot.StoredAt).Round(time.Second))
+	return nil
+}
+
 // Store ListMcmpApisServices 응답(McmpApiDefinitions)에서 Services + ServiceActions 저장.
+// 보관소가 설정되어 있으면 응답 원문을 마지막 정상 레지스트리로 보관한다.
 func (rc *RegistryCache) Store(responseData interface{}) {
-	services, actions := extractDefinitions(responseData)
+	services, actions, issues := extractDefinitions(responseData)
+	rc.mu.Lock()
+	rc.issues = issues
+	rc.mu.Unlock()
 	if len(services) == 0 {
 		return
 	}
+	now := time.Now()
 	rc.mu.Lock()
 	rc.services = services
 	rc.actions = actions
-	rc.storedAt = time.Now()
+	rc.storedAt = now
+	rc.source = "live"
+	rc.expiredLogged = false
+	store := rc.snapshots
 	rc.mu.Unlock()
 	log.Printf("[RegistryCache] stored %d services (actions: %d)", len(services), len(actions))
+
+	if store != nil {
+		rc.persist(store, responseData, now)
+	}
+}
+
+func (rc *RegistryCache) persist(store RegistrySnapshotStore, responseData interface{}, storedAt time.Time) {
+	payload, err := json.Marshal(responseData)
+	if err == nil {
+		err = store.Save(payload, storedAt)
+	}
+	rc.mu.Lock()
+	if err != nil {
+		rc.persistErr = err.Error()
+	} else {
+		rc.persistErr = ""
+	}
+	rc.mu.Unlock()
+	if err != nil {
+		log.Printf("[RegistryCache] failed to persist snapshot (%s): %v", store.Kind(), err)
+	}
 }
 
 // GetAllServices 캐시가 유효한 경우 전체 서비스 목록 반환.
@@ -58,7 +159,7 @@ func (rc *RegistryCache) GetAllServices() map[string]config.Service {
 	storedAt := rc.storedAt
 	rc.mu.RUnlock()
 
-	if services == nil || rc.isExpired(storedAt) {
+	if services == nil || !rc.usable(storedAt) {
 		return nil
 	}
 
@@ -69,13 +170,41 @@ func (rc *RegistryCache) GetAllServices() map[string]config.Service {
 	return result
 }
 
+// SetInvalidationBus 무효화 버스 연결. 다른 replica의 Invalidate가 이 캐시에도 반영된다.
+func (rc *RegistryCache) SetInvalidationBus(bus InvalidationBus) {
+	rc.mu.Lock()
+	rc.bus = bus
+	rc.mu.Unlock()
+	bus.Subscribe(InvalidationTopicRegistry, func(string) {
+		log.Printf("[RegistryCache] invalidation received from another replica")
+		rc.invalidateLocal()
+	})
+}
+
 // Invalidate 캐시 무효화. UpdateFrameworkService 성공 시 호출.
+// 재검증기가 있으면 즉시 백그라운드 갱신을 요청하고, 무효화 버스가 있으면 다른 replica에 전파한다.
 func (rc *RegistryCache) Invalidate() {
+	rc.invalidateLocal()
+	rc.mu.RLock()
+	bus := rc.bus
+	rc.mu.RUnlock()
+	if bus != nil {
+		if err := bus.Publish(InvalidationTopicRegistry, ""); err != nil {
+			log.Printf("[RegistryCache] failed to publish invalidation: %v", err)
+		}
+	}
+}
+
+func (rc *RegistryCache) invalidateLocal() {
 	rc.mu.Lock()
 	rc.services = nil
 	rc.actions = nil
+	revalidate := rc.revalidate
 	rc.mu.Unlock()
 	log.Printf("[RegistryCache] invalidated")
+	if revalidate != nil {
+		revalidate()
+	}
 }
 
 // GetBaseURL 캐시가 유효한 경우 subsystem의 BaseURL 반환.
@@ -93,7 +222,7 @@ func (rc *RegistryCache) GetBaseURL(subsystem, operationId string) string {
 	storedAt := rc.storedAt
 	rc.mu.RUnlock()
 
-	if services == nil || rc.isExpired(storedAt) {
+	if services == nil || !rc.usable(storedAt) {
 		return ""
 	}
 
@@ -118,7 +247,7 @@ func (rc *RegistryCache) GetActionSpec(subsystem, operationId string) *config.Ac
 	storedAt := rc.storedAt
 	rc.mu.RUnlock()
 
-	if actions == nil || rc.isExpired(storedAt) {
+	if actions == nil || !rc.usable(storedAt) {
 		return nil
 	}
 
@@ -138,11 +267,85 @@ func (rc *RegistryCache) GetActionSpec(subsystem, operationId string) *config.Ac
 	return nil
 }
 
-func (rc *RegistryCache) isExpired(storedAt time.Time) bool {
-	if time.Since(storedAt) > rc.ttl {
-		log.Printf("[RegistryCache] TTL expired, using api.yaml")
+// RegistryCacheStatus 캐시 신선도 (GET /api/admin/status → registry)
+type RegistryCacheStatus struct {
+	State        string    `json:"state"`
+	Source       string    `json:"source,omitempty"`
+	Services     int       `json:"services"`
+	StoredAt     time.Time `json:"storedAt,omitempty"`
+	AgeSeconds   int64     `json:"ageSeconds"`
+	TTL          string    `json:"ttl"`
+	MaxStale     string    `json:"maxStale"`
+	Revalidating bool      `json:"revalidating"` // stale-while-revalidate 사용 (RegistryRefresher 설정됨)
+	Persistence  string    `json:"persistence,omitempty"`
+	PersistError string    `json:"persistError,omitempty"`
+	SchemaIssues []string  `json:"schemaIssues,omitempty"` // 마지막 응답에서 제외된 서비스/action
+}
+
+// Status 현재 캐시 상태 반환
+func (rc *RegistryCache) Status() RegistryCacheStatus {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	status := RegistryCacheStatus{
+		State:        rc.stateLocked(),
+		Source:       rc.source,
+		Services:     len(rc.services),
+		TTL:          rc.ttl.String(),
+		MaxStale:     rc.maxStale.String(),
+		Revalidating: rc.revalidate != nil,
+		PersistError: rc.persistErr,
+	}
+	for _, issue := range rc.issues {
+		status.SchemaIssues = append(status.SchemaIssues, issue.String())
+	}
+	if rc.snapshots != nil {
+		status.Persistence = rc.snapshots.Kind()
+	}
+	if !rc.storedAt.IsZero() {
+		status.StoredAt = rc.storedAt
+		status.AgeSeconds = int64(time.Since(rc.storedAt).Seconds())
+	}
+	return status
+}
+
+func (rc *RegistryCache) stateLocked() string {
+	if rc.services == nil {
+		return RegistryStateEmpty
+	}
+	age := time.Since(rc.storedAt)
+	switch {
+	case age <= rc.ttl:
+		return RegistryStateFresh
+	case rc.revalidate != nil && age <= rc.ttl+rc.maxStale:
+		return RegistryStateStale
+	default:
+		return RegistryStateExpired
+	}
+}
+
+// usable storedAt에 저장된 값을 제공할 수 있는지 확인.
+// TTL 내면 그대로, TTL 경과 후 MaxStale 이내면 재검증을 요청하고 기존 값을 제공한다.
+func (rc *RegistryCache) usable(storedAt time.Time) bool {
+	age := time.Since(storedAt)
+	if age <= rc.ttl {
+		return true
+	}
+	rc.mu.RLock()
+	revalidate := rc.revalidate
+	maxStale := rc.maxStale
+	rc.mu.RUnlock()
+	if revalidate != nil && age <= rc.ttl+maxStale {
+		revalidate()
 		return true
 	}
+	// 요청마다 호출되므로 만료로 바뀐 뒤 처음 한 번만 로그를 남긴다
+	rc.mu.Lock()
+	logged := rc.expiredLogged
+	rc.expiredLogged = true
+	rc.mu.Unlock()
+	if !logged {
+		log.Printf("[RegistryCache] TTL expired, using api.yaml")
+	}
 	return false
 }
 
@@ -156,63 +359,15 @@ func isRegistryManagementAPI(subsystem, operationId string) bool {
 	return opLower == "listmcmpapisservices" || opLower == "updateframeworkservice"
 }
 
-// extractDefinitions responseData에서 Services 와 ServiceActions 추출.
-func extractDefinitions(responseData interface{}) (map[string]config.Service, map[string]map[string]config.ActionSpec) {
-	b, err := json.Marshal(responseData)
+// extractDefinitions responseData에서 Services 와 ServiceActions 추출 (parseRegistryPayload).
+// 스키마 불일치 항목은 로그로 남기고 issues로 반환한다. 페이로드 전체가 거부되면 services는 nil.
+func extractDefinitions(responseData interface{}) (map[string]config.Service, map[string]map[string]config.ActionSpec, []RegistryPayloadIssue) {
+	services, actions, issues, err := parseRegistryPayload(responseData)
+	logRegistryPayloadIssues(issues)
 	if err != nil {
-		log.Printf("[RegistryCache] marshal error: %v", err)
-		return nil, nil
-	}
-
-	var raw struct {
-		Services map[string]struct {
-			Version string `json:"Version"`
-			BaseURL string `json:"BaseURL"`
-			Auth    struct {
-				Type     string `json:"Type"`
-				Username string `json:"Username"`
-				Password string `json:"Password"`
-			} `json:"Auth"`
-		} `json:"Services"`
-		ServiceActions map[string]map[string]struct {
-			Method       string `json:"Method"`
-			ResourcePath string `json:"ResourcePath"`
-			Description  string `json:"Description"`
-		} `json:"ServiceActions"`
-	}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		log.Printf("[RegistryCache] unmarshal error: %v", err)
-		return nil, nil
-	}
-
-	if len(raw.Services) == 0 {
-		return nil, nil
-	}
-
-	services := make(map[string]config.Service, len(raw.Services))
-	for name, svc := range raw.Services {
-		services[name] = config.Service{
-			Version: svc.Version,
-			BaseURL: svc.BaseURL,
-			Auth: config.AuthConfig{
-				Type:     svc.Auth.Type,
-				Username: svc.Auth.Username,
-				Password: svc.Auth.Password,
-			},
-		}
+		log.Printf("[RegistryCache] payload rejected: %v", err)
+		issues = append(issues, RegistryPayloadIssue{Reason: "payload rejected: " + err.Error()})
+		return nil, nil, issues
 	}
-
-	actions := make(map[string]map[string]config.ActionSpec, len(raw.ServiceActions))
-	for svcName, svcActions := range raw.ServiceActions {
-		actions[svcName] = make(map[string]config.ActionSpec, len(svcActions))
-		for opId, action := range svcActions {
-			actions[svcName][opId] = config.ActionSpec{
-				Method:       action.Method,
-				ResourcePath: action.ResourcePath,
-				Description:  action.Description,
-			}
-		}
-	}
-
-	return services, actions
+	return services, actions, issues
 }
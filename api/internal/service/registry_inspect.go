@@ -0,0 +1,321 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"mc_web_console_api/internal/config"
+)
+
+// 레지스트리 캐시 조회와 api.yaml 비교 (GET /api/admin/registry, /api/admin/registry/diff).
+// 프록시가 어느 BaseURL/ActionSpec을 사용하는지 SubsystemAnyController와 같은 규칙으로 계산한다.
+
+// 값 출처
+const (
+	RegistrySourceRegistry = "registry"
+	RegistrySourceApiYaml  = "api.yaml"
+)
+
+// 비교 결과 상태
+const (
+	RegistryDiffSame         = "same"
+	RegistryDiffChanged      = "changed"
+	RegistryDiffRegistryOnly = "registry_only"
+	RegistryDiffApiYamlOnly  = "apiyaml_only"
+)
+
+// RegistryServiceView 캐시된 서비스 (Auth 자격 증명은 제외)
+type RegistryServiceView struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	BaseURL  string `json:"baseUrl"`
+	AuthType string `json:"authType,omitempty"`
+	Actions  int    `json:"actions"`
+}
+
+// RegistryActionView 캐시된 action
+type RegistryActionView struct {
+	Service       string            `json:"service"`
+	OperationId   string            `json:"operationId"`
+	Method        string            `json:"method"`
+	ResourcePath  string            `json:"resourcePath"`
+	Description   string            `json:"description,omitempty"`
+	RequestCoerce map[string]string `json:"requestCoerce,omitempty"`
+}
+
+// RegistryCacheView 캐시 내용과 신선도. 만료된 값도 그대로 보여준다 (State로 사용 여부 판단).
+type RegistryCacheView struct {
+	RegistryCacheStatus
+	Services []RegistryServiceView `json:"services"`
+	Actions  []RegistryActionView  `json:"actions"`
+}
+
+// Inspect 캐시 내용 조회. 재검증을 유발하지 않는다.
+func (rc *RegistryCache) Inspect() RegistryCacheView {
+	view := RegistryCacheView{RegistryCacheStatus: rc.Status()}
+
+	rc.mu.RLock()
+	services, actions := rc.services, rc.actions
+	rc.mu.RUnlock()
+
+	view.Services = make([]RegistryServiceView, 0, len(services))
+	for _, name := range sortedKeys(services) {
+		svc := services[name]
+		view.Services = append(view.Services, RegistryServiceView{
+			Name:     name,
+			Version:  svc.Version,
+			BaseURL:  svc.BaseURL,
+			AuthType: svc.Auth.Type,
+			Actions:  len(actions[name]),
+		})
+	}
+	view.Actions = []RegistryActionView{}
+	for _, svcName := range sortedKeys(actions) {
+		for _, opId := range sortedKeys(actions[svcName]) {
+			spec := actions[svcName][opId]
+			view.Actions = append(view.Actions, RegistryActionView{
+				Service:       svcName,
+				OperationId:   opId,
+				Method:        spec.Method,
+				ResourcePath:  spec.ResourcePath,
+				Description:   spec.Description,
+				RequestCoerce: spec.RequestCoerce,
+			})
+		}
+	}
+	return view
+}
+
+// RegistryServiceDiff 서비스별 BaseURL 비교와 프록시가 실제 사용하는 값
+type RegistryServiceDiff struct {
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	ApiYamlBaseURL   string `json:"apiYamlBaseUrl,omitempty"`
+	RegistryBaseURL  string `json:"registryBaseUrl,omitempty"`
+	EffectiveBaseURL string `json:"effectiveBaseUrl,omitempty"`
+	EffectiveSource  string `json:"effectiveSource,omitempty"` // registry | api.yaml, 프록시 대상이 아니면 비어 있음
+}
+
+// RegistryFieldDiff 필드 값 차이
+type RegistryFieldDiff struct {
+	Field    string `json:"field"`
+	ApiYaml  string `json:"apiYaml"`
+	Registry string `json:"registry"`
+}
+
+// RegistryActionDiff action별 차이 (같은 action은 포함하지 않음)
+type RegistryActionDiff struct {
+	Service     string              `json:"service"`
+	OperationId string              `json:"operationId"`
+	Status      string              `json:"status"`
+	Fields      []RegistryFieldDiff `json:"fields,omitempty"`
+	Effective   *RegistryActionView `json:"effective,omitempty"` // 프록시가 사용하는 값 (config.MergeActionSpec), 프록시 불가면 nil
+}
+
+// RegistryDiffSummary 차이 건수
+type RegistryDiffSummary struct {
+	RegistryOnlyServices int `json:"registryOnlyServices"`
+	ApiYamlOnlyServices  int `json:"apiYamlOnlyServices"`
+	ChangedServices      int `json:"changedServices"`
+	RegistryOnlyActions  int `json:"registryOnlyActions"`
+	ApiYamlOnlyActions   int `json:"apiYamlOnlyActions"`
+	ChangedActions       int `json:"changedActions"`
+}
+
+// RegistryDiff 레지스트리 캐시와 api.yaml 비교 결과
+type RegistryDiff struct {
+	Cache          RegistryCacheStatus   `json:"cache"`
+	CacheInUse     bool                  `json:"cacheInUse"`     // 캐시가 fresh/stale이라 프록시가 사용 중
+	UseRegistryURL bool                  `json:"useRegistryUrl"` // MC_WEB_CONSOLE_USE_REGISTRY_URL (BaseURL override 여부)
+	Summary        RegistryDiffSummary   `json:"summary"`
+	Services       []RegistryServiceDiff `json:"services"`
+	Actions        []RegistryActionDiff  `json:"actions"`
+}
+
+// DiffRegistry 레지스트리 캐시와 api.yaml을 비교한다. 서비스/operation 이름은 대소문자를 무시한다.
+// Description은 표시용이므로 비교하지 않는다.
+func DiffRegistry(spec *config.ApiSpec, rc *RegistryCache, useRegistryURL bool) RegistryDiff {
+	status := rc.Status()
+	inUse := status.State == RegistryStateFresh || status.State == RegistryStateStale
+
+	rc.mu.RLock()
+	regServices, regActions := rc.services, rc.actions
+	rc.mu.RUnlock()
+
+	diff := RegistryDiff{
+		Cache:          status,
+		CacheInUse:     inUse,
+		UseRegistryURL: useRegistryURL,
+		Services:       []RegistryServiceDiff{},
+		Actions:        []RegistryActionDiff{},
+	}
+
+	specServices, specActions := spec.Snapshot()
+	staticServices := lowerKeys(specServices)
+	registryServices := lowerKeys(regServices)
+	staticActionSets := lowerKeys(specActions)
+	registryActionSets := lowerKeys(regActions)
+	for _, key := range unionKeys(staticServices, registryServices, staticActionSets, registryActionSets) {
+		staticName, inStatic := staticServices[key]
+		registryName, inRegistry := registryServices[key]
+		name := firstNonEmpty(staticName, registryName, staticActionSets[key], registryActionSets[key])
+
+		if inStatic || inRegistry {
+			entry := RegistryServiceDiff{Name: name}
+			var staticSvc, registrySvc config.Service
+			if inStatic {
+				staticSvc = specServices[staticName]
+				entry.ApiYamlBaseURL = staticSvc.BaseURL
+			}
+			if inRegistry {
+				registrySvc = regServices[registryName]
+				entry.RegistryBaseURL = registrySvc.BaseURL
+			}
+
+			switch {
+			case !inStatic:
+				entry.Status = RegistryDiffRegistryOnly
+				diff.Summary.RegistryOnlyServices++
+			case !inRegistry:
+				entry.Status = RegistryDiffApiYamlOnly
+				diff.Summary.ApiYamlOnlyServices++
+			case strings.TrimRight(staticSvc.BaseURL, "/") != strings.TrimRight(registrySvc.BaseURL, "/"):
+				entry.Status = RegistryDiffChanged
+				diff.Summary.ChangedServices++
+			default:
+				entry.Status = RegistryDiffSame
+			}
+
+			// 프록시는 api.yaml에 있는 서비스만 대상으로 하고, BaseURL은 USE_REGISTRY_URL일 때만 레지스트리 값을 쓴다
+			if inStatic {
+				entry.EffectiveBaseURL, entry.EffectiveSource = staticSvc.BaseURL, RegistrySourceApiYaml
+				if inUse && useRegistryURL && inRegistry && registrySvc.BaseURL != "" {
+					entry.EffectiveBaseURL, entry.EffectiveSource = registrySvc.BaseURL, RegistrySourceRegistry
+				}
+			}
+			diff.Services = append(diff.Services, entry)
+		}
+
+		diff.Actions = append(diff.Actions, diffServiceActions(name, inStatic, inUse,
+			specActions[staticActionSets[key]], regActions[registryActionSets[key]], &diff.Summary)...)
+	}
+	return diff
+}
+
+// diffServiceActions 한 서비스의 action 비교. proxiable은 api.yaml에 서비스가 있어 프록시 가능한지 여부.
+func diffServiceActions(serviceName string, proxiable, inUse bool, staticActions, registryActions map[string]config.ActionSpec,
+	summary *RegistryDiffSummary) []RegistryActionDiff {
+	staticOps := lowerKeys(staticActions)
+	registryOps := lowerKeys(registryActions)
+
+	var result []RegistryActionDiff
+	for _, key := range unionKeys(staticOps, registryOps) {
+		staticOp, hasStatic := staticOps[key]
+		registryOp, hasRegistry := registryOps[key]
+		entry := RegistryActionDiff{Service: serviceName, OperationId: firstNonEmpty(staticOp, registryOp)}
+
+		var staticSpec, registrySpec *config.ActionSpec
+		if hasStatic {
+			s := staticActions[staticOp]
+			staticSpec = &s
+		}
+		if hasRegistry && !isRegistryManagementAPI(serviceName, registryOp) {
+			s := registryActions[registryOp]
+			registrySpec = &s
+		}
+
+		switch {
+		case staticSpec != nil && registrySpec == nil:
+			if hasRegistry {
+				continue // 레지스트리 관리 API는 항상 api.yaml 사용
+			}
+			entry.Status = RegistryDiffApiYamlOnly
+			summary.ApiYamlOnlyActions++
+		case staticSpec == nil && registrySpec != nil:
+			entry.Status = RegistryDiffRegistryOnly
+			summary.RegistryOnlyActions++
+		case staticSpec != nil && registrySpec != nil:
+			entry.Fields = diffActionFields(staticSpec, registrySpec)
+			if len(entry.Fields) == 0 {
+				continue
+			}
+			entry.Status = RegistryDiffChanged
+			summary.ChangedActions++
+		default:
+			continue
+		}
+
+		if proxiable {
+			effective := staticSpec
+			if inUse && registrySpec != nil {
+				effective = config.MergeActionSpec(staticSpec, registrySpec)
+			}
+			entry.Effective = &RegistryActionView{
+				Service:       serviceName,
+				OperationId:   entry.OperationId,
+				Method:        effective.Method,
+				ResourcePath:  effective.ResourcePath,
+				Description:   effective.Description,
+				RequestCoerce: effective.RequestCoerce,
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func diffActionFields(static, registry *config.ActionSpec) []RegistryFieldDiff {
+	var fields []RegistryFieldDiff
+	if !strings.EqualFold(static.Method, registry.Method) {
+		fields = append(fields, RegistryFieldDiff{Field: "method", ApiYaml: static.Method, Registry: registry.Method})
+	}
+	if static.ResourcePath != registry.ResourcePath {
+		fields = append(fields, RegistryFieldDiff{Field: "resourcePath", ApiYaml: static.ResourcePath, Registry: registry.ResourcePath})
+	}
+	// 레지스트리에 RequestCoerce가 없으면 api.yaml 값이 그대로 쓰이므로 차이로 보지 않는다
+	if a, b := formatCoerce(static.RequestCoerce), formatCoerce(registry.RequestCoerce); b != "" && a != b {
+		fields = append(fields, RegistryFieldDiff{Field: "requestCoerce", ApiYaml: a, Registry: b})
+	}
+	return fields
+}
+
+func formatCoerce(coerce map[string]string) string {
+	parts := make([]string, 0, len(coerce))
+	for _, field := range sortedKeys(coerce) {
+		parts = append(parts, field+"="+coerce[field])
+	}
+	return strings.Join(parts, ",")
+}
+
+// lowerKeys 소문자 키 → 원래 키
+func lowerKeys[V any](m map[string]V) map[string]string {
+	result := make(map[string]string, len(m))
+	for k := range m {
+		result[strings.ToLower(k)] = k
+	}
+	return result
+}
+
+func unionKeys(maps ...map[string]string) []string {
+	set := make(map[string]bool)
+	for _, m := range maps {
+		for k := range m {
+			set[k] = true
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"mc_web_console_api/internal/config"
+)
+
+// RegistrySchemaVersion 지원하는 ListMcmpApisServices 응답 스키마 버전.
+// 응답에 SchemaVersion이 없으면 1로 간주한다.
+const RegistrySchemaVersion = 1
+
+// registryPayloadKeys 스키마 v1 최상위 키
+var registryPayloadKeys = map[string]bool{"SchemaVersion": true, "Services": true, "ServiceActions": true}
+
+// registryServiceV1 Services.<name> 스키마 v1
+type registryServiceV1 struct {
+	Version string `json:"Version"`
+	BaseURL string `json:"BaseURL"`
+	Auth    struct {
+		Type     string `json:"Type"`
+		Username string `json:"Username"`
+		Password string `json:"Password"`
+	} `json:"Auth"`
+}
+
+// registryActionV1 ServiceActions.<name>.<operationId> 스키마 v1
+type registryActionV1 struct {
+	Method        string            `json:"Method"`
+	ResourcePath  string            `json:"ResourcePath"`
+	Description   string            `json:"Description"`
+	RequestCoerce map[string]string `json:"RequestCoerce"`
+}
+
+// RegistryPayloadIssue 스키마 불일치 항목. 해당 서비스/action만 제외되고 나머지는 캐시된다.
+type RegistryPayloadIssue struct {
+	Service     string `json:"service,omitempty"`
+	OperationId string `json:"operationId,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+func (i RegistryPayloadIssue) String() string {
+	switch {
+	case i.OperationId != "":
+		return fmt.Sprintf("service=%s operationId=%s: %s", i.Service, i.OperationId, i.Reason)
+	case i.Service != "":
+		return fmt.Sprintf("service=%s: %s", i.Service, i.Reason)
+	default:
+		return i.Reason
+	}
+}
+
+// parseRegistryPayload ListMcmpApisServices 응답을 스키마 버전에 맞춰 엄격하게 파싱한다.
+//
+// 지원하지 않는 SchemaVersion이거나 Services가 없으면 error (기존 캐시 유지).
+// 알 수 없는 필드, 잘못된 BaseURL/Method/ResourcePath/RequestCoerce가 있는 서비스·action은
+// 제외하고 issues로 반환한다.
+func parseRegistryPayload(responseData interface{}) (map[string]config.Service, map[string]map[string]config.ActionSpec, []RegistryPayloadIssue, error) {
+	b, err := json.Marshal(responseData)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal error: %w", err)
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(b, &top); err != nil {
+		return nil, nil, nil, fmt.Errorf("payload is not an object: %w", err)
+	}
+
+	version := RegistrySchemaVersion
+	if raw, ok := top["SchemaVersion"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid SchemaVersion: %s", raw)
+		}
+	}
+	if version != RegistrySchemaVersion {
+		return nil, nil, nil, fmt.Errorf("unsupported SchemaVersion %d (supported: %d)", version, RegistrySchemaVersion)
+	}
+
+	var issues []RegistryPayloadIssue
+	for _, key := range sortedKeys(top) {
+		if !registryPayloadKeys[key] {
+			issues = append(issues, RegistryPayloadIssue{Reason: fmt.Sprintf("unknown top-level field %q", key)})
+		}
+	}
+
+	var rawServices map[string]json.RawMessage
+	if err := json.Unmarshal(top["Services"], &rawServices); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid Services: %w", err)
+	}
+	if len(rawServices) == 0 {
+		return nil, nil, nil, fmt.Errorf("no Services in payload")
+	}
+	var rawActions map[string]map[string]json.RawMessage
+	if raw, ok := top["ServiceActions"]; ok {
+		if err := json.Unmarshal(raw, &rawActions); err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid ServiceActions: %w", err)
+		}
+	}
+
+	services := make(map[string]config.Service, len(rawServices))
+	for _, name := range sortedKeys(rawServices) {
+		var svc registryServiceV1
+		if err := decodeStrict(rawServices[name], &svc); err != nil {
+			issues = append(issues, RegistryPayloadIssue{Service: name, Reason: err.Error()})
+			continue
+		}
+		if err := validateRegistryBaseURL(svc.BaseURL); err != nil {
+			issues = append(issues, RegistryPayloadIssue{Service: name, Reason: err.Error()})
+			continue
+		}
+		services[name] = config.Service{
+			Version: svc.Version,
+			BaseURL: svc.BaseURL,
+			Auth: config.AuthConfig{
+				Type:     svc.Auth.Type,
+				Username: svc.Auth.Username,
+				Password: svc.Auth.Password,
+			},
+		}
+	}
+
+	actions := make(map[string]map[string]config.ActionSpec, len(rawActions))
+	for _, svcName := range sortedKeys(rawActions) {
+		svcActions := rawActions[svcName]
+		actions[svcName] = make(map[string]config.ActionSpec, len(svcActions))
+		for _, opId := range sortedKeys(svcActions) {
+			var action registryActionV1
+			if err := decodeStrict(svcActions[opId], &action); err != nil {
+				issues = append(issues, RegistryPayloadIssue{Service: svcName, OperationId: opId, Reason: err.Error()})
+				continue
+			}
+			if err := validateRegistryAction(action); err != nil {
+				issues = append(issues, RegistryPayloadIssue{Service: svcName, OperationId: opId, Reason: err.Error()})
+				continue
+			}
+			actions[svcName][opId] = config.ActionSpec{
+				Method:        action.Method,
+				ResourcePath:  action.ResourcePath,
+				Description:   action.Description,
+				RequestCoerce: action.RequestCoerce,
+			}
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, nil, issues, fmt.Errorf("no valid Services in payload")
+	}
+	return services, actions, issues, nil
+}
+
+// decodeStrict 알 수 없는 필드를 허용하지 않고 디코딩
+func decodeStrict(raw json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func validateRegistryBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid BaseURL %q", baseURL)
+	}
+	return nil
+}
+
+func validateRegistryAction(action registryActionV1) error {
+	switch strings.ToUpper(action.Method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+	default:
+		return fmt.Errorf("invalid Method %q", action.Method)
+	}
+	if !strings.HasPrefix(action.ResourcePath, "/") {
+		return fmt.Errorf("invalid ResourcePath %q", action.ResourcePath)
+	}
+	for field, typ := range action.RequestCoerce {
+		if !config.RequestCoerceTypes[typ] {
+			return fmt.Errorf("invalid RequestCoerce type %q for field %q", typ, field)
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// logRegistryPayloadIssues 스키마 불일치 항목 로그
+func logRegistryPayloadIssues(issues []RegistryPayloadIssue) {
+	for _, issue := range issues {
+		log.Printf("[RegistryCache] schema mismatch %s", issue)
+	}
+}
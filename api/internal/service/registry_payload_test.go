@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeRegistryPayload(t *testing.T, payload string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(payload), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestParseRegistryPayload(t *testing.T) {
+	payload := decodeRegistryPayload(t, `{
+		"SchemaVersion": 1,
+		"Services": {
+			"mc-infra-manager": {"Version": "0.12.9", "BaseURL": "http://infra:1323/tumblebug", "Auth": {"Type": "basic", "Username": "u", "Password": "p"}},
+			"mc-extra": {"BaseURL": "http://extra:8080", "Region": "kr"},
+			"mc-bad-url": {"BaseURL": "infra:1323"}
+		},
+		"ServiceActions": {
+			"mc-infra-manager": {
+				"GetAllNs": {"Method": "get", "ResourcePath": "/ns", "RequestCoerce": {"limit": "int"}},
+				"PostNs": {"Method": "post", "ResourcePath": "/ns", "Timeout": 30},
+				"BadMethod": {"Method": "fetch", "ResourcePath": "/ns"},
+				"BadCoerce": {"Method": "get", "ResourcePath": "/ns", "RequestCoerce": {"limit": "uint"}}
+			}
+		},
+		"Extra": true
+	}`)
+
+	services, actions, issues, err := parseRegistryPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services["mc-infra-manager"].BaseURL != "http://infra:1323/tumblebug" ||
+		services["mc-infra-manager"].Auth.Username != "u" {
+		t.Fatalf("services = %+v, want only mc-infra-manager", services)
+	}
+	infra := actions["mc-infra-manager"]
+	if len(infra) != 1 || infra["GetAllNs"].Method != "get" || infra["GetAllNs"].RequestCoerce["limit"] != "int" {
+		t.Fatalf("actions = %+v, want only GetAllNs", infra)
+	}
+
+	// 스키마가 맞지 않는 서비스·action만 제외된다
+	want := []string{
+		`unknown top-level field "Extra"`,
+		"service=mc-bad-url: invalid BaseURL",
+		`service=mc-extra: json: unknown field "Region"`,
+		"service=mc-infra-manager operationId=BadCoerce: invalid RequestCoerce type",
+		"service=mc-infra-manager operationId=BadMethod: invalid Method",
+		`service=mc-infra-manager operationId=PostNs: json: unknown field "Timeout"`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %v, want %d", issues, len(want))
+	}
+	for i, issue := range issues {
+		if !strings.HasPrefix(issue.String(), want[i]) {
+			t.Errorf("issue[%d] = %q, want prefix %q", i, issue, want[i])
+		}
+	}
+}
+
+func TestParseRegistryPayloadRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		wantErr string
+	}{
+		{"unsupported schema version", `{"SchemaVersion": 2, "Services": {"mc-infra-manager": {"BaseURL": "http://infra:1323"}}}`, "unsupported SchemaVersion 2"},
+		{"invalid schema version", `{"SchemaVersion": "v1", "Services": {"mc-infra-manager": {"BaseURL": "http://infra:1323"}}}`, "invalid SchemaVersion"},
+		{"no services", `{"SchemaVersion": 1, "Services": {}}`, "no Services"},
+		{"no valid services", `{"Services": {"mc-infra-manager": {"BaseURL": "http://infra:1323", "Extra": 1}}}`, "no valid Services"},
+		{"not an object", `[]`, "payload is not an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, _, _, err := parseRegistryPayload(decodeRegistryPayload(t, tt.payload))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || services != nil {
+				t.Fatalf("parseRegistryPayload error = %v, services = %v, want %q", err, services, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/pkg/jwt"
+)
+
+// registryTokenSkew 서비스 계정 토큰을 만료 이 시간 전에 재발급
+const registryTokenSkew = 30 * time.Second
+
+// RegistryRefresher mc-iam-manager 서비스 계정으로 ListMcmpApisServices를 주기적으로 호출해 RegistryCache를 갱신하는 백그라운드 작업.
+//
+// 사용자 요청의 토큰에 의존하지 않으므로 캐시가 TTL로 비는 일 없이 유지되고, RegistryCache는
+// TTL 경과 시 Trigger로 즉시 재검증을 요청하면서 기존 값을 제공한다 (stale-while-revalidate).
+// 갱신이 실패하면 마지막 정상 값이 MaxStale까지 계속 사용된다.
+type RegistryRefresher struct {
+	cfg      *config.Config
+	cache    *RegistryCache
+	client   *http.Client
+	interval time.Duration
+	trigger  chan struct{}
+
+	refreshMu sync.Mutex // RefreshNow 직렬화
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	stats       RegistryRefresherStats
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// RegistryRefresherStats 백그라운드 갱신 상태 (GET /api/admin/status → registryRefresher)
+type RegistryRefresherStats struct {
+	Interval      string    `json:"interval"`
+	LastAttemptAt time.Time `json:"lastAttemptAt,omitempty"`
+	LastSuccessAt time.Time `json:"lastSuccessAt,omitempty"`
+	LastError     string    `json:"lastError,omitempty"`
+	Refreshed     int64     `json:"refreshed"`
+	Failed        int64     `json:"failed"`
+}
+
+var registryRefresher *RegistryRefresher
+
+// InitRegistryRefresher RegistryRefresher 생성 후 전역 등록 (관리자 즉시 갱신용). 서비스 계정이 없으면 nil
+func InitRegistryRefresher(cfg *config.Config, cache *RegistryCache) *RegistryRefresher {
+	registryRefresher = NewRegistryRefresher(cfg, cache)
+	return registryRefresher
+}
+
+// GetRegistryRefresher 전역 RegistryRefresher 반환 (미설정이면 nil)
+func GetRegistryRefresher() *RegistryRefresher {
+	return registryRefresher
+}
+
+// NewRegistryRefresher RegistryRefresher 생성. 서비스 계정이 없으면 nil
+func NewRegistryRefresher(cfg *config.Config, cache *RegistryCache) *RegistryRefresher {
+	if cfg.Registry.ServiceUser == "" || cfg.Registry.ServicePassword == "" || cfg.Registry.RefreshInterval <= 0 {
+		return nil
+	}
+	return &RegistryRefresher{
+		cfg:      cfg,
+		cache:    cache,
+		client:   &http.Client{Timeout: 15 * time.Second},
+		interval: cfg.Registry.RefreshInterval,
+		trigger:  make(chan struct{}, 1),
+		stats:    RegistryRefresherStats{Interval: cfg.Registry.RefreshInterval.String()},
+	}
+}
+
+// Start 갱신 goroutine 시작. 시작 직후 1회 실행 후 interval마다, 또는 Trigger 시 반복한다.
+func (r *RegistryRefresher) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	RegisterStatusProvider("registryRefresher", func() interface{} { return r.Stats() })
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.runOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[RegistryRefresher] stopped")
+				return
+			case <-ticker.C:
+				r.runOnce(ctx)
+			case <-r.trigger:
+				r.runOnce(ctx)
+			}
+		}
+	}()
+	log.Printf("[RegistryRefresher] started (interval=%s, user=%s)", r.interval, r.cfg.Registry.ServiceUser)
+}
+
+// Stop 갱신 goroutine 종료 후 진행 중인 실행이 끝날 때까지 대기 (graceful shutdown)
+func (r *RegistryRefresher) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+// Trigger 즉시 갱신 요청 (비동기, 이미 요청이 대기 중이면 무시)
+func (r *RegistryRefresher) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// RefreshNow 레지스트리를 동기로 갱신한다. ctx가 취소되면 진행 중인 mc-iam-manager 호출도 중단된다.
+func (r *RegistryRefresher) RefreshNow(ctx context.Context) error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	r.mu.Lock()
+	r.stats.LastAttemptAt = time.Now()
+	r.mu.Unlock()
+
+	err := r.refresh(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.stats.Failed++
+		r.stats.LastError = err.Error()
+		return err
+	}
+	r.stats.Refreshed++
+	r.stats.LastSuccessAt = time.Now()
+	r.stats.LastError = ""
+	return nil
+}
+
+// Stats 갱신 통계
+func (r *RegistryRefresher) Stats() RegistryRefresherStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+func (r *RegistryRefresher) runOnce(ctx context.Context) {
+	if err := r.RefreshNow(ctx); err != nil {
+		log.Printf("[RegistryRefresher] refresh failed: %v", err)
+	}
+}
+
+func (r *RegistryRefresher) refresh(ctx context.Context) error {
+	svc, action, err := r.cfg.ApiSpec.GetAction("mc-iam-manager", "ListMcmpApisServices")
+	if err != nil {
+		return fmt.Errorf("ListMcmpApisServices not found in api.yaml: %w", err)
+	}
+	token, err := r.serviceToken(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	status, body, err := r.call(ctx, strings.ToUpper(action.Method), svc.BaseURL+action.ResourcePath, token, nil)
+	if err == nil && status == http.StatusUnauthorized {
+		// 서비스 계정 토큰이 폐기되었을 수 있으므로 재발급 후 1회 재시도
+		if token, err = r.serviceToken(ctx, true); err != nil {
+			return err
+		}
+		status, body, err = r.call(ctx, strings.ToUpper(action.Method), svc.BaseURL+action.ResourcePath, token, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("ListMcmpApisServices call failed: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("ListMcmpApisServices returned %d", status)
+	}
+
+	var responseData interface{}
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		return fmt.Errorf("ListMcmpApisServices response parse failed: %w", err)
+	}
+	r.cache.Store(responseData)
+	return nil
+}
+
+// serviceToken 서비스 계정 access token 반환. 만료 임박이거나 force면 mc-iam-manager login으로 재발급한다.
+func (r *RegistryRefresher) serviceToken(ctx context.Context, force bool) (string, error) {
+	r.mu.Lock()
+	token, expiry := r.token, r.tokenExpiry
+	r.mu.Unlock()
+	if !force && token != "" && time.Until(expiry) > registryTokenSkew {
+		return token, nil
+	}
+
+	token, expiry, err := loginServiceAccount(ctx, r.client, r.cfg)
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	r.token, r.tokenExpiry = token, expiry
+	r.mu.Unlock()
+	return token, nil
+}
+
+// loginServiceAccount 서비스 계정(MC_WEB_CONSOLE_REGISTRY_SERVICE_USER/PASSWORD)으로 mc-iam-manager login 후 access token과 만료 시각 반환
+func loginServiceAccount(ctx context.Context, client *http.Client, cfg *config.Config) (string, time.Time, error) {
+	if cfg.Registry.ServiceUser == "" || cfg.Registry.ServicePassword == "" {
+		return "", time.Time{}, fmt.Errorf("service account is not configured (MC_WEB_CONSOLE_REGISTRY_SERVICE_USER, MC_WEB_CONSOLE_REGISTRY_SERVICE_PASSWORD)")
+	}
+	svc, action, err := cfg.ApiSpec.GetAction("mc-iam-manager", "login")
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("MCIAM login config not found: %w", err)
+	}
+	body, _ := json.Marshal(map[string]string{
+		"id":       cfg.Registry.ServiceUser,
+		"password": cfg.Registry.ServicePassword,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, svc.BaseURL+action.ResourcePath, bytes.NewReader(body))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("service account login failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("service account login returned %d", resp.StatusCode)
+	}
+	var login struct {
+		AccessToken string  `json:"access_token"`
+		ExpiresIn   float64 `json:"expires_in"`
+	}
+	if err := json.Unmarshal(respBody, &login); err != nil || login.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("access_token not found in service account login response")
+	}
+
+	expiry := jwt.PeekExpiry(login.AccessToken)
+	if expiry.IsZero() {
+		expiry = time.Now().Add(time.Duration(login.ExpiresIn) * time.Second)
+	}
+	return login.AccessToken, expiry, nil
+}
+
+func (r *RegistryRefresher) call(ctx context.Context, method, url, token string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, respBody, err
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+// newTestRegistryRefresher login이 release가 닫힐 때까지 응답하지 않는 mc-iam-manager를 사용하는 refresher
+func newTestRegistryRefresher(t *testing.T, release chan struct{}) *RegistryRefresher {
+	t.Helper()
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "service-token", "expires_in": 300})
+	}))
+	t.Cleanup(iam.Close)
+	t.Cleanup(func() { close(release) })
+
+	cfg := &config.Config{
+		ApiSpec: &config.ApiSpec{
+			Services: map[string]config.Service{"mc-iam-manager": {BaseURL: iam.URL}},
+			ServiceActions: map[string]map[string]config.ActionSpec{
+				"mc-iam-manager": {
+					"login":                {Method: "post", ResourcePath: "/api/auth/login"},
+					"ListMcmpApisServices": {Method: "get", ResourcePath: "/api/setup/mcmp-apis"},
+				},
+			},
+		},
+	}
+	cfg.Registry.ServiceUser = "svc"
+	cfg.Registry.ServicePassword = "secret"
+	cfg.Registry.RefreshInterval = time.Minute
+	return NewRegistryRefresher(cfg, NewRegistryCache(time.Minute))
+}
+
+func TestRegistryRefresherHonorsContext(t *testing.T) {
+	refresher := newTestRegistryRefresher(t, make(chan struct{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := refresher.RefreshNow(ctx)
+	if err == nil {
+		t.Fatal("RefreshNow succeeded while mc-iam-manager was hanging")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("RefreshNow returned after %s, want it to stop with the context", elapsed)
+	}
+	if got := refresher.Stats().Failed; got != 1 {
+		t.Fatalf("Failed = %d, want 1", got)
+	}
+}
+
+func TestRegistryCacheLogsExpiryOnce(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	cache := NewRegistryCache(time.Minute)
+	cache.Store(map[string]interface{}{
+		"Services": map[string]interface{}{
+			"mc-infra-manager": map[string]interface{}{"BaseURL": "http://mc-infra-manager:1323"},
+		},
+	})
+	cache.mu.Lock()
+	cache.storedAt = time.Now().Add(-2 * time.Minute)
+	cache.mu.Unlock()
+
+	for i := 0; i < 3; i++ {
+		if url := cache.GetBaseURL("mc-infra-manager", "GetAllNs"); url != "" {
+			t.Fatalf("GetBaseURL = %q from an expired cache", url)
+		}
+	}
+	if got := strings.Count(buf.String(), "TTL expired"); got != 1 {
+		t.Fatalf("TTL expired logged %d times, want 1", got)
+	}
+
+	// 재적재 후 다시 만료되면 한 번 더 남긴다
+	cache.Store(map[string]interface{}{
+		"Services": map[string]interface{}{
+			"mc-infra-manager": map[string]interface{}{"BaseURL": "http://mc-infra-manager:1323"},
+		},
+	})
+	cache.mu.Lock()
+	cache.storedAt = time.Now().Add(-2 * time.Minute)
+	cache.mu.Unlock()
+	cache.GetBaseURL("mc-infra-manager", "GetAllNs")
+	cache.GetBaseURL("mc-infra-manager", "GetAllNs")
+	if got := strings.Count(buf.String(), "TTL expired"); got != 2 {
+		t.Fatalf("TTL expired logged %d times after reload, want 2", got)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// registrySnapshotName registry_snapshots 테이블의 ListMcmpApisServices 스냅샷 키
+const registrySnapshotName = "mcmp-apis-services"
+
+// RegistrySnapshotStore 마지막 정상 레지스트리 응답 보관소 (재시작 시 복원)
+type RegistrySnapshotStore interface {
+	// Kind 보관 위치 종류 (db | file)
+	Kind() string
+	// Save payload(ListMcmpApisServices 응답 JSON) 저장
+	Save(payload []byte, storedAt time.Time) error
+	// Load 저장된 스냅샷 반환. 없으면 (nil, nil)
+	Load() (*model.RegistrySnapshot, error)
+}
+
+// NewRegistrySnapshotStore MC_WEB_CONSOLE_REGISTRY_PERSIST 값으로 보관소 생성.
+// "db"는 Postgres(registry_snapshots), 그 외 값은 파일 경로, 비어 있거나 DB 없이 "db"이면 nil.
+func NewRegistrySnapshotStore(persist string, db *gorm.DB) RegistrySnapshotStore {
+	switch persist {
+	case "":
+		return nil
+	case "db":
+		if db == nil {
+			return nil
+		}
+		return &dbRegistrySnapshotStore{repo: repository.NewRegistrySnapshotRepository(db)}
+	default:
+		return &fileRegistrySnapshotStore{path: persist}
+	}
+}
+
+type dbRegistrySnapshotStore struct {
+	repo *repository.RegistrySnapshotRepository
+}
+
+func (s *dbRegistrySnapshotStore) Kind() string { return "db" }
+
+func (s *dbRegistrySnapshotStore) Save(payload []byte, storedAt time.Time) error {
+	return s.repo.Save(&model.RegistrySnapshot{Name: registrySnapshotName, Payload: string(payload), StoredAt: storedAt})
+}
+
+func (s *dbRegistrySnapshotStore) Load() (*model.RegistrySnapshot, error) {
+	snapshot, err := s.repo.Find(registrySnapshotName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// fileRegistrySnapshotStore JSON 파일 보관소. 임시 파일에 쓴 뒤 rename하여 중간 상태 파일이 남지 않게 한다.
+type fileRegistrySnapshotStore struct {
+	path string
+}
+
+func (s *fileRegistrySnapshotStore) Kind() string { return "file" }
+
+func (s *fileRegistrySnapshotStore) Save(payload []byte, storedAt time.Time) error {
+	data, err := json.Marshal(model.RegistrySnapshot{Name: registrySnapshotName, Payload: string(payload), StoredAt: storedAt})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileRegistrySnapshotStore) Load() (*model.RegistrySnapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot model.RegistrySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid registry snapshot %s: %w", s.path, err)
+	}
+	return &snapshot, nil
+}
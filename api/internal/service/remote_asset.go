@@ -0,0 +1,643 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	ErrRemoteAssetUnknown       = errors.New("unknown remote asset")
+	ErrRemoteAssetNotConfigured = errors.New("remote asset url is not configured")
+	ErrRemoteAssetHistoryToken  = errors.New("MC_WEB_CONSOLE_REMOTE_ASSET_GITHUB_TOKEN is required to locate the local copy in the commit history")
+)
+
+const (
+	remoteAssetUserAgent   = "mc-web-console-bff/remote-asset"
+	remoteAssetMaxBytes    = 16 << 20 // 원격 파일 최대 크기
+	remoteAssetPreviewSize = 20       // diff 미리보기 줄 수
+	githubRawHost          = "raw.githubusercontent.com"
+	githubAPIBase          = "https://api.github.com"
+	githubHistoryMaxDepth  = 100              // GraphQL history(first:) 상한
+	remoteAssetVersionTTL  = 10 * time.Minute // 이력 계산 결과(실패 포함) 재사용 시간
+)
+
+// githubHistoryQuery 경로의 커밋 이력과 각 커밋 시점의 파일 blob SHA
+const githubHistoryQuery = `query($owner: String!, $name: String!, $ref: String!, $path: String!, $depth: Int!) {
+  repository(owner: $owner, name: $name) {
+    object(expression: $ref) {
+      ... on Commit {
+        history(first: $depth, path: $path) {
+          nodes { oid committedDate file(path: $path) { oid } }
+        }
+      }
+    }
+  }
+}`
+
+// RemoteAssetChecker 원격 기준 파일(메뉴/API yaml, metainfo, 권한 CSV)의 도달성, 문법, 로컬 conf/ 사본과의 차이를 점검한다.
+//
+// 원격 본문은 ETag/Last-Modified 조건부 요청으로 캐시하고,
+// raw.githubusercontent.com 자산은 GitHub 커밋 이력(GraphQL, 토큰 필요)에서 로컬 사본이 몇 버전 뒤처졌는지 계산한다.
+type RemoteAssetChecker struct {
+	cfg     config.RemoteAssetsConfig
+	client  *http.Client
+	apiBase string
+
+	mu       sync.Mutex
+	bodies   map[string]*remoteAssetBody    // name → 마지막으로 받은 원격 본문
+	versions map[string]*RemoteAssetVersion // name → 이력 계산 결과 (upstream/local blob이 같으면 재사용)
+}
+
+// RemoteAssetProbe 원격 URL 도달성과 메타정보
+type RemoteAssetProbe struct {
+	URL           string `json:"url"`                     // 검사 대상 raw URL
+	Reachable     bool   `json:"reachable"`               // 200~299 (조건부 요청이면 304 포함) 응답 여부
+	HTTPStatus    int    `json:"httpStatus"`              // 실제 HTTP status
+	LastModified  string `json:"lastModified,omitempty"`  // RFC1123
+	ETag          string `json:"etag,omitempty"`          // 원본 ETag (양 끝 따옴표 포함)
+	ContentLength int64  `json:"contentLength,omitempty"` // -1 = 알 수 없음
+	CheckedAt     string `json:"checkedAt"`               // RFC3339
+	ErrorMessage  string `json:"errorMessage,omitempty"`  // 네트워크/타임아웃 에러
+}
+
+// RemoteAssetReport 원격 자산 점검 결과
+type RemoteAssetReport struct {
+	Name      string `json:"name"`
+	Format    string `json:"format"` // yaml | csv
+	LocalPath string `json:"localPath"`
+	RemoteAssetProbe
+	FromCache       bool                `json:"fromCache"` // 304 Not Modified로 캐시 본문 사용
+	Valid           bool                `json:"valid"`     // 원격 본문 문법 검사 통과
+	ValidationError string              `json:"validationError,omitempty"`
+	Diff            *RemoteAssetDiff    `json:"diff,omitempty"`
+	Version         *RemoteAssetVersion `json:"version,omitempty"`
+}
+
+// RemoteAssetDiff 로컬 사본과 원격 본문의 줄 단위 차이 (줄 순서 변경은 차이로 보지 않음)
+type RemoteAssetDiff struct {
+	LocalExists bool     `json:"localExists"`
+	Identical   bool     `json:"identical"`
+	LocalSHA    string   `json:"localSha,omitempty"`    // git blob SHA
+	UpstreamSHA string   `json:"upstreamSha,omitempty"` // git blob SHA
+	Added       int      `json:"added"`                 // 원격에만 있는 줄
+	Removed     int      `json:"removed"`               // 로컬에만 있는 줄
+	Preview     []string `json:"preview,omitempty"`     // "+ line" / "- line"
+	Error       string   `json:"error,omitempty"`
+}
+
+// RemoteAssetVersion GitHub 커밋 이력 기준 로컬 사본의 버전 차이
+type RemoteAssetVersion struct {
+	Supported    bool      `json:"supported"`              // raw.githubusercontent.com URL 여부
+	Behind       int       `json:"behind"`                 // 로컬이 뒤처진 커밋 수, -1 = 이력에서 찾지 못함
+	Searched     int       `json:"searched"`               // 비교한 커밋 수
+	LocalCommit  string    `json:"localCommit,omitempty"`  // 로컬 사본과 내용이 같은 커밋
+	LatestCommit string    `json:"latestCommit,omitempty"` // 해당 경로의 최신 커밋
+	LatestDate   time.Time `json:"latestDate,omitempty"`
+	Error        string    `json:"error,omitempty"`
+
+	key       string    // upstream/local blob SHA
+	checkedAt time.Time // 계산 시각 (remoteAssetVersionTTL 기준)
+}
+
+type remoteAssetBody struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+var remoteAssetChecker *RemoteAssetChecker
+
+// InitRemoteAssetChecker RemoteAssetChecker 생성 후 전역 등록
+func InitRemoteAssetChecker(cfg config.RemoteAssetsConfig) *RemoteAssetChecker {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.HistoryDepth <= 0 {
+		cfg.HistoryDepth = 20
+	}
+	remoteAssetChecker = &RemoteAssetChecker{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		apiBase:  githubAPIBase,
+		bodies:   make(map[string]*remoteAssetBody),
+		versions: make(map[string]*RemoteAssetVersion),
+	}
+	return remoteAssetChecker
+}
+
+// GetRemoteAssetChecker 전역 RemoteAssetChecker (미초기화 시 nil)
+func GetRemoteAssetChecker() *RemoteAssetChecker {
+	return remoteAssetChecker
+}
+
+// Assets 설정된 원격 자산 목록
+func (c *RemoteAssetChecker) Assets() []config.RemoteAsset {
+	return c.cfg.Assets
+}
+
+// Asset 이름(대소문자 무시)으로 원격 자산 조회
+func (c *RemoteAssetChecker) Asset(name string) (config.RemoteAsset, error) {
+	for _, asset := range c.cfg.Assets {
+		if strings.EqualFold(asset.Name, name) {
+			if asset.URL == "" {
+				return asset, ErrRemoteAssetNotConfigured
+			}
+			return asset, nil
+		}
+	}
+	return config.RemoteAsset{}, ErrRemoteAssetUnknown
+}
+
+// Probe 원격 자산 도달성만 확인 (본문을 받지 않음)
+func (c *RemoteAssetChecker) Probe(name string) (RemoteAssetProbe, error) {
+	asset, err := c.Asset(name)
+	if err != nil {
+		return RemoteAssetProbe{}, err
+	}
+	return ProbeRemoteURL(c.client, asset.URL), nil
+}
+
+// CheckAll 설정된 모든 원격 자산 점검 (URL 미설정 자산은 reachable=false로 포함)
+func (c *RemoteAssetChecker) CheckAll(ctx context.Context) []RemoteAssetReport {
+	reports := make([]RemoteAssetReport, len(c.cfg.Assets))
+	var wg sync.WaitGroup
+	for i, asset := range c.cfg.Assets {
+		if asset.URL == "" {
+			reports[i] = RemoteAssetReport{Name: asset.Name, Format: asset.Format, LocalPath: asset.LocalPath,
+				RemoteAssetProbe: RemoteAssetProbe{ContentLength: -1, ErrorMessage: ErrRemoteAssetNotConfigured.Error()}}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, asset config.RemoteAsset) {
+			defer wg.Done()
+			reports[i] = c.check(ctx, asset)
+		}(i, asset)
+	}
+	wg.Wait()
+	return reports
+}
+
+// Check 원격 자산 하나를 받아 문법 검사, 로컬 사본 비교, 버전 차이 계산
+func (c *RemoteAssetChecker) Check(ctx context.Context, name string) (*RemoteAssetReport, error) {
+	asset, err := c.Asset(name)
+	if err != nil {
+		return nil, err
+	}
+	report := c.check(ctx, asset)
+	return &report, nil
+}
+
+func (c *RemoteAssetChecker) check(ctx context.Context, asset config.RemoteAsset) RemoteAssetReport {
+	report := RemoteAssetReport{
+		Name:      asset.Name,
+		Format:    asset.Format,
+		LocalPath: asset.LocalPath,
+		RemoteAssetProbe: RemoteAssetProbe{
+			URL:           asset.URL,
+			ContentLength: -1,
+			CheckedAt:     time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	body, err := c.fetch(ctx, asset, &report)
+	if err != nil {
+		report.ErrorMessage = err.Error()
+		return report
+	}
+
+	if err := validateRemoteAsset(asset.Format, body); err != nil {
+		report.ValidationError = err.Error()
+	} else {
+		report.Valid = true
+	}
+
+	local, err := os.ReadFile(asset.LocalPath)
+	if err != nil {
+		report.Diff = &RemoteAssetDiff{Error: err.Error(), UpstreamSHA: gitBlobSHA(body)}
+		return report
+	}
+	report.Diff = diffRemoteAsset(local, body)
+	if !report.Diff.Identical {
+		report.Version = c.version(ctx, asset, report.Diff.LocalSHA, report.Diff.UpstreamSHA)
+	} else {
+		report.Version = &RemoteAssetVersion{Supported: isGithubRawURL(asset.URL)}
+	}
+	return report
+}
+
+// fetch 조건부 GET으로 원격 본문을 받는다. 304면 캐시 본문을 사용한다.
+func (c *RemoteAssetChecker) fetch(ctx context.Context, asset config.RemoteAsset, report *RemoteAssetReport) ([]byte, error) {
+	c.mu.Lock()
+	cached := c.bodies[asset.Name]
+	c.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", remoteAssetUserAgent)
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	fillProbeFromResponse(&report.RemoteAssetProbe, resp)
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		report.Reachable = true
+		report.FromCache = true
+		report.ContentLength = int64(len(cached.body))
+		if report.ETag == "" {
+			report.ETag = cached.etag
+		}
+		if report.LastModified == "" {
+			report.LastModified = cached.lastModified
+		}
+		return cached.body, nil
+	}
+	if !report.Reachable {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, remoteAssetMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > remoteAssetMaxBytes {
+		return nil, fmt.Errorf("remote asset is larger than %d bytes", remoteAssetMaxBytes)
+	}
+	report.ContentLength = int64(len(body))
+
+	c.mu.Lock()
+	c.bodies[asset.Name] = &remoteAssetBody{etag: report.ETag, lastModified: report.LastModified, body: body}
+	c.mu.Unlock()
+	return body, nil
+}
+
+// version 로컬 사본이 원격 이력의 몇 번째 이전 버전과 같은지 계산.
+// 같은 upstream/local blob의 결과는 성공·실패 모두 remoteAssetVersionTTL 동안 재사용한다 (GitHub 레이트 리밋 보호).
+func (c *RemoteAssetChecker) version(ctx context.Context, asset config.RemoteAsset, localSHA, upstreamSHA string) *RemoteAssetVersion {
+	owner, repo, ref, path, ok := parseGithubRawURL(asset.URL)
+	if !ok {
+		return &RemoteAssetVersion{Behind: -1}
+	}
+	key := localSHA + ":" + upstreamSHA
+	c.mu.Lock()
+	cached := c.versions[asset.Name]
+	c.mu.Unlock()
+	if cached != nil && cached.key == key && time.Since(cached.checkedAt) < remoteAssetVersionTTL {
+		return cached
+	}
+
+	result := &RemoteAssetVersion{Supported: true, Behind: -1, key: key, checkedAt: time.Now()}
+	if c.cfg.GithubToken == "" {
+		// GraphQL API는 인증이 필요하므로 최신 커밋만 조회한다
+		c.latestCommit(ctx, owner, repo, ref, path, result)
+		result.Error = ErrRemoteAssetHistoryToken.Error()
+	} else {
+		c.history(ctx, owner, repo, ref, path, localSHA, result)
+	}
+
+	c.mu.Lock()
+	c.versions[asset.Name] = result
+	c.mu.Unlock()
+	return result
+}
+
+// history 경로의 커밋 이력과 커밋별 blob SHA를 GraphQL 한 번으로 받아 로컬 blob SHA와 비교
+func (c *RemoteAssetChecker) history(ctx context.Context, owner, repo, ref, path, localSHA string, result *RemoteAssetVersion) {
+	depth := c.cfg.HistoryDepth
+	if depth > githubHistoryMaxDepth {
+		depth = githubHistoryMaxDepth
+	}
+	request := map[string]interface{}{
+		"query": githubHistoryQuery,
+		"variables": map[string]interface{}{
+			"owner": owner, "name": repo, "ref": ref, "path": path, "depth": depth,
+		},
+	}
+	var response struct {
+		Data struct {
+			Repository *struct {
+				Object *struct {
+					History struct {
+						Nodes []struct {
+							OID           string    `json:"oid"`
+							CommittedDate time.Time `json:"committedDate"`
+							File          *struct {
+								OID string `json:"oid"`
+							} `json:"file"`
+						} `json:"nodes"`
+					} `json:"history"`
+				} `json:"object"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := c.githubDo(ctx, http.MethodPost, "/graphql", request, &response); err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if len(response.Errors) > 0 {
+		result.Error = "github graphql: " + response.Errors[0].Message
+		return
+	}
+	if response.Data.Repository == nil || response.Data.Repository.Object == nil {
+		result.Error = fmt.Sprintf("github graphql: %s/%s@%s not found", owner, repo, ref)
+		return
+	}
+	nodes := response.Data.Repository.Object.History.Nodes
+	if len(nodes) > 0 {
+		result.LatestCommit = nodes[0].OID
+		result.LatestDate = nodes[0].CommittedDate
+	}
+	for i, node := range nodes {
+		result.Searched = i + 1
+		if node.File != nil && node.File.OID == localSHA {
+			result.Behind = i
+			result.LocalCommit = node.OID
+			return
+		}
+	}
+}
+
+// latestCommit REST commits API로 경로의 최신 커밋만 조회 (토큰 없이 이력 비교를 못 할 때)
+func (c *RemoteAssetChecker) latestCommit(ctx context.Context, owner, repo, ref, path string, result *RemoteAssetVersion) {
+	var commits []struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	query := url.Values{"sha": {ref}, "path": {path}, "per_page": {"1"}}
+	if err := c.githubDo(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/commits?%s", owner, repo, query.Encode()), nil, &commits); err != nil {
+		return
+	}
+	if len(commits) > 0 {
+		result.LatestCommit = commits[0].SHA
+		result.LatestDate = commits[0].Commit.Committer.Date
+	}
+}
+
+func (c *RemoteAssetChecker) githubDo(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.apiBase+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", remoteAssetUserAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.GithubToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.GithubToken)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github api %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ProbeRemoteURL HEAD를 1차 시도하고, 405/501 또는 HEAD 실패 시 GET Range:0-0로 fallback.
+// 어떤 실패가 발생해도 결과를 반환한다 (errorMessage에 사유 기재).
+func ProbeRemoteURL(client *http.Client, rawURL string) RemoteAssetProbe {
+	result := RemoteAssetProbe{
+		URL:           rawURL,
+		ContentLength: -1,
+		CheckedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+
+	// 1) HEAD 시도
+	headResp, headErr := doRemoteAssetRequest(client, http.MethodHead, rawURL, nil)
+	if headErr == nil {
+		fillProbeFromResponse(&result, headResp)
+		_ = headResp.Body.Close()
+		// HEAD가 명시적으로 거부된 경우에만 GET fallback 시도
+		if result.HTTPStatus != http.StatusMethodNotAllowed && result.HTTPStatus != http.StatusNotImplemented {
+			return result
+		}
+	}
+
+	// 2) GET Range:0-0 fallback (HEAD 실패/거부 시)
+	getResp, getErr := doRemoteAssetRequest(client, http.MethodGet, rawURL, map[string]string{
+		"Range": "bytes=0-0",
+	})
+	if getErr != nil {
+		if headErr != nil {
+			result.ErrorMessage = fmt.Sprintf("HEAD failed: %v; GET fallback failed: %v", headErr, getErr)
+		} else {
+			result.ErrorMessage = fmt.Sprintf("GET fallback failed: %v", getErr)
+		}
+		return result
+	}
+	defer getResp.Body.Close()
+	// Range 응답은 보통 206 Partial Content; 일부 서버는 200 그대로 반환
+	fillProbeFromResponse(&result, getResp)
+	// body 일부 소비 (connection 재활용 — Range 1바이트라 비용 무시)
+	_, _ = io.CopyN(io.Discard, getResp.Body, 1)
+	return result
+}
+
+// doRemoteAssetRequest 단일 HTTP 요청을 보내고 응답 또는 에러 반환. 호출자가 Body.Close 책임.
+func doRemoteAssetRequest(client *http.Client, method, rawURL string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", remoteAssetUserAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return client.Do(req)
+}
+
+// fillProbeFromResponse HTTP 응답에서 메타데이터 추출. 200~299만 reachable=true.
+func fillProbeFromResponse(result *RemoteAssetProbe, resp *http.Response) {
+	result.HTTPStatus = resp.StatusCode
+	result.Reachable = resp.StatusCode >= 200 && resp.StatusCode < 300
+
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		result.LastModified = lm
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		result.ETag = etag
+	}
+	// HEAD나 일반 GET의 Content-Length 활용 (Range 206은 Content-Range에서 파싱)
+	if cl := resp.Header.Get("Content-Length"); cl != "" && resp.StatusCode != http.StatusPartialContent {
+		if n, err := strconv.ParseInt(cl, 10, 64); err == nil {
+			result.ContentLength = n
+		}
+	}
+	// Content-Range 헤더 형식: "bytes 0-0/12345" → 슬래시 뒤 전체 크기 파싱
+	if cr := resp.Header.Get("Content-Range"); cr != "" {
+		if idx := strings.LastIndex(cr, "/"); idx >= 0 && idx+1 < len(cr) {
+			if n, err := strconv.ParseInt(strings.TrimSpace(cr[idx+1:]), 10, 64); err == nil {
+				result.ContentLength = n
+			}
+		}
+	}
+}
+
+// validateRemoteAsset yaml은 최상위 매핑, csv는 모든 행의 열 수가 같아야 한다 ('#' 주석 줄 허용)
+func validateRemoteAsset(format string, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return fmt.Errorf("empty document")
+	}
+	switch format {
+	case "csv":
+		reader := csv.NewReader(bytes.NewReader(body))
+		reader.Comment = '#'
+		records, err := reader.ReadAll()
+		if err != nil {
+			return err
+		}
+		if len(records) < 2 {
+			return fmt.Errorf("csv has no data rows")
+		}
+	default:
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal(body, &doc); err != nil {
+			return err
+		}
+		if len(doc) == 0 {
+			return fmt.Errorf("yaml has no top-level keys")
+		}
+	}
+	return nil
+}
+
+// diffRemoteAsset 줄 multiset 비교. 원격에만 있는 줄은 added, 로컬에만 있는 줄은 removed.
+func diffRemoteAsset(local, upstream []byte) *RemoteAssetDiff {
+	diff := &RemoteAssetDiff{
+		LocalExists: true,
+		LocalSHA:    gitBlobSHA(local),
+		UpstreamSHA: gitBlobSHA(upstream),
+	}
+	if diff.LocalSHA == diff.UpstreamSHA {
+		diff.Identical = true
+		return diff
+	}
+	localLines := splitAssetLines(local)
+	upstreamLines := splitAssetLines(upstream)
+
+	removed := unmatchedLines(localLines, upstreamLines)
+	added := unmatchedLines(upstreamLines, localLines)
+	diff.Added = len(added)
+	diff.Removed = len(removed)
+	for _, line := range removed {
+		if len(diff.Preview) >= remoteAssetPreviewSize {
+			break
+		}
+		diff.Preview = append(diff.Preview, "- "+line)
+	}
+	for _, line := range added {
+		if len(diff.Preview) >= remoteAssetPreviewSize {
+			break
+		}
+		diff.Preview = append(diff.Preview, "+ "+line)
+	}
+	// 줄 끝/공백만 다른 경우
+	diff.Identical = diff.Added == 0 && diff.Removed == 0
+	return diff
+}
+
+// unmatchedLines lines 중 other에 대응하는 줄이 없는 것 (순서 유지)
+func unmatchedLines(lines, other []string) []string {
+	counts := make(map[string]int, len(other))
+	for _, line := range other {
+		counts[line]++
+	}
+	var out []string
+	for _, line := range lines {
+		if counts[line] > 0 {
+			counts[line]--
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func splitAssetLines(body []byte) []string {
+	text := strings.ReplaceAll(string(body), "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimRight(line, " \t"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// gitBlobSHA git이 파일 내용에 부여하는 blob SHA-1 (GitHub 트리 항목의 oid와 비교)
+func gitBlobSHA(body []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(body))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isGithubRawURL(rawURL string) bool {
+	_, _, _, _, ok := parseGithubRawURL(rawURL)
+	return ok
+}
+
+// parseGithubRawURL https://raw.githubusercontent.com/{owner}/{repo}/[refs/heads/|refs/tags/]{ref}/{path}
+func parseGithubRawURL(rawURL string) (owner, repo, ref, path string, ok bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Host, githubRawHost) {
+		return "", "", "", "", false
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 4 {
+		return "", "", "", "", false
+	}
+	owner, repo, rest := segments[0], segments[1], segments[2:]
+	if rest[0] == "refs" && len(rest) >= 4 && (rest[1] == "heads" || rest[1] == "tags") {
+		rest = rest[2:]
+	}
+	return owner, repo, rest[0], strings.Join(rest[1:], "/"), true
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+)
+
+const testRemoteAssetURL = "https://raw.githubusercontent.com/m-cmp/mc-web-console/refs/heads/main/conf/api.yaml"
+
+// newTestGithubAPI 커밋 3개(최신순)의 이력을 GraphQL로 응답하는 GitHub API. status가 200이 아니면 그 상태로 실패한다.
+func newTestGithubAPI(t *testing.T, status int, blobs []string) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("unexpected github call %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var request struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decode graphql request: %v", err)
+		}
+		if request.Variables["path"] != "conf/api.yaml" || request.Variables["ref"] != "main" {
+			t.Errorf("variables = %v", request.Variables)
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		nodes := make([]map[string]interface{}, len(blobs))
+		for i, blob := range blobs {
+			nodes[i] = map[string]interface{}{
+				"oid":           fmt.Sprintf("commit%d", i),
+				"committedDate": time.Date(2026, 1, 10-i, 0, 0, 0, 0, time.UTC),
+				"file":          map[string]string{"oid": blob},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"repository": map[string]interface{}{"object": map[string]interface{}{
+				"history": map[string]interface{}{"nodes": nodes},
+			}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestRemoteAssetChecker(apiBase, token string) *RemoteAssetChecker {
+	checker := InitRemoteAssetChecker(config.RemoteAssetsConfig{GithubToken: token})
+	checker.apiBase = apiBase
+	return checker
+}
+
+func TestRemoteAssetVersionSingleCall(t *testing.T) {
+	server, calls := newTestGithubAPI(t, http.StatusOK, []string{"blob-new", "blob-mid", "blob-local"})
+	checker := newTestRemoteAssetChecker(server.URL, "test-token")
+	asset := config.RemoteAsset{Name: "api", URL: testRemoteAssetURL}
+
+	version := checker.version(context.Background(), asset, "blob-local", "blob-new")
+	if version.Error != "" {
+		t.Fatalf("Error = %q", version.Error)
+	}
+	if version.Behind != 2 || version.LocalCommit != "commit2" || version.Searched != 3 {
+		t.Fatalf("version = %+v, want behind 2 at commit2", version)
+	}
+	if version.LatestCommit != "commit0" {
+		t.Fatalf("LatestCommit = %q", version.LatestCommit)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("github calls = %d, want 1", got)
+	}
+
+	checker.version(context.Background(), asset, "blob-local", "blob-new")
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("github calls after cached lookup = %d, want 1", got)
+	}
+}
+
+func TestRemoteAssetVersionNotFound(t *testing.T) {
+	server, _ := newTestGithubAPI(t, http.StatusOK, []string{"blob-new", "blob-old"})
+	checker := newTestRemoteAssetChecker(server.URL, "test-token")
+
+	version := checker.version(context.Background(), config.RemoteAsset{Name: "api", URL: testRemoteAssetURL}, "blob-edited", "blob-new")
+	if version.Behind != -1 || version.Searched != 2 || version.LocalCommit != "" {
+		t.Fatalf("version = %+v, want behind -1 after 2 commits", version)
+	}
+}
+
+func TestRemoteAssetVersionCachesFailure(t *testing.T) {
+	server, calls := newTestGithubAPI(t, http.StatusForbidden, nil)
+	checker := newTestRemoteAssetChecker(server.URL, "test-token")
+	asset := config.RemoteAsset{Name: "api", URL: testRemoteAssetURL}
+
+	for i := 0; i < 3; i++ {
+		version := checker.version(context.Background(), asset, "blob-local", "blob-new")
+		if version.Error == "" || version.Behind != -1 {
+			t.Fatalf("version = %+v, want error", version)
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("github calls = %d, want 1 (failure cached)", got)
+	}
+
+	// TTL이 지나면 다시 조회한다
+	checker.mu.Lock()
+	checker.versions["api"].checkedAt = time.Now().Add(-remoteAssetVersionTTL)
+	checker.mu.Unlock()
+	checker.version(context.Background(), asset, "blob-local", "blob-new")
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("github calls after TTL = %d, want 2", got)
+	}
+}
+
+func TestRemoteAssetVersionRequiresToken(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != "/repos/m-cmp/mc-web-console/commits" || r.URL.Query().Get("per_page") != "1" {
+			t.Errorf("unexpected github call %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Authorization sent without a token")
+		}
+		_, _ = w.Write([]byte(`[{"sha":"latest","commit":{"committer":{"date":"2026-01-10T00:00:00Z"}}}]`))
+	}))
+	defer server.Close()
+	checker := newTestRemoteAssetChecker(server.URL, "")
+
+	version := checker.version(context.Background(), config.RemoteAsset{Name: "api", URL: testRemoteAssetURL}, "blob-local", "blob-new")
+	if version.Error != ErrRemoteAssetHistoryToken.Error() || version.Behind != -1 {
+		t.Fatalf("version = %+v, want token error", version)
+	}
+	if version.LatestCommit != "latest" {
+		t.Fatalf("LatestCommit = %q", version.LatestCommit)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("github calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserScope 사용자가 속한 workspace/project와 project에 연결된 namespace(nsId) 집합.
+// 모든 키는 소문자로 정규화한다.
+type UserScope struct {
+	Workspaces map[string]bool `json:"workspaces"` // workspace id/name
+	Projects   map[string]bool `json:"projects"`   // project id/name
+	Namespaces map[string]bool `json:"namespaces"` // project nsId (Tumblebug namespace)
+	FetchedAt  time.Time       `json:"fetchedAt"`
+}
+
+// AllowsNamespace nsId가 사용자 project의 namespace인지 확인
+func (s *UserScope) AllowsNamespace(nsID string) bool {
+	return s.Namespaces[strings.ToLower(nsID)]
+}
+
+// AllowsWorkspace workspace id/name이 사용자 소속인지 확인
+func (s *UserScope) AllowsWorkspace(workspace string) bool {
+	return s.Workspaces[strings.ToLower(workspace)]
+}
+
+// AllowsProject project id/name이 사용자 소속인지 확인
+func (s *UserScope) AllowsProject(project string) bool {
+	return s.Projects[strings.ToLower(project)]
+}
+
+// BuildUserScope mc-iam-manager listUserWorkspaces 응답에서 범위를 추출한다.
+// 응답 구조 변화에 견디도록 JSON 트리를 순회하며, "projects" 배열 안의 객체는 project로,
+// project를 담은 객체는 workspace로 본다. nsid/nsId/NsId/ns_id 필드는 namespace로 수집한다.
+func BuildUserScope(responseData interface{}) *UserScope {
+	scope := &UserScope{
+		Workspaces: make(map[string]bool),
+		Projects:   make(map[string]bool),
+		Namespaces: make(map[string]bool),
+		FetchedAt:  time.Now(),
+	}
+	collectScope(responseData, scope, false)
+	return scope
+}
+
+func collectScope(node interface{}, scope *UserScope, inProjects bool) {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			collectScope(item, scope, inProjects)
+		}
+	case map[string]interface{}:
+		var projects interface{}
+		for key, val := range v {
+			if strings.EqualFold(key, "projects") {
+				projects = val
+			}
+		}
+		if inProjects {
+			addScopeKeys(scope.Projects, v, "id", "name")
+			addScopeKeys(scope.Namespaces, v, "nsid", "ns_id")
+		} else if projects != nil {
+			addScopeKeys(scope.Workspaces, v, "id", "name")
+		}
+		for key, val := range v {
+			if strings.EqualFold(key, "projects") {
+				collectScope(val, scope, true)
+			} else {
+				collectScope(val, scope, false)
+			}
+		}
+	}
+}
+
+// addScopeKeys 객체에서 이름이 일치하는(대소문자 무시) 문자열/숫자 필드 값을 집합에 추가
+func addScopeKeys(set map[string]bool, obj map[string]interface{}, names ...string) {
+	for key, val := range obj {
+		for _, name := range names {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			switch x := val.(type) {
+			case string:
+				if x != "" {
+					set[strings.ToLower(x)] = true
+				}
+			case float64:
+				set[strconv.FormatFloat(x, 'f', -1, 64)] = true
+			}
+		}
+	}
+}
+
+// ScopeCache 사용자별 UserScope 캐시 (멤버십 변경은 TTL 이내에 반영된다)
+type ScopeCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]*UserScope
+}
+
+var scopeCache = &ScopeCache{ttl: 2 * time.Minute, entries: make(map[string]*UserScope)}
+
+// SetScopeCacheTTL 범위 캐시 유효 시간 설정 및 상태 노출 등록
+func SetScopeCacheTTL(ttl time.Duration) {
+	scopeCache.mu.Lock()
+	scopeCache.ttl = ttl
+	scopeCache.mu.Unlock()
+	RegisterStatusProvider("scope", func() interface{} {
+		return map[string]interface{}{"cachedUsers": scopeCache.Len(), "ttl": ttl.String()}
+	})
+}
+
+// GetScopeCache 전역 범위 캐시 반환
+func GetScopeCache() *ScopeCache {
+	return scopeCache
+}
+
+// Get 유효한 캐시 항목 조회
+func (c *ScopeCache) Get(userID string) (*UserScope, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	scope, ok := c.entries[userID]
+	if !ok || time.Since(scope.FetchedAt) > c.ttl {
+		return nil, false
+	}
+	return scope, true
+}
+
+// Put 캐시 저장 (만료 항목 정리 후)
+func (c *ScopeCache) Put(userID string, scope *UserScope) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[userID] = scope
+	c.evictExpiredLocked()
+}
+
+// InvalidateUser 사용자 캐시 제거 (로그아웃, 멤버십 변경 시). 다른 replica에도 전파한다.
+func (c *ScopeCache) InvalidateUser(userID string) {
+	c.invalidateUserLocal(userID)
+	PublishInvalidation(InvalidationTopicScope, userID)
+}
+
+// Clear 전체 캐시 제거 (workspace/project 멤버십 변경 시). 다른 replica에도 전파한다.
+func (c *ScopeCache) Clear() {
+	c.clearLocal()
+	PublishInvalidation(InvalidationTopicScope, "")
+}
+
+func (c *ScopeCache) invalidateUserLocal(userID string) {
+	c.mu.Lock()
+	delete(c.entries, userID)
+	c.mu.Unlock()
+}
+
+func (c *ScopeCache) clearLocal() {
+	c.mu.Lock()
+	c.entries = make(map[string]*UserScope)
+	c.mu.Unlock()
+}
+
+// Len 캐시 항목 수 (만료 항목 정리 후)
+func (c *ScopeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpiredLocked()
+	return len(c.entries)
+}
+
+func (c *ScopeCache) evictExpiredLocked() {
+	for key, scope := range c.entries {
+		if time.Since(scope.FetchedAt) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// scopeKeys 집합의 키 목록 (정렬)
+func scopeKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestBuildUserScope(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		body                                 string
+		workspaces, projects, namespaceNames []string
+	}{
+		{
+			name: "listUserWorkspaces response",
+			body: `{"responseData":[{"id":"WS01","name":"Workspace One","projects":[
+				{"id":7,"name":"Proj-A","nsid":"NS-A"},{"id":"p8","name":"proj-b","NsId":"ns-b"}]}]}`,
+			workspaces:     []string{"workspace one", "ws01"},
+			projects:       []string{"7", "p8", "proj-a", "proj-b"},
+			namespaceNames: []string{"ns-a", "ns-b"},
+		},
+		{
+			name:           "nested wrapper and ns_id",
+			body:           `{"data":{"items":[{"workspace":{"ID":"ws02","Projects":[{"ID":"p9","ns_id":"ns-c"}]}}]}}`,
+			workspaces:     []string{"ws02"},
+			projects:       []string{"p9"},
+			namespaceNames: []string{"ns-c"},
+		},
+		{
+			// projects 배열 밖의 nsId나 projects 없는 객체의 id는 범위가 아니다
+			name:           "ids outside projects are ignored",
+			body:           `[{"id":"user01","nsId":"ns-x","roles":[{"id":"r1"}]}]`,
+			workspaces:     []string{},
+			projects:       []string{},
+			namespaceNames: []string{},
+		},
+		{
+			name:           "empty values and nested project children",
+			body:           `[{"id":"","name":"ws03","projects":[{"id":"p10","name":"","nsid":"","mci":[{"id":"m1"}]}]}]`,
+			workspaces:     []string{"ws03"},
+			projects:       []string{"p10"},
+			namespaceNames: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data interface{}
+			if err := json.Unmarshal([]byte(tt.body), &data); err != nil {
+				t.Fatal(err)
+			}
+			scope := BuildUserScope(data)
+			if got := scopeKeys(scope.Workspaces); !reflect.DeepEqual(got, tt.workspaces) {
+				t.Errorf("workspaces = %v, want %v", got, tt.workspaces)
+			}
+			if got := scopeKeys(scope.Projects); !reflect.DeepEqual(got, tt.projects) {
+				t.Errorf("projects = %v, want %v", got, tt.projects)
+			}
+			if got := scopeKeys(scope.Namespaces); !reflect.DeepEqual(got, tt.namespaceNames) {
+				t.Errorf("namespaces = %v, want %v", got, tt.namespaceNames)
+			}
+		})
+	}
+
+	scope := BuildUserScope(map[string]interface{}{"projects": []interface{}{map[string]interface{}{"id": "P1", "nsId": "NS1"}}})
+	if !scope.AllowsProject("p1") || !scope.AllowsNamespace("ns1") || scope.AllowsNamespace("ns2") {
+		t.Errorf("Allows* are not case-insensitive lookups: %+v", scope)
+	}
+}
+
+func TestScopeCacheEvictsOnPut(t *testing.T) {
+	cache := &ScopeCache{ttl: time.Minute, entries: make(map[string]*UserScope)}
+	cache.Put("stale", &UserScope{FetchedAt: time.Now().Add(-2 * time.Minute)})
+	cache.Put("fresh", &UserScope{FetchedAt: time.Now()})
+
+	if _, ok := cache.entries["stale"]; ok {
+		t.Fatal("Put kept an expired entry")
+	}
+	if _, ok := cache.Get("fresh"); !ok {
+		t.Fatal("fresh entry missing")
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("Len = %d, want 1", cache.Len())
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// SessionCache AuthMiddleware 세션 검증 결과 in-memory 캐시.
+//
+// key: access token SHA-256 해시 → 검증에 성공한 세션 요약.
+// 요청마다 DB를 조회하지 않도록 짧은 TTL 동안 결과를 재사용하며,
+// 로그아웃/재로그인 시 InvalidateUser로 해당 사용자의 항목을 즉시 제거한다.
+// 검증 실패 결과는 캐시하지 않는다 (폐기 직후 재검증 비용보다 오탐 방지가 우선).
+type SessionCache struct {
+	mu         sync.RWMutex
+	entries    map[string]sessionCacheEntry
+	ttl        time.Duration
+	maxEntries int
+}
+
+type sessionCacheEntry struct {
+	userID    string
+	sessionID string
+	expiresAt time.Time // 세션(refresh) 만료 시각
+	cachedAt  time.Time
+}
+
+// defaultSessionCacheMaxEntries 캐시 최대 항목 수. 초과 시 만료 항목 정리 후에도 넘치면 전체 비운다.
+const defaultSessionCacheMaxEntries = 10000
+
+var sessionCache = NewSessionCache(30 * time.Second)
+
+// NewSessionCache SessionCache 생성 (ttl: 캐시 유효 시간, 0 이하면 캐시 비활성)
+func NewSessionCache(ttl time.Duration) *SessionCache {
+	return &SessionCache{
+		entries:    make(map[string]sessionCacheEntry),
+		ttl:        ttl,
+		maxEntries: defaultSessionCacheMaxEntries,
+	}
+}
+
+// SetSessionCacheTTL 전역 세션 캐시 TTL 설정 (main에서 config 로드 후 호출)
+func SetSessionCacheTTL(ttl time.Duration) {
+	sessionCache.mu.Lock()
+	sessionCache.ttl = ttl
+	sessionCache.entries = make(map[string]sessionCacheEntry)
+	sessionCache.mu.Unlock()
+}
+
+// GetSessionCache 전역 세션 캐시 반환
+func GetSessionCache() *SessionCache {
+	return sessionCache
+}
+
+// Get 토큰 해시에 해당하는 유효한 캐시 항목의 userID 반환. 없거나 만료면 ok=false.
+func (sc *SessionCache) Get(tokenHash string) (userID string, ok bool) {
+	sc.mu.RLock()
+	entry, exists := sc.entries[tokenHash]
+	ttl := sc.ttl
+	sc.mu.RUnlock()
+
+	if !exists || ttl <= 0 {
+		return "", false
+	}
+	now := time.Now()
+	if now.Sub(entry.cachedAt) > ttl || now.After(entry.expiresAt) {
+		sc.mu.Lock()
+		delete(sc.entries, tokenHash)
+		sc.mu.Unlock()
+		return "", false
+	}
+	return entry.userID, true
+}
+
+// Put 검증에 성공한 세션을 캐시에 저장
+func (sc *SessionCache) Put(tokenHash, userID, sessionID string, expiresAt time.Time) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.ttl <= 0 {
+		return
+	}
+	if len(sc.entries) >= sc.maxEntries {
+		sc.evictExpiredLocked()
+		if len(sc.entries) >= sc.maxEntries {
+			sc.entries = make(map[string]sessionCacheEntry)
+		}
+	}
+	sc.entries[tokenHash] = sessionCacheEntry{
+		userID:    userID,
+		sessionID: sessionID,
+		expiresAt: expiresAt,
+		cachedAt:  time.Now(),
+	}
+}
+
+// InvalidateUser 사용자의 모든 캐시 항목 제거 (로그아웃, 세션 교체 시 호출).
+// 다른 replica에도 무효화 버스로 전파한다.
+func (sc *SessionCache) InvalidateUser(userID string) {
+	sc.invalidateUserLocal(userID)
+	PublishInvalidation(InvalidationTopicSession, userID)
+}
+
+func (sc *SessionCache) invalidateUserLocal(userID string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for hash, entry := range sc.entries {
+		if entry.userID == userID {
+			delete(sc.entries, hash)
+		}
+	}
+}
+
+func (sc *SessionCache) clearLocal() {
+	sc.mu.Lock()
+	sc.entries = make(map[string]sessionCacheEntry)
+	sc.mu.Unlock()
+}
+
+// Len 현재 캐시 항목 수
+func (sc *SessionCache) Len() int {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return len(sc.entries)
+}
+
+func (sc *SessionCache) evictExpiredLocked() {
+	now := time.Now()
+	for hash, entry := range sc.entries {
+		if now.Sub(entry.cachedAt) > sc.ttl || now.After(entry.expiresAt) {
+			delete(sc.entries, hash)
+		}
+	}
+}
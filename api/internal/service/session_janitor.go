@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// sessionJanitorLockKey 세션 정리 작업용 Postgres advisory lock 키 (replica 간 단일 실행 보장)
+const sessionJanitorLockKey int64 = 0x6d63776373657373 // "mcwcsess"
+
+// SessionJanitor 만료(refresh_expires_at, 토큰 갱신 시 연장)/폐기된 usersesses 행을 주기적으로 삭제하는 백그라운드 작업.
+//
+// 여러 BFF replica가 같은 DB를 공유하므로 매 실행마다 advisory lock을 시도하고,
+// 획득한 인스턴스만 DELETE를 수행한다. 실행 결과는 관리자 상태 화면에 "sessions"로 노출된다.
+type SessionJanitor struct {
+	db       *gorm.DB
+	interval time.Duration
+
+	mu    sync.RWMutex
+	stats SessionJanitorStats
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// SessionJanitorStats 세션 정리 작업 상태 (GET /api/admin/status → sessions)
+type SessionJanitorStats struct {
+	Interval        string    `json:"interval"`
+	ActiveSessions  int64     `json:"activeSessions"`
+	LastRunAt       time.Time `json:"lastRunAt,omitempty"`
+	LastDurationMs  int64     `json:"lastDurationMs"`
+	LastRemoved     int64     `json:"lastRemoved"`
+	TotalRemoved    int64     `json:"totalRemoved"`
+	LastLockSkipped bool      `json:"lastLockSkipped"` // 다른 replica가 락을 보유하여 건너뜀
+	LastError       string    `json:"lastError,omitempty"`
+	CacheEntries    int       `json:"cacheEntries"`
+}
+
+// NewSessionJanitor SessionJanitor 생성 (interval: 정리 주기)
+func NewSessionJanitor(db *gorm.DB, interval time.Duration) *SessionJanitor {
+	return &SessionJanitor{
+		db:       db,
+		interval: interval,
+		stats:    SessionJanitorStats{Interval: interval.String()},
+	}
+}
+
+// Start 정리 goroutine 시작. 시작 직후 1회 실행 후 interval마다 반복한다.
+func (j *SessionJanitor) Start(ctx context.Context) {
+	ctx, j.cancel = context.WithCancel(ctx)
+	RegisterStatusProvider("sessions", func() interface{} { return j.Stats() })
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.RunOnce()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[SessionJanitor] stopped")
+				return
+			case <-ticker.C:
+				j.RunOnce()
+			}
+		}
+	}()
+	log.Printf("[SessionJanitor] started (interval=%s)", j.interval)
+}
+
+// Stop 정리 goroutine 종료 후 진행 중인 실행이 끝날 때까지 대기 (graceful shutdown)
+func (j *SessionJanitor) Stop() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+}
+
+// RunOnce 정리 작업 1회 실행
+func (j *SessionJanitor) RunOnce() {
+	started := time.Now()
+	var removed int64
+
+	acquired, err := repository.WithAdvisoryLock(j.db, sessionJanitorLockKey, func(tx *gorm.DB) error {
+		n, delErr := repository.NewSessionRepository(tx).DeleteExpired()
+		removed = n
+		return delErr
+	})
+	elapsed := time.Since(started)
+
+	active, countErr := repository.NewSessionRepository(j.db).CountActive()
+
+	j.mu.Lock()
+	j.stats.LastRunAt = started
+	j.stats.LastDurationMs = elapsed.Milliseconds()
+	j.stats.LastLockSkipped = !acquired && err == nil
+	j.stats.LastRemoved = removed
+	j.stats.TotalRemoved += removed
+	j.stats.LastError = ""
+	if err != nil {
+		j.stats.LastError = err.Error()
+	} else if countErr != nil {
+		j.stats.LastError = countErr.Error()
+	}
+	if countErr == nil {
+		j.stats.ActiveSessions = active
+	}
+	j.mu.Unlock()
+
+	switch {
+	case err != nil:
+		log.Printf("[SessionJanitor] sweep failed: %v", err)
+	case !acquired:
+		log.Printf("[SessionJanitor] sweep skipped: lock held by another instance")
+	case removed > 0:
+		log.Printf("[SessionJanitor] removed %d expired/revoked sessions in %s", removed, elapsed)
+	}
+}
+
+// Stats 현재 정리 작업 상태 반환
+func (j *SessionJanitor) Stats() SessionJanitorStats {
+	j.mu.RLock()
+	stats := j.stats
+	j.mu.RUnlock()
+	stats.CacheEntries = sessionCache.Len()
+	return stats
+}
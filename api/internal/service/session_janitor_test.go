@@ -0,0 +1,89 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/repository/repotest"
+)
+
+// newTestSessionJanitor lock 획득 결과와 DELETE 결과를 정할 수 있는 가짜 DB를 쓰는 SessionJanitor
+func newTestSessionJanitor(t *testing.T, acquired bool, deleted repotest.Result) (*SessionJanitor, *repotest.DB) {
+	t.Helper()
+	db, fake := repotest.Open(t)
+	fake.Handle("pg_try_advisory_xact_lock", func([]driver.Value) repotest.Result {
+		return repotest.Result{Columns: []string{"pg_try_advisory_xact_lock"}, Rows: [][]driver.Value{{acquired}}}
+	})
+	fake.Handle("DELETE FROM usersesses", func([]driver.Value) repotest.Result { return deleted })
+	fake.Handle("SELECT count(*)", func([]driver.Value) repotest.Result {
+		return repotest.Result{Columns: []string{"count"}, Rows: [][]driver.Value{{int64(5)}}}
+	})
+	return NewSessionJanitor(db, time.Minute), fake
+}
+
+func TestSessionJanitorSweep(t *testing.T) {
+	janitor, fake := newTestSessionJanitor(t, true, repotest.Result{RowsAffected: 3})
+
+	janitor.RunOnce()
+	janitor.RunOnce()
+
+	stats := janitor.Stats()
+	if stats.LastRemoved != 3 || stats.TotalRemoved != 6 || stats.ActiveSessions != 5 || stats.LastLockSkipped || stats.LastError != "" {
+		t.Fatalf("stats = %+v", stats)
+	}
+	// 락 시도와 삭제는 같은 트랜잭션 안에서 실행된다
+	var sequence []string
+	for _, stmt := range fake.Statements("") {
+		switch {
+		case stmt.SQL == "BEGIN", stmt.SQL == "COMMIT", stmt.SQL == "ROLLBACK":
+			sequence = append(sequence, stmt.SQL)
+		case strings.Contains(stmt.SQL, "pg_try_advisory_xact_lock"):
+			if stmt.Args[0] != sessionJanitorLockKey {
+				t.Errorf("lock key = %v, want %d", stmt.Args[0], sessionJanitorLockKey)
+			}
+			sequence = append(sequence, "LOCK")
+		case strings.HasPrefix(stmt.SQL, "DELETE"):
+			if !strings.Contains(stmt.SQL, "refresh_expires_at") {
+				t.Errorf("sweep ignores refresh_expires_at: %s", stmt.SQL)
+			}
+			sequence = append(sequence, "DELETE")
+		}
+	}
+	if got := strings.Join(sequence[:4], " "); got != "BEGIN LOCK DELETE COMMIT" {
+		t.Fatalf("first run = %s, want BEGIN LOCK DELETE COMMIT", got)
+	}
+}
+
+func TestSessionJanitorLockHeldElsewhere(t *testing.T) {
+	janitor, fake := newTestSessionJanitor(t, false, repotest.Result{RowsAffected: 3})
+
+	janitor.RunOnce()
+
+	stats := janitor.Stats()
+	if !stats.LastLockSkipped || stats.LastRemoved != 0 || stats.TotalRemoved != 0 || stats.LastError != "" {
+		t.Fatalf("stats = %+v, want skipped without removals", stats)
+	}
+	if deletes := fake.Statements("DELETE FROM usersesses"); len(deletes) != 0 {
+		t.Fatalf("swept without the lock: %+v", deletes)
+	}
+	if stats.ActiveSessions != 5 {
+		t.Fatalf("ActiveSessions = %d, want 5 (counted even when skipped)", stats.ActiveSessions)
+	}
+}
+
+func TestSessionJanitorSweepError(t *testing.T) {
+	janitor, fake := newTestSessionJanitor(t, true, repotest.Result{Err: errors.New("connection reset")})
+
+	janitor.RunOnce()
+
+	stats := janitor.Stats()
+	if stats.LastLockSkipped || !strings.Contains(stats.LastError, "connection reset") {
+		t.Fatalf("stats = %+v, want the delete error", stats)
+	}
+	if rollbacks := fake.Statements("ROLLBACK"); len(rollbacks) != 1 {
+		t.Fatalf("rollbacks = %d, want 1", len(rollbacks))
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"mc_web_console_api/internal/model"
 	"mc_web_console_api/internal/repository"
@@ -8,6 +9,14 @@ import (
 	"time"
 )
 
+// 세션 바인딩 검증 실패 사유
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session revoked")
+	ErrSessionExpired  = errors.New("session expired")
+	ErrSessionMismatch = errors.New("token does not belong to session user")
+)
+
 // SessionService 세션 서비스
 type SessionService struct {
 	repo *repository.SessionRepository
@@ -18,11 +27,8 @@ func NewSessionService(repo *repository.SessionRepository) *SessionService {
 	return &SessionService{repo: repo}
 }
 
-// CreateSession 세션 생성
+// CreateSession 세션 생성. 로그인마다 새 세션을 만들며 같은 사용자의 다른 세션은 유지된다.
 func (s *SessionService) CreateSession(userID, userName, email, role string, accessExpiresIn, refreshExpiresIn float64) (*model.UserSession, error) {
-	// 기존 세션 확인
-	existingSession, _ := s.repo.FindByUserID(userID)
-
 	// 토큰 생성
 	accessToken, err := jwt.GenerateToken(
 		userID,
@@ -35,7 +41,7 @@ func (s *SessionService) CreateSession(userID, userName, email, role string, acc
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshToken, err := jwt.GenerateToken(
+	refreshToken, err := jwt.GenerateRefreshToken(
 		userID,
 		userName,
 		email,
@@ -46,22 +52,6 @@ func (s *SessionService) CreateSession(userID, userName, email, role string, acc
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// 세션 생성 또는 업데이트
-	if existingSession != nil {
-		// 기존 세션 업데이트
-		existingSession.AccessToken = accessToken
-		existingSession.RefreshToken = refreshToken
-		existingSession.ExpiresIn = accessExpiresIn
-		existingSession.RefreshExpiresIn = refreshExpiresIn
-
-		if err := s.repo.Update(existingSession); err != nil {
-			return nil, fmt.Errorf("failed to update session: %w", err)
-		}
-
-		return existingSession, nil
-	}
-
-	// 새 세션 생성
 	session := &model.UserSession{
 		UserID:           userID,
 		AccessToken:      accessToken,
@@ -97,16 +87,93 @@ func (s *SessionService) ValidateSession(userID string) (bool, error) {
 	return true, nil
 }
 
+// ValidateAccessToken 제시된 access token이 해당 사용자의 살아있는 세션에 바인딩되어 있는지 검증.
+//
+// 세션은 저장된 access token의 SHA-256 해시로 식별하므로, 서명이 유효하더라도
+// 로그아웃 이전 발급 토큰·다른 기기에서 교체된 토큰·다른 사용자의 세션은 거부된다.
+// 성공 결과는 SessionCache에 짧게 캐시되어 DB 조회를 줄인다.
+func (s *SessionService) ValidateAccessToken(userID, accessToken string) error {
+	return s.ValidateTokenHash(userID, jwt.TokenHash(accessToken))
+}
+
+// ValidateTokenHash access token 해시로 사용자의 살아있는 세션을 검증한다.
+// 대리 토큰은 원문 대신 관리자 세션 토큰 해시를 담고 있어 이 함수로 관리자 세션을 확인한다.
+func (s *SessionService) ValidateTokenHash(userID, tokenHash string) error {
+	if cachedUserID, ok := sessionCache.Get(tokenHash); ok {
+		if cachedUserID != userID {
+			return ErrSessionMismatch
+		}
+		return nil
+	}
+
+	session, err := s.repo.FindByAccessTokenHash(tokenHash)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+	if session.UserID != userID {
+		return ErrSessionMismatch
+	}
+	if session.IsRevoked() {
+		return ErrSessionRevoked
+	}
+	if session.IsRefreshExpired() {
+		return ErrSessionExpired
+	}
+
+	sessionCache.Put(tokenHash, session.UserID, session.ID, session.RefreshExpiry())
+	return nil
+}
+
+// ValidateSessionID 세션 ID로 세션을 조회해 userID의 활성 세션인지 검증 (대리 토큰의 관리자 세션 확인).
+// access token 해시 대신 ID로 찾으므로 관리자 토큰이 갱신되어도 같은 세션으로 확인된다.
+func (s *SessionService) ValidateSessionID(userID, sessionID string) (*model.UserSession, error) {
+	session, err := s.repo.FindByID(sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.UserID != userID {
+		return nil, ErrSessionMismatch
+	}
+	if session.IsRevoked() {
+		return nil, ErrSessionRevoked
+	}
+	if session.IsRefreshExpired() {
+		return nil, ErrSessionExpired
+	}
+	return session, nil
+}
+
+// GetSessionByTokenHash access token 해시로 세션 조회
+func (s *SessionService) GetSessionByTokenHash(tokenHash string) (*model.UserSession, error) {
+	return s.repo.FindByAccessTokenHash(tokenHash)
+}
+
+// RevokeSession 사용자의 모든 세션 폐기 및 캐시 제거 (비밀번호 변경/재설정)
+func (s *SessionService) RevokeSession(userID string) error {
+	sessionCache.InvalidateUser(userID)
+	if err := s.repo.Revoke(userID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessTokenSession access token이 바인딩된 세션만 폐기 (로그아웃). 다른 기기의 세션은 유지된다.
+func (s *SessionService) RevokeAccessTokenSession(userID, accessToken string) error {
+	sessionCache.InvalidateUser(userID)
+	if err := s.repo.RevokeByAccessTokenHash(userID, jwt.TokenHash(accessToken)); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
 // RefreshSession 세션 갱신
 func (s *SessionService) RefreshSession(userID, refreshToken string) (*model.UserSession, error) {
-	// 기존 세션 조회
-	session, err := s.repo.FindByUserID(userID)
+	// refresh token이 바인딩된 세션 조회
+	session, err := s.repo.FindByRefreshToken(refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
-
-	// 리프레시 토큰 확인
-	if session.RefreshToken != refreshToken {
+	if session.UserID != userID {
 		return nil, fmt.Errorf("invalid refresh token")
 	}
 
@@ -121,7 +188,8 @@ func (s *SessionService) RefreshSession(userID, refreshToken string) (*model.Use
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
 
-	// 세션 업데이트
+	// 세션 업데이트 (이전 access token의 캐시 항목 제거)
+	sessionCache.InvalidateUser(userID)
 	session.AccessToken = newAccessToken
 
 	if err := s.repo.Update(session); err != nil {
@@ -133,6 +201,7 @@ func (s *SessionService) RefreshSession(userID, refreshToken string) (*model.Use
 
 // DeleteSession 세션 삭제
 func (s *SessionService) DeleteSession(userID string) error {
+	sessionCache.InvalidateUser(userID)
 	if err := s.repo.Delete(userID); err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
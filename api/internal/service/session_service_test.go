@@ -0,0 +1,151 @@
+package service
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/internal/repository/repotest"
+	"mc_web_console_api/pkg/jwt"
+)
+
+var sessionColumns = []string{
+	"id", "user_id", "access_token", "expires_in", "refresh_token", "refresh_expires_in",
+	"access_token_hash", "revoked_at", "refresh_expires_at", "created_at", "updated_at",
+}
+
+func sessionRow(session model.UserSession) []driver.Value {
+	nullable := func(t *time.Time) driver.Value {
+		if t == nil {
+			return nil
+		}
+		return *t
+	}
+	return []driver.Value{
+		session.ID, session.UserID, session.AccessToken, session.ExpiresIn, session.RefreshToken, session.RefreshExpiresIn,
+		session.AccessTokenHash, nullable(session.RevokedAt), nullable(session.RefreshExpiresAt), session.CreatedAt, session.UpdatedAt,
+	}
+}
+
+// newTestSessionService 토큰 해시 조회에 sessions[hash]를 돌려주는 가짜 DB를 쓰는 SessionService
+func newTestSessionService(t *testing.T, sessions map[string]model.UserSession) (*SessionService, *repotest.DB) {
+	t.Helper()
+	db, fake := repotest.Open(t)
+	fake.Handle(`FROM "usersesses" WHERE access_token_hash = $1`, func(args []driver.Value) repotest.Result {
+		session, ok := sessions[args[0].(string)]
+		if !ok {
+			return repotest.Result{Columns: sessionColumns}
+		}
+		return repotest.Result{Columns: sessionColumns, Rows: [][]driver.Value{sessionRow(session)}}
+	})
+	sessionCache.clearLocal()
+	t.Cleanup(sessionCache.clearLocal)
+	return NewSessionService(repository.NewSessionRepository(db)), fake
+}
+
+func TestValidateTokenHashBinding(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	tests := []struct {
+		name    string
+		session *model.UserSession
+		userID  string
+		wantErr error
+	}{
+		{
+			name:    "unknown token",
+			userID:  "user01",
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name:    "token of another user",
+			session: &model.UserSession{UserID: "user02", RefreshExpiresAt: at(time.Hour), CreatedAt: now},
+			userID:  "user01",
+			wantErr: ErrSessionMismatch,
+		},
+		{
+			name:    "revoked",
+			session: &model.UserSession{UserID: "user01", RevokedAt: at(-time.Minute), RefreshExpiresAt: at(time.Hour), CreatedAt: now},
+			userID:  "user01",
+			wantErr: ErrSessionRevoked,
+		},
+		{
+			name:    "legacy row past created_at + refresh_expires_in",
+			session: &model.UserSession{UserID: "user01", RefreshExpiresIn: 3600, CreatedAt: now.Add(-2 * time.Hour)},
+			userID:  "user01",
+			wantErr: ErrSessionExpired,
+		},
+		{
+			name:    "refresh_expires_at passed",
+			session: &model.UserSession{UserID: "user01", RefreshExpiresIn: 3600, RefreshExpiresAt: at(-time.Minute), CreatedAt: now.Add(-2 * time.Hour)},
+			userID:  "user01",
+			wantErr: ErrSessionExpired,
+		},
+		{
+			// 갱신으로 연장된 세션은 최초 refresh TTL이 지나도 유효하다
+			name:    "rotated after the original TTL",
+			session: &model.UserSession{UserID: "user01", RefreshExpiresIn: 3600, RefreshExpiresAt: at(30 * time.Minute), CreatedAt: now.Add(-2 * time.Hour)},
+			userID:  "user01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := map[string]model.UserSession{}
+			if tt.session != nil {
+				tt.session.ID = "session-1"
+				tt.session.AccessTokenHash = jwt.TokenHash("access-token")
+				sessions[tt.session.AccessTokenHash] = *tt.session
+			}
+			svc, _ := newTestSessionService(t, sessions)
+			if err := svc.ValidateAccessToken(tt.userID, "access-token"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateAccessToken error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTokenHashCacheAndLogout(t *testing.T) {
+	hash := jwt.TokenHash("access-token")
+	expiresAt := time.Now().Add(time.Hour)
+	sessions := map[string]model.UserSession{
+		hash: {ID: "session-1", UserID: "user01", AccessTokenHash: hash, RefreshExpiresAt: &expiresAt, CreatedAt: time.Now()},
+	}
+	svc, fake := newTestSessionService(t, sessions)
+
+	if err := svc.ValidateAccessToken("user01", "access-token"); err != nil {
+		t.Fatal(err)
+	}
+	// 캐시된 결과로 검증하므로 DB 행이 없어도 통과하고, 다른 사용자는 캐시에서 거부된다
+	delete(sessions, hash)
+	if err := svc.ValidateAccessToken("user01", "access-token"); err != nil {
+		t.Fatalf("cached validation: %v", err)
+	}
+	if got := len(fake.Statements(`FROM "usersesses"`)); got != 1 {
+		t.Fatalf("session lookups = %d, want 1 (second call from cache)", got)
+	}
+	if err := svc.ValidateAccessToken("user02", "access-token"); !errors.Is(err, ErrSessionMismatch) {
+		t.Fatalf("cached mismatch: err = %v", err)
+	}
+
+	// 로그아웃은 요청 토큰의 세션만 폐기하고 캐시를 비운다
+	if err := svc.RevokeAccessTokenSession("user01", "access-token"); err != nil {
+		t.Fatal(err)
+	}
+	revokes := fake.Statements(`UPDATE "usersesses" SET "revoked_at"`)
+	if len(revokes) != 1 || !strings.Contains(revokes[0].SQL, "access_token_hash = $") {
+		t.Fatalf("revoke statements = %+v, want one scoped to the token hash", revokes)
+	}
+	if args := revokes[0].Args; args[len(args)-2] != "user01" || args[len(args)-1] != hash {
+		t.Fatalf("revoke args = %v", args)
+	}
+	if err := svc.ValidateAccessToken("user01", "access-token"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("after logout: err = %v, want ErrSessionNotFound", err)
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+	"mc_web_console_api/pkg/jwt"
+	"mc_web_console_api/pkg/secretbox"
+
+	"gorm.io/gorm"
+)
+
+// 회원가입 초대/승인 처리 실패 사유
+var (
+	ErrInvitationRequired = errors.New("invitation code is required")
+	ErrInvitationInvalid  = errors.New("invalid or expired invitation code")
+	ErrSignupPending      = errors.New("a signup request for this email is already pending")
+	ErrSignupNotPending   = errors.New("signup request is not pending")
+	ErrSignupKeyMissing   = errors.New("signup encryption key is not configured")
+	ErrSignupNotFound     = errors.New("signup request not found")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationMaxUses  = errors.New("max_uses must not be negative")
+	ErrInvitationExpiry   = errors.New("expires_in_days must not be negative")
+)
+
+// SignupService 초대 코드와 관리자 승인 대기열 서비스
+type SignupService struct {
+	repo  *repository.SignupRepository
+	cfg   config.SignupConfig
+	mciam bool
+}
+
+// NewSignupService 새로운 회원가입 서비스 생성. mciam이면 승인 대기 중 비밀번호를 암호화해 보관한다.
+func NewSignupService(repo *repository.SignupRepository, cfg config.SignupConfig, mciam bool) *SignupService {
+	return &SignupService{repo: repo, cfg: cfg, mciam: mciam}
+}
+
+// InvitationInput 초대 코드 발급 입력
+type InvitationInput struct {
+	Email     string        // 지정 시 해당 이메일만 사용 가능
+	MaxUses   int           // 0이면 1
+	ExpiresIn time.Duration // 0이면 InvitationTTL
+	CreatedBy string
+}
+
+// PendingSignupInput 승인 대기 회원가입 입력
+type PendingSignupInput struct {
+	Email        string
+	Password     string
+	FirstName    string
+	LastName     string
+	Organization string
+	InvitationID string
+	IP           string
+}
+
+// CreateInvitation 초대 코드 발급. 코드 원문은 반환값으로만 제공된다.
+func (s *SignupService) CreateInvitation(in InvitationInput) (string, *model.SignupInvitation, error) {
+	if in.MaxUses < 0 {
+		return "", nil, ErrInvitationMaxUses
+	}
+	if in.ExpiresIn < 0 {
+		return "", nil, ErrInvitationExpiry
+	}
+	maxUses := in.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	ttl := in.ExpiresIn
+	if ttl == 0 {
+		ttl = s.cfg.InvitationTTL
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate invitation code: %w", err)
+	}
+	code := hex.EncodeToString(raw)
+
+	invitation := &model.SignupInvitation{
+		CodeHash:  jwt.TokenHash(code),
+		Email:     strings.ToLower(strings.TrimSpace(in.Email)),
+		MaxUses:   maxUses,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedBy: in.CreatedBy,
+	}
+	if err := s.repo.CreateInvitation(invitation); err != nil {
+		return "", nil, fmt.Errorf("failed to store invitation: %w", err)
+	}
+	return code, invitation, nil
+}
+
+// ListInvitations 초대 목록
+func (s *SignupService) ListInvitations() ([]model.SignupInvitation, error) {
+	return s.repo.ListInvitations()
+}
+
+// RevokeInvitation 초대 폐기
+func (s *SignupService) RevokeInvitation(id string) error {
+	ok, err := s.repo.RevokeInvitation(id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+	if !ok {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// CheckInvitation 초대 코드 확인 (사용 횟수는 올리지 않는다). 초대가 필수가 아니고 code가 비어 있으면 (nil, nil)을 반환한다.
+// 사용 처리는 가입이 끝난 뒤 ConsumeInvitation, 승인 대기열 저장 시 Submit에서 한다.
+func (s *SignupService) CheckInvitation(code, email string) (*model.SignupInvitation, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		if s.cfg.InvitationRequired {
+			return nil, ErrInvitationRequired
+		}
+		return nil, nil
+	}
+	invitation, err := s.repo.FindInvitationByHash(jwt.TokenHash(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationInvalid
+		}
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if !invitation.IsUsable(time.Now()) {
+		return nil, ErrInvitationInvalid
+	}
+	if invitation.Email != "" && !strings.EqualFold(invitation.Email, strings.TrimSpace(email)) {
+		return nil, ErrInvitationInvalid
+	}
+	return invitation, nil
+}
+
+// ConsumeInvitation 확인된 초대의 사용 횟수 증가. 그 사이 한도에 도달했거나 폐기/만료되었으면 ErrInvitationInvalid
+func (s *SignupService) ConsumeInvitation(invitationID string) error {
+	ok, err := s.repo.UseInvitation(invitationID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to use invitation: %w", err)
+	}
+	if !ok {
+		return ErrInvitationInvalid
+	}
+	return nil
+}
+
+// Submit 회원가입 요청을 승인 대기열에 저장.
+// 로컬 모드는 bcrypt 해시를, MCIAM 모드는 승인 시 mc-iam-manager에 전달할 비밀번호를 암호화해 보관한다.
+// InvitationID가 있으면 초대 사용 처리와 요청 저장을 한 트랜잭션으로 수행한다.
+func (s *SignupService) Submit(in PendingSignupInput) (*model.PendingSignup, error) {
+	email := strings.TrimSpace(in.Email)
+	pending, err := s.repo.ExistsPendingEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending signup: %w", err)
+	}
+	if pending {
+		return nil, ErrSignupPending
+	}
+
+	record := &model.PendingSignup{
+		Email:        email,
+		FirstName:    in.FirstName,
+		LastName:     in.LastName,
+		Organization: in.Organization,
+		InvitationID: in.InvitationID,
+		Status:       model.SignupStatusPending,
+		IP:           in.IP,
+	}
+	if s.mciam {
+		box, err := s.box()
+		if err != nil {
+			return nil, err
+		}
+		if record.PasswordSealed, err = box.Seal(in.Password); err != nil {
+			return nil, err
+		}
+	} else {
+		if record.PasswordHash, err = HashPassword(in.Password); err != nil {
+			return nil, err
+		}
+	}
+	if in.InvitationID == "" {
+		if err := s.repo.CreatePending(record); err != nil {
+			return nil, fmt.Errorf("failed to store signup request: %w", err)
+		}
+		return record, nil
+	}
+	ok, err := s.repo.CreatePendingWithInvitation(record, in.InvitationID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store signup request: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvitationInvalid
+	}
+	return record, nil
+}
+
+// ListPending 상태별 회원가입 요청 목록
+func (s *SignupService) ListPending(status string) ([]model.PendingSignup, error) {
+	return s.repo.ListPending(status)
+}
+
+// GetPending 승인 대기 중인 요청 조회. MCIAM 모드면 보관된 비밀번호 원문을 함께 반환한다.
+func (s *SignupService) GetPending(id string) (*model.PendingSignup, string, error) {
+	record, err := s.repo.FindPendingByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrSignupNotFound
+		}
+		return nil, "", fmt.Errorf("failed to find signup request: %w", err)
+	}
+	if record.Status != model.SignupStatusPending {
+		return nil, "", ErrSignupNotPending
+	}
+	if !s.mciam {
+		return record, "", nil
+	}
+	box, err := s.box()
+	if err != nil {
+		return nil, "", err
+	}
+	password, err := box.Open(record.PasswordSealed)
+	if err != nil {
+		return nil, "", err
+	}
+	return record, password, nil
+}
+
+// Decide 요청을 승인/거절 처리하고 보관하던 비밀번호를 삭제한다
+func (s *SignupService) Decide(id, status, decidedBy, reason string) error {
+	ok, err := s.repo.Decide(id, status, decidedBy, reason)
+	if err != nil {
+		return fmt.Errorf("failed to update signup request: %w", err)
+	}
+	if !ok {
+		return ErrSignupNotPending
+	}
+	return nil
+}
+
+func (s *SignupService) box() (*secretbox.Box, error) {
+	if s.cfg.EncryptionKey == "" {
+		return nil, ErrSignupKeyMissing
+	}
+	return secretbox.New(s.cfg.EncryptionKey)
+}
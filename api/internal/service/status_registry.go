@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sort"
+	"sync"
+)
+
+// StatusProvider 관리자 상태 화면(GET /api/admin/status)에 노출할 컴포넌트 상태를 반환하는 함수.
+// 반환값은 JSON 직렬화 가능해야 한다.
+type StatusProvider func() interface{}
+
+var (
+	statusMu        sync.RWMutex
+	statusProviders = make(map[string]StatusProvider)
+)
+
+// RegisterStatusProvider 관리자 상태 화면에 컴포넌트를 등록한다. 같은 이름이면 교체된다.
+// 백그라운드 작업(세션 정리, 레지스트리 갱신 등)이 시작 시 자신을 등록한다.
+func RegisterStatusProvider(name string, provider StatusProvider) {
+	statusMu.Lock()
+	statusProviders[name] = provider
+	statusMu.Unlock()
+}
+
+// StatusSnapshot 등록된 모든 컴포넌트의 현재 상태를 이름별로 수집
+func StatusSnapshot() map[string]interface{} {
+	statusMu.RLock()
+	names := make([]string, 0, len(statusProviders))
+	for name := range statusProviders {
+		names = append(names, name)
+	}
+	providers := make(map[string]StatusProvider, len(statusProviders))
+	for name, p := range statusProviders {
+		providers[name] = p
+	}
+	statusMu.RUnlock()
+
+	sort.Strings(names)
+	snapshot := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		snapshot[name] = providers[name]()
+	}
+	return snapshot
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// RefreshedTokens 토큰 갱신 결과
+type RefreshedTokens struct {
+	AccessToken      string
+	ExpiresIn        float64
+	RefreshToken     string // 회전되지 않았으면 빈 값
+	RefreshExpiresIn float64
+}
+
+// refreshResultTTL 같은 만료 토큰으로 들어온 후속 요청이 이전 갱신 결과를 재사용하는 시간
+const refreshResultTTL = 30 * time.Second
+
+// RefreshCoordinator 사용자별 토큰 갱신 직렬화.
+// 같은 사용자의 동시 요청이 모두 401을 받아도 mc-iam-manager에는 한 번만 갱신을 요청하고,
+// 뒤이은 요청은 같은 만료 토큰에 대한 직전 결과를 재사용한다.
+type RefreshCoordinator struct {
+	mu    sync.Mutex
+	users map[string]*userRefresh
+
+	refreshed int64
+	reused    int64
+	failed    int64
+}
+
+type userRefresh struct {
+	mu         sync.Mutex
+	active     int // Refresh 진행/대기 중인 호출 수 (RefreshCoordinator.mu로 보호)
+	staleToken string
+	result     *RefreshedTokens
+	at         time.Time
+}
+
+var (
+	refreshCoordinator     *RefreshCoordinator
+	refreshCoordinatorOnce sync.Once
+)
+
+// GetRefreshCoordinator 싱글톤 갱신 코디네이터 반환
+func GetRefreshCoordinator() *RefreshCoordinator {
+	refreshCoordinatorOnce.Do(func() {
+		refreshCoordinator = &RefreshCoordinator{users: make(map[string]*userRefresh)}
+		RegisterStatusProvider("tokenRefresh", refreshCoordinator.Status)
+	})
+	return refreshCoordinator
+}
+
+// Refresh userID의 staleToken을 refresh로 갱신한다. 같은 사용자의 갱신은 순차 실행되며,
+// 직전 갱신이 같은 staleToken에 대한 것이면 refresh를 호출하지 않고 그 결과를 반환한다.
+func (rc *RefreshCoordinator) Refresh(userID, staleToken string, refresh func() (*RefreshedTokens, error)) (*RefreshedTokens, error) {
+	rc.mu.Lock()
+	entry, ok := rc.users[userID]
+	if !ok {
+		rc.evictIdleLocked()
+		entry = &userRefresh{}
+		rc.users[userID] = entry
+	}
+	entry.active++
+	rc.mu.Unlock()
+
+	entry.mu.Lock()
+	defer func() {
+		entry.mu.Unlock()
+		rc.mu.Lock()
+		entry.active--
+		rc.mu.Unlock()
+	}()
+
+	if entry.result != nil && entry.staleToken == staleToken && time.Since(entry.at) < refreshResultTTL {
+		rc.count(&rc.reused)
+		return entry.result, nil
+	}
+
+	result, err := refresh()
+	if err != nil {
+		rc.count(&rc.failed)
+		return nil, err
+	}
+	entry.staleToken = staleToken
+	entry.result = result
+	entry.at = time.Now()
+	rc.count(&rc.refreshed)
+	return result, nil
+}
+
+// evictIdleLocked 진행 중인 갱신이 없고 결과 재사용 시간이 지난 사용자 항목 제거 (rc.mu 보유 상태에서 호출)
+func (rc *RefreshCoordinator) evictIdleLocked() {
+	for userID, entry := range rc.users {
+		if entry.active == 0 && time.Since(entry.at) >= refreshResultTTL {
+			delete(rc.users, userID)
+		}
+	}
+}
+
+// Forget 사용자 갱신 기록 제거 (로그아웃)
+func (rc *RefreshCoordinator) Forget(userID string) {
+	rc.mu.Lock()
+	delete(rc.users, userID)
+	rc.mu.Unlock()
+}
+
+func (rc *RefreshCoordinator) count(counter *int64) {
+	rc.mu.Lock()
+	*counter++
+	rc.mu.Unlock()
+}
+
+// Status /api/admin/status 용 갱신 통계
+func (rc *RefreshCoordinator) Status() interface{} {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return map[string]interface{}{
+		"users":     len(rc.users),
+		"refreshed": rc.refreshed,
+		"reused":    rc.reused,
+		"failed":    rc.failed,
+	}
+}
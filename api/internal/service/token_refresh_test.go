@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshCoordinatorReusesResult(t *testing.T) {
+	rc := &RefreshCoordinator{users: make(map[string]*userRefresh)}
+	calls := 0
+	refresh := func() (*RefreshedTokens, error) {
+		calls++
+		return &RefreshedTokens{AccessToken: "fresh"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := rc.Refresh("user01", "stale", refresh)
+		if err != nil || got.AccessToken != "fresh" {
+			t.Fatalf("Refresh = %v, %v", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("refresh calls = %d, want 1 (later calls reuse the result)", calls)
+	}
+	if _, err := rc.Refresh("user01", "another-stale", refresh); err != nil || calls != 2 {
+		t.Fatalf("different stale token: calls = %d, err = %v, want a new refresh", calls, err)
+	}
+}
+
+func TestRefreshCoordinatorEvictsIdleUsers(t *testing.T) {
+	rc := &RefreshCoordinator{users: make(map[string]*userRefresh)}
+	refresh := func() (*RefreshedTokens, error) { return &RefreshedTokens{AccessToken: "fresh"}, nil }
+
+	if _, err := rc.Refresh("idle", "stale", refresh); err != nil {
+		t.Fatal(err)
+	}
+	rc.users["idle"].at = time.Now().Add(-refreshResultTTL)
+	// 진행 중인 갱신은 오래되었어도 제거하지 않는다
+	rc.users["busy"] = &userRefresh{active: 1, at: time.Now().Add(-time.Hour)}
+
+	if _, err := rc.Refresh("new", "stale", refresh); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rc.users["idle"]; ok {
+		t.Error("idle user entry was not evicted")
+	}
+	if _, ok := rc.users["busy"]; !ok {
+		t.Error("in-flight user entry was evicted")
+	}
+	if got := rc.users["new"].active; got != 0 {
+		t.Errorf("active after Refresh = %d, want 0", got)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"mc_web_console_api/internal/config"
+)
+
+// UserMenuSource 사용자 메뉴 판정 출처
+const (
+	UserMenuSourceMCIAM = "mc-iam-manager" // Getallavailablemenus
+	UserMenuSourceLocal = "local"          // 메뉴 yaml + 메뉴 권한 CSV (MCIAM_USE=false)
+)
+
+// UserMenus 사용자에게 허용된 메뉴 (GET /api/auth/menus).
+// front는 Tree로 페이지 경로의 메뉴를 찾고 Granted로 사이드바 표시와 직접 접근 차단을 결정한다.
+type UserMenus struct {
+	Source     string               `json:"source"`
+	TreeSource string               `json:"treeSource"` // "mc-iam-manager" 또는 메뉴 yaml 경로
+	Tree       config.MenuResources `json:"tree"`       // 전체 메뉴 정의
+	Granted    []string             `json:"granted"`    // 허용된 메뉴 id (정렬)
+}
+
+// UserMenus 사용자 메뉴 조회.
+// MCIAM 모드는 사용자 토큰으로 mc-iam-manager의 전체 메뉴와 허용 메뉴를 조회하고, 전체 메뉴 조회가 거부되면 메뉴 yaml을 사용한다.
+// 로컬 모드는 메뉴 yaml과 메뉴 권한 CSV에서 roles의 정책 컬럼이 TRUE인 메뉴를 허용한다.
+func (s *MenuSyncer) UserMenus(ctx context.Context, authHeader string, roles []string) (*UserMenus, error) {
+	if !s.cfg.MCIAM.Use {
+		return s.DeclaredMenus(roles)
+	}
+
+	body, err := s.call(ctx, authHeader, "Getallavailablemenus", nil, "application/json", []byte("{}"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMenuSyncUnavailable, err)
+	}
+	available, err := decodeMenuList(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Getallavailablemenus response parse failed: %v", ErrMenuSyncUnavailable, err)
+	}
+	result := &UserMenus{Source: UserMenuSourceMCIAM, Granted: []string{}}
+	for _, menu := range available {
+		result.Granted = append(result.Granted, menu.Id)
+	}
+	sort.Strings(result.Granted)
+
+	if tree, err := s.listMenus(ctx, authHeader); err == nil && len(tree) > 0 {
+		result.Tree, result.TreeSource = tree, UserMenuSourceMCIAM
+		return result, nil
+	} else if err != nil {
+		log.Printf("[UserMenus] listMenus failed, using %s: %v", s.cfg.MenuSync.MenusFile, err)
+	}
+	tree, err := config.LoadMenuResources(s.cfg.MenuSync.MenusFile)
+	if err != nil {
+		return nil, err
+	}
+	result.Tree, result.TreeSource = tree, s.cfg.MenuSync.MenusFile
+	return result, nil
+}
+
+// DeclaredMenus 메뉴 yaml과 메뉴 권한 CSV 선언에서 roles에 허용된 메뉴 (로컬 모드, MCIAM 모드의 대리 요청).
+// 대리 요청의 mc-iam-manager 호출은 관리자 토큰을 쓰므로 Getallavailablemenus 대신 대상 사용자 역할로 계산한다.
+func (s *MenuSyncer) DeclaredMenus(roles []string) (*UserMenus, error) {
+	menus, err := config.LoadMenuResources(s.cfg.MenuSync.MenusFile)
+	if err != nil {
+		return nil, err
+	}
+	perms, err := config.LoadMenuPermissions(s.cfg.MenuSync.PermissionsFile)
+	if err != nil {
+		return nil, err
+	}
+	policies := s.cfg.Authz.PoliciesForRoles(roles...)
+	granted := []string{}
+	for id, allowed := range managedGrants(perms.Grants(s.cfg.MenuSync.Framework), menus) {
+		for _, policy := range policies {
+			if containsPolicy(allowed, policy) {
+				granted = append(granted, id)
+				break
+			}
+		}
+	}
+	sort.Strings(granted)
+	return &UserMenus{Source: UserMenuSourceLocal, TreeSource: s.cfg.MenuSync.MenusFile, Tree: menus, Granted: granted}, nil
+}
+
+// decodeMenuList mc-iam-manager 메뉴 목록 응답 (배열 또는 {menus|responseData: 배열})
+func decodeMenuList(body []byte) (config.MenuResources, error) {
+	var menus config.MenuResources
+	if err := json.Unmarshal(body, &menus); err == nil {
+		return menus, nil
+	}
+	var wrapped struct {
+		Menus        config.MenuResources `json:"menus"`
+		ResponseData config.MenuResources `json:"responseData"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.Menus != nil {
+		return wrapped.Menus, nil
+	}
+	return wrapped.ResponseData, nil
+}
@@ -0,0 +1,565 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+	"mc_web_console_api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// webhook 요청 헤더
+const (
+	WebhookEventHeader     = "X-MCWC-Event"
+	WebhookEventIDHeader   = "X-MCWC-Event-Id"
+	WebhookSchemaHeader    = "X-MCWC-Schema-Version"
+	WebhookSignatureHeader = "X-MCWC-Signature" // "t=<unix>,v1=<hex(HMAC-SHA256(secret, t + "." + body))>"
+)
+
+const (
+	webhookBatchSize       = 50
+	webhookMaxBackoff      = time.Hour
+	webhookMemoryDeadLimit = 100
+	webhookEmitBuffer      = 1024 // 대기열 저장 전 이벤트 버퍼 (가득 차면 버림)
+)
+
+// WebhookDispatcher 인증 이벤트를 설정된 webhook URL로 전송하는 백그라운드 작업.
+//
+// Emit은 이벤트를 메모리 버퍼에 넣고 즉시 반환하며(요청 경로에서 DB 쓰기 없음), 저장 goroutine이
+// 엔드포인트별 전송 항목을 대기열에 넣는다. 전송 goroutine이 대기열을 비우며,
+// 실패한 항목은 지수 백오프로 재시도하다 MaxAttempts를 넘으면 dead-letter로 옮긴다.
+// DB가 있으면 webhook_deliveries/webhook_dead_letters 테이블을 replica 간 공유하고, 없으면 메모리에 보관한다.
+type WebhookDispatcher struct {
+	cfg    config.WebhookConfig
+	queue  webhookQueue
+	client *http.Client
+	events map[string]bool
+	emits  chan model.AuthEvent
+	wake   chan struct{}
+
+	mu    sync.Mutex
+	stats WebhookStats
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// WebhookStats webhook 전송 상태 (GET /api/admin/status → webhooks)
+type WebhookStats struct {
+	Endpoints   int       `json:"endpoints"`
+	Storage     string    `json:"storage"` // database | memory
+	Emitted     int64     `json:"emitted"`
+	Dropped     int64     `json:"dropped"` // 버퍼가 가득 차 버린 이벤트 수
+	Delivered   int64     `json:"delivered"`
+	Failed      int64     `json:"failed"` // 재시도 예정 실패 횟수
+	DeadLetters int64     `json:"deadLetters"`
+	Queued      int64     `json:"queued"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt,omitempty"`
+}
+
+var webhookDispatcher *WebhookDispatcher
+
+// InitWebhookDispatcher 전역 webhook 전송기 초기화. URL이 없거나 서명 키가 없으면 nil (Emit은 아무것도 하지 않음)
+func InitWebhookDispatcher(cfg config.WebhookConfig, db *gorm.DB) *WebhookDispatcher {
+	if len(cfg.URLs) == 0 {
+		webhookDispatcher = nil
+		return nil
+	}
+	if cfg.Secret == "" {
+		// 서명 없는 인증 이벤트는 수신 측에서 위조 여부를 확인할 수 없으므로 전송하지 않는다
+		log.Println("⚠️  MC_WEB_CONSOLE_WEBHOOK_URLS set but MC_WEB_CONSOLE_WEBHOOK_SECRET is missing, webhooks disabled")
+		webhookDispatcher = nil
+		return nil
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	d := &WebhookDispatcher{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		emits:  make(chan model.AuthEvent, webhookEmitBuffer),
+		wake:   make(chan struct{}, 1),
+		stats:  WebhookStats{Endpoints: len(cfg.URLs)},
+	}
+	if db != nil {
+		d.queue = &dbWebhookQueue{repo: repository.NewWebhookRepository(db)}
+		d.stats.Storage = "database"
+	} else {
+		d.queue = &memoryWebhookQueue{}
+		d.stats.Storage = "memory"
+	}
+	if len(cfg.Events) > 0 {
+		d.events = make(map[string]bool, len(cfg.Events))
+		for _, e := range cfg.Events {
+			d.events[e] = true
+		}
+	}
+	webhookDispatcher = d
+	return d
+}
+
+// GetWebhookDispatcher 전역 webhook 전송기 반환 (미설정이면 nil)
+func GetWebhookDispatcher() *WebhookDispatcher {
+	return webhookDispatcher
+}
+
+// EmitAuthEvent 전역 전송기로 인증 이벤트 발행 (미설정이면 무시)
+func EmitAuthEvent(event model.AuthEvent) {
+	if d := GetWebhookDispatcher(); d != nil {
+		d.Emit(event)
+	}
+}
+
+// Emit 이벤트를 저장 버퍼에 넣고 바로 반환한다. 버퍼가 가득 차면 이벤트를 버리고 통계에 남긴다.
+func (d *WebhookDispatcher) Emit(event model.AuthEvent) {
+	if d.events != nil && !d.events[event.Type] {
+		return
+	}
+	select {
+	case d.emits <- event:
+	default:
+		d.count(func(s *WebhookStats) { s.Dropped++ })
+		log.Printf("[Webhook] emit buffer full, dropped %s", event.Type)
+	}
+}
+
+// enqueue 이벤트를 엔드포인트별로 대기열에 넣는다. ID/시각/스키마 버전이 비어 있으면 채운다.
+func (d *WebhookDispatcher) enqueue(event model.AuthEvent) {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	if event.SchemaVersion == 0 {
+		event.SchemaVersion = model.AuthEventSchemaVersion
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[Webhook] failed to encode event %s: %v", event.Type, err)
+		return
+	}
+
+	now := time.Now()
+	for _, endpoint := range d.cfg.URLs {
+		delivery := &model.WebhookDelivery{
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Endpoint:      endpoint,
+			Payload:       string(payload),
+			NextAttemptAt: now,
+		}
+		if err := d.queue.Enqueue(delivery); err != nil {
+			d.recordError(fmt.Errorf("enqueue %s: %w", event.Type, err))
+			continue
+		}
+	}
+	d.count(func(s *WebhookStats) { s.Emitted++ })
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drainEmits 버퍼에 남은 이벤트를 모두 대기열에 넣는다 (종료 시 유실 방지)
+func (d *WebhookDispatcher) drainEmits() {
+	for {
+		select {
+		case event := <-d.emits:
+			d.enqueue(event)
+		default:
+			return
+		}
+	}
+}
+
+// Start 저장/전송 goroutine 시작. 전송은 새 이벤트가 들어오거나 PollInterval마다 대기열을 확인한다.
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+	RegisterStatusProvider("webhooks", func() interface{} { return d.Stats() })
+
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				d.drainEmits()
+				return
+			case event := <-d.emits:
+				d.enqueue(event)
+			}
+		}
+	}()
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.cfg.PollInterval)
+		defer ticker.Stop()
+
+		d.RunOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[Webhook] stopped")
+				return
+			case <-ticker.C:
+				d.RunOnce(ctx)
+			case <-d.wake:
+				d.RunOnce(ctx)
+			}
+		}
+	}()
+	log.Printf("[Webhook] started (endpoints=%d, storage=%s)", len(d.cfg.URLs), d.stats.Storage)
+}
+
+// Stop 전송 goroutine 종료 후 진행 중인 전송이 끝날 때까지 대기 (graceful shutdown)
+func (d *WebhookDispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+// RunOnce 전송 시각이 된 항목을 모두 처리한다
+func (d *WebhookDispatcher) RunOnce(ctx context.Context) {
+	// 전송 중 다른 replica가 같은 항목을 가져가지 않도록 전송 타임아웃보다 넉넉히 lease를 잡는다
+	lease := 2*d.cfg.Timeout + time.Minute
+	for ctx.Err() == nil {
+		due, err := d.queue.ClaimDue(time.Now(), lease, webhookBatchSize)
+		if err != nil {
+			d.recordError(fmt.Errorf("claim: %w", err))
+			return
+		}
+		for i := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			d.deliver(ctx, &due[i])
+		}
+		if len(due) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// RequeueDeadLetter dead-letter 항목을 대기열에 되돌린다
+func (d *WebhookDispatcher) RequeueDeadLetter(id uint) (bool, error) {
+	ok, err := d.queue.RequeueDeadLetter(id, time.Now())
+	if ok {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+	return ok, err
+}
+
+// DeadLetters dead-letter 목록
+func (d *WebhookDispatcher) DeadLetters(limit int) ([]model.WebhookDeadLetter, error) {
+	return d.queue.ListDeadLetters(limit)
+}
+
+// Stats 전송 통계
+func (d *WebhookDispatcher) Stats() WebhookStats {
+	queued, dead, err := d.queue.Counts()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := d.stats
+	if err == nil {
+		stats.Queued, stats.DeadLetters = queued, dead
+	}
+	return stats
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	err := d.post(ctx, delivery)
+	if err == nil {
+		if err := d.queue.Delete(delivery.ID); err != nil {
+			d.recordError(fmt.Errorf("delete delivery %d: %w", delivery.ID, err))
+		}
+		d.count(func(s *WebhookStats) { s.Delivered++ })
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		log.Printf("[Webhook] giving up %s (%s) to %s after %d attempts: %v",
+			delivery.EventType, delivery.EventID, delivery.Endpoint, delivery.Attempts, err)
+		if err := d.queue.MoveToDeadLetter(delivery); err != nil {
+			d.recordError(fmt.Errorf("dead-letter delivery %d: %w", delivery.ID, err))
+		}
+		return
+	}
+	d.count(func(s *WebhookStats) { s.Failed++ })
+	next := time.Now().Add(webhookBackoff(d.cfg.RetryBackoff, delivery.Attempts))
+	if err := d.queue.Reschedule(delivery.ID, delivery.Attempts, next, delivery.LastError); err != nil {
+		d.recordError(fmt.Errorf("reschedule delivery %d: %w", delivery.ID, err))
+	}
+}
+
+// post 서명된 이벤트 전송. 2xx가 아니면 에러
+func (d *WebhookDispatcher) post(ctx context.Context, delivery *model.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookEventIDHeader, delivery.EventID)
+	req.Header.Set(WebhookSchemaHeader, strconv.Itoa(payloadSchemaVersion(delivery.Payload)))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(d.cfg.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// payloadSchemaVersion 저장된 이벤트의 스키마 버전 (재시도·dead-letter 재전송 시 현재 버전이 아닌 발행 당시 버전)
+func payloadSchemaVersion(payload string) int {
+	var event struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil || event.SchemaVersion == 0 {
+		return model.AuthEventSchemaVersion
+	}
+	return event.SchemaVersion
+}
+
+// SignWebhookPayload X-MCWC-Signature 값 생성.
+// 수신 측은 t와 본문으로 같은 HMAC을 계산해 비교하고, t가 오래된 요청은 재전송 공격으로 거부할 수 있다.
+func SignWebhookPayload(secret string, at time.Time, body []byte) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff attempts번째 실패 후 대기 시간 (base * 2^(attempts-1), 최대 1시간)
+func webhookBackoff(base time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > webhookMaxBackoff {
+		wait = webhookMaxBackoff
+	}
+	return wait
+}
+
+func (d *WebhookDispatcher) count(update func(*WebhookStats)) {
+	d.mu.Lock()
+	update(&d.stats)
+	d.mu.Unlock()
+}
+
+func (d *WebhookDispatcher) recordError(err error) {
+	log.Printf("[Webhook] %v", err)
+	d.count(func(s *WebhookStats) {
+		s.LastError = err.Error()
+		s.LastErrorAt = time.Now()
+	})
+}
+
+func newEventID() string {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(raw)
+}
+
+// webhookQueue 전송 대기열 저장소 (DB 또는 메모리)
+type webhookQueue interface {
+	Enqueue(delivery *model.WebhookDelivery) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	Delete(id uint) error
+	Reschedule(id uint, attempts int, next time.Time, lastErr string) error
+	MoveToDeadLetter(delivery *model.WebhookDelivery) error
+	ListDeadLetters(limit int) ([]model.WebhookDeadLetter, error)
+	RequeueDeadLetter(id uint, now time.Time) (bool, error)
+	Counts() (queued, dead int64, err error)
+}
+
+// dbWebhookQueue webhook_deliveries/webhook_dead_letters 테이블 대기열
+type dbWebhookQueue struct {
+	repo *repository.WebhookRepository
+}
+
+func (q *dbWebhookQueue) Enqueue(delivery *model.WebhookDelivery) error {
+	return q.repo.Enqueue(delivery)
+}
+
+func (q *dbWebhookQueue) ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	return q.repo.ClaimDue(now, lease, limit)
+}
+
+func (q *dbWebhookQueue) Delete(id uint) error {
+	return q.repo.Delete(id)
+}
+
+func (q *dbWebhookQueue) Reschedule(id uint, attempts int, next time.Time, lastErr string) error {
+	return q.repo.Reschedule(id, attempts, next, lastErr)
+}
+
+func (q *dbWebhookQueue) MoveToDeadLetter(delivery *model.WebhookDelivery) error {
+	return q.repo.MoveToDeadLetter(delivery)
+}
+
+func (q *dbWebhookQueue) ListDeadLetters(limit int) ([]model.WebhookDeadLetter, error) {
+	return q.repo.ListDeadLetters(limit)
+}
+
+func (q *dbWebhookQueue) RequeueDeadLetter(id uint, now time.Time) (bool, error) {
+	return q.repo.RequeueDeadLetter(id, now)
+}
+
+func (q *dbWebhookQueue) Counts() (int64, int64, error) {
+	return q.repo.Counts()
+}
+
+// memoryWebhookQueue DB가 없을 때의 프로세스 내 대기열 (재시작 시 유실, dead-letter는 최근 100건만 보관)
+type memoryWebhookQueue struct {
+	mu     sync.Mutex
+	nextID uint
+	items  map[uint]*model.WebhookDelivery
+	dead   []model.WebhookDeadLetter
+}
+
+func (q *memoryWebhookQueue) Enqueue(delivery *model.WebhookDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.items == nil {
+		q.items = make(map[uint]*model.WebhookDelivery)
+	}
+	q.nextID++
+	delivery.ID = q.nextID
+	delivery.CreatedAt = time.Now()
+	copied := *delivery
+	q.items[copied.ID] = &copied
+	return nil
+}
+
+func (q *memoryWebhookQueue) ClaimDue(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var due []model.WebhookDelivery
+	for _, item := range q.items {
+		if !item.NextAttemptAt.After(now) {
+			due = append(due, *item)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, d := range due {
+		q.items[d.ID].NextAttemptAt = now.Add(lease)
+	}
+	return due, nil
+}
+
+func (q *memoryWebhookQueue) Delete(id uint) error {
+	q.mu.Lock()
+	delete(q.items, id)
+	q.mu.Unlock()
+	return nil
+}
+
+func (q *memoryWebhookQueue) Reschedule(id uint, attempts int, next time.Time, lastErr string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if item, ok := q.items[id]; ok {
+		item.Attempts, item.NextAttemptAt, item.LastError = attempts, next, lastErr
+	}
+	return nil
+}
+
+func (q *memoryWebhookQueue) MoveToDeadLetter(delivery *model.WebhookDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.items, delivery.ID)
+	q.nextID++
+	q.dead = append(q.dead, model.WebhookDeadLetter{
+		ID:        q.nextID,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		Endpoint:  delivery.Endpoint,
+		Payload:   delivery.Payload,
+		Attempts:  delivery.Attempts,
+		LastError: delivery.LastError,
+		CreatedAt: time.Now(),
+	})
+	if len(q.dead) > webhookMemoryDeadLimit {
+		q.dead = q.dead[len(q.dead)-webhookMemoryDeadLimit:]
+	}
+	return nil
+}
+
+func (q *memoryWebhookQueue) ListDeadLetters(limit int) ([]model.WebhookDeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	list := make([]model.WebhookDeadLetter, 0, len(q.dead))
+	for i := len(q.dead) - 1; i >= 0 && len(list) < limit; i-- {
+		list = append(list, q.dead[i])
+	}
+	return list, nil
+}
+
+func (q *memoryWebhookQueue) RequeueDeadLetter(id uint, now time.Time) (bool, error) {
+	q.mu.Lock()
+	var found *model.WebhookDeadLetter
+	for i := range q.dead {
+		if q.dead[i].ID == id {
+			dead := q.dead[i]
+			found = &dead
+			q.dead = append(q.dead[:i], q.dead[i+1:]...)
+			break
+		}
+	}
+	q.mu.Unlock()
+	if found == nil {
+		return false, nil
+	}
+	return true, q.Enqueue(&model.WebhookDelivery{
+		EventID:       found.EventID,
+		EventType:     found.EventType,
+		Endpoint:      found.Endpoint,
+		Payload:       found.Payload,
+		NextAttemptAt: now,
+	})
+}
+
+func (q *memoryWebhookQueue) Counts() (int64, int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.items)), int64(len(q.dead)), nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mc_web_console_api/internal/config"
+	"mc_web_console_api/internal/model"
+)
+
+// webhookReceiver 요청 헤더/본문을 기록하고 status로 응답하는 수신 서버
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	headers  []http.Header
+	payloads []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.headers = append(r.headers, req.Header.Clone())
+	r.payloads = append(r.payloads, string(body))
+	status := r.status
+	r.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func newTestWebhookDispatcher(t *testing.T, status, maxAttempts int) (*WebhookDispatcher, *webhookReceiver) {
+	t.Helper()
+	receiver := &webhookReceiver{status: status}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+	d := InitWebhookDispatcher(config.WebhookConfig{
+		URLs:        []string{server.URL},
+		Secret:      "test-webhook-secret",
+		MaxAttempts: maxAttempts,
+		Timeout:     5 * time.Second,
+	}, nil)
+	t.Cleanup(func() { webhookDispatcher = nil })
+	if d == nil {
+		t.Fatal("InitWebhookDispatcher returned nil")
+	}
+	return d, receiver
+}
+
+// verifySignature 수신 측 검증 절차: t와 본문으로 HMAC을 다시 계산해 v1과 비교
+func verifySignature(secret, header, body string) bool {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		if v, ok := strings.CutPrefix(part, "t="); ok {
+			ts = v
+		}
+		if v, ok := strings.CutPrefix(part, "v1="); ok {
+			sig = v
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "." + body))
+	return ts != "" && hmac.Equal([]byte(sig), []byte(hex.EncodeToString(mac.Sum(nil))))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"auth.login.succeeded"}`)
+	got := SignWebhookPayload("secret", at, body)
+	if !strings.HasPrefix(got, "t=1700000000,v1=") {
+		t.Fatalf("SignWebhookPayload = %q, want t=1700000000 prefix", got)
+	}
+	if !verifySignature("secret", got, string(body)) {
+		t.Fatal("signature does not verify")
+	}
+	if verifySignature("other-secret", got, string(body)) || verifySignature("secret", got, string(body)+" ") {
+		t.Fatal("signature verified with a wrong secret or a modified body")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{12, 2048 * time.Second},
+		{13, webhookMaxBackoff},
+		{100, webhookMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(time.Second, tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(1s, %d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestInitWebhookDispatcherRequiresSecret(t *testing.T) {
+	t.Cleanup(func() { webhookDispatcher = nil })
+	if d := InitWebhookDispatcher(config.WebhookConfig{URLs: []string{"http://127.0.0.1:1/hook"}}, nil); d != nil {
+		t.Fatal("dispatcher enabled without a signing secret")
+	}
+	if GetWebhookDispatcher() != nil {
+		t.Fatal("global dispatcher set without a signing secret")
+	}
+}
+
+func TestWebhookEmitDoesNotWriteQueue(t *testing.T) {
+	d, _ := newTestWebhookDispatcher(t, http.StatusOK, 3)
+	d.Emit(model.AuthEvent{Type: model.AuthEventLogout})
+	if queued, _, _ := d.queue.Counts(); queued != 0 {
+		t.Fatalf("queued after Emit = %d, want 0 (stored by the background goroutine)", queued)
+	}
+	d.drainEmits()
+	if queued, _, _ := d.queue.Counts(); queued != 1 {
+		t.Fatalf("queued after drain = %d, want 1", queued)
+	}
+}
+
+func TestWebhookDeliverySignedWithStoredSchemaVersion(t *testing.T) {
+	d, receiver := newTestWebhookDispatcher(t, http.StatusOK, 3)
+	d.enqueue(model.AuthEvent{Type: model.AuthEventLogout, SchemaVersion: model.AuthEventSchemaVersion + 1})
+	d.RunOnce(context.Background())
+
+	if len(receiver.headers) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(receiver.headers))
+	}
+	header := receiver.headers[0]
+	if !verifySignature("test-webhook-secret", header.Get(WebhookSignatureHeader), receiver.payloads[0]) {
+		t.Errorf("signature %q does not verify", header.Get(WebhookSignatureHeader))
+	}
+	if got, want := header.Get(WebhookSchemaHeader), strconv.Itoa(model.AuthEventSchemaVersion+1); got != want {
+		t.Errorf("%s = %s, want %s (stored event version)", WebhookSchemaHeader, got, want)
+	}
+	if header.Get(WebhookEventHeader) != model.AuthEventLogout || header.Get(WebhookEventIDHeader) == "" {
+		t.Errorf("event headers = %v", header)
+	}
+	if queued, _, _ := d.queue.Counts(); queued != 0 {
+		t.Errorf("queued after delivery = %d, want 0", queued)
+	}
+}
+
+func TestWebhookDeadLettersAfterMaxAttempts(t *testing.T) {
+	d, receiver := newTestWebhookDispatcher(t, http.StatusInternalServerError, 2)
+	d.enqueue(model.AuthEvent{Type: model.AuthEventLoginFailed})
+
+	// RetryBackoff 0: 재시도 항목은 바로 다시 전송 대상이 된다
+	d.RunOnce(context.Background())
+	if queued, dead, _ := d.queue.Counts(); queued != 1 || dead != 0 {
+		t.Fatalf("after first failure queued=%d dead=%d, want 1/0", queued, dead)
+	}
+	d.RunOnce(context.Background())
+	queued, dead, _ := d.queue.Counts()
+	if queued != 0 || dead != 1 {
+		t.Fatalf("after max attempts queued=%d dead=%d, want 0/1", queued, dead)
+	}
+	if len(receiver.headers) != 2 {
+		t.Fatalf("delivery attempts = %d, want 2", len(receiver.headers))
+	}
+	letters, _ := d.DeadLetters(10)
+	if len(letters) != 1 || letters[0].Attempts != 2 || !strings.Contains(letters[0].LastError, "500") {
+		t.Fatalf("dead letters = %+v", letters)
+	}
+
+	// 재전송하면 대기열로 돌아간다
+	if ok, err := d.RequeueDeadLetter(letters[0].ID); !ok || err != nil {
+		t.Fatalf("RequeueDeadLetter = %v, %v", ok, err)
+	}
+	if queued, dead, _ := d.queue.Counts(); queued != 1 || dead != 0 {
+		t.Fatalf("after requeue queued=%d dead=%d, want 1/0", queued, dead)
+	}
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnknownKeyID 토큰의 kid가 키 집합에 없음 (키 회전 후 재조회 필요)
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// KeySet kid → RSA 공개키 (mc-iam-manager /api/auth/certs JWKS)
+type KeySet map[string]*rsa.PublicKey
+
+// jwk JWKS의 개별 키 (RSA 서명 키만 사용)
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// ParseJWKS JWKS JSON({"keys":[...]})에서 RSA 서명 키를 읽는다.
+func ParseJWKS(data []byte) (KeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(KeySet)
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus for kid %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent for kid %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks contains no RSA signing keys")
+	}
+	return keys, nil
+}
+
+// ParseTokenRS256 mc-iam-manager(Keycloak) 발급 RS256 토큰을 키 집합으로 검증하고
+// 공통 Claims와 전체 역할 목록을 반환한다. kid가 없으면 ErrUnknownKeyID를 감싸서 반환한다.
+//
+// 사용자 ID는 upn → preferred_username → sub 순서로 결정한다.
+func ParseTokenRS256(tokenString string, keys KeySet) (*Claims, []string, error) {
+	mapClaims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, mapClaims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, nil, fmt.Errorf("invalid token")
+	}
+
+	claims := &Claims{
+		UserID:   firstStringClaim(mapClaims, "upn", "preferred_username", "sub"),
+		UserName: firstStringClaim(mapClaims, "name", "preferred_username"),
+		Email:    firstStringClaim(mapClaims, "email"),
+	}
+	roles := rolesFromClaims(mapClaims)
+	claims.Role = firstStringClaim(mapClaims, "role")
+	if claims.Role == "" && len(roles) > 0 {
+		claims.Role = roles[0]
+	}
+	return claims, roles, nil
+}
+
+// IsRS256 서명 검증 없이 토큰 헤더의 alg가 RS256인지 확인 (검증기 선택용)
+func IsRS256(tokenString string) bool {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+	return token.Method.Alg() == jwt.SigningMethodRS256.Alg()
+}
+
+func firstStringClaim(claims jwt.MapClaims, names ...string) string {
+	for _, name := range names {
+		if v, ok := claims[name].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// PeekUserID 서명 검증 없이 토큰의 사용자 ID(upn → preferred_username → sub) 추출.
+// ParseTokenRS256과 같은 규칙이므로 로그인 시 세션 사용자 키를 토큰 검증 결과와 일치시키는 데 사용한다.
+func PeekUserID(tokenString string) string {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return ""
+	}
+	return firstStringClaim(claims, "upn", "preferred_username", "sub")
+}
+
+// PeekExpiry 서명 검증 없이 토큰의 exp 시각 추출 (없거나 파싱 실패 시 zero time)
+func PeekExpiry(tokenString string) time.Time {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return time.Time{}
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return time.Time{}
+	}
+	return exp.Time
+}
+
+// IsImpersonationToken 서명 검증 없이 act 클레임 존재 여부 확인 (검증 미들웨어 선택용)
+func IsImpersonationToken(tokenString string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return false
+	}
+	_, ok := claims["act"]
+	return ok
+}
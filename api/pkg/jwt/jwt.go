@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -13,9 +15,26 @@ type Claims struct {
 	UserName string `json:"name"`     // 사용자 이름
 	Email    string `json:"email"`    // 이메일
 	Role     string `json:"role"`     // 역할
+	// Act 대리(impersonation) 토큰의 실제 수행자 (RFC 8693 act 클레임). 일반 토큰이면 nil
+	Act *Actor `json:"act,omitempty"`
+	// IAMUserID 대리 대상의 mc-iam-manager 내부 사용자 ID (MCIAM 모드 대리 토큰에서만 사용)
+	IAMUserID string `json:"iam_uid,omitempty"`
+	// TokenType 토큰 용도. refresh token은 TokenTypeRefresh, access token은 빈 값
+	TokenType string `json:"typ,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenTypeRefresh refresh token의 typ 클레임 값 (access token으로 사용 불가)
+const TokenTypeRefresh = "refresh"
+
+// Actor 대리 토큰을 발급받은 관리자
+type Actor struct {
+	Subject     string `json:"sub"` // 관리자 사용자 ID
+	SessionHash string `json:"sid"` // 관리자 세션 access token 해시 (관리자 로그아웃 시 대리 토큰도 무효)
+	SessionID   string `json:"ses,omitempty"` // 관리자 세션 ID (관리자 access token이 갱신되어도 같은 세션에 바인딩)
+	ReadOnly    bool   `json:"ro"`  // 읽기 전용 대리 여부
+}
+
 var (
 	// JWT 시크릿 키 (환경 변수에서 로드해야 함)
 	secretKey = []byte("your-secret-key-change-in-production")
@@ -28,12 +47,22 @@ func SetSecretKey(key string) {
 
 // GenerateToken JWT 토큰 생성
 func GenerateToken(userID, userName, email, role string, expiresIn time.Duration) (string, error) {
+	return generateUserToken(userID, userName, email, role, "", expiresIn)
+}
+
+// GenerateRefreshToken typ=refresh 클레임을 가진 refresh token 생성 (ParseRefreshToken으로만 검증)
+func GenerateRefreshToken(userID, userName, email, role string, expiresIn time.Duration) (string, error) {
+	return generateUserToken(userID, userName, email, role, TokenTypeRefresh, expiresIn)
+}
+
+func generateUserToken(userID, userName, email, role, tokenType string, expiresIn time.Duration) (string, error) {
 	now := time.Now()
 	claims := &Claims{
-		UserID:   userID,
-		UserName: userName,
-		Email:    email,
-		Role:     role,
+		UserID:    userID,
+		UserName:  userName,
+		Email:     email,
+		Role:      role,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -50,6 +79,31 @@ func GenerateToken(userID, userName, email, role string, expiresIn time.Duration
 	return tokenString, nil
 }
 
+// GenerateImpersonationToken 관리자가 대상 사용자로 동작하는 단기 대리 토큰 생성 (갱신 불가)
+func GenerateImpersonationToken(target Claims, actor Actor, expiresIn time.Duration) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    target.UserID,
+		UserName:  target.UserName,
+		Email:     target.Email,
+		Role:      target.Role,
+		IAMUserID: target.IAMUserID,
+		Act:       &actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(secretKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
 // ParseToken JWT 토큰 파싱 및 검증
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -82,10 +136,22 @@ func ValidateToken(tokenString string) error {
 	return err
 }
 
+// ParseRefreshToken refresh token 검증. typ=refresh 클레임이 없는 토큰(access token, 대리 토큰)은 거부한다.
+func ParseRefreshToken(tokenString string) (*Claims, error) {
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh || claims.Act != nil {
+		return nil, fmt.Errorf("not a refresh token")
+	}
+	return claims, nil
+}
+
 // RefreshToken 리프레시 토큰으로 새 액세스 토큰 생성
 func RefreshToken(refreshTokenString string, accessTokenDuration time.Duration) (string, error) {
 	// 리프레시 토큰 파싱
-	claims, err := ParseToken(refreshTokenString)
+	claims, err := ParseRefreshToken(refreshTokenString)
 	if err != nil {
 		return "", fmt.Errorf("invalid refresh token: %w", err)
 	}
@@ -118,3 +184,38 @@ func ExtractUserID(tokenString string) (string, error) {
 func GetTokenClaims(tokenString string) (*Claims, error) {
 	return ParseToken(tokenString)
 }
+
+// TokenHash 토큰 문자열의 SHA-256 hex 해시 반환 (세션 바인딩/저장용, 원문 비교 회피)
+func TokenHash(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
+
+// PeekRoles 서명 검증 없이 토큰에서 역할 목록을 추출한다.
+// 신뢰 채널(mc-iam-manager 로그인 응답 등)로 직접 받은 토큰에만 사용해야 한다.
+// "role" 클레임과 Keycloak 형식 "realm_access.roles"를 모두 지원한다.
+func PeekRoles(tokenString string) []string {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return nil
+	}
+	return rolesFromClaims(claims)
+}
+
+// rolesFromClaims "role" 클레임과 Keycloak 형식 "realm_access.roles"에서 역할 목록 추출
+func rolesFromClaims(claims jwt.MapClaims) []string {
+	var roles []string
+	if role, ok := claims["role"].(string); ok && role != "" {
+		roles = append(roles, role)
+	}
+	if realmAccess, ok := claims["realm_access"].(map[string]interface{}); ok {
+		if list, ok := realmAccess["roles"].([]interface{}); ok {
+			for _, r := range list {
+				if s, ok := r.(string); ok && s != "" {
+					roles = append(roles, s)
+				}
+			}
+		}
+	}
+	return roles
+}
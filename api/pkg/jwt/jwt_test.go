@@ -0,0 +1,34 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRefreshTokenRequiresType(t *testing.T) {
+	refresh, err := GenerateRefreshToken("user01", "User", "user01@example.com", "viewer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	access, err := GenerateToken("user01", "User", "user01@example.com", "viewer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impersonation, err := GenerateImpersonationToken(Claims{UserID: "user01"}, Actor{Subject: "admin"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := ParseRefreshToken(refresh)
+	if err != nil || claims.UserID != "user01" || claims.TokenType != TokenTypeRefresh {
+		t.Fatalf("ParseRefreshToken(refresh) = %+v, %v", claims, err)
+	}
+	for name, token := range map[string]string{"access": access, "impersonation": impersonation} {
+		if _, err := ParseRefreshToken(token); err == nil {
+			t.Errorf("ParseRefreshToken(%s token) succeeded, want error", name)
+		}
+		if _, err := RefreshToken(token, time.Hour); err == nil {
+			t.Errorf("RefreshToken(%s token) succeeded, want error", name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// Box AES-256-GCM 기반 필드 암호화기. DB에 저장되는 비밀값(MFA 시크릿 등)을 암호화한다.
+// 키 문자열은 SHA-256으로 32바이트 키로 유도한다.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New 키 문자열로 Box 생성
+func New(key string) (*Box, error) {
+	if key == "" {
+		return nil, fmt.Errorf("encryption key is empty")
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal 평문 암호화 → base64(nonce + ciphertext)
+func (b *Box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open Seal 결과 복호화
+func (b *Box) Open(encoded string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode sealed value: %w", err)
+	}
+	nonceSize := b.aead.NonceSize()
+	if len(raw) < nonceSize {
+		return "", fmt.Errorf("sealed value too short")
+	}
+	plain, err := b.aead.Open(nil, raw[:nonceSize], raw[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt sealed value: %w", err)
+	}
+	return string(plain), nil
+}
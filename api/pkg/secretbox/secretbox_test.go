@@ -0,0 +1,57 @@
+package secretbox
+
+import "testing"
+
+func TestSealOpen(t *testing.T) {
+	box, err := New("key-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed == "JBSWY3DPEHPK3PXP" {
+		t.Fatal("sealed value is plaintext")
+	}
+	again, _ := box.Seal("JBSWY3DPEHPK3PXP")
+	if again == sealed {
+		t.Error("nonce is reused")
+	}
+	plain, err := box.Open(sealed)
+	if err != nil || plain != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	box, _ := New("key-1")
+	other, _ := New("key-2")
+	sealed, _ := box.Seal("secret")
+
+	tampered := []byte(sealed)
+	tampered[len(tampered)-3] ^= 1
+	tests := map[string]string{
+		"wrong key":  "",
+		"tampered":   string(tampered),
+		"too short":  "AAAA",
+		"not base64": "%%%",
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, input := box, value
+			if name == "wrong key" {
+				b, input = other, sealed
+			}
+			if _, err := b.Open(input); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewEmptyKey(t *testing.T) {
+	if _, err := New(""); err == nil {
+		t.Error("empty key accepted")
+	}
+}
@@ -1,27 +1,24 @@
 #!/bin/bash
-FILE="./api.yaml"
+# api.yaml 동기화 — API 서버의 asset-sync 명령을 사용한다.
+# 원격 api.yaml을 검증하고, 로컬 baseurl을 유지하며, 기존 파일을 ../conf/backup 에 백업한 뒤 교체한다.
+#   되돌리기: (cd ../api && go run ./cmd asset-sync rollback api)
+#   실행 중인 서버 반영: POST /api/admin/asset-sync/api/reload (또는 재시작)
 
-read -p "MCIAM USE? (y/n): " MCIAM_USE 
+read -p "MCIAM USE? (y/n): " MCIAM_USE
 
 case "$MCIAM_USE" in
-    y|Y ) URL="https://raw.githubusercontent.com/m-cmp/mc-admin-cli/refs/heads/main/conf/api.yaml";; # m-cmp/mc-admin-cli
-    n|N ) URL="https://raw.githubusercontent.com/cloud-barista/cm-mayfly/main/conf/api.yaml";; # cloud-barista/cm-mayfly
+    y|Y ) export MC_ADMIN_CLI_APIYAML="https://raw.githubusercontent.com/m-cmp/mc-admin-cli/refs/heads/main/conf/api.yaml";; # m-cmp/mc-admin-cli
+    n|N ) export MC_ADMIN_CLI_APIYAML="https://raw.githubusercontent.com/cloud-barista/cm-mayfly/main/conf/api.yaml";; # cloud-barista/cm-mayfly
+    * ) echo "Invalid input, operation cancelled."; exit 1;;
 esac
 
-if [ -f "$FILE" ]; then
-    echo "api.yaml already exists."
-    read -p "Are you sure you want to overwrite it? (y/n): " choice
-    case "$choice" in
-        y|Y ) echo "Overwrite file...";;
-        n|N ) echo "The operation has been canceled."; exit 0;;
-        * ) echo "Invalid input, operation cancelled."; exit 1;;
-    esac
+cd "$(dirname "$0")/../api" || exit 1
+if [ -x ./api ]; then
+    ./api asset-sync apply api
+else
+    go run ./cmd asset-sync apply api
 fi
-
-wget -O "./api.yaml" "$URL"
 if [ $? -ne 0 ]; then
     echo "ERROR: api.yaml update failed."
     exit 1
 fi
-
-echo "File updated successfully, please change baseUrl."
\ No newline at end of file
@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"crypto/sha256"
 	"net/http"
 
 	"front/middleware"
@@ -26,7 +27,11 @@ func App() *echo.Echo {
 		app.HideBanner = true
 
 		// Session middleware (using Gorilla sessions)
-		store := sessions.NewCookieStore([]byte(SESSION_SECRET))
+		// API tokens are kept server-side: session files are signed and AES-encrypted
+		// with keys derived from SESSION_SECRET; the browser only holds the session id.
+		hashKey, blockKey := sessionKeys(SESSION_SECRET)
+		store := sessions.NewFilesystemStore(SESSION_DIR, hashKey, blockKey)
+		store.MaxLength(64 * 1024)
 		store.Options = &sessions.Options{
 			Path:     "/",
 			MaxAge:   86400 * 7, // 7 days
@@ -44,8 +49,11 @@ func App() *echo.Echo {
 		// Recover middleware
 		app.Use(echomiddleware.Recover())
 
+		// CSRF protection for cookie-authenticated state-changing requests
+		app.Use(middleware.CSRFMiddleware(CSRF_TRUSTED_ORIGINS))
+
 		// Custom auth middleware (applied globally, skipped for specific routes)
-		app.Use(middleware.IsTokenExistMiddleware)
+		app.Use(middleware.IsTokenExistMiddleware(refreshViaAPI))
 
 		// Health check endpoint (no auth required)
 		app.GET("/readyz", readyz)
@@ -56,11 +64,14 @@ func App() *echo.Echo {
 		auth.GET("/logout", UserLogout)
 		auth.GET("/unauthorized", UserUnauthorized)
 		auth.GET("/signup", UserSignup)
+		auth.GET("/plugin-token", PluginToken)
 
 		// API auth endpoints (no auth required)
 		authapi := app.Group("/api")
 		authapi.POST("/auth/login", SessionInitializer)
-		authapi.POST("/auth/refresh", SessionInitializer)
+		authapi.POST("/auth/refresh", RefreshSession)
+		authapi.POST("/auth/login/mfa", SessionInitializer)
+		authapi.POST("/auth/login/mfa/enroll", LoginMFAEnrollProxy)
 		authapi.POST("/auth/signup", SignupProxy)
 
 		// Root redirect
@@ -95,6 +106,13 @@ func App() *echo.Echo {
 	return app
 }
 
+// sessionKeys derives the session signing (HMAC) and encryption (AES-256) keys from the secret
+func sessionKeys(secret string) ([]byte, []byte) {
+	hashKey := sha256.Sum256([]byte("mc-web-console/session/hash:" + secret))
+	blockKey := sha256.Sum256([]byte("mc-web-console/session/block:" + secret))
+	return hashKey[:], blockKey[:]
+}
+
 // forceSSL returns a middleware that adds security headers
 func forceSSL() echo.MiddlewareFunc {
 	secureMiddleware := secure.New(secure.Options{
@@ -1,10 +1,20 @@
 package actions
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"front/middleware"
+	"front/templates"
 
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
@@ -22,6 +32,8 @@ func SessionInitializer(c echo.Context) error {
 			req.Header.Add(key, value)
 		}
 	}
+	appendForwardedFor(c, req)
+	forwardCookies(c, req)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -30,6 +42,11 @@ func SessionInitializer(c echo.Context) error {
 	}
 	defer resp.Body.Close()
 
+	// 로그인 성공 시 API가 설정한 인증 쿠키를 세션으로 옮기고 나머지(CSRF 쿠키)만 브라우저에 전달
+	var tokens middleware.SessionTokens
+	middleware.ApplyAuthCookies(&tokens, resp)
+	copySetCookies(c, resp)
+
 	respBody, ioerr := io.ReadAll(resp.Body)
 	if ioerr != nil {
 		log.Println("Error CommonHttp reading response:", ioerr)
@@ -53,27 +70,123 @@ func SessionInitializer(c echo.Context) error {
 		return c.JSON(resp.StatusCode, data)
 	}
 
-	// access_token과 refresh_token 추출 (MCIAM 직접 응답 또는 responseData 래퍼 모두 지원)
-	var accessToken, refreshToken string
-	if at, ok := data["access_token"].(string); ok {
-		// MCIAM 직접 응답 형식: {access_token, refresh_token, ...}
-		accessToken = at
-		refreshToken, _ = data["refresh_token"].(string)
-	} else if responseDataMap, ok := data["responseData"].(map[string]interface{}); ok {
-		// API CommonResponse 래퍼 형식: {responseData: {access_token, ...}}
-		accessToken, _ = responseDataMap["access_token"].(string)
-		refreshToken, _ = responseDataMap["refresh_token"].(string)
-	} else {
-		log.Println("could not find access_token in response")
+	// 2차 인증(MFA) 대기 응답은 토큰 없이 챌린지 정보만 전달
+	if responseDataMap, ok := data["responseData"].(map[string]interface{}); ok {
+		if mfaRequired, _ := responseDataMap["mfa_required"].(bool); mfaRequired {
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"mfa_required":        true,
+				"mfa_token":           responseDataMap["mfa_token"],
+				"enrollment_required": responseDataMap["enrollment_required"],
+				"expires_in":          responseDataMap["expires_in"],
+			})
+		}
+	}
+
+	// 토큰은 서버 세션에만 저장하고 브라우저 JS에는 전달하지 않는다
+	if tokens.AccessToken == "" {
+		log.Println("could not find auth cookies in login response")
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Invalid response format"})
 	}
+	// 로그인 전의 세션 id를 재사용하지 않도록 새 세션으로 교체 (session fixation 방지)
+	if err := middleware.RegenerateSession(c); err != nil {
+		log.Println("failed to regenerate session:", err)
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to create session"})
+	}
+	if err := middleware.SaveSessionTokens(c, tokens); err != nil {
+		log.Println("failed to save session tokens:", err)
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to create session"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"authenticated": true,
+	})
+}
+
+// RefreshSession 세션의 refresh token으로 토큰 갱신 (JS의 401 재시도용, 토큰은 응답하지 않음)
+func RefreshSession(c echo.Context) error {
+	tokens := middleware.LoadSessionTokens(c)
+	if tokens.AccessToken == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{"message": "Not authenticated"})
+	}
+	if tokens.ImpersonatorToken != "" {
+		// 대리 토큰은 갱신하지 않는다 (만료 시 IsTokenExistMiddleware가 관리자 토큰으로 복귀)
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{"message": "Impersonation token cannot be refreshed"})
+	}
+	if _, err := middleware.RefreshSessionTokens(c, tokens, refreshViaAPI); err != nil {
+		log.Println("session refresh failed:", err)
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{"message": "Session has expired"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"refreshed": true,
+	})
+}
+
+// refreshViaAPI API 서버 /api/auth/refresh 호출 (middleware.TokenRefresher)
+func refreshViaAPI(c echo.Context, refreshToken string) (*http.Response, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"request": map[string]string{"refresh_token": refreshToken},
+	})
+	req, err := http.NewRequest(http.MethodPost, ApiBaseHost.String()+"/api/auth/refresh", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	appendForwardedFor(c, req)
+	return (&http.Client{Timeout: 10 * time.Second}).Do(req)
+}
 
+// PluginToken iframe 플러그인(외부 서비스)에 전달할 access token 조회.
+// 플러그인 연동 규약상 토큰을 postMessage로 넘겨야 하므로, 플러그인 화면에서만 필요할 때 조회한다.
+// 같은 출처의 fetch이고 Referer가 사용자에게 허용된 플러그인 페이지(.iframe.html 템플릿)일 때만 응답한다.
+func PluginToken(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+	if err := checkPluginTokenRequest(c); err != nil {
+		log.Printf("plugin token refused: %v", err)
+		return c.JSON(http.StatusForbidden, map[string]interface{}{"message": "Plugin token is only available to plugin pages"})
+	}
+	authorization, _ := c.Get("Authorization").(string)
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
+		"access_token": strings.TrimPrefix(authorization, "Bearer "),
 	})
 }
 
+// checkPluginTokenRequest 플러그인 토큰 요청의 출처 확인: same-origin fetch, 플러그인 페이지 Referer, 메뉴 허용 여부
+func checkPluginTokenRequest(c echo.Context) error {
+	req := c.Request()
+	if site := req.Header.Get("Sec-Fetch-Site"); site != "" && site != "same-origin" {
+		return fmt.Errorf("cross-site request (Sec-Fetch-Site %s)", site)
+	}
+	referer, err := url.Parse(req.Referer())
+	if err != nil || req.Referer() == "" {
+		return fmt.Errorf("missing referer")
+	}
+	page := path.Clean("/" + strings.TrimPrefix(referer.Path, "/webconsole"))
+	if !strings.HasPrefix(referer.Path, "/webconsole/") || !isPluginPage(page) {
+		return fmt.Errorf("referer %s is not a plugin page", referer.Path)
+	}
+	if !MENU_GUARD {
+		return nil
+	}
+	menus, err := LoadUserMenus(c)
+	if err != nil {
+		return fmt.Errorf("menu lookup failed: %w", err)
+	}
+	if current := menus.PageMenu(referer.Path); current == "" || !menus.Granted(current) {
+		return fmt.Errorf("plugin page %s is not granted", referer.Path)
+	}
+	return nil
+}
+
+// isPluginPage reports whether the page (path below /webconsole) is rendered from an iframe plugin template
+func isPluginPage(page string) bool {
+	f, err := templates.FS().Open("pages" + page + ".iframe.html")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
 func UserLogin(c echo.Context) error {
 	// 로그인 페이지는 레이아웃 없이 렌더링
 	return RenderWithoutLayout(c, http.StatusOK, "pages/auth/login.html", nil)
@@ -81,10 +194,15 @@ func UserLogin(c echo.Context) error {
 
 func UserLogout(c echo.Context) error {
 	// Session clear
-	sess, _ := session.Get("mc_web_console", c)
+	sess, _ := session.Get(middleware.SessionName, c)
 	sess.Options.MaxAge = -1
 	sess.Save(c.Request(), c.Response())
 
+	// 이전 방식의 인증 쿠키와 CSRF 쿠키 삭제 (HttpOnly라 브라우저 JS에서 지울 수 없음)
+	for _, name := range []string{middleware.AccessTokenCookie, middleware.RefreshTokenCookie, middleware.ImpersonatorCookie, middleware.CSRFCookie} {
+		c.SetCookie(&http.Cookie{Name: name, Value: "", Path: "/", MaxAge: -1})
+	}
+
 	// 로그아웃 페이지는 레이아웃 없이 렌더링
 	return RenderWithoutLayout(c, http.StatusOK, "pages/auth/logout.html", nil)
 }
@@ -99,7 +217,17 @@ func UserSignup(c echo.Context) error {
 }
 
 func SignupProxy(c echo.Context) error {
-	req, err := http.NewRequest(c.Request().Method, ApiBaseHost.String()+"/api/auth/signup", c.Request().Body)
+	return proxyAuthRequest(c, "/api/auth/signup")
+}
+
+// LoginMFAEnrollProxy 로그인 중 MFA 등록 시작 요청 전달 (시크릿/복구 코드 응답을 그대로 반환)
+func LoginMFAEnrollProxy(c echo.Context) error {
+	return proxyAuthRequest(c, "/api/auth/login/mfa/enroll")
+}
+
+// proxyAuthRequest 인증 전 단계의 요청을 API 서버로 전달하고 응답을 그대로 반환
+func proxyAuthRequest(c echo.Context, path string) error {
+	req, err := http.NewRequest(c.Request().Method, ApiBaseHost.String()+path, c.Request().Body)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": err.Error()})
 	}
@@ -109,6 +237,8 @@ func SignupProxy(c echo.Context) error {
 			req.Header.Add(key, value)
 		}
 	}
+	appendForwardedFor(c, req)
+	forwardCookies(c, req)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -117,6 +247,8 @@ func SignupProxy(c echo.Context) error {
 	}
 	defer resp.Body.Close()
 
+	copySetCookies(c, resp)
+
 	respBody, ioerr := io.ReadAll(resp.Body)
 	if ioerr != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": ioerr.Error()})
@@ -129,3 +261,49 @@ func SignupProxy(c echo.Context) error {
 
 	return c.JSON(resp.StatusCode, data)
 }
+
+// appendForwardedFor 직접 연결한 클라이언트 주소를 X-Forwarded-For에 추가한다.
+// API 서버는 이 값으로 로그인 실패 제한의 IP를 식별한다.
+func appendForwardedFor(c echo.Context, req *http.Request) {
+	host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		host = c.Request().RemoteAddr
+	}
+	if prior := req.Header.Values("X-Forwarded-For"); len(prior) > 0 {
+		host = strings.Join(prior, ", ") + ", " + host
+	}
+	req.Header.Set("X-Forwarded-For", host)
+
+	// API 서버가 쿠키 Secure 속성과 CSRF 출처 검사에 브라우저가 접속한 호스트/스킴을 사용하도록 전달
+	if req.Header.Get("X-Forwarded-Host") == "" {
+		req.Header.Set("X-Forwarded-Host", c.Request().Host)
+	}
+	if req.Header.Get("X-Forwarded-Proto") == "" {
+		req.Header.Set("X-Forwarded-Proto", c.Scheme())
+	}
+}
+
+// forwardCookies API로 보내는 요청에서 브라우저의 토큰 쿠키를 제거한다.
+// API는 쿠키 토큰을 헤더보다 우선하므로, 세션 토큰(Authorization 헤더)이 이전 방식의 쿠키에 가려지지 않게 한다.
+// 대리 중이면 종료 요청 검증용 관리자 토큰을 ImpersonatorToken 쿠키로 붙인다.
+func forwardCookies(c echo.Context, req *http.Request) {
+	cookies := c.Request().Cookies()
+	req.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		switch cookie.Name {
+		case middleware.AccessTokenCookie, middleware.RefreshTokenCookie, middleware.ImpersonatorCookie:
+			continue
+		}
+		req.AddCookie(cookie)
+	}
+	if impersonator, _ := c.Get(middleware.ImpersonatorCookie).(string); impersonator != "" {
+		req.AddCookie(&http.Cookie{Name: middleware.ImpersonatorCookie, Value: impersonator})
+	}
+}
+
+// copySetCookies API 응답의 Set-Cookie(CSRF 쿠키 등)를 브라우저 응답에 전달
+func copySetCookies(c echo.Context, resp *http.Response) {
+	for _, cookie := range resp.Header.Values("Set-Cookie") {
+		c.Response().Header().Add("Set-Cookie", cookie)
+	}
+}
@@ -1,8 +1,12 @@
 package actions
 
 import (
+	"fmt"
+	"log"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 )
 
 var FRONT_ADDR string
@@ -11,9 +15,13 @@ var API_SCHEME string
 var API_ADDR string
 var API_PORT string
 var SESSION_SECRET string
+var SESSION_DIR string
 var INFRA_MANAGER_URL string
 var INFRA_MANAGER_USER string
 var INFRA_MANAGER_PASS string
+var CSRF_TRUSTED_ORIGINS []string
+var MENU_GUARD bool
+var MENU_CACHE_TTL time.Duration
 
 func init() {
 	// Get environment variables with defaults
@@ -22,10 +30,43 @@ func init() {
 	API_SCHEME = getEnvOrDefault("MC_WEB_CONSOLE_API_SCHEME", "http")
 	API_ADDR = getEnvOrDefault("MC_WEB_CONSOLE_API_ADDR", "localhost")
 	API_PORT = getEnvOrDefault("MC_WEB_CONSOLE_API_PORT", "3000")
-	SESSION_SECRET = getEnvOrDefault("MC_WEB_CONSOLE_SESSION_SECRET", "mc-web-console-secret-key")
+	SESSION_SECRET = getEnvOrDefault("MC_WEB_CONSOLE_SESSION_SECRET", "")
+	SESSION_DIR = getEnvOrDefault("MC_WEB_CONSOLE_SESSION_DIR", "")
 	INFRA_MANAGER_URL = getEnvOrDefault("MC_WEB_CONSOLE_INFRA_MANAGER_URL", "http://localhost:1323/tumblebug")
 	INFRA_MANAGER_USER = getEnvOrDefault("MC_WEB_CONSOLE_INFRA_MANAGER_USER", "default")
 	INFRA_MANAGER_PASS = getEnvOrDefault("MC_WEB_CONSOLE_INFRA_MANAGER_PASS", "default")
+	for _, origin := range strings.Split(getEnvOrDefault("MC_WEB_CONSOLE_CSRF_TRUSTED_ORIGINS", ""), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			CSRF_TRUSTED_ORIGINS = append(CSRF_TRUSTED_ORIGINS, origin)
+		}
+	}
+	// Pages whose menu is not granted to the user are rejected unless the guard is disabled
+	MENU_GUARD = getEnvOrDefault("MC_WEB_CONSOLE_FRONT_MENU_GUARD", "true") != "false"
+	ttl, err := time.ParseDuration(getEnvOrDefault("MC_WEB_CONSOLE_FRONT_MENU_CACHE_TTL", "1m"))
+	if err != nil || ttl <= 0 {
+		log.Printf("invalid MC_WEB_CONSOLE_FRONT_MENU_CACHE_TTL, using 1m: %v", err)
+		ttl = time.Minute
+	}
+	MENU_CACHE_TTL = ttl
+}
+
+// defaultSessionSecret is the placeholder shipped in conf/.env.sample
+const defaultSessionSecret = "mc-web-console-secret-key"
+
+// CheckSessionSettings validates the session settings before the server starts.
+// Sessions hold the users' API tokens, so the secret must be set and not the sample value,
+// and the session directory must be chosen explicitly (it is created with 0700 if missing).
+func CheckSessionSettings() error {
+	if SESSION_SECRET == "" || SESSION_SECRET == defaultSessionSecret {
+		return fmt.Errorf("MC_WEB_CONSOLE_SESSION_SECRET must be set to a random value (not the sample %q)", defaultSessionSecret)
+	}
+	if SESSION_DIR == "" {
+		return fmt.Errorf("MC_WEB_CONSOLE_SESSION_DIR must be set to a private directory for session files")
+	}
+	if err := os.MkdirAll(SESSION_DIR, 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory %s: %w", SESSION_DIR, err)
+	}
+	return nil
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
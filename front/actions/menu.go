@@ -0,0 +1,273 @@
+package actions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	// errMenuUnauthorized the API rejected the session token while resolving menus
+	errMenuUnauthorized = errors.New("menu lookup unauthorized")
+	// errMenuForbidden the page belongs to a menu that is not granted to the user
+	errMenuForbidden = errors.New("menu not granted")
+)
+
+// menuResource is one menu of the tree returned by GET /api/auth/menus
+type menuResource struct {
+	Id          string `json:"id"`
+	ParentId    string `json:"parentId"`
+	DisplayName string `json:"displayName"`
+	IsAction    bool   `json:"isAction"`
+	Priority    int    `json:"priority"`
+	MenuNumber  int    `json:"menuNumber"`
+}
+
+// MenuNode is a sidebar node rendered by partials/layout/_sidebar.html
+type MenuNode struct {
+	Id          string
+	DisplayName string
+	IsAction    bool
+	Href        string // /webconsole/<ancestor ids>/<id>
+	Priority    int
+	MenuNumber  int
+	Menus       []*MenuNode
+}
+
+// Breadcrumb is one step of the page header breadcrumb (menus found in the page path)
+type Breadcrumb struct {
+	Id          string
+	DisplayName string
+	Href        string // empty when the menu has no page of its own
+	Active      bool
+}
+
+// UserMenus is the menu state of one session: the full tree, the granted menu ids and the filtered sidebar
+type UserMenus struct {
+	tree    map[string]menuResource
+	granted map[string]bool
+	Sidebar []*MenuNode
+}
+
+// menuStaleTTL is how long past MENU_CACHE_TTL the last-known menus of a session are kept for API outages
+const menuStaleTTL = 15 * time.Minute
+
+// menuCache caches UserMenus per access token for MENU_CACHE_TTL and keeps the last successfully
+// fetched tree, so pages can still be classified as menu-backed or not while the menu API is down
+var menuCache = struct {
+	sync.Mutex
+	entries map[string]menuCacheEntry
+	tree    *UserMenus // tree of the last successful lookup (no grants)
+}{entries: map[string]menuCacheEntry{}}
+
+type menuCacheEntry struct {
+	menus *UserMenus
+	at    time.Time
+}
+
+// LoadUserMenus resolves the menus granted to the session user through the API (cached per access token)
+func LoadUserMenus(c echo.Context) (*UserMenus, error) {
+	key := menuCacheKey(c)
+	menuCache.Lock()
+	for k, entry := range menuCache.entries {
+		if time.Since(entry.at) > MENU_CACHE_TTL+menuStaleTTL {
+			delete(menuCache.entries, k)
+		}
+	}
+	entry, ok := menuCache.entries[key]
+	menuCache.Unlock()
+	if ok && time.Since(entry.at) <= MENU_CACHE_TTL {
+		return entry.menus, nil
+	}
+
+	authorization, _ := c.Get("Authorization").(string)
+	menus, err := fetchUserMenus(c, authorization)
+	if err != nil {
+		return nil, err
+	}
+	menuCache.Lock()
+	menuCache.entries[key] = menuCacheEntry{menus: menus, at: time.Now()}
+	menuCache.tree = &UserMenus{tree: menus.tree}
+	menuCache.Unlock()
+	return menus, nil
+}
+
+// lastKnownMenus returns the session's menus from the last successful lookup within menuStaleTTL, or nil
+func lastKnownMenus(c echo.Context) *UserMenus {
+	menuCache.Lock()
+	defer menuCache.Unlock()
+	entry, ok := menuCache.entries[menuCacheKey(c)]
+	if !ok || time.Since(entry.at) > MENU_CACHE_TTL+menuStaleTTL {
+		return nil
+	}
+	return entry.menus
+}
+
+// lastKnownMenuTree returns the menu tree of the last successful lookup of any session, or nil
+func lastKnownMenuTree() *UserMenus {
+	menuCache.Lock()
+	defer menuCache.Unlock()
+	return menuCache.tree
+}
+
+func menuCacheKey(c echo.Context) string {
+	authorization, _ := c.Get("Authorization").(string)
+	sum := sha256.Sum256([]byte(authorization))
+	return hex.EncodeToString(sum[:])
+}
+
+func fetchUserMenus(c echo.Context, authorization string) (*UserMenus, error) {
+	req, err := http.NewRequest(http.MethodGet, ApiBaseHost.String()+"/api/auth/menus", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", authorization)
+	appendForwardedFor(c, req)
+	forwardCookies(c, req)
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errMenuUnauthorized
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("menu lookup returned %d", resp.StatusCode)
+	}
+
+	var data struct {
+		ResponseData struct {
+			Tree    []menuResource `json:"tree"`
+			Granted []string       `json:"granted"`
+		} `json:"responseData"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("menu lookup response parse failed: %w", err)
+	}
+	return newUserMenus(data.ResponseData.Tree, data.ResponseData.Granted), nil
+}
+
+// newUserMenus builds the sidebar from granted menus only. A granted menu whose parent is not granted
+// is left out of the sidebar (its page stays reachable): promoting it would turn a category or page
+// into a top-level header without a link.
+func newUserMenus(tree []menuResource, granted []string) *UserMenus {
+	menus := &UserMenus{
+		tree:    make(map[string]menuResource, len(tree)),
+		granted: make(map[string]bool, len(granted)),
+		Sidebar: []*MenuNode{},
+	}
+	for _, menu := range tree {
+		menus.tree[menu.Id] = menu
+	}
+	for _, id := range granted {
+		menus.granted[id] = true
+	}
+
+	nodes := make(map[string]*MenuNode)
+	for _, menu := range tree {
+		if menus.granted[menu.Id] {
+			nodes[menu.Id] = &MenuNode{
+				Id:          menu.Id,
+				DisplayName: menu.DisplayName,
+				IsAction:    menu.IsAction,
+				Href:        "/webconsole/" + strings.Join(menus.ancestry(menu.Id), "/"),
+				Priority:    menu.Priority,
+				MenuNumber:  menu.MenuNumber,
+			}
+		}
+	}
+	for _, menu := range tree {
+		node, ok := nodes[menu.Id]
+		if !ok {
+			continue
+		}
+		if parent, ok := nodes[menu.ParentId]; ok && menu.ParentId != menu.Id {
+			parent.Menus = append(parent.Menus, node)
+		} else if _, known := menus.tree[menu.ParentId]; !known || menu.ParentId == menu.Id {
+			// top-level menu (parent "home") or a parent missing from the tree
+			menus.Sidebar = append(menus.Sidebar, node)
+		}
+	}
+	sortMenuNodes(menus.Sidebar)
+	return menus
+}
+
+func sortMenuNodes(nodes []*MenuNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Priority != nodes[j].Priority {
+			return nodes[i].Priority < nodes[j].Priority
+		}
+		return nodes[i].MenuNumber < nodes[j].MenuNumber
+	})
+	for _, node := range nodes {
+		sortMenuNodes(node.Menus)
+	}
+}
+
+// ancestry returns the menu ids from the top level down to id (stops at "home", unknown parents and cycles)
+func (m *UserMenus) ancestry(id string) []string {
+	var path []string
+	seen := map[string]bool{}
+	for id != "" && id != "home" && !seen[id] {
+		menu, ok := m.tree[id]
+		if !ok {
+			break
+		}
+		seen[id] = true
+		path = append([]string{id}, path...)
+		id = menu.ParentId
+	}
+	return path
+}
+
+// PageMenu returns the menu a /webconsole page path belongs to: the deepest path segment that is a menu id.
+// Pages outside the menu tree return "".
+func (m *UserMenus) PageMenu(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/webconsole"), "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if _, ok := m.tree[segments[i]]; ok {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// Granted reports whether the menu id is granted to the user
+func (m *UserMenus) Granted(id string) bool {
+	return m.granted[id]
+}
+
+// Breadcrumb returns the menus found in the page path, in order (the last one is active)
+func (m *UserMenus) Breadcrumb(path string) []Breadcrumb {
+	crumbs := []Breadcrumb{}
+	for _, segment := range strings.Split(strings.Trim(strings.TrimPrefix(path, "/webconsole"), "/"), "/") {
+		menu, ok := m.tree[segment]
+		if !ok {
+			continue
+		}
+		crumb := Breadcrumb{Id: menu.Id, DisplayName: menu.DisplayName}
+		if menu.IsAction && m.granted[menu.Id] {
+			crumb.Href = "/webconsole/" + strings.Join(m.ancestry(menu.Id), "/")
+		}
+		crumbs = append(crumbs, crumb)
+	}
+	if len(crumbs) > 0 {
+		crumbs[len(crumbs)-1].Active = true
+	}
+	return crumbs
+}
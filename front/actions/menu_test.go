@@ -0,0 +1,226 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var testMenuTree = []menuResource{
+	{Id: "operations", ParentId: "home", DisplayName: "Operations", Priority: 1},
+	{Id: "manage", ParentId: "operations", DisplayName: "Manage", Priority: 1},
+	{Id: "workloads", ParentId: "manage", DisplayName: "Workloads", Priority: 2, IsAction: true},
+	{Id: "mciworkloads", ParentId: "workloads", DisplayName: "MCI Workloads", Priority: 1, IsAction: true},
+	{Id: "pmkworkloads", ParentId: "workloads", DisplayName: "PMK Workloads", Priority: 2, IsAction: true},
+	{Id: "settings", ParentId: "home", DisplayName: "Settings", Priority: 0},
+	{Id: "account", ParentId: "settings", DisplayName: "Account", Priority: 1},
+	{Id: "users", ParentId: "account", DisplayName: "Users", Priority: 1, IsAction: true},
+}
+
+func TestUserMenusPageMenu(t *testing.T) {
+	menus := newUserMenus(testMenuTree, nil)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/webconsole/operations/manage/workloads/mciworkloads", "mciworkloads"},
+		{"/webconsole/operations/manage/workloads/mciworkloads/", "mciworkloads"},
+		{"/webconsole/operations/manage/workloads/detail", "workloads"},
+		{"/webconsole/settings/account/users", "users"},
+		{"/webconsole/auth/profile", ""},
+		{"/webconsole", ""},
+	}
+	for _, tt := range tests {
+		if got := menus.PageMenu(tt.path); got != tt.want {
+			t.Errorf("PageMenu(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// sidebarShape flattens the sidebar into "parent>id href" strings for comparison
+func sidebarShape(nodes []*MenuNode) []string {
+	var shape []string
+	var walk func(prefix string, nodes []*MenuNode)
+	walk = func(prefix string, nodes []*MenuNode) {
+		for _, node := range nodes {
+			shape = append(shape, prefix+node.Id+" "+node.Href)
+			walk(prefix+node.Id+">", node.Menus)
+		}
+	}
+	walk("", nodes)
+	return shape
+}
+
+func TestUserMenusSidebar(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		want    []string
+	}{
+		{
+			name:    "fully granted, ordered by priority",
+			granted: []string{"operations", "manage", "workloads", "mciworkloads", "pmkworkloads", "settings", "account", "users"},
+			want: []string{
+				"settings /webconsole/settings",
+				"settings>account /webconsole/settings/account",
+				"settings>account>users /webconsole/settings/account/users",
+				"operations /webconsole/operations",
+				"operations>manage /webconsole/operations/manage",
+				"operations>manage>workloads /webconsole/operations/manage/workloads",
+				"operations>manage>workloads>mciworkloads /webconsole/operations/manage/workloads/mciworkloads",
+				"operations>manage>workloads>pmkworkloads /webconsole/operations/manage/workloads/pmkworkloads",
+			},
+		},
+		{
+			name:    "ungranted children are hidden",
+			granted: []string{"operations", "manage", "workloads", "mciworkloads"},
+			want: []string{
+				"operations /webconsole/operations",
+				"operations>manage /webconsole/operations/manage",
+				"operations>manage>workloads /webconsole/operations/manage/workloads",
+				"operations>manage>workloads>mciworkloads /webconsole/operations/manage/workloads/mciworkloads",
+			},
+		},
+		{
+			name:    "granted child of an ungranted parent is not promoted",
+			granted: []string{"operations", "users", "account"},
+			want:    []string{"operations /webconsole/operations"},
+		},
+	}
+	for _, tt := range tests {
+		menus := newUserMenus(testMenuTree, tt.granted)
+		if got := sidebarShape(menus.Sidebar); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: sidebar = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// menuAPI serves GET /api/auth/menus with the test tree, or 502 while failing is set
+type menuAPI struct {
+	granted []string
+	failing atomic.Bool
+}
+
+func newTestMenuAPI(t *testing.T, granted []string) *menuAPI {
+	t.Helper()
+	api := &menuAPI{granted: granted}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"responseData": map[string]interface{}{"tree": testMenuTree, "granted": api.granted},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	prevHost, prevGuard, prevTTL := ApiBaseHost, MENU_GUARD, MENU_CACHE_TTL
+	ApiBaseHost, _ = url.Parse(server.URL)
+	MENU_GUARD, MENU_CACHE_TTL = true, time.Minute
+	resetMenuCache()
+	t.Cleanup(func() {
+		ApiBaseHost, MENU_GUARD, MENU_CACHE_TTL = prevHost, prevGuard, prevTTL
+		resetMenuCache()
+	})
+	return api
+}
+
+func resetMenuCache() {
+	menuCache.Lock()
+	menuCache.entries = map[string]menuCacheEntry{}
+	menuCache.tree = nil
+	menuCache.Unlock()
+}
+
+func newMenuTestContext(authorization, path string) echo.Context {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, path, nil), httptest.NewRecorder())
+	if authorization != "" {
+		c.Set("Authorization", authorization)
+	}
+	return c
+}
+
+func TestPageMenuDataGuard(t *testing.T) {
+	newTestMenuAPI(t, []string{"operations", "manage", "workloads", "mciworkloads"})
+
+	tests := []struct {
+		path        string
+		wantErr     error
+		wantCurrent string
+	}{
+		{"/webconsole/operations/manage/workloads/mciworkloads", nil, "mciworkloads"},
+		{"/webconsole/operations/manage/workloads/pmkworkloads", errMenuForbidden, ""},
+		{"/webconsole/settings/account/users", errMenuForbidden, ""},
+		{"/webconsole/auth/profile", nil, ""},
+	}
+	for _, tt := range tests {
+		data, err := pageMenuData(newMenuTestContext("Bearer a", tt.path), tt.path)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if err == nil && data["currentMenu"] != tt.wantCurrent {
+			t.Errorf("%s: currentMenu = %v, want %q", tt.path, data["currentMenu"], tt.wantCurrent)
+		}
+	}
+
+	if _, err := pageMenuData(newMenuTestContext("", "/webconsole/auth/profile"), "/webconsole/auth/profile"); !errors.Is(err, errMenuUnauthorized) {
+		t.Errorf("without a token: err = %v, want errMenuUnauthorized", err)
+	}
+}
+
+func TestPageMenuDataDuringOutage(t *testing.T) {
+	api := newTestMenuAPI(t, []string{"operations", "manage", "workloads", "mciworkloads"})
+	menuPage := "/webconsole/operations/manage/workloads/mciworkloads"
+	plainPage := "/webconsole/auth/profile"
+
+	if _, err := pageMenuData(newMenuTestContext("Bearer a", menuPage), menuPage); err != nil {
+		t.Fatalf("initial lookup: %v", err)
+	}
+	// the cache entry expires, then the menu API goes down
+	menuCache.Lock()
+	for key, entry := range menuCache.entries {
+		entry.at = time.Now().Add(-2 * time.Minute)
+		menuCache.entries[key] = entry
+	}
+	menuCache.Unlock()
+	api.failing.Store(true)
+
+	// last-known menus of the same session keep both the sidebar and the guard
+	data, err := pageMenuData(newMenuTestContext("Bearer a", menuPage), menuPage)
+	if err != nil || data["currentMenu"] != "mciworkloads" {
+		t.Fatalf("stale session: data = %v, err = %v", data, err)
+	}
+	forbidden := "/webconsole/settings/account/users"
+	if _, err := pageMenuData(newMenuTestContext("Bearer a", forbidden), forbidden); !errors.Is(err, errMenuForbidden) {
+		t.Fatalf("stale session on an ungranted page: err = %v, want errMenuForbidden", err)
+	}
+
+	// a session without cached menus still reaches pages outside the menu tree
+	data, err = pageMenuData(newMenuTestContext("Bearer b", plainPage), plainPage)
+	if err != nil || data != nil {
+		t.Fatalf("non-menu page: data = %v, err = %v, want render without menus", data, err)
+	}
+	if _, err := pageMenuData(newMenuTestContext("Bearer b", menuPage), menuPage); err == nil {
+		t.Fatal("menu page without any known grants rendered during the outage")
+	}
+
+	// without any known tree every page reports the failure
+	resetMenuCache()
+	if _, err := pageMenuData(newMenuTestContext("Bearer b", plainPage), plainPage); err == nil {
+		t.Fatal("page rendered without a known menu tree")
+	}
+}
@@ -1,7 +1,9 @@
 package actions
 
 import (
+	"errors"
 	"front/templates"
+	"log"
 	"net/http"
 	"strings"
 
@@ -12,6 +14,7 @@ import (
 // path에 맞게 호출을 해야 render 와 breadCrumb 가 동작함.
 // ex) "/webconsole/configuration/workspace/manage" 롤 경로를 주고
 // templates/pages 아래에 /configuration/workspace/manage 에 html 파일을 만들면 됨.
+// 경로의 메뉴가 사용자에게 허용되지 않았으면 403 (MC_WEB_CONSOLE_FRONT_MENU_GUARD=false 로 해제)
 func PageController(c echo.Context) error {
 	// Echo의 /* 와일드카드는 전체 경로를 포함하므로 파라미터에서 추출
 	path := c.Request().URL.Path
@@ -19,17 +22,60 @@ func PageController(c echo.Context) error {
 	suffix := ".html"
 	iframefix := ".iframe"
 
-	_, err := templates.FS().Open(renderHtmlPath + suffix)
-	if err != nil {
-		_, err := templates.FS().Open(renderHtmlPath + iframefix + suffix)
-		if err != nil {
+	templatePath := renderHtmlPath + suffix
+	if _, err := templates.FS().Open(templatePath); err != nil {
+		if _, err := templates.FS().Open(renderHtmlPath + iframefix + suffix); err != nil {
 			return c.HTML(http.StatusNotFound, "<html><body><h1>404 Not Found</h1></body></html>")
-		} else {
-			// iFrame 템플릿 렌더링
-			return RenderHTML(c, http.StatusOK, renderHtmlPath+iframefix+suffix, nil)
 		}
+		// iFrame 템플릿 렌더링
+		templatePath = renderHtmlPath + iframefix + suffix
+	}
+
+	data, err := pageMenuData(c, path)
+	if err != nil {
+		if errors.Is(err, errMenuForbidden) {
+			return c.HTML(http.StatusForbidden, "<html><body><h1>403 Forbidden</h1><p>You do not have access to this menu.</p></body></html>")
+		}
+		if errors.Is(err, errMenuUnauthorized) {
+			return c.Redirect(http.StatusSeeOther, "/auth/unauthorized#sessionExpired")
+		}
+		if MENU_GUARD {
+			log.Printf("menu lookup failed for %s: %v", path, err)
+			return c.HTML(http.StatusServiceUnavailable, "<html><body><h1>503 Service Unavailable</h1><p>Failed to load menus.</p></body></html>")
+		}
+		log.Printf("menu lookup failed for %s, rendering without menus: %v", path, err)
 	}
 
-	// 일반 템플릿 렌더링
-	return RenderHTML(c, http.StatusOK, renderHtmlPath+suffix, nil)
+	return RenderHTML(c, http.StatusOK, templatePath, data)
+}
+
+// pageMenuData resolves the sidebar, breadcrumb and current menu of the page.
+// errMenuForbidden is returned when the page belongs to a menu the user is not granted and the guard is on.
+// When the menu API is unavailable the session's last-known menus are used; without them, a page that is
+// not in the last-known menu tree renders without menus and only menu pages report the error.
+func pageMenuData(c echo.Context, path string) (map[string]interface{}, error) {
+	menus, err := LoadUserMenus(c)
+	if err != nil {
+		if errors.Is(err, errMenuUnauthorized) {
+			return nil, err
+		}
+		if menus = lastKnownMenus(c); menus == nil {
+			if tree := lastKnownMenuTree(); tree != nil && tree.PageMenu(path) == "" {
+				log.Printf("menu lookup failed, %s is not a menu page: %v", path, err)
+				return nil, nil
+			}
+			return nil, err
+		}
+		log.Printf("menu lookup failed for %s, using last-known menus: %v", path, err)
+	}
+	current := menus.PageMenu(path)
+	if current != "" && !menus.Granted(current) && MENU_GUARD {
+		log.Printf("menu %s is not granted, rejecting %s", current, path)
+		return nil, errMenuForbidden
+	}
+	return map[string]interface{}{
+		"menus":       menus.Sidebar,
+		"breadcrumb":  menus.Breadcrumb(path),
+		"currentMenu": current,
+	}, nil
 }
@@ -1,9 +1,13 @@
 package actions
 
 import (
+	"log"
+	"net/http"
 	"net/http/httputil"
 	"net/url"
 
+	"front/middleware"
+
 	"github.com/labstack/echo/v4"
 )
 
@@ -18,15 +22,32 @@ func init() {
 	proxy = httputil.NewSingleHostReverseProxy(ApiBaseHost)
 }
 
-// ApiCaller proxies API requests to the backend
+// ApiCaller proxies API requests to the backend.
+// The session access token is sent as the Authorization header, and auth cookies
+// set by the API (login, refresh, logout, impersonation) are stored in the session
+// instead of being passed to the browser.
 func ApiCaller(c echo.Context) error {
 	// Get Authorization from context (set by middleware)
 	authorization, _ := c.Get("Authorization").(string)
 	if authorization != "" {
 		c.Request().Header.Set("Authorization", authorization)
 	}
+	forwardCookies(c, c.Request())
+
+	caller := &httputil.ReverseProxy{
+		Director: proxy.Director,
+		ModifyResponse: func(resp *http.Response) error {
+			tokens := middleware.LoadSessionTokens(c)
+			if middleware.ApplyAuthCookies(&tokens, resp) {
+				if err := middleware.SaveSessionTokens(c, tokens); err != nil {
+					log.Printf("failed to update session tokens: %v", err)
+				}
+			}
+			return nil
+		},
+	}
 
 	// Serve the reverse proxy
-	proxy.ServeHTTP(c.Response(), c.Request())
+	caller.ServeHTTP(c.Response(), c.Request())
 	return nil
 }
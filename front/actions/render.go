@@ -215,6 +215,12 @@ func RenderHTML(c echo.Context, status int, templatePath string, data map[string
 	layoutVars.Set("current_path", c.Request().URL.Path)
 	layoutVars.Set("request", c.Request())
 	layoutVars.Set("response", c.Response())
+	// 서버 렌더링 사이드바/브레드크럼 (없으면 sidebar.js가 localStorage 메뉴로 그린다)
+	layoutVars.Set("menus", []*MenuNode{})
+	layoutVars.Set("breadcrumb", []Breadcrumb{})
+	for k, v := range data {
+		layoutVars.Set(k, v)
+	}
 
 	log.Printf("Executing layout template with content length: %d", len(pageContent))
 
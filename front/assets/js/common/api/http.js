@@ -1,5 +1,10 @@
 import axios from 'axios';
 
+// 인증 토큰은 서버가 HttpOnly 쿠키로 관리 / Auth tokens live in HttpOnly cookies set by the server
+// 상태 변경 요청에는 CSRF 쿠키 값을 헤더로 함께 전송 (double-submit) / Echo the CSRF cookie as a header
+axios.defaults.xsrfCookieName = 'mcwc_csrf';
+axios.defaults.xsrfHeaderName = 'X-CSRF-Token';
+
 // 활성 프로그레스 toast 추적 / Track active progress toasts
 const activeProgressToasts = new Map();
 
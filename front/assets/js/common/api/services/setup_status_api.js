@@ -50,7 +50,7 @@ async function proxyPost(url, body = {}, opts = STATUS_OPTS) {
   return await http().commonAPIPost(url, body, undefined, opts);
 }
 
-// BFF 자체 GET (yaml reachability check 등)
+// BFF 자체 GET (remote asset check 등)
 async function bffGet(url) {
   return await http().commonAPIGet(url);
 }
@@ -74,9 +74,9 @@ export async function fetchAllStatus() {
     roles:        proxyPost(iamUrl('Getrolelist'),               {}),
     // ─ mc-infra-manager: 1개 (system ns asset summary) ───────────
     summary:      proxyPost(infraUrl('GetAssetsSummary'), { queryParams: { nsId: SYSTEM_NS } }),
-    // ─ BFF: 2개 (raw yaml URL 도달성) ─────────────────────────────
-    menuYaml:     bffGet(bffUrl('/api/admin/setup-yaml-check?which=menu')),
-    apiYaml:      bffGet(bffUrl('/api/admin/setup-yaml-check?which=api')),
+    // ─ BFF: 2개 (raw yaml 도달성 + 로컬 conf 사본 비교) ─────────────
+    menuYaml:     bffGet(bffUrl('/api/admin/remote-assets/menu')),
+    apiYaml:      bffGet(bffUrl('/api/admin/remote-assets/api')),
   };
 
   const keys = Object.keys(calls);
@@ -123,7 +123,7 @@ export async function rerunLoadAssets() {
 export async function fetchMenuOnly() {
   const calls = {
     menus:    proxyPost(iamUrl('listMenus'), {}),
-    menuYaml: bffGet(bffUrl('/api/admin/setup-yaml-check?which=menu')),
+    menuYaml: bffGet(bffUrl('/api/admin/remote-assets/menu')),
   };
   const settled = await Promise.allSettled(Object.values(calls));
   return {
@@ -136,7 +136,7 @@ export async function fetchMenuOnly() {
 export async function fetchApiOnly() {
   const calls = {
     mcmpApis: proxyPost(iamUrl('ListMcmpApisServices'), {}),
-    apiYaml:  bffGet(bffUrl('/api/admin/setup-yaml-check?which=api')),
+    apiYaml:  bffGet(bffUrl('/api/admin/remote-assets/api')),
   };
   const settled = await Promise.allSettled(Object.values(calls));
   return {
@@ -327,6 +327,10 @@ export function buildViewModel(raw) {
       sourceLastModified: menuYaml.lastModified,
       sourceETag: menuYaml.etag,
       sourceErrorMessage: menuYaml.errorMessage,
+      sourceValid: menuYaml.valid,
+      sourceValidationError: menuYaml.validationError,
+      sourceDiff: menuYaml.diff,
+      sourceVersion: menuYaml.version,
     },
     api: {
       registeredCount: apiCount,
@@ -338,6 +342,10 @@ export function buildViewModel(raw) {
       sourceLastModified: apiYaml.lastModified,
       sourceETag: apiYaml.etag,
       sourceErrorMessage: apiYaml.errorMessage,
+      sourceValid: apiYaml.valid,
+      sourceValidationError: apiYaml.validationError,
+      sourceDiff: apiYaml.diff,
+      sourceVersion: apiYaml.version,
     },
     credentials: {
       holders: holderList,
@@ -507,11 +515,13 @@ function aggregateProvidersFromHolders(holders) {
   return out;
 }
 
-// BFF setup-yaml-check 응답 파싱 — 항상 200으로 오므로 reachable 필드로 판정
+// BFF remote-assets 응답 파싱 — 원격 도달 실패도 200으로 오므로 reachable 필드로 판정
+// diff/version은 로컬 conf/ 사본 비교 결과 (원격 본문을 받지 못하면 null)
 function parseYamlCheck(settled) {
   const empty = {
     url: null, reachable: false, httpStatus: null,
     lastModified: null, etag: null, errorMessage: null,
+    valid: false, validationError: null, diff: null, version: null,
   };
   if (!settled || settled.status !== 'fulfilled') {
     return { ...empty, errorMessage: settled && settled.reason ? String(settled.reason) : 'request failed' };
@@ -527,6 +537,10 @@ function parseYamlCheck(settled) {
     lastModified: data.lastModified || null,
     etag: data.etag || null,
     errorMessage: data.errorMessage || null,
+    valid: !!data.valid,
+    validationError: data.validationError || null,
+    diff: data.diff || null,
+    version: data.version || null,
   };
 }
 
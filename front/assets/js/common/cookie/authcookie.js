@@ -12,19 +12,6 @@ function getProactiveRefreshIntervalMs() {
   return DEFAULT_PROACTIVE_REFRESH_INTERVAL_MS;
 }
 
-function isValidJWT(token) {
-  if (!token || typeof token !== 'string') {
-    return false;
-  }
-
-  const parts = token.split('.');
-  if (parts.length !== 3) {
-    return false;
-  }
-
-  return Boolean(parts[0] && parts[1] && parts[2]);
-}
-
 function getCookie(name) {
   const cookies = document.cookie.split(';');
 
@@ -38,43 +25,10 @@ function getCookie(name) {
   return null;
 }
 
-function getAccessTokenFromCookie() {
-  return getCookie('Authorization');
-}
-
-function getRefreshTokenFromCookie() {
-  return getCookie('RefreshToken');
-}
-
-function syncSessionTokens() {
-  if (!webconsolejs || !webconsolejs['common/storage/sessionstorage']) {
-    return;
-  }
-
-  webconsolejs['common/storage/sessionstorage'].setSessionCurrentUserToken();
-  webconsolejs['common/storage/sessionstorage'].setSessionCurrentUserRefreshToken();
-}
-
+// access/refresh 토큰은 front 서버 세션에만 있어 JS에서 읽을 수 없다.
+// 로그인 시 함께 발급되는 CSRF 쿠키(mcwc_csrf)로 로그인 상태를 판단한다.
 function hasValidAuthTokens() {
-  const accessToken = getAccessTokenFromCookie();
-  const refreshToken = getRefreshTokenFromCookie();
-
-  return Boolean(
-    accessToken && refreshToken
-      && isValidJWT(accessToken) && isValidJWT(refreshToken),
-  );
-}
-
-export async function updateCookieAccessToken(accessToken) {
-  let now = new Date();
-  now.setTime(now.getTime() + (24 * 60 * 60 * 1000));
-  document.cookie = `Authorization=${accessToken}; path=/; expires=${now.toUTCString()};`;
-}
-
-export async function updateCookieRefreshToken(refreshToken) {
-  let now = new Date();
-  now.setTime(now.getTime() + (24 * 60 * 60 * 1000));
-  document.cookie = `RefreshToken=${refreshToken}; path=/; expires=${now.toUTCString()};`;
+  return Boolean(getCookie('mcwc_csrf'));
 }
 
 export function stopProactiveTokenRefresh() {
@@ -167,22 +121,13 @@ export async function refreshCookieAccessToken(options = {}) {
   }
 
   const controller = '/api/auth/refresh';
-  const refreshToken = getRefreshTokenFromCookie();
-
-  if (!refreshToken) {
-    return false;
-  }
 
-  if (!isValidJWT(refreshToken)) {
-    document.cookie = 'RefreshToken=; expires=Thu, 01 Jan 1970 00:00:00 UTC; path=/;';
+  if (!hasValidAuthTokens()) {
     return false;
   }
 
-  const data = {
-    request: {
-      refresh_token: refreshToken,
-    },
-  };
+  // 토큰은 front 서버 세션에 있으며 갱신도 서버에서 처리된다
+  const data = { request: {} };
 
   try {
     const response = await webconsolejs['common/api/http'].commonAPIPostWithoutRetry(
@@ -200,29 +145,11 @@ export async function refreshCookieAccessToken(options = {}) {
       return false;
     }
 
-    const accessToken = response.data.access_token;
-    if (!accessToken || accessToken === '') {
-      console.error('ERROR: Access token is missing or empty in response');
-      return false;
-    }
-
-    if (!isValidJWT(accessToken)) {
-      console.error('ERROR: Invalid access token format received:', accessToken);
+    if (!response.data.refreshed) {
+      console.error('ERROR: Session refresh was not confirmed');
       return false;
     }
 
-    await updateCookieAccessToken(accessToken);
-
-    if (response.data.refresh_token) {
-      const newRefreshToken = response.data.refresh_token;
-      if (isValidJWT(newRefreshToken)) {
-        await updateCookieRefreshToken(newRefreshToken);
-      } else {
-        console.error('ERROR: Invalid new refresh token format:', newRefreshToken);
-      }
-    }
-
-    syncSessionTokens();
     lastRefreshAtMs = Date.now();
     return true;
   } catch (error) {
@@ -1,30 +1,15 @@
 // user token mng START
 //////////////////////////////////////////////////////////
 
-function getCookie(name) {
-    const cookies = document.cookie.split(';');
-    for (let cookie of cookies) {
-        const [key, value] = cookie.split('=').map(c => c.trim());
-        if (key === name) {
-            return value;
-        }
+// 인증 토큰은 front 서버 세션에만 보관된다.
+// iframe 플러그인(외부 서비스)에 넘겨야 할 때만 서버에서 조회하며 브라우저 저장소에는 남기지 않는다.
+export async function getPluginAccessToken() {
+    const response = await fetch('/auth/plugin-token', { credentials: 'same-origin', cache: 'no-store' });
+    if (!response.ok) {
+        return null;
     }
-    return null;
-}
-
-export function setSessionCurrentUserToken() {
-    const accesstoken = getCookie('Authorization');
-    sessionStorage.setItem('currentUserToken', accesstoken)
-}
-
-export function getSessionCurrentUserToken() {
-    let currentUserToken = sessionStorage.getItem('currentUserToken')
-    return currentUserToken
-}
-
-export function setSessionCurrentUserRefreshToken() {
-    const refreshToken = getCookie('RefreshToken');
-    sessionStorage.setItem('currentUserRefreshToken', refreshToken)
+    const data = await response.json();
+    return data.access_token || null;
 }
 
 // user token mng END
@@ -5,21 +5,24 @@ document.getElementById("loginbtn").addEventListener('click',async function () {
             "password":document.getElementById("password").value
         }
     };
-    const response = await webconsolejs["common/api/http"].commonAPIPostWithoutRetry('/api/auth/login', data);
+    let response = await webconsolejs["common/api/http"].commonAPIPostWithoutRetry('/api/auth/login', data);
+    if (response && response.status === 200 && response.data && response.data.mfa_required) {
+        response = await completeMFALogin(response.data);
+        if (!response) {
+            document.getElementById('password').value = '';
+            return;
+        }
+    }
     const isSuccess = response && response.status === 200 && response.data &&
-        response.data.refresh_token && response.data.access_token;
+        response.data.authenticated;
     if (!isSuccess) {
         const errMsg = response && response.response ? (response.response.data?.message || response.message) : 'Login failed';
         alert('LoginFail\n' + (typeof errMsg === 'string' ? errMsg : JSON.stringify(errMsg)));
         document.getElementById('id').value = '';
         document.getElementById('password').value = '';
     } else {
-        // 로그인 성공 시에만 토큰 저장
+        // 토큰은 front 서버 세션에 저장됨
         try {
-            await webconsolejs["common/cookie/authcookie"].updateCookieRefreshToken(response.data.refresh_token);
-            await webconsolejs["common/cookie/authcookie"].updateCookieAccessToken(response.data.access_token);
-            await webconsolejs["common/storage/sessionstorage"].setSessionCurrentUserToken();
-            await webconsolejs["common/storage/sessionstorage"].setSessionCurrentUserRefreshToken();
             webconsolejs["common/cookie/authcookie"].startProactiveTokenRefresh();
         } catch (error) {
             console.error("Error saving tokens:", error);
@@ -62,6 +65,47 @@ document.getElementById('password').addEventListener('keydown', function(event)
 
 
 
+// 2차 인증(MFA) 처리: 필요 시 인증 앱 등록 후 코드를 입력받아 로그인을 완료한다.
+// 성공 시 토큰이 포함된 응답, 취소 시 null 반환
+async function completeMFALogin(challenge) {
+    const http = webconsolejs["common/api/http"];
+    const mfaToken = challenge.mfa_token;
+
+    if (challenge.enrollment_required) {
+        const enrollResponse = await http.commonAPIPostWithoutRetry('/api/auth/login/mfa/enroll', {
+            "request": { "mfa_token": mfaToken }
+        });
+        const enrollment = enrollResponse && enrollResponse.data && enrollResponse.data.responseData;
+        if (!enrollment) {
+            alert('MFA enrollment failed');
+            return null;
+        }
+        alert('MFA is required for your account.\n\n'
+            + 'Register this key in your authenticator app:\n' + enrollment.secret + '\n\n'
+            + enrollment.provisioning_uri + '\n\n'
+            + 'Recovery codes (store them safely, shown only once):\n' + enrollment.recovery_codes.join('\n'));
+    }
+
+    for (;;) {
+        const code = window.prompt('Enter the 6-digit code from your authenticator app (or a recovery code)');
+        if (code === null) {
+            return null;
+        }
+        const response = await http.commonAPIPostWithoutRetry('/api/auth/login/mfa', {
+            "request": { "mfa_token": mfaToken, "code": code.trim() }
+        });
+        if (response && response.status === 200) {
+            return response;
+        }
+        const errMsg = response && response.response ? (response.response.data?.message || response.message) : 'MFA failed';
+        alert('MFA failed\n' + errMsg);
+        // 챌린지 만료 또는 시도 횟수 초과 시 처음부터 다시 로그인
+        if (typeof errMsg === 'string' && (errMsg.includes('expired') || errMsg.includes('Too many'))) {
+            return null;
+        }
+    }
+}
+
 // 평면 배열을 트리 구조로 변환하는 함수
 function convertToMenuTree(menuList) {
     // 1. 메뉴 맵 생성 (빠른 검색용)
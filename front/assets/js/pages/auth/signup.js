@@ -68,6 +68,7 @@ document.addEventListener("DOMContentLoaded", function() {
         const firstName = document.getElementById("firstName").value.trim();
         const lastName = document.getElementById("lastName").value.trim();
         const organization = document.getElementById("organization").value.trim();
+        const invitationCode = document.getElementById("invitationCode").value.trim();
 
         if (!validateSignupForm(email, password, firstName, lastName)) {
             return;
@@ -86,11 +87,17 @@ document.addEventListener("DOMContentLoaded", function() {
             if (organization !== "") {
                 requestData.organization = organization;
             }
+            if (invitationCode !== "") {
+                requestData.invitationCode = invitationCode;
+            }
 
             const res = await webconsolejs["common/api/http"].commonAPIPostWithoutRetry("/api/auth/signup", { request: requestData });
 
-            if (res.data && res.data.success === true) {
+            if (res.data && (res.data.success === true || res.status === 201 || res.status === 202)) {
                 showSuccessState(res.data.redirectUrl);
+            } else if (res.response && res.response.data && res.response.data.message) {
+                // 비밀번호 정책, 이메일 도메인, 초대 코드 위반 사유
+                showError(res.response.data.message);
             } else {
                 showError((res.data && res.data.error) || "An error occurred during registration.");
             }
@@ -102,7 +109,7 @@ document.addEventListener("DOMContentLoaded", function() {
         }
     });
 
-    const fields = ["email", "password", "firstName", "lastName", "organization"];
+    const fields = ["email", "password", "firstName", "lastName", "organization", "invitationCode"];
     fields.forEach(function(fieldId) {
         const field = document.getElementById(fieldId);
         if (field) {
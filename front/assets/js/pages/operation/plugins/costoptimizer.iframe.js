@@ -1,7 +1,7 @@
-function getCostOptimizerData() {
+async function getCostOptimizerData() {
   const currentWorkspace = webconsolejs['common/api/services/workspace_api'].getCurrentWorkspace();
   const currentProject = webconsolejs['common/api/services/workspace_api'].getCurrentProject();
-  const accessToken = webconsolejs['common/storage/sessionstorage'].getSessionCurrentUserToken();
+  const accessToken = await webconsolejs['common/storage/sessionstorage'].getPluginAccessToken();
 
   return {
     accessToken: accessToken,
@@ -37,7 +37,7 @@ async function loadCostOptimizer() {
     return;
   }
 
-  const data = getCostOptimizerData();
+  const data = await getCostOptimizerData();
   targetDiv.innerHTML = '';
   webconsolejs['common/iframe/iframe'].addIframe('costIframe', host, data);
 }
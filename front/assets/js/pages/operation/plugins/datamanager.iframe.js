@@ -1,7 +1,7 @@
-function getDataManagerData() {
+async function getDataManagerData() {
   const currentWorkspace = webconsolejs['common/api/services/workspace_api'].getCurrentWorkspace();
   const currentProject = webconsolejs['common/api/services/workspace_api'].getCurrentProject();
-  const accessToken = webconsolejs['common/storage/sessionstorage'].getSessionCurrentUserToken();
+  const accessToken = await webconsolejs['common/storage/sessionstorage'].getPluginAccessToken();
 
   return {
     accessToken: accessToken,
@@ -37,7 +37,7 @@ async function loadDataManager() {
     return;
   }
 
-  const data = getDataManagerData();
+  const data = await getDataManagerData();
   targetDiv.innerHTML = '';
   webconsolejs['common/iframe/iframe'].addIframe('targetIframe', host, data);
 }
@@ -1,7 +1,7 @@
-function getObservabilityData() {
+async function getObservabilityData() {
   const currentWorkspace = webconsolejs['common/api/services/workspace_api'].getCurrentWorkspace();
   const currentProject = webconsolejs['common/api/services/workspace_api'].getCurrentProject();
-  const accessToken = webconsolejs['common/storage/sessionstorage'].getSessionCurrentUserToken();
+  const accessToken = await webconsolejs['common/storage/sessionstorage'].getPluginAccessToken();
 
   return {
     accessToken: accessToken,
@@ -40,7 +40,7 @@ async function loadObservability() {
     return;
   }
 
-  const data = getObservabilityData();
+  const data = await getObservabilityData();
   const iframeSrc = host;
 
   bannerDiv.innerHTML = '';
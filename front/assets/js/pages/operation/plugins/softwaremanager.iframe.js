@@ -1,7 +1,7 @@
-function getSoftwareManagerData() {
+async function getSoftwareManagerData() {
   const currentWorkspace = webconsolejs['common/api/services/workspace_api'].getCurrentWorkspace();
   const currentProject = webconsolejs['common/api/services/workspace_api'].getCurrentProject();
-  const accessToken = webconsolejs['common/storage/sessionstorage'].getSessionCurrentUserToken();
+  const accessToken = await webconsolejs['common/storage/sessionstorage'].getPluginAccessToken();
 
   return {
     accessToken: accessToken,
@@ -37,7 +37,7 @@ async function loadSoftwareManager() {
     return;
   }
 
-  const data = getSoftwareManagerData();
+  const data = await getSoftwareManagerData();
 
   const apiHost = await webconsolejs['common/iframe/iframe'].GetApiHosts('mc-application-manager');
   if (apiHost) {
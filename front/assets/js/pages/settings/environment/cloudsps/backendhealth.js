@@ -0,0 +1,129 @@
+import { TabulatorFull as Tabulator } from "tabulator-tables";
+
+// Backend Health — BFF HealthMonitor 결과 (GET /api/admin/health, POST /api/admin/health/check)
+// api.yaml 서비스별 상태, 응답 시간, 최근 점검 이력, TLS 인증서 만료를 표시한다.
+
+const HEALTH_URL = '/api/admin/health';
+const HEALTH_CHECK_URL = '/api/admin/health/check';
+
+const STATUS_BADGE = {
+  up:       'bg-green-lt',
+  degraded: 'bg-yellow-lt',
+  down:     'bg-red-lt',
+  unknown:  'bg-secondary-lt',
+};
+
+let healthTable = null;
+
+function http() {
+  return webconsolejs['common/api/http'];
+}
+
+// BFF CommonResponse unwrap
+function extractResult(resp) {
+  if (!resp || !resp.data) return null;
+  return resp.data.responseData || null;
+}
+
+function statusBadge(status) {
+  const cls = STATUS_BADGE[status] || STATUS_BADGE.unknown;
+  return `<span class="badge ${cls}">${status || 'unknown'}</span>`;
+}
+
+function formatDate(value) {
+  if (!value || value.startsWith('0001-')) return '-';
+  return new Date(value).toLocaleString();
+}
+
+// 최근 점검 이력 (왼쪽이 오래된 결과)
+function historyDots(history) {
+  return (history || []).map(function(sample) {
+    const color = sample.up ? 'bg-green' : 'bg-red';
+    const title = `${formatDate(sample.at)} ${sample.statusCode || ''} ${sample.error || ''}`.trim();
+    return `<span class="badge ${color} p-1 me-1" title="${title.replace(/"/g, '&quot;')}"></span>`;
+  }).join('');
+}
+
+function certificateText(cert) {
+  if (!cert) return '-';
+  return `${cert.daysLeft}d (${new Date(cert.notAfter).toLocaleDateString()})`;
+}
+
+function renderSummary(summary) {
+  const el = document.getElementById('backend-health-summary');
+  if (!el || !summary) return;
+  const counts = summary.counts || {};
+  el.innerHTML = `
+    <div class="d-flex align-items-center gap-3 flex-wrap">
+      <div>Overall ${statusBadge(summary.status)}</div>
+      <div>Up <strong>${counts.up || 0}</strong></div>
+      <div>Degraded <strong>${counts.degraded || 0}</strong></div>
+      <div>Down <strong>${counts.down || 0}</strong></div>
+      ${(summary.requiredDown || []).length ? `<div class="text-danger">Required down: ${summary.requiredDown.join(', ')}</div>` : ''}
+      <div class="ms-auto text-secondary">Checked ${formatDate(summary.checkedAt)}</div>
+    </div>`;
+}
+
+function renderError(message) {
+  const el = document.getElementById('backend-health-summary');
+  if (el) el.innerHTML = `<div class="text-danger">${message}</div>`;
+}
+
+function initTable() {
+  if (healthTable) return healthTable;
+  healthTable = new Tabulator("#backend-health-table", {
+    data: [],
+    layout: "fitColumns",
+    height: 520,
+    placeholder: "No backend services",
+    columns: [
+      { title: "Service", field: "name", minWidth: 180 },
+      { title: "Status", field: "status", width: 110, formatter: function(cell) { return statusBadge(cell.getValue()); } },
+      { title: "URL", field: "url", minWidth: 240, tooltip: true,
+        formatter: function(cell) {
+          const row = cell.getRow().getData();
+          return `${cell.getValue()} <span class="badge bg-azure-lt ms-1">${row.source}</span>`;
+        } },
+      { title: "Code", field: "statusCode", width: 80, hozAlign: "center" },
+      { title: "Latency", field: "latencyMs", width: 100, hozAlign: "right", formatter: function(cell) { return `${cell.getValue()} ms`; } },
+      { title: "Uptime", field: "uptime", width: 100, hozAlign: "right", formatter: function(cell) { return `${Math.round((cell.getValue() || 0) * 100)}%`; } },
+      { title: "Certificate", field: "certificate", width: 150, formatter: function(cell) { return certificateText(cell.getValue()); } },
+      { title: "History", field: "history", minWidth: 200, formatter: function(cell) { return historyDots(cell.getValue()); } },
+      { title: "Reason", field: "reason", minWidth: 200, tooltip: true,
+        formatter: function(cell) {
+          const row = cell.getRow().getData();
+          return cell.getValue() || row.error || '';
+        } },
+    ],
+  });
+  return healthTable;
+}
+
+function render(result) {
+  if (!result) {
+    renderError('Failed to load backend health.');
+    return;
+  }
+  renderSummary(result.summary);
+  initTable().setData(result.backends || []);
+}
+
+// 최근 점검 결과 조회
+export async function refreshBackendHealth() {
+  const resp = await http().commonAPIGet(HEALTH_URL);
+  if (resp && resp.response && resp.response.status === 503) {
+    renderError('Backend health monitor is disabled (MC_WEB_CONSOLE_HEALTH_INTERVAL=0).');
+    return;
+  }
+  render(extractResult(resp));
+}
+
+// 즉시 점검 후 결과 표시
+export async function checkBackendHealthNow() {
+  const resp = await http().commonAPIPost(HEALTH_CHECK_URL, {});
+  render(extractResult(resp));
+}
+
+document.addEventListener("DOMContentLoaded", async function() {
+  await refreshBackendHealth();
+});
@@ -540,6 +540,10 @@ function mergeMenu(prev, partial) {
     sourceLastModified: menuYaml.lastModified,
     sourceETag: menuYaml.etag,
     sourceErrorMessage: menuYaml.errorMessage,
+    sourceValid: menuYaml.valid,
+    sourceValidationError: menuYaml.validationError,
+    sourceDiff: menuYaml.diff,
+    sourceVersion: menuYaml.version,
   };
   return next;
 }
@@ -564,6 +568,10 @@ function mergeApi(prev, partial) {
     sourceLastModified: apiYaml.lastModified,
     sourceETag: apiYaml.etag,
     sourceErrorMessage: apiYaml.errorMessage,
+    sourceValid: apiYaml.valid,
+    sourceValidationError: apiYaml.validationError,
+    sourceDiff: apiYaml.diff,
+    sourceVersion: apiYaml.version,
   };
   return next;
 }
@@ -946,6 +954,8 @@ function renderMenuCard(menu) {
           <div>${reachableBadge(menu.sourceUrlReachable, menu.sourceHttpStatus, menu.sourceErrorMessage)}</div></div>
         <div class="col-md-6"><div class="text-muted small">Last-Modified / ETag</div>
           <div class="small">${formatYamlMeta(menu)}</div></div>
+        <div class="col-12"><div class="text-muted small">Local conf copy</div>
+          <div class="small">${formatLocalCopy(menu)}</div></div>
         <div class="col-12"><div class="text-muted small">URL</div>
           <div class="small text-break">${menu.sourceUrl ? escapeHtml(menu.sourceUrl) : '<span class="text-muted">env not configured</span>'}</div></div>
       </div>
@@ -987,6 +997,8 @@ function renderApiCard(apiVm) {
           <div>${reachableBadge(apiVm.sourceUrlReachable, apiVm.sourceHttpStatus, apiVm.sourceErrorMessage)}</div></div>
         <div class="col-md-3"><div class="text-muted small">Last-Modified</div>
           <div class="small">${formatYamlMeta(apiVm)}</div></div>
+        <div class="col-12"><div class="text-muted small">Local conf copy</div>
+          <div class="small">${formatLocalCopy(apiVm)}</div></div>
         <div class="col-12"><div class="text-muted small">URL</div>
           <div class="small text-break">${apiVm.sourceUrl ? escapeHtml(apiVm.sourceUrl) : '<span class="text-muted">env not configured</span>'}</div></div>
         ${renderServicesTable(apiVm.services)}
@@ -1476,6 +1488,35 @@ function formatYamlMeta(card) {
   return parts.length > 0 ? parts.join('<br>') : '<span class="text-muted">-</span>';
 }
 
+// 로컬 conf/ 사본과 원격 yaml 비교 결과 — 문법 오류, 뒤처진 버전 수, 줄 단위 차이
+function formatLocalCopy(card) {
+  const diff = card.sourceDiff;
+  if (!diff) return '<span class="text-muted">-</span>';
+  const parts = [];
+  if (card.sourceValidationError) {
+    parts.push(`<span class="badge bg-danger-lt" title="${escapeAttr(card.sourceValidationError)}">❌ upstream invalid</span>`);
+  }
+  if (!diff.localExists) {
+    parts.push(`<span class="badge bg-warning-lt" title="${escapeAttr(diff.error || '')}">local file missing</span>`);
+    return parts.join(' ');
+  }
+  if (diff.identical) {
+    parts.push('<span class="badge bg-success-lt">✅ up to date</span>');
+    return parts.join(' ');
+  }
+  const version = card.sourceVersion;
+  if (version && version.behind > 0) {
+    parts.push(`<span class="badge bg-warning-lt">local is ${version.behind} version${version.behind > 1 ? 's' : ''} behind upstream</span>`);
+  } else if (version && version.supported && !version.error && version.behind < 0) {
+    parts.push(`<span class="badge bg-warning-lt">local differs from the last ${version.searched} upstream versions</span>`);
+  } else {
+    parts.push('<span class="badge bg-warning-lt">local differs from upstream</span>');
+  }
+  const preview = (diff.preview || []).join('\n');
+  parts.push(`<span class="text-muted" title="${escapeAttr(preview)}">+${diff.added} / -${diff.removed} lines</span>`);
+  return parts.join(' ');
+}
+
 function setActionsDisabled(disabled) {
   const ids = [
     'setup-refresh-all-btn',
@@ -1573,6 +1614,7 @@ function parseYaml(settled) {
   const empty = {
     url: null, reachable: false, httpStatus: null,
     lastModified: null, etag: null, errorMessage: null,
+    valid: false, validationError: null, diff: null, version: null,
   };
   if (!settled || settled.status !== 'fulfilled') return empty;
   const resp = settled.value;
@@ -1585,5 +1627,9 @@ function parseYaml(settled) {
     lastModified: data.lastModified || null,
     etag: data.etag || null,
     errorMessage: data.errorMessage || null,
+    valid: !!data.valid,
+    validationError: data.validationError || null,
+    diff: data.diff || null,
+    version: data.version || null,
   };
 }
@@ -9,8 +9,15 @@ let projectListselectBox = document.getElementById("select-current-project");
 
 let workspaceRefreshBtn = document.getElementById("refresh-user-ws-prj")// ws prj refresh 버튼
 
+const IMPERSONATION_ADMIN_ROLES = ['platformadmin', 'admin'];
+let impersonating = false;
+
 document.addEventListener('DOMContentLoaded', async function () {
-    webconsolejs['common/cookie/authcookie'].startProactiveTokenRefresh();
+    // 대리(impersonation) 중에는 관리자 refresh token으로 토큰이 교체되지 않도록 자동 갱신을 하지 않는다
+    impersonating = await initImpersonation();
+    if (!impersonating) {
+        webconsolejs['common/cookie/authcookie'].startProactiveTokenRefresh();
+    }
     await workspaceProjectInit() // workspace select box, project select box 초기화 from local storage
     if (workspaceListselectBox.value === ""){
         workspaceListselectBox.classList.add('is-invalid');
@@ -150,16 +157,79 @@ export async function workspaceProjectInit() {
 
 
 document.getElementById("logoutbtn").addEventListener('click', async function () {
+    // 대리 중 로그아웃은 대리를 끝내고 관리자 본인 세션으로 돌아간다
+    if (impersonating) {
+        await stopImpersonation();
+        return;
+    }
     webconsolejs['common/cookie/authcookie'].stopProactiveTokenRefresh();
-    destroyAccessToken()
+    await destroyAccessToken()
     sessionStorage.clear();
     window.location = "/auth/logout"
 });
 
-export function destroyAccessToken() {
-    document.cookie = `Authorization=; path=/; expires=Thu, 01 Jan 1970 00:00:00 UTC;`;
+// 인증 쿠키는 HttpOnly이므로 서버 로그아웃으로 세션 폐기 및 쿠키 삭제
+export async function destroyAccessToken() {
+    await webconsolejs["common/api/http"].commonAPIPostWithoutRetry('/api/auth/logout');
+}
+
+// impersonation
+// - userinfo 응답의 impersonation 정보로 배너 표시
+// - 관리자 역할이면 사용자 메뉴에 "View as User" 노출
+// - 시작/종료 시 서버가 인증 쿠키를 대리 토큰 ↔ 관리자 토큰으로 교체한다
+async function initImpersonation() {
+    const response = await webconsolejs["common/api/http"].commonAPIGet('/api/auth/userinfo');
+    const userinfo = response?.data?.responseData;
+    if (!userinfo) {
+        return false;
+    }
+
+    const impersonation = userinfo.impersonation;
+    if (impersonation) {
+        document.getElementById("impersonation-target").textContent = userinfo.user_name || userinfo.user_id;
+        document.getElementById("impersonation-actor").textContent = impersonation.actor;
+        document.getElementById("impersonation-mode").textContent = impersonation.read_only ? "read-only" : "read-write";
+        document.getElementById("impersonation-banner").classList.remove('d-none');
+        return true;
+    }
+
+    if (IMPERSONATION_ADMIN_ROLES.includes(String(userinfo.role || '').toLowerCase())) {
+        document.getElementById("impersonate-btn").classList.remove('d-none');
+    }
+    return false;
+}
+
+export async function startImpersonation(userId) {
+    const response = await webconsolejs["common/api/http"].commonAPIPost('/api/admin/impersonate', { request: { user_id: userId } });
+    const grant = response?.data?.responseData;
+    if (!grant || !grant.access_token) {
+        const message = response?.response?.data?.status?.message || "Failed to start impersonation";
+        alert(message);
+        return;
+    }
+
+    webconsolejs['common/cookie/authcookie'].stopProactiveTokenRefresh();
+    sessionStorage.clear();
+    window.location.reload();
+}
+
+export async function stopImpersonation() {
+    await webconsolejs["common/api/http"].commonAPIPostWithoutRetry('/api/auth/impersonation/stop');
+    sessionStorage.clear();
+    window.location.reload();
 }
 
+document.getElementById("impersonate-btn").addEventListener('click', async function () {
+    const userId = prompt("User ID to view as");
+    if (userId && userId.trim() !== "") {
+        await startImpersonation(userId.trim());
+    }
+});
+
+document.getElementById("impersonation-stop-btn").addEventListener('click', function () {
+    stopImpersonation();
+});
+
 // workspaceObj
 // export async function setWorkspaceChanged(selectedWorkspaceValue){
 //     console.log(" setWorkspaceChanged ")
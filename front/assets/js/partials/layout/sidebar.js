@@ -1,5 +1,11 @@
 document.addEventListener("DOMContentLoaded", function () {
-    updatemenu();
+    // 서버에서 허용 메뉴로 렌더링한 사이드바는 아이콘만 채우고, 아니면 localStorage 메뉴로 그린다
+    const sidebar = document.getElementById("sidebar-menu-inner");
+    if (sidebar && sidebar.dataset.serverRendered === "true") {
+        fillMenuIcons(sidebar);
+    } else {
+        updatemenu();
+    }
     document.querySelectorAll('div[name^="sidebar_"]').forEach(function(item) {
         item.addEventListener('click', function(e) {
             const hrefStr = this.getAttribute('href')
@@ -34,6 +40,13 @@ function updatemenu(){
     document.getElementById("sidebar-menu-inner").innerHTML = menuHTML;
 }
 
+function fillMenuIcons(sidebar) {
+    sidebar.querySelectorAll("[data-menu-icon]").forEach(function (icon) {
+        const id = icon.dataset.menuIcon;
+        icon.innerHTML = iconsArr[id] ? iconsArr[id] : iconsArr["undefined"];
+    });
+}
+
 function generateMenuHTML(menus) {
     let html = '';
     let debugMenu = document.getElementById("sidebar-menu-inner").innerHTML ; // 디버그 용도
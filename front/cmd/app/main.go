@@ -8,6 +8,9 @@ import (
 
 // main is the starting point for the Echo application.
 func main() {
+	if err := actions.CheckSessionSettings(); err != nil {
+		log.Fatal(err)
+	}
 	e := actions.App()
 	addr := actions.FRONT_ADDR + ":" + actions.FRONT_PORT
 	if err := e.Start(addr); err != nil {
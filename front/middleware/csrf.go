@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cookie names shared with the API server (api/internal/middleware/csrf.go)
+const (
+	AccessTokenCookie  = "Authorization"
+	RefreshTokenCookie = "RefreshToken"
+	ImpersonatorCookie = "ImpersonatorToken"
+	CSRFCookie         = "mcwc_csrf"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// csrfExemptPaths are pre-session auth endpoints (a stale cookie must not block a new login)
+var csrfExemptPaths = []string{
+	"/api/auth/login",
+	"/api/auth/signup",
+	"/api/auth/verify-email",
+	"/api/auth/password/reset",
+}
+
+// CSRFMiddleware rejects state-changing requests authenticated by cookie (session or token cookie) unless
+// the X-CSRF-Token header matches the mcwc_csrf cookie (double-submit) or the
+// Origin/Referer is this host or one of trustedOrigins.
+// Requests handled directly by the front (e.g. PostCmdInfra) rely on this check;
+// proxied /api calls are checked again by the API server.
+func CSRFMiddleware(trustedOrigins []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if isSafeMethod(req.Method) || !hasAuthCookie(req) || isCSRFExempt(req.URL.Path) {
+				return next(c)
+			}
+			if token := req.Header.Get(CSRFHeader); token != "" {
+				if cookie, err := req.Cookie(CSRFCookie); err == nil &&
+					subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) == 1 {
+					return next(c)
+				}
+			}
+			if originTrusted(req, trustedOrigins) {
+				return next(c)
+			}
+			return c.JSON(http.StatusForbidden, map[string]interface{}{"error": "CSRF validation failed"})
+		}
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func hasAuthCookie(req *http.Request) bool {
+	for _, name := range []string{SessionName, AccessTokenCookie, RefreshTokenCookie} {
+		if cookie, err := req.Cookie(name); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isCSRFExempt(path string) bool {
+	for _, p := range csrfExemptPaths {
+		if path == p || strings.HasPrefix(path, p+"/") || strings.HasPrefix(path, p+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// originTrusted reports whether Origin (or Referer when Origin is absent) points at this host or a trusted origin
+func originTrusted(req *http.Request, trustedOrigins []string) bool {
+	source := req.Header.Get("Origin")
+	if source == "" || source == "null" {
+		source = req.Header.Get("Referer")
+	}
+	if source == "" {
+		return false
+	}
+	u, err := url.Parse(source)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, req.Host) {
+		return true
+	}
+	origin := u.Scheme + "://" + u.Host
+	for _, trusted := range trustedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(trusted, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,55 +1,70 @@
 package middleware
 
 import (
-	"fmt"
 	"log"
 	"net/http"
 	"strings"
 	"time"
-	"net/url"
 
 	"github.com/labstack/echo/v4"
 )
 
-// IsTokenExistMiddleware checks if the Authorization token exists in cookies
-func IsTokenExistMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		// Skip auth for specific paths
-		if shouldSkipAuth(c.Request().URL.Path) {
-			return next(c)
-		}
+// IsTokenExistMiddleware checks that the server-side session holds an access token,
+// refreshes it through refresh shortly before it expires, and exposes it as the
+// Authorization value for ApiCaller.
+func IsTokenExistMiddleware(refresh TokenRefresher) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// Skip auth for specific paths
+			if shouldSkipAuth(c.Request().URL.Path) {
+				return next(c)
+			}
 
-		cookie, err := c.Request().Cookie("Authorization")
-		if err != nil && c.Request().RequestURI == "/" {
-			log.Println(err.Error())
-			return c.Redirect(http.StatusSeeOther, "/auth/login")
-		} else if err != nil {
-			log.Println(err.Error())
-			return c.Redirect(http.StatusSeeOther, "/auth/unauthorized#cookieError")
-		}
+			tokens := LoadSessionTokens(c)
+			if tokens.AccessToken == "" {
+				log.Println("token is not exist in session")
+				return unauthenticated(c, "AuthorizationNotExist")
+			}
 
-		if cookie == nil || cookie.Value == "" || cookie.Value == "undefined" {
-			errMsg := fmt.Errorf("token is not exist")
-			log.Println(errMsg.Error())
-			return c.Redirect(http.StatusSeeOther, "/auth/unauthorized#AuthorizationNotExist")
-		}
+			// An expired impersonation token ends the impersonation and falls back to the admin's own token
+			if tokens.ImpersonatorToken != "" && needsRefresh(tokens.AccessToken) {
+				tokens = SessionTokens{AccessToken: tokens.ImpersonatorToken, RefreshToken: tokens.RefreshToken}
+				if err := SaveSessionTokens(c, tokens); err != nil {
+					log.Printf("failed to end expired impersonation: %v", err)
+				}
+			}
 
-		// JWT는 base64 패딩(=) 포함 가능 → 쿠키 저장 시 encodeURIComponent 적용되어 있으면 디코딩
-		tokenValue, _ := url.QueryUnescape(cookie.Value)
-		if tokenValue == "" {
-			tokenValue = cookie.Value
-		}
+			if needsRefresh(tokens.AccessToken) {
+				refreshed, err := RefreshSessionTokens(c, tokens, refresh)
+				if err != nil {
+					log.Printf("session token refresh failed: %v", err)
+					if exp := TokenExpiry(tokens.AccessToken); exp.IsZero() || time.Now().After(exp) {
+						SaveSessionTokens(c, SessionTokens{})
+						return unauthenticated(c, "sessionExpired")
+					}
+				} else {
+					tokens = refreshed
+				}
+			}
 
-		// Check cookie expiration (if Expires is set)
-		if !cookie.Expires.IsZero() && cookie.Expires.Before(time.Now()) {
-			errMsg := fmt.Errorf("cookie token is expired")
-			log.Println(errMsg.Error())
-			return c.Redirect(http.StatusSeeOther, "/auth/unauthorized#cookieExpired")
+			c.Set("Authorization", "Bearer "+tokens.AccessToken)
+			if tokens.ImpersonatorToken != "" {
+				c.Set(ImpersonatorCookie, tokens.ImpersonatorToken)
+			}
+			return next(c)
 		}
+	}
+}
 
-		c.Set("Authorization", tokenValue)
-		return next(c)
+// unauthenticated answers API calls with 401 (so JS can redirect to login) and pages with a redirect
+func unauthenticated(c echo.Context, reason string) error {
+	if strings.HasPrefix(c.Request().URL.Path, "/api/") {
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{"message": "Not authenticated"})
+	}
+	if c.Request().RequestURI == "/" {
+		return c.Redirect(http.StatusSeeOther, "/auth/login")
 	}
+	return c.Redirect(http.StatusSeeOther, "/auth/unauthorized#"+reason)
 }
 
 // shouldSkipAuth checks if the given path should skip authentication
@@ -64,6 +79,9 @@ func shouldSkipAuth(path string) bool {
 		"/api/auth/login",
 		"/api/auth/refresh",
 		"/api/auth/signup",
+		"/api/auth/verify-email",
+		"/api/auth/password/reset-request",
+		"/api/auth/password/reset",
 		"/static/",
 		"/assets/",
 		"/favicon.ico",
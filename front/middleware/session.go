@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+)
+
+// SessionName is the Gorilla session that holds the user's tokens on the server side.
+// Browser JS never sees the tokens; it only holds the opaque session cookie.
+const SessionName = "mc_web_console"
+
+const (
+	sessionAccessToken       = "access_token"
+	sessionRefreshToken      = "refresh_token"
+	sessionImpersonatorToken = "impersonator_token"
+)
+
+// refreshSkew refreshes the access token this long before it expires
+const refreshSkew = 30 * time.Second
+
+// SessionTokens are the API tokens kept in the front session
+type SessionTokens struct {
+	AccessToken       string
+	RefreshToken      string
+	ImpersonatorToken string // admin token kept while impersonating (sent back on impersonation stop)
+}
+
+// TokenRefresher calls the API refresh endpoint with the given refresh token (injected by actions)
+type TokenRefresher func(c echo.Context, refreshToken string) (*http.Response, error)
+
+// LoadSessionTokens reads tokens from the session (zero value if absent)
+func LoadSessionTokens(c echo.Context) SessionTokens {
+	sess, err := session.Get(SessionName, c)
+	if err != nil {
+		return SessionTokens{}
+	}
+	str := func(key string) string {
+		v, _ := sess.Values[key].(string)
+		return v
+	}
+	return SessionTokens{
+		AccessToken:       str(sessionAccessToken),
+		RefreshToken:      str(sessionRefreshToken),
+		ImpersonatorToken: str(sessionImpersonatorToken),
+	}
+}
+
+// SaveSessionTokens stores tokens in the session; an empty access token clears the session
+func SaveSessionTokens(c echo.Context, tokens SessionTokens) error {
+	sess, err := session.Get(SessionName, c)
+	if err != nil {
+		return err
+	}
+	if tokens.AccessToken == "" {
+		sess.Options.MaxAge = -1
+		sess.Values = map[interface{}]interface{}{}
+		return sess.Save(c.Request(), c.Response())
+	}
+	sess.Values[sessionAccessToken] = tokens.AccessToken
+	sess.Values[sessionRefreshToken] = tokens.RefreshToken
+	if tokens.ImpersonatorToken != "" {
+		sess.Values[sessionImpersonatorToken] = tokens.ImpersonatorToken
+	} else {
+		delete(sess.Values, sessionImpersonatorToken)
+	}
+	return sess.Save(c.Request(), c.Response())
+}
+
+// RegenerateSession discards the current session (its server-side file is deleted) and continues under a new
+// session id, so a session id planted before login (session fixation) never carries the user's tokens.
+// Call it before SaveSessionTokens on login.
+func RegenerateSession(c echo.Context) error {
+	sess, err := session.Get(SessionName, c)
+	if err != nil && sess == nil {
+		return err
+	}
+	if sess.ID != "" {
+		maxAge := sess.Options.MaxAge
+		sess.Options.MaxAge = -1
+		if err := sess.Save(c.Request(), c.Response()); err != nil {
+			return err
+		}
+		sess.Options.MaxAge = maxAge
+	}
+	sess.ID = ""
+	sess.IsNew = true
+	sess.Values = map[interface{}]interface{}{}
+	return nil
+}
+
+// ApplyAuthCookies moves the auth cookies the API set (Authorization, RefreshToken, ImpersonatorToken)
+// from resp into tokens and strips them from resp, so they are kept in the session instead of the browser.
+// Other cookies (e.g. the CSRF cookie) are left in place. Returns true if tokens changed.
+func ApplyAuthCookies(tokens *SessionTokens, resp *http.Response) bool {
+	changed := false
+	var keep []string
+	for _, raw := range resp.Header.Values("Set-Cookie") {
+		cookie, err := http.ParseSetCookie(raw)
+		if err != nil {
+			keep = append(keep, raw)
+			continue
+		}
+		cleared := cookie.MaxAge < 0 || cookie.Value == ""
+		switch cookie.Name {
+		case AccessTokenCookie:
+			if cleared {
+				*tokens = SessionTokens{}
+			} else {
+				tokens.AccessToken = cookie.Value
+			}
+			changed = true
+		case RefreshTokenCookie:
+			if !cleared {
+				tokens.RefreshToken = cookie.Value
+				changed = true
+			}
+		case ImpersonatorCookie:
+			if cleared {
+				tokens.ImpersonatorToken = ""
+			} else {
+				tokens.ImpersonatorToken = cookie.Value
+			}
+			changed = true
+		default:
+			keep = append(keep, raw)
+		}
+	}
+	resp.Header.Del("Set-Cookie")
+	for _, raw := range keep {
+		resp.Header.Add("Set-Cookie", raw)
+	}
+	return changed
+}
+
+// TokenExpiry returns the exp claim of a JWT without verifying it (zero time if unreadable)
+func TokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
+}
+
+// needsRefresh reports whether the access token expires within refreshSkew
+func needsRefresh(token string) bool {
+	exp := TokenExpiry(token)
+	return !exp.IsZero() && time.Until(exp) < refreshSkew
+}
+
+// refreshLocks serializes refreshes per refresh token; concurrent requests of one session
+// wait for the first refresh and reuse its result instead of replaying a rotated refresh token.
+// Entries are dropped refreshResultTTL after they were created, whether the refresh succeeded or not.
+var refreshLocks = struct {
+	sync.Mutex
+	entries map[string]*refreshEntry
+}{entries: map[string]*refreshEntry{}}
+
+type refreshEntry struct {
+	sync.Mutex
+	created time.Time
+	done    bool // tokens hold the result of a successful refresh
+	tokens  SessionTokens
+}
+
+// refreshResultTTL how long a refresh result is reused for requests still carrying the old session
+const refreshResultTTL = time.Minute
+
+// refreshEntryFor returns the entry of the refresh token, evicting expired entries
+func refreshEntryFor(key string) *refreshEntry {
+	refreshLocks.Lock()
+	defer refreshLocks.Unlock()
+	for k, entry := range refreshLocks.entries {
+		if time.Since(entry.created) > refreshResultTTL {
+			delete(refreshLocks.entries, k)
+		}
+	}
+	entry, ok := refreshLocks.entries[key]
+	if !ok {
+		entry = &refreshEntry{created: time.Now()}
+		refreshLocks.entries[key] = entry
+	}
+	return entry
+}
+
+// RefreshSessionTokens refreshes the session tokens through the API and saves them to the session
+func RefreshSessionTokens(c echo.Context, tokens SessionTokens, refresh TokenRefresher) (SessionTokens, error) {
+	if tokens.RefreshToken == "" {
+		return tokens, fmt.Errorf("no refresh token in session")
+	}
+	if exp := TokenExpiry(tokens.RefreshToken); !exp.IsZero() && time.Now().After(exp) {
+		return tokens, fmt.Errorf("refresh token expired")
+	}
+
+	entry := refreshEntryFor(tokens.RefreshToken)
+	entry.Lock()
+	defer entry.Unlock()
+
+	refreshed := entry.tokens
+	if !entry.done {
+		resp, err := refresh(c, tokens.RefreshToken)
+		if err != nil {
+			return tokens, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return tokens, fmt.Errorf("refresh failed with status %d", resp.StatusCode)
+		}
+		refreshed = tokens
+		ApplyAuthCookies(&refreshed, resp)
+		if refreshed.AccessToken == "" || refreshed.AccessToken == tokens.AccessToken {
+			return tokens, fmt.Errorf("refresh response contained no access token")
+		}
+		entry.tokens, entry.done = refreshed, true
+	}
+
+	if err := SaveSessionTokens(c, refreshed); err != nil {
+		return tokens, err
+	}
+	return refreshed, nil
+}